@@ -0,0 +1,169 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	apiKeyCmd.PersistentFlags().String("database", "sqlite://cloud.db", "The database backing the provisioning server.")
+
+	apiKeyCreateCmd.Flags().String("name", "", "A name describing the holder of the API key.")
+	apiKeyCreateCmd.Flags().StringSlice("scope", []string{model.APIKeyScopeReadOnly}, "The scopes granted to the API key (read-only, installation-admin, cluster-admin). Accepts multiple values.")
+	apiKeyCreateCmd.Flags().String("owner", "", "Bind the API key to the given owner, limiting it to that owner's installations, cluster installations and webhooks.")
+	apiKeyCreateCmd.MarkFlagRequired("name")
+
+	apiKeyListCmd.Flags().Int("page", 0, "The page of API keys to fetch, starting at 0.")
+	apiKeyListCmd.Flags().Int("per-page", 100, "The number of API keys to fetch per page.")
+	apiKeyListCmd.Flags().Bool("include-deleted", false, "Whether to include revoked API keys.")
+	apiKeyListCmd.Flags().Bool("table", false, "Whether to display the returned API key list in a table or not")
+
+	apiKeyRevokeCmd.Flags().String("apikey", "", "The id of the API key to be revoked.")
+	apiKeyRevokeCmd.MarkFlagRequired("apikey")
+
+	apiKeyCmd.AddCommand(apiKeyCreateCmd)
+	apiKeyCmd.AddCommand(apiKeyListCmd)
+	apiKeyCmd.AddCommand(apiKeyRevokeCmd)
+}
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manipulate the API keys used to authenticate with the provisioning server.",
+}
+
+var apiKeyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		name, _ := command.Flags().GetString("name")
+		ownerID, _ := command.Flags().GetString("owner")
+		scopes, _ := command.Flags().GetStringSlice("scope")
+		if len(scopes) == 0 {
+			return errors.New("must specify at least one scope")
+		}
+		for _, scope := range scopes {
+			if !model.IsValidAPIKeyScope(scope) {
+				return errors.Errorf("unknown scope %s; expected one of %s", scope, strings.Join(model.AllAPIKeyScopes, ", "))
+			}
+		}
+
+		sqlStore, err := sqlStore(command)
+		if err != nil {
+			return err
+		}
+
+		key, err := model.NewAPIKeySecret()
+		if err != nil {
+			return err
+		}
+
+		apiKey := &model.APIKey{
+			Name:    name,
+			KeyHash: model.HashAPIKey(key),
+			Scopes:  scopes,
+			OwnerID: ownerID,
+		}
+		err = sqlStore.CreateAPIKey(apiKey)
+		if err != nil {
+			return errors.Wrap(err, "failed to create API key")
+		}
+
+		err = printJSON(apiKey)
+		if err != nil {
+			return err
+		}
+
+		// The key itself is not stored, so this is the only chance to see it.
+		fmt.Fprintf(os.Stderr, "API key (this will not be shown again): %s\n", key)
+
+		return nil
+	},
+}
+
+var apiKeyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List created API keys.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		sqlStore, err := sqlStore(command)
+		if err != nil {
+			return err
+		}
+
+		page, _ := command.Flags().GetInt("page")
+		perPage, _ := command.Flags().GetInt("per-page")
+		includeDeleted, _ := command.Flags().GetBool("include-deleted")
+		apiKeys, err := sqlStore.GetAPIKeys(&model.APIKeyFilter{
+			Page:           page,
+			PerPage:        perPage,
+			IncludeDeleted: includeDeleted,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query API keys")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "NAME", "SCOPES", "OWNER", "REVOKED"})
+
+			for _, apiKey := range apiKeys {
+				table.Append([]string{apiKey.ID, apiKey.Name, strings.Join(apiKey.Scopes, ","), apiKey.OwnerID, fmt.Sprintf("%t", apiKey.IsRevoked())})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		err = printJSON(apiKeys)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var apiKeyRevokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke an API key.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		apiKeyID, _ := command.Flags().GetString("apikey")
+
+		sqlStore, err := sqlStore(command)
+		if err != nil {
+			return err
+		}
+
+		apiKey, err := sqlStore.GetAPIKey(apiKeyID)
+		if err != nil {
+			return errors.Wrap(err, "failed to query API key")
+		}
+		if apiKey == nil {
+			return errors.Errorf("API key %s not found", apiKeyID)
+		}
+
+		err = sqlStore.RevokeAPIKey(apiKeyID)
+		if err != nil {
+			return errors.Wrap(err, "failed to revoke API key")
+		}
+
+		return nil
+	},
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	auditCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The provisioning server whose API will be queried.")
+
+	auditListCmd.Flags().String("resource-type", "", "The resource type by which to filter audit records (cluster, installation, cluster_installaton, group, webhook).")
+	auditListCmd.Flags().String("resource-id", "", "The resource id by which to filter audit records.")
+	auditListCmd.Flags().String("since", "", "Only list audit records created at or after the given RFC3339 time.")
+	auditListCmd.Flags().String("until", "", "Only list audit records created before the given RFC3339 time.")
+	auditListCmd.Flags().Int("page", 0, "The page of audit records to fetch, starting at 0.")
+	auditListCmd.Flags().Int("per-page", 100, "The number of audit records to fetch per page.")
+	auditListCmd.Flags().Bool("table", false, "Whether to display the returned audit record list in a table or not")
+
+	auditCmd.AddCommand(auditListCmd)
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "View the audit log of mutating requests made to the provisioning server.",
+}
+
+var auditListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List audit records, newest first.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		startTime, err := parseTimeFlag(command, "since")
+		if err != nil {
+			return err
+		}
+		endTime, err := parseTimeFlag(command, "until")
+		if err != nil {
+			return err
+		}
+
+		resourceType, _ := command.Flags().GetString("resource-type")
+		resourceID, _ := command.Flags().GetString("resource-id")
+		page, _ := command.Flags().GetInt("page")
+		perPage, _ := command.Flags().GetInt("per-page")
+		records, err := client.GetAuditRecords(&model.GetAuditRecordsRequest{
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			StartTime:    startTime,
+			EndTime:      endTime,
+			Page:         page,
+			PerPage:      perPage,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query audit records")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"TIME", "API KEY", "REMOTE ADDRESS", "METHOD", "PATH", "STATUS"})
+
+			for _, record := range records {
+				timestamp := time.Unix(0, record.CreateAt*int64(time.Millisecond)).Format(time.RFC3339)
+				table.Append([]string{timestamp, record.APIKeyID, record.RemoteAddr, record.Method, record.Path, strconv.Itoa(record.StatusCode)})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		err = printJSON(records)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+// parseTimeFlag returns the RFC3339 time of the given flag in milliseconds
+// since the epoch, or 0 if the flag is unset.
+func parseTimeFlag(command *cobra.Command, name string) (int64, error) {
+	value, _ := command.Flags().GetString(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse %s as an RFC3339 time", name)
+	}
+
+	return t.UnixNano() / int64(time.Millisecond), nil
+}
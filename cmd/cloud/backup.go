@@ -0,0 +1,158 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	installationBackupCreateCmd.Flags().String("installation", "", "The id of the installation to back up.")
+	installationBackupCreateCmd.MarkFlagRequired("installation")
+
+	installationBackupGetCmd.Flags().String("installation", "", "The id of the installation whose backup is fetched.")
+	installationBackupGetCmd.Flags().String("backup", "", "The id of the backup to be fetched.")
+	installationBackupGetCmd.MarkFlagRequired("installation")
+	installationBackupGetCmd.MarkFlagRequired("backup")
+
+	installationBackupListCmd.Flags().String("installation", "", "The id of the installation whose backups are listed.")
+	installationBackupListCmd.Flags().String("state", "", "The state by which to filter backups.")
+	installationBackupListCmd.Flags().Int("page", 0, "The page of backups to fetch, starting at 0.")
+	installationBackupListCmd.Flags().Int("per-page", 100, "The number of backups to fetch per page.")
+	installationBackupListCmd.Flags().Bool("table", false, "Whether to display the returned backup list in a table or not")
+	installationBackupListCmd.MarkFlagRequired("installation")
+
+	installationBackupRestoreCmd.Flags().String("installation", "", "The id of the hibernating installation to restore.")
+	installationBackupRestoreCmd.Flags().String("backup", "", "The id of the backup to restore the installation from.")
+	installationBackupRestoreCmd.MarkFlagRequired("installation")
+	installationBackupRestoreCmd.MarkFlagRequired("backup")
+
+	installationBackupCmd.AddCommand(installationBackupCreateCmd)
+	installationBackupCmd.AddCommand(installationBackupGetCmd)
+	installationBackupCmd.AddCommand(installationBackupListCmd)
+	installationBackupCmd.AddCommand(installationBackupRestoreCmd)
+}
+
+var installationBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up installations and restore them from their backups.",
+	Long: `Back up installations and restore them from their backups.
+
+Only installations with a single-tenant RDS database and an S3 filestore,
+dedicated or multitenant, can be backed up. Installations using operator
+backends can be moved to those with 'cloud installation migrate-database'.`,
+}
+
+var installationBackupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Request a backup of the database and filestore of an installation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+
+		backup, err := client.CreateInstallationBackup(installationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to request installation backup")
+		}
+
+		return printJSON(backup)
+	},
+}
+
+var installationBackupGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a particular backup of an installation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+		backupID, _ := command.Flags().GetString("backup")
+
+		backup, err := client.GetInstallationBackup(installationID, backupID)
+		if err != nil {
+			return errors.Wrap(err, "failed to query installation backup")
+		}
+		if backup == nil {
+			return nil
+		}
+
+		return printJSON(backup)
+	},
+}
+
+var installationBackupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the backups of an installation, newest first.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+		state, _ := command.Flags().GetString("state")
+		page, _ := command.Flags().GetInt("page")
+		perPage, _ := command.Flags().GetInt("per-page")
+
+		backups, err := client.GetInstallationBackups(installationID, &model.GetBackupsRequest{
+			State:   state,
+			Page:    page,
+			PerPage: perPage,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query installation backups")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "STATE", "SIZE (BYTES)", "ERROR"})
+
+			for _, backup := range backups {
+				table.Append([]string{backup.ID, backup.State, fmt.Sprintf("%d", backup.Size), backup.Error})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(backups)
+	},
+}
+
+var installationBackupRestoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the database and filestore of a hibernating installation from one of its backups.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+		backupID, _ := command.Flags().GetString("backup")
+
+		installation, err := client.RestoreInstallationBackup(installationID, backupID)
+		if err != nil {
+			return errors.Wrap(err, "failed to restore installation backup")
+		}
+
+		return printJSON(installation)
+	},
+}
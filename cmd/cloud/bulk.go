@@ -0,0 +1,213 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	bulkCmd.PersistentFlags().String("server", defaultLocalServerAPI, "The provisioning server whose API will be queried.")
+
+	bulkCreateCmd.Flags().String("action", "", "The action to apply to each installation (hibernate, wake-up, update, join-group, delete).")
+	bulkCreateCmd.Flags().Bool("dry-run", false, "Only report the installations that would be acted on, without modifying them.")
+	bulkCreateCmd.Flags().Int("concurrency", model.DefaultBulkOperationConcurrency, "The number of installations to act on at once.")
+	bulkCreateCmd.Flags().Bool("all", false, "Act on every installation when no filter flag is set.")
+	bulkCreateCmd.Flags().String("owner", "", "The owner by which to filter installations.")
+	bulkCreateCmd.Flags().String("group", "", "The group ID by which to filter installations.")
+	bulkCreateCmd.Flags().String("dns", "", "The DNS name by which to filter installations.")
+	bulkCreateCmd.Flags().String("version", "", "The Mattermost version by which to filter installations.")
+	bulkCreateCmd.Flags().String("image", "", "The Mattermost container image by which to filter installations.")
+	bulkCreateCmd.Flags().String("database", "", "The database backend type by which to filter installations.")
+	bulkCreateCmd.Flags().String("filestore", "", "The filestore backend type by which to filter installations.")
+	bulkCreateCmd.Flags().String("size", "", "The size by which to filter installations.")
+	bulkCreateCmd.Flags().String("affinity", "", "The affinity by which to filter installations.")
+	addListFilterFlags(bulkCreateCmd, "installations", model.InstallationSortKeys)
+	bulkCreateCmd.Flags().String("set-version", "", "The Mattermost version to set with the update action.")
+	bulkCreateCmd.Flags().String("set-image", "", "The Mattermost container image to set with the update action.")
+	bulkCreateCmd.Flags().String("set-size", "", "The size to set with the update action.")
+	bulkCreateCmd.Flags().StringArray("set-mattermost-env", []string{}, "Env vars to add to the Mattermost App with the update action. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
+	bulkCreateCmd.Flags().String("join-group", "", "The ID of the group to join with the join-group action.")
+	bulkCreateCmd.MarkFlagRequired("action")
+
+	bulkGetCmd.Flags().String("bulk-operation", "", "The id of the bulk operation to be fetched.")
+	bulkGetCmd.Flags().Bool("table", false, "Whether to display the results of the bulk operation in a table or not")
+	bulkGetCmd.MarkFlagRequired("bulk-operation")
+
+	bulkListCmd.Flags().String("state", "", "The state by which to filter bulk operations.")
+	bulkListCmd.Flags().Int("page", 0, "The page of bulk operations to fetch, starting at 0.")
+	bulkListCmd.Flags().Int("per-page", 100, "The number of bulk operations to fetch per page.")
+	bulkListCmd.Flags().Bool("table", false, "Whether to display the returned bulk operation list in a table or not")
+
+	bulkCmd.AddCommand(bulkCreateCmd)
+	bulkCmd.AddCommand(bulkGetCmd)
+	bulkCmd.AddCommand(bulkListCmd)
+}
+
+var bulkCmd = &cobra.Command{
+	Use:   "bulk",
+	Short: "Act on many installations at once with bulk operations.",
+}
+
+var bulkCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Apply an action to every installation matching a filter.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		action, _ := command.Flags().GetString("action")
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		all, _ := command.Flags().GetBool("all")
+		concurrency, _ := command.Flags().GetInt("concurrency")
+		owner, _ := command.Flags().GetString("owner")
+		group, _ := command.Flags().GetString("group")
+		dns, _ := command.Flags().GetString("dns")
+		version, _ := command.Flags().GetString("version")
+		image, _ := command.Flags().GetString("image")
+		database, _ := command.Flags().GetString("database")
+		filestore, _ := command.Flags().GetString("filestore")
+		size, _ := command.Flags().GetString("size")
+		affinity, _ := command.Flags().GetString("affinity")
+		joinGroup, _ := command.Flags().GetString("join-group")
+		filter, err := parseListFilterFlags(command)
+		if err != nil {
+			return err
+		}
+
+		request := &model.CreateBulkOperationRequest{
+			Filter: model.InstallationFilter{
+				OwnerID:       owner,
+				GroupID:       group,
+				DNS:           dns,
+				Version:       version,
+				Image:         image,
+				Database:      database,
+				Filestore:     filestore,
+				Size:          size,
+				Affinity:      affinity,
+				States:        filter.States,
+				Annotations:   filter.Annotations,
+				CreatedAfter:  filter.CreatedAfter,
+				CreatedBefore: filter.CreatedBefore,
+				SortBy:        filter.SortBy,
+				SortOrder:     filter.SortOrder,
+			},
+			All:         all,
+			Action:      action,
+			GroupID:     joinGroup,
+			DryRun:      dryRun,
+			Concurrency: concurrency,
+		}
+
+		if action == model.BulkActionUpdate {
+			mattermostEnv, _ := command.Flags().GetStringArray("set-mattermost-env")
+			envVarMap, err := parseEnvVarInput(mattermostEnv, false)
+			if err != nil {
+				return err
+			}
+
+			request.Patch = &model.PatchInstallationRequest{
+				Version:       getStringFlagPointer(command, "set-version"),
+				Image:         getStringFlagPointer(command, "set-image"),
+				Size:          getStringFlagPointer(command, "set-size"),
+				MattermostEnv: envVarMap,
+			}
+		}
+
+		bulkOperation, err := client.CreateBulkOperation(request)
+		if err != nil {
+			return errors.Wrap(err, "failed to create bulk operation")
+		}
+
+		return printJSON(bulkOperation)
+	},
+}
+
+var bulkGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a particular bulk operation and its per-installation results.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		bulkOperationID, _ := command.Flags().GetString("bulk-operation")
+		bulkOperation, err := client.GetBulkOperation(bulkOperationID)
+		if err != nil {
+			return errors.Wrap(err, "failed to query bulk operation")
+		}
+		if bulkOperation == nil {
+			return nil
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"INSTALLATION", "STATUS", "OLD STATE", "NEW STATE", "MESSAGE"})
+
+			for _, result := range bulkOperation.Results {
+				table.Append([]string{result.InstallationID, result.Status, result.OldState, result.NewState, result.Message})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(bulkOperation)
+	},
+}
+
+var bulkListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List bulk operations, newest first.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		state, _ := command.Flags().GetString("state")
+		page, _ := command.Flags().GetInt("page")
+		perPage, _ := command.Flags().GetInt("per-page")
+		bulkOperations, err := client.GetBulkOperations(&model.GetBulkOperationsRequest{
+			State:   state,
+			Page:    page,
+			PerPage: perPage,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query bulk operations")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "ACTION", "STATE", "DRY RUN", "ERROR"})
+
+			for _, bulkOperation := range bulkOperations {
+				dryRun := "no"
+				if bulkOperation.DryRun {
+					dryRun = "yes"
+				}
+				table.Append([]string{bulkOperation.ID, bulkOperation.Action, bulkOperation.State, dryRun, bulkOperation.Error})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		return printJSON(bulkOperations)
+	},
+}
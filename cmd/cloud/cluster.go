@@ -65,16 +65,25 @@ func init() {
 	clusterResizeCmd.Flags().Int64("size-node-max-count", 0, "The maximum number of k8s worker nodes. Overwrites value from 'size'.")
 	clusterResizeCmd.MarkFlagRequired("cluster")
 
+	clusterDrainCmd.Flags().String("cluster", "", "The id of the cluster to be drained.")
+	clusterDrainCmd.Flags().Int("max-concurrent", model.DefaultClusterDrainMaxConcurrent, "The maximum number of installations to move off of the cluster at the same time.")
+	clusterDrainCmd.MarkFlagRequired("cluster")
+
 	clusterDeleteCmd.Flags().String("cluster", "", "The id of the cluster to be deleted.")
 	clusterDeleteCmd.MarkFlagRequired("cluster")
 
 	clusterGetCmd.Flags().String("cluster", "", "The id of the cluster to be fetched.")
+
+	clusterEventsCmd.Flags().String("cluster", "", "The id of the cluster whose events are fetched.")
+	clusterEventsCmd.MarkFlagRequired("cluster")
+	addEventsFlags(clusterEventsCmd)
 	clusterGetCmd.MarkFlagRequired("cluster")
 
 	clusterListCmd.Flags().Int("page", 0, "The page of clusters to fetch, starting at 0.")
 	clusterListCmd.Flags().Int("per-page", 100, "The number of clusters to fetch per page.")
 	clusterListCmd.Flags().Bool("include-deleted", false, "Whether to include deleted clusters.")
 	clusterListCmd.Flags().Bool("table", false, "Whether to display the returned cluster list in a table or not")
+	addListFilterFlags(clusterListCmd, "clusters", model.ClusterSortKeys)
 
 	clusterUtilitiesCmd.Flags().String("cluster", "", "The id of the cluster whose utilities are to be fetched.")
 	clusterUtilitiesCmd.MarkFlagRequired("cluster")
@@ -84,8 +93,10 @@ func init() {
 	clusterCmd.AddCommand(clusterUpdateCmd)
 	clusterCmd.AddCommand(clusterUpgradeCmd)
 	clusterCmd.AddCommand(clusterResizeCmd)
+	clusterCmd.AddCommand(clusterDrainCmd)
 	clusterCmd.AddCommand(clusterDeleteCmd)
 	clusterCmd.AddCommand(clusterGetCmd)
+	clusterCmd.AddCommand(clusterEventsCmd)
 	clusterCmd.AddCommand(clusterListCmd)
 	clusterCmd.AddCommand(clusterInstallationCmd)
 	clusterCmd.AddCommand(clusterShowStateReport)
@@ -111,7 +122,7 @@ var clusterCreateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		provider, _ := command.Flags().GetString("provider")
 		version, _ := command.Flags().GetString("version")
@@ -186,7 +197,7 @@ var clusterProvisionCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 		clusterID, _ := command.Flags().GetString("cluster")
 
 		var request *model.ProvisionClusterRequest = nil
@@ -227,7 +238,7 @@ var clusterUpdateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		allowInstallations, _ := command.Flags().GetBool("allow-installations")
@@ -267,7 +278,7 @@ var clusterUpgradeCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 
@@ -307,7 +318,7 @@ var clusterResizeCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 
@@ -355,6 +366,47 @@ var clusterResizeCmd = &cobra.Command{
 	},
 }
 
+var clusterDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Move all installations off of a k8s cluster",
+	Long:  "Move all installations off of a k8s cluster. Isolated installations are left in place. The drain fails if installations use an in-cluster database or filestore, which cannot be migrated, or are hibernating or failed, as they cannot be migrated until they are stable.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		clusterID, _ := command.Flags().GetString("cluster")
+		maxConcurrent, _ := command.Flags().GetInt("max-concurrent")
+
+		request := &model.DrainClusterRequest{
+			MaxConcurrent: maxConcurrent,
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			err := printJSON(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to print API request")
+			}
+
+			return nil
+		}
+
+		cluster, err := client.DrainCluster(clusterID, request)
+		if err != nil {
+			return errors.Wrap(err, "failed to drain cluster")
+		}
+
+		err = printJSON(cluster)
+		if err != nil {
+			return errors.Wrap(err, "failed to print cluster response")
+		}
+
+		return nil
+	},
+}
+
 var clusterDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a cluster.",
@@ -362,7 +414,7 @@ var clusterDeleteCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 
@@ -382,7 +434,7 @@ var clusterGetCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		cluster, err := client.GetCluster(clusterID)
@@ -402,6 +454,25 @@ var clusterGetCmd = &cobra.Command{
 	},
 }
 
+var clusterEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List the state change history of a cluster.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		clusterID, _ := command.Flags().GetString("cluster")
+		events, err := client.GetClusterEvents(clusterID, getEventsRequestFromFlags(command))
+		if err != nil {
+			return errors.Wrap(err, "failed to query cluster events")
+		}
+
+		return printEvents(command, events)
+	},
+}
+
 var clusterListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List created clusters.",
@@ -409,15 +480,26 @@ var clusterListCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		page, _ := command.Flags().GetInt("page")
 		perPage, _ := command.Flags().GetInt("per-page")
 		includeDeleted, _ := command.Flags().GetBool("include-deleted")
+		filter, err := parseListFilterFlags(command)
+		if err != nil {
+			return err
+		}
+
 		clusters, err := client.GetClusters(&model.GetClustersRequest{
 			Page:           page,
 			PerPage:        perPage,
 			IncludeDeleted: includeDeleted,
+			States:         filter.States,
+			Annotations:    filter.Annotations,
+			CreatedAfter:   filter.CreatedAfter,
+			CreatedBefore:  filter.CreatedBefore,
+			SortBy:         filter.SortBy,
+			SortOrder:      filter.SortOrder,
 		})
 		if err != nil {
 			return errors.Wrap(err, "failed to query clusters")
@@ -459,7 +541,7 @@ var clusterUtilitiesCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 		clusterID, err := command.Flags().GetString("cluster")
 		if err != nil {
 			return err
@@ -28,6 +28,10 @@ func init() {
 	clusterInstallationListCmd.Flags().Bool("include-deleted", false, "Whether to include deleted cluster installations.")
 	clusterInstallationListCmd.Flags().Bool("table", false, "Whether to display the returned cluster installation list in a table or not")
 
+	clusterInstallationEventsCmd.Flags().String("cluster-installation", "", "The id of the cluster installation whose events are fetched.")
+	clusterInstallationEventsCmd.MarkFlagRequired("cluster-installation")
+	addEventsFlags(clusterInstallationEventsCmd)
+
 	clusterInstallationConfigCmd.PersistentFlags().String("cluster-installation", "", "The id of the cluster installation.")
 	clusterInstallationConfigCmd.MarkFlagRequired("cluster-installation")
 
@@ -48,6 +52,7 @@ func init() {
 
 	clusterInstallationCmd.AddCommand(clusterInstallationGetCmd)
 	clusterInstallationCmd.AddCommand(clusterInstallationListCmd)
+	clusterInstallationCmd.AddCommand(clusterInstallationEventsCmd)
 	clusterInstallationCmd.AddCommand(clusterInstallationConfigCmd)
 	clusterInstallationCmd.AddCommand(clusterInstallationMMCTL)
 	clusterInstallationCmd.AddCommand(clusterInstallationMattermostCLICmd)
@@ -68,7 +73,7 @@ var clusterInstallationGetCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		clusterInstallation, err := client.GetClusterInstallation(clusterInstallationID)
@@ -95,7 +100,7 @@ var clusterInstallationListCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		cluster, _ := command.Flags().GetString("cluster")
 		installation, _ := command.Flags().GetString("installation")
@@ -137,6 +142,25 @@ var clusterInstallationListCmd = &cobra.Command{
 	},
 }
 
+var clusterInstallationEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List the state change history of a cluster installation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
+		events, err := client.GetClusterInstallationEvents(clusterInstallationID, getEventsRequestFromFlags(command))
+		if err != nil {
+			return errors.Wrap(err, "failed to query cluster installation events")
+		}
+
+		return printEvents(command, events)
+	},
+}
+
 var clusterInstallationConfigCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manipulate a particular cluster installation's config.",
@@ -149,7 +173,7 @@ var clusterInstallationConfigGetCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		clusterInstallationConfig, err := client.GetClusterInstallationConfig(clusterInstallationID)
@@ -176,7 +200,7 @@ var clusterInstallationConfigSetCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		key, _ := command.Flags().GetString("key")
@@ -211,7 +235,7 @@ var clusterInstallationMMCTL = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		subcommand, _ := command.Flags().GetString("command")
@@ -235,7 +259,7 @@ var clusterInstallationMattermostCLICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		subcommand, _ := command.Flags().GetString("command")
@@ -33,7 +33,7 @@ var databaseListCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		vpcID, _ := command.Flags().GetString("vpc-id")
 		databaseType, _ := command.Flags().GetString("database-type")
@@ -0,0 +1,50 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// addEventsFlags registers the flags shared by the commands listing events.
+func addEventsFlags(command *cobra.Command) {
+	command.Flags().Int("page", 0, "The page of events to fetch, starting at 0.")
+	command.Flags().Int("per-page", 100, "The number of events to fetch per page.")
+	command.Flags().Bool("table", false, "Whether to display the returned event list in a table or not")
+}
+
+func getEventsRequestFromFlags(command *cobra.Command) *model.GetEventsRequest {
+	page, _ := command.Flags().GetInt("page")
+	perPage, _ := command.Flags().GetInt("per-page")
+
+	return &model.GetEventsRequest{
+		Page:    page,
+		PerPage: perPage,
+	}
+}
+
+func printEvents(command *cobra.Command, events []*model.Event) error {
+	outputToTable, _ := command.Flags().GetBool("table")
+	if !outputToTable {
+		return printJSON(events)
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetHeader([]string{"TIME", "RESOURCE", "OLD STATE", "NEW STATE", "INSTANCE", "ERROR"})
+
+	for _, event := range events {
+		timestamp := time.Unix(0, event.CreateAt*int64(time.Millisecond)).Format(time.RFC3339)
+		table.Append([]string{timestamp, event.ResourceID, event.OldState, event.NewState, event.InstanceID, event.Error})
+	}
+	table.Render()
+
+	return nil
+}
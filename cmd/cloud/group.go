@@ -24,6 +24,7 @@ func init() {
 	groupCreateCmd.Flags().String("image", "", "The Mattermost container image to use.")
 	groupCreateCmd.Flags().Int64("max-rolling", 1, "The maximum number of installations that can be updated at one time when a group is updated")
 	groupCreateCmd.Flags().StringArray("mattermost-env", []string{}, "Env vars to add to the Mattermost App. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
+	addHibernationScheduleFlags(groupCreateCmd, "installations of the group")
 	groupCreateCmd.MarkFlagRequired("name")
 
 	groupUpdateCmd.Flags().String("group", "", "The id of the group to be updated.")
@@ -34,12 +35,18 @@ func init() {
 	groupUpdateCmd.Flags().Int64("max-rolling", 0, "The maximum number of installations that can be updated at one time when a group is updated")
 	groupUpdateCmd.Flags().StringArray("mattermost-env", []string{}, "Env vars to add to the Mattermost App. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
 	groupUpdateCmd.Flags().Bool("mattermost-env-clear", false, "Clears all env var data.")
+	addHibernationScheduleFlags(groupUpdateCmd, "installations of the group, replacing the whole schedule")
+	groupUpdateCmd.Flags().Bool("hibernation-schedule-clear", false, "Removes the hibernation schedule of the group.")
 	groupUpdateCmd.MarkFlagRequired("group")
 
 	groupDeleteCmd.Flags().String("group", "", "The id of the group to be deleted.")
 	groupDeleteCmd.MarkFlagRequired("group")
 
 	groupGetCmd.Flags().String("group", "", "The id of the group to be fetched.")
+
+	groupEventsCmd.Flags().String("group", "", "The id of the group whose installation events are fetched.")
+	groupEventsCmd.MarkFlagRequired("group")
+	addEventsFlags(groupEventsCmd)
 	groupGetCmd.MarkFlagRequired("group")
 
 	groupListCmd.Flags().Int("page", 0, "The page of groups to fetch, starting at 0.")
@@ -65,6 +72,7 @@ func init() {
 	groupCmd.AddCommand(groupGetCmd)
 	groupCmd.AddCommand(groupListCmd)
 	groupCmd.AddCommand(groupGetStatusCmd)
+	groupCmd.AddCommand(groupEventsCmd)
 	groupCmd.AddCommand(groupJoinCmd)
 	groupCmd.AddCommand(groupLeaveCmd)
 }
@@ -81,7 +89,7 @@ var groupCreateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		name, _ := command.Flags().GetString("name")
 		image, _ := command.Flags().GetString("image")
@@ -96,12 +104,13 @@ var groupCreateCmd = &cobra.Command{
 		}
 
 		request := &model.CreateGroupRequest{
-			Name:          name,
-			MaxRolling:    maxRolling,
-			Description:   description,
-			Version:       version,
-			Image:         image,
-			MattermostEnv: envVarMap,
+			Name:                name,
+			MaxRolling:          maxRolling,
+			Description:         description,
+			Version:             version,
+			Image:               image,
+			MattermostEnv:       envVarMap,
+			HibernationSchedule: getHibernationScheduleFlags(command),
 		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
@@ -130,7 +139,7 @@ var groupUpdateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		groupID, _ := command.Flags().GetString("group")
 		mattermostEnv, _ := command.Flags().GetStringArray("mattermost-env")
@@ -142,13 +151,18 @@ var groupUpdateCmd = &cobra.Command{
 		}
 
 		request := &model.PatchGroupRequest{
-			ID:            groupID,
-			Name:          getStringFlagPointer(command, "name"),
-			Description:   getStringFlagPointer(command, "description"),
-			Version:       getStringFlagPointer(command, "version"),
-			Image:         getStringFlagPointer(command, "image"),
-			MaxRolling:    getInt64FlagPointer(command, "max-rolling"),
-			MattermostEnv: envVarMap,
+			ID:                  groupID,
+			Name:                getStringFlagPointer(command, "name"),
+			Description:         getStringFlagPointer(command, "description"),
+			Version:             getStringFlagPointer(command, "version"),
+			Image:               getStringFlagPointer(command, "image"),
+			MaxRolling:          getInt64FlagPointer(command, "max-rolling"),
+			MattermostEnv:       envVarMap,
+			HibernationSchedule: getHibernationScheduleFlags(command),
+		}
+		hibernationScheduleClear, _ := command.Flags().GetBool("hibernation-schedule-clear")
+		if hibernationScheduleClear {
+			request.HibernationSchedule = &model.HibernationSchedule{}
 		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
@@ -177,7 +191,7 @@ var groupDeleteCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		groupID, _ := command.Flags().GetString("group")
 
@@ -197,7 +211,7 @@ var groupGetCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		groupID, _ := command.Flags().GetString("group")
 		group, err := client.GetGroup(groupID)
@@ -217,6 +231,25 @@ var groupGetCmd = &cobra.Command{
 	},
 }
 
+var groupEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List the state change history of the installations in a group.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		groupID, _ := command.Flags().GetString("group")
+		events, err := client.GetGroupEvents(groupID, getEventsRequestFromFlags(command))
+		if err != nil {
+			return errors.Wrap(err, "failed to query group events")
+		}
+
+		return printEvents(command, events)
+	},
+}
+
 var groupListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List created groups.",
@@ -224,7 +257,7 @@ var groupListCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		page, _ := command.Flags().GetInt("page")
 		perPage, _ := command.Flags().GetInt("per-page")
@@ -272,7 +305,7 @@ var groupGetStatusCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		groupID, _ := command.Flags().GetString("group")
 		groupStatus, err := client.GetGroupStatus(groupID)
@@ -299,7 +332,7 @@ var groupJoinCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		groupID, _ := command.Flags().GetString("group")
 		installationID, _ := command.Flags().GetString("installation")
@@ -321,7 +354,7 @@ var groupLeaveCmd = &cobra.Command{
 
 		serverAddress, _ := command.Flags().GetString("server")
 		retainConfig, _ := command.Flags().GetBool("retain-config")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		installationID, _ := command.Flags().GetString("installation")
 		request := &model.LeaveGroupRequest{RetainConfig: retainConfig}
@@ -0,0 +1,33 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/spf13/cobra"
+)
+
+func addHibernationScheduleFlags(command *cobra.Command, resourceName string) {
+	command.Flags().String("hibernate-at", "", fmt.Sprintf("A cron expression, in UTC, of when to hibernate the %s, e.g. '0 20 * * 1-5'.", resourceName))
+	command.Flags().String("wake-up-at", "", fmt.Sprintf("A cron expression, in UTC, of when to wake up the %s, e.g. '0 8 * * 1-5'.", resourceName))
+}
+
+// getHibernationScheduleFlags returns the hibernation schedule given by the
+// flags, or nil if none was given.
+func getHibernationScheduleFlags(command *cobra.Command) *model.HibernationSchedule {
+	if !command.Flags().Changed("hibernate-at") && !command.Flags().Changed("wake-up-at") {
+		return nil
+	}
+
+	hibernateAt, _ := command.Flags().GetString("hibernate-at")
+	wakeUpAt, _ := command.Flags().GetString("wake-up-at")
+
+	return &model.HibernationSchedule{
+		HibernateAt: hibernateAt,
+		WakeUpAt:    wakeUpAt,
+	}
+}
@@ -5,7 +5,11 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/olekukonko/tablewriter"
@@ -24,11 +28,15 @@ func init() {
 	installationCreateCmd.Flags().String("dns", "", "The URL at which the Mattermost server will be available.")
 	installationCreateCmd.Flags().String("size", model.InstallationDefaultSize, "The size of the installation. Accepts 100users, 1000users, 5000users, 10000users, 25000users, miniSingleton, or miniHA. Defaults to 100users.")
 	installationCreateCmd.Flags().String("affinity", model.InstallationAffinityIsolated, "How other installations may be co-located in the same cluster.")
+	installationCreateCmd.Flags().String("placement-strategy", "", "The strategy used to select a cluster for the installation. Accepts bin-packing, spread, or annotation-affinity. Defaults to the server placement strategy.")
 	installationCreateCmd.Flags().String("license", "", "The Mattermost License to use in the server.")
 	installationCreateCmd.Flags().String("database", model.InstallationDatabaseMysqlOperator, "The Mattermost server database type. Accepts mysql-operator, aws-rds, aws-rds-postgres, or aws-multitenant-rds")
 	installationCreateCmd.Flags().String("filestore", model.InstallationFilestoreMinioOperator, "The Mattermost server filestore type. Accepts minio-operator or aws-s3")
 	installationCreateCmd.Flags().StringArray("mattermost-env", []string{}, "Env vars to add to the Mattermost App. Accepts format: KEY_NAME=VALUE. Use the flag multiple times to set multiple env vars.")
 	installationCreateCmd.Flags().StringArray("annotation", []string{}, "Additional annotations for the installation. Accepts multiple values, for example: '... --annotation abc --annotation def'")
+	installationCreateCmd.Flags().StringArray("required-cluster-annotation", []string{}, "Annotations a cluster must have for the installation to be scheduled on it. Accepts multiple values, for example: '... --required-cluster-annotation abc --required-cluster-annotation def'")
+	installationCreateCmd.Flags().StringArray("preferred-cluster-annotation", []string{}, "Annotations of clusters that should be preferred when scheduling the installation. Accepts multiple values, for example: '... --preferred-cluster-annotation abc --preferred-cluster-annotation def'")
+	addHibernationScheduleFlags(installationCreateCmd, "installation")
 	installationCreateCmd.MarkFlagRequired("owner")
 	installationCreateCmd.MarkFlagRequired("dns")
 
@@ -46,6 +54,10 @@ func init() {
 	installationGetCmd.Flags().Bool("include-group-config-overrides", true, "Whether to include a group configuration override summary in the installation or not.")
 	installationGetCmd.MarkFlagRequired("installation")
 
+	installationEventsCmd.Flags().String("installation", "", "The id of the installation whose events are fetched.")
+	installationEventsCmd.MarkFlagRequired("installation")
+	addEventsFlags(installationEventsCmd)
+
 	installationListCmd.Flags().String("owner", "", "The owner by which to filter installations.")
 	installationListCmd.Flags().String("group", "", "The group ID by which to filter installations.")
 	installationListCmd.Flags().Bool("include-group-config", true, "Whether to include group configuration in the installations or not.")
@@ -54,6 +66,13 @@ func init() {
 	installationListCmd.Flags().Int("per-page", 100, "The number of installations to fetch per page.")
 	installationListCmd.Flags().Bool("include-deleted", false, "Whether to include deleted installations.")
 	installationListCmd.Flags().Bool("table", false, "Whether to display the returned installation list in a table or not")
+	installationListCmd.Flags().String("version", "", "The Mattermost version by which to filter installations.")
+	installationListCmd.Flags().String("image", "", "The Mattermost container image by which to filter installations.")
+	installationListCmd.Flags().String("database", "", "The database backend type by which to filter installations.")
+	installationListCmd.Flags().String("filestore", "", "The filestore backend type by which to filter installations.")
+	installationListCmd.Flags().String("size", "", "The size by which to filter installations.")
+	installationListCmd.Flags().String("affinity", "", "The affinity by which to filter installations.")
+	addListFilterFlags(installationListCmd, "installations", model.InstallationSortKeys)
 
 	installationHibernateCmd.Flags().String("installation", "", "The id of the installation to put into hibernation.")
 	installationHibernateCmd.MarkFlagRequired("installation")
@@ -61,6 +80,30 @@ func init() {
 	installationWakeupCmd.Flags().String("installation", "", "The id of the installation to wake up from hibernation.")
 	installationWakeupCmd.MarkFlagRequired("installation")
 
+	installationHibernationScheduleCmd.Flags().String("installation", "", "The id of the installation to schedule.")
+	addHibernationScheduleFlags(installationHibernationScheduleCmd, "installation")
+	installationHibernationScheduleCmd.Flags().Bool("clear", false, "Remove the hibernation schedule of the installation.")
+	installationHibernationScheduleCmd.MarkFlagRequired("installation")
+
+	installationMigrateCmd.Flags().String("installation", "", "The id of the installation to migrate.")
+	installationMigrateCmd.Flags().String("to-cluster", "", "The id of the cluster to migrate the installation to.")
+	installationMigrateCmd.MarkFlagRequired("installation")
+	installationMigrateCmd.MarkFlagRequired("to-cluster")
+
+	installationCloneCmd.Flags().String("installation", "", "The id of the installation to clone.")
+	installationCloneCmd.Flags().String("owner", "", "An opaque identifier describing the owner of the clone.")
+	installationCloneCmd.Flags().String("dns", "", "The URL at which the clone will be reachable.")
+	installationCloneCmd.Flags().String("backup", "", "The id of the backup of the installation to clone. A new backup is taken if not set.")
+	installationCloneCmd.Flags().StringArray("scrub-command", []string{}, "The command run on the clone once created, for instance to scrub user data. Requires the cluster-admin scope. Use the flag once per argument, for example: '... --scrub-command sh --scrub-command -c --scrub-command ./scrub.sh'")
+	installationCloneCmd.MarkFlagRequired("installation")
+	installationCloneCmd.MarkFlagRequired("owner")
+	installationCloneCmd.MarkFlagRequired("dns")
+
+	installationMigrateDatabaseCmd.Flags().String("installation", "", "The id of the hibernating installation to migrate the data of.")
+	installationMigrateDatabaseCmd.Flags().String("database", "", "The database type to migrate the installation data to. Supports aws-rds and aws-multitenant-rds for mysql-operator installations.")
+	installationMigrateDatabaseCmd.Flags().String("filestore", "", "The filestore type to migrate the installation data to. Supports aws-s3 for minio-operator installations.")
+	installationMigrateDatabaseCmd.MarkFlagRequired("installation")
+
 	installationDeleteCmd.Flags().String("installation", "", "The id of the installation to be deleted.")
 	installationDeleteCmd.MarkFlagRequired("installation")
 
@@ -69,7 +112,14 @@ func init() {
 	installationCmd.AddCommand(installationDeleteCmd)
 	installationCmd.AddCommand(installationHibernateCmd)
 	installationCmd.AddCommand(installationWakeupCmd)
+	installationCmd.AddCommand(installationHibernationScheduleCmd)
+	installationCmd.AddCommand(installationMigrateCmd)
+	installationCmd.AddCommand(installationMigrateDatabaseCmd)
+	installationCmd.AddCommand(installationCloneCmd)
 	installationCmd.AddCommand(installationGetCmd)
+	installationCmd.AddCommand(installationWatchCmd)
+	installationCmd.AddCommand(installationEventsCmd)
+	installationCmd.AddCommand(installationBackupCmd)
 	installationCmd.AddCommand(installationListCmd)
 	installationCmd.AddCommand(installationShowStateReport)
 }
@@ -86,7 +136,7 @@ var installationCreateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		ownerID, _ := command.Flags().GetString("owner")
 		groupID, _ := command.Flags().GetString("group")
@@ -95,11 +145,14 @@ var installationCreateCmd = &cobra.Command{
 		size, _ := command.Flags().GetString("size")
 		dns, _ := command.Flags().GetString("dns")
 		affinity, _ := command.Flags().GetString("affinity")
+		placementStrategy, _ := command.Flags().GetString("placement-strategy")
 		license, _ := command.Flags().GetString("license")
 		database, _ := command.Flags().GetString("database")
 		filestore, _ := command.Flags().GetString("filestore")
 		mattermostEnv, _ := command.Flags().GetStringArray("mattermost-env")
 		annotations, _ := command.Flags().GetStringArray("annotation")
+		requiredClusterAnnotations, _ := command.Flags().GetStringArray("required-cluster-annotation")
+		preferredClusterAnnotations, _ := command.Flags().GetStringArray("preferred-cluster-annotation")
 
 		envVarMap, err := parseEnvVarInput(mattermostEnv, false)
 		if err != nil {
@@ -107,18 +160,22 @@ var installationCreateCmd = &cobra.Command{
 		}
 
 		request := &model.CreateInstallationRequest{
-			OwnerID:          ownerID,
-			GroupID:          groupID,
-			Version:          version,
-			Image:            image,
-			Size:             size,
-			DNS:              dns,
-			License:          license,
-			Affinity:         affinity,
-			Database:         database,
-			Filestore:        filestore,
-			MattermostEnv:    envVarMap,
-			Annotations: annotations,
+			OwnerID:                     ownerID,
+			GroupID:                     groupID,
+			Version:                     version,
+			Image:                       image,
+			Size:                        size,
+			DNS:                         dns,
+			License:                     license,
+			Affinity:                    affinity,
+			PlacementStrategy:           placementStrategy,
+			Database:                    database,
+			Filestore:                   filestore,
+			MattermostEnv:               envVarMap,
+			Annotations:                 annotations,
+			RequiredClusterAnnotations:  requiredClusterAnnotations,
+			PreferredClusterAnnotations: preferredClusterAnnotations,
+			HibernationSchedule:         getHibernationScheduleFlags(command),
 		}
 
 		dryRun, _ := command.Flags().GetBool("dry-run")
@@ -147,7 +204,7 @@ var installationUpdateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		installationID, _ := command.Flags().GetString("installation")
 		mattermostEnv, _ := command.Flags().GetStringArray("mattermost-env")
@@ -192,7 +249,7 @@ var installationDeleteCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		installationID, _ := command.Flags().GetString("installation")
 
@@ -212,7 +269,7 @@ var installationHibernateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		installationID, _ := command.Flags().GetString("installation")
 
@@ -237,7 +294,7 @@ var installationWakeupCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		installationID, _ := command.Flags().GetString("installation")
 
@@ -255,6 +312,164 @@ var installationWakeupCmd = &cobra.Command{
 	},
 }
 
+var installationHibernationScheduleCmd = &cobra.Command{
+	Use:   "hibernation-schedule",
+	Short: "Set or clear the schedule on which an installation hibernates and wakes up.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+		clear, _ := command.Flags().GetBool("clear")
+
+		if clear {
+			err := client.DeleteInstallationHibernationSchedule(installationID)
+			if err != nil {
+				return errors.Wrap(err, "failed to clear installation hibernation schedule")
+			}
+
+			return nil
+		}
+
+		schedule := getHibernationScheduleFlags(command)
+		if schedule == nil {
+			return errors.New("must specify --hibernate-at, --wake-up-at or --clear")
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			err := printJSON(schedule)
+			if err != nil {
+				return errors.Wrap(err, "failed to print API request")
+			}
+
+			return nil
+		}
+
+		installation, err := client.SetInstallationHibernationSchedule(installationID, schedule)
+		if err != nil {
+			return errors.Wrap(err, "failed to set installation hibernation schedule")
+		}
+
+		return printJSON(installation)
+	},
+}
+
+var installationMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move an installation to another cluster.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+		targetClusterID, _ := command.Flags().GetString("to-cluster")
+
+		request := &model.MigrateInstallationRequest{
+			TargetClusterID: targetClusterID,
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			err := printJSON(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to print API request")
+			}
+
+			return nil
+		}
+
+		installation, err := client.MigrateInstallation(installationID, request)
+		if err != nil {
+			return errors.Wrap(err, "failed to migrate installation")
+		}
+
+		return printJSON(installation)
+	},
+}
+
+var installationMigrateDatabaseCmd = &cobra.Command{
+	Use:   "migrate-database",
+	Short: "Move the data of a hibernating installation to another database and filestore.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+		database, _ := command.Flags().GetString("database")
+		filestore, _ := command.Flags().GetString("filestore")
+
+		request := &model.MigrateInstallationDatabaseRequest{
+			Database:  database,
+			Filestore: filestore,
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			err := printJSON(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to print API request")
+			}
+
+			return nil
+		}
+
+		installation, err := client.MigrateInstallationDatabase(installationID, request)
+		if err != nil {
+			return errors.Wrap(err, "failed to migrate installation database")
+		}
+
+		return printJSON(installation)
+	},
+}
+
+var installationCloneCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Create a new installation from a copy of the database and filestore of an installation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+		ownerID, _ := command.Flags().GetString("owner")
+		dns, _ := command.Flags().GetString("dns")
+		backupID, _ := command.Flags().GetString("backup")
+		scrubCommand, _ := command.Flags().GetStringArray("scrub-command")
+
+		request := &model.CloneInstallationRequest{
+			OwnerID:      ownerID,
+			DNS:          dns,
+			BackupID:     backupID,
+			ScrubCommand: scrubCommand,
+		}
+
+		dryRun, _ := command.Flags().GetBool("dry-run")
+		if dryRun {
+			err := printJSON(request)
+			if err != nil {
+				return errors.Wrap(err, "failed to print API request")
+			}
+
+			return nil
+		}
+
+		installation, err := client.CloneInstallation(installationID, request)
+		if err != nil {
+			return errors.Wrap(err, "failed to clone installation")
+		}
+
+		return printJSON(installation)
+	},
+}
+
 var installationGetCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get a particular installation.",
@@ -262,7 +477,7 @@ var installationGetCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		installationID, _ := command.Flags().GetString("installation")
 		includeGroupConfig, _ := command.Flags().GetBool("include-group-config")
@@ -288,6 +503,106 @@ var installationGetCmd = &cobra.Command{
 	},
 }
 
+var installationEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List the state change history of an installation.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID, _ := command.Flags().GetString("installation")
+		events, err := client.GetInstallationEvents(installationID, getEventsRequestFromFlags(command))
+		if err != nil {
+			return errors.Wrap(err, "failed to query installation events")
+		}
+
+		return printEvents(command, events)
+	},
+}
+
+// watchReconnectDelay is how long to wait before reconnecting to an event
+// stream that was interrupted by an error.
+const watchReconnectDelay = time.Second
+
+var installationWatchCmd = &cobra.Command{
+	Use:   "watch <installation>",
+	Short: "Follow an installation until it is stable or failed, printing each state transition.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		installationID := args[0]
+		var lastState string
+
+		// The server closes event streams periodically, and they may be
+		// interrupted, so reconnect until the installation settles. The
+		// state is re-read after every (re)connection to catch transitions
+		// that happened in between.
+		for {
+			stream, err := client.StreamEvents(&model.StreamEventsRequest{
+				Type: model.TypeInstallation,
+				ID:   installationID,
+			})
+			if err != nil {
+				return errors.Wrap(err, "failed to stream installation events")
+			}
+
+			installation, err := client.GetInstallation(installationID, &model.GetInstallationRequest{})
+			if err != nil {
+				stream.Close()
+				return errors.Wrap(err, "failed to query installation")
+			}
+			if installation == nil {
+				stream.Close()
+				return errors.Errorf("installation %s not found", installationID)
+			}
+
+			if installation.State != lastState {
+				printInstallationTransition(lastState, installation.State)
+				lastState = installation.State
+			}
+
+			for !model.InstallationStateIsSettled(lastState) {
+				payload, err := stream.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					logger.WithError(err).Debug("Event stream interrupted; reconnecting")
+					time.Sleep(watchReconnectDelay)
+					break
+				}
+
+				printInstallationTransition(payload.OldState, payload.NewState)
+				lastState = payload.NewState
+			}
+			stream.Close()
+
+			if model.InstallationStateIsSettled(lastState) {
+				if strings.HasSuffix(lastState, "-failed") {
+					return errors.Errorf("installation %s is %s", installationID, lastState)
+				}
+
+				return nil
+			}
+		}
+	},
+}
+
+func printInstallationTransition(oldState, newState string) {
+	if len(oldState) == 0 {
+		fmt.Printf("%s %s\n", time.Now().Format(time.RFC3339), newState)
+		return
+	}
+
+	fmt.Printf("%s %s -> %s\n", time.Now().Format(time.RFC3339), oldState, newState)
+}
+
 var installationListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List created installations.",
@@ -295,7 +610,7 @@ var installationListCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		owner, _ := command.Flags().GetString("owner")
 		group, _ := command.Flags().GetString("group")
@@ -304,6 +619,17 @@ var installationListCmd = &cobra.Command{
 		page, _ := command.Flags().GetInt("page")
 		perPage, _ := command.Flags().GetInt("per-page")
 		includeDeleted, _ := command.Flags().GetBool("include-deleted")
+		version, _ := command.Flags().GetString("version")
+		image, _ := command.Flags().GetString("image")
+		database, _ := command.Flags().GetString("database")
+		filestore, _ := command.Flags().GetString("filestore")
+		size, _ := command.Flags().GetString("size")
+		affinity, _ := command.Flags().GetString("affinity")
+		filter, err := parseListFilterFlags(command)
+		if err != nil {
+			return err
+		}
+
 		installations, err := client.GetInstallations(&model.GetInstallationsRequest{
 			OwnerID:                     owner,
 			GroupID:                     group,
@@ -312,6 +638,18 @@ var installationListCmd = &cobra.Command{
 			Page:                        page,
 			PerPage:                     perPage,
 			IncludeDeleted:              includeDeleted,
+			Version:                     version,
+			Image:                       image,
+			Database:                    database,
+			Filestore:                   filestore,
+			Size:                        size,
+			Affinity:                    affinity,
+			States:                      filter.States,
+			Annotations:                 filter.Annotations,
+			CreatedAfter:                filter.CreatedAfter,
+			CreatedBefore:               filter.CreatedBefore,
+			SortBy:                      filter.SortBy,
+			SortOrder:                   filter.SortOrder,
 		})
 		if err != nil {
 			return errors.Wrap(err, "failed to query installations")
@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/spf13/cobra"
+)
+
+// listFilter holds the filter and sort flags shared by the list commands.
+type listFilter struct {
+	States        []string
+	Annotations   []string
+	CreatedAfter  int64
+	CreatedBefore int64
+	SortBy        string
+	SortOrder     string
+}
+
+func addListFilterFlags(command *cobra.Command, resourceName string, sortKeys []string) {
+	command.Flags().StringSlice("state", []string{}, fmt.Sprintf("Only list %s in one of the given states. Accepts multiple values.", resourceName))
+	command.Flags().StringSlice("annotation", []string{}, fmt.Sprintf("Only list %s having all of the given annotations. Accepts multiple values.", resourceName))
+	command.Flags().String("created-after", "", fmt.Sprintf("Only list %s created after the given RFC3339 time.", resourceName))
+	command.Flags().String("created-before", "", fmt.Sprintf("Only list %s created before the given RFC3339 time.", resourceName))
+	command.Flags().String("sort-by", "", fmt.Sprintf("The key by which to sort the %s (%s).", resourceName, strings.Join(sortKeys, ", ")))
+	command.Flags().String("sort-order", "", fmt.Sprintf("The order in which to sort the %s (%s, %s).", resourceName, model.SortOrderAscending, model.SortOrderDescending))
+}
+
+func parseListFilterFlags(command *cobra.Command) (*listFilter, error) {
+	createdAfter, err := parseTimeFlag(command, "created-after")
+	if err != nil {
+		return nil, err
+	}
+
+	createdBefore, err := parseTimeFlag(command, "created-before")
+	if err != nil {
+		return nil, err
+	}
+
+	states, _ := command.Flags().GetStringSlice("state")
+	annotations, _ := command.Flags().GetStringSlice("annotation")
+	sortBy, _ := command.Flags().GetString("sort-by")
+	sortOrder, _ := command.Flags().GetString("sort-order")
+
+	return &listFilter{
+		States:        states,
+		Annotations:   annotations,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		SortBy:        sortBy,
+		SortOrder:     sortOrder,
+	}, nil
+}
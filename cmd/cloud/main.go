@@ -23,6 +23,7 @@ var rootCmd = &cobra.Command{
 
 func init() {
 	rootCmd.MarkFlagRequired("database")
+	rootCmd.PersistentFlags().String("api-key", os.Getenv("CLOUD_API_KEY"), "The API key used to authenticate with the provisioning server. Defaults to the CLOUD_API_KEY environment variable.")
 
 	rootCmd.AddCommand(serverCmd)
 	rootCmd.AddCommand(clusterCmd)
@@ -30,7 +31,10 @@ func init() {
 	rootCmd.AddCommand(groupCmd)
 	rootCmd.AddCommand(databaseCmd)
 	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(apiKeyCmd)
 	rootCmd.AddCommand(webhookCmd)
+	rootCmd.AddCommand(bulkCmd)
+	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(securityCmd)
 	rootCmd.AddCommand(workbenchCmd)
 	rootCmd.AddCommand(completionCmd)
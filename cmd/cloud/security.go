@@ -5,7 +5,6 @@
 package main
 
 import (
-	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -59,7 +58,7 @@ var securityClusterLockAPICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		err := client.LockAPIForCluster(clusterID)
@@ -78,7 +77,7 @@ var securityClusterUnlockAPICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterID, _ := command.Flags().GetString("cluster")
 		err := client.UnlockAPIForCluster(clusterID)
@@ -102,7 +101,7 @@ var securityInstallationLockAPICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		installationID, _ := command.Flags().GetString("installation")
 		err := client.LockAPIForInstallation(installationID)
@@ -121,7 +120,7 @@ var securityInstallationUnlockAPICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		installationID, _ := command.Flags().GetString("installation")
 		err := client.UnlockAPIForInstallation(installationID)
@@ -145,7 +144,7 @@ var securityClusterInstallationLockAPICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		err := client.LockAPIForClusterInstallation(clusterInstallationID)
@@ -164,7 +163,7 @@ var securityClusterInstallationUnlockAPICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		clusterInstallationID, _ := command.Flags().GetString("cluster-installation")
 		err := client.UnlockAPIForClusterInstallation(clusterInstallationID)
@@ -188,7 +187,7 @@ var securityGroupLockAPICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		groupID, _ := command.Flags().GetString("group")
 		err := client.LockAPIForGroup(groupID)
@@ -207,7 +206,7 @@ var securityGroupUnlockAPICmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		groupID, _ := command.Flags().GetString("group")
 		err := client.UnlockAPIForGroup(groupID)
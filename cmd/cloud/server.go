@@ -19,14 +19,20 @@ import (
 
 	sdkAWS "github.com/aws/aws-sdk-go/aws"
 	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/clusterdictionary"
 	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/events"
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/internal/provisioner"
 	"github.com/mattermost/mattermost-cloud/internal/store"
 	"github.com/mattermost/mattermost-cloud/internal/supervisor"
 	toolsAWS "github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	logrus "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
@@ -43,16 +49,40 @@ func init() {
 
 	serverCmd.PersistentFlags().String("database", "sqlite://cloud.db", "The database backing the provisioning server.")
 	serverCmd.PersistentFlags().String("listen", ":8075", "The interface and port on which to listen.")
+	serverCmd.PersistentFlags().String("metrics-listen", ":8076", "The interface and port on which to serve Prometheus metrics. Metrics are served without authentication, so this must not be exposed publicly.")
 	serverCmd.PersistentFlags().Bool("cluster-supervisor", true, "Whether this server will run a cluster supervisor or not.")
 	serverCmd.PersistentFlags().Bool("group-supervisor", false, "Whether this server will run an installation group supervisor or not.")
 	serverCmd.PersistentFlags().Bool("installation-supervisor", true, "Whether this server will run an installation supervisor or not.")
 	serverCmd.PersistentFlags().Bool("cluster-installation-supervisor", true, "Whether this server will run a cluster installation supervisor or not.")
+	serverCmd.PersistentFlags().Bool("webhook-delivery-supervisor", true, "Whether this server will run a webhook delivery supervisor to retry failed webhooks or not.")
+	serverCmd.PersistentFlags().Duration("webhook-delivery-retention", supervisor.DefaultWebhookDeliveryRetention, "How long delivered and failed webhook deliveries are kept.")
+	serverCmd.PersistentFlags().Bool("bulk-operation-supervisor", true, "Whether this server will run a bulk operation supervisor to act on installations in bulk or not.")
+	serverCmd.PersistentFlags().Bool("hibernation-schedule-supervisor", true, "Whether this server will run a hibernation schedule supervisor to hibernate and wake up installations on schedule or not.")
+	serverCmd.PersistentFlags().Bool("idle-hibernation-supervisor", false, "Whether this server will run an idle hibernation supervisor to hibernate installations without active users or API traffic or not. Installations annotated with 'no-idle-hibernation' are never hibernated for being idle.")
+	serverCmd.PersistentFlags().Duration("idle-hibernation-period", 7*24*time.Hour, "How long an installation must have had no active users nor API requests to be hibernated by the idle hibernation supervisor.")
+	serverCmd.PersistentFlags().Duration("idle-hibernation-check-interval", 10*time.Minute, "How often the idle hibernation supervisor measures the activity of installations.")
+	serverCmd.PersistentFlags().Int64("idle-hibernation-max-requests-per-hour", 3600, "The average number of API requests per hour an installation may serve and still be considered idle. Must cover the requests of liveness and readiness probes, which are counted like any other.")
+	serverCmd.PersistentFlags().Bool("backup-supervisor", true, "Whether this server will run a backup supervisor to take requested installation backups or not.")
 	serverCmd.PersistentFlags().String("state-store", "dev.cloud.mattermost.com", "The S3 bucket used to store cluster state.")
 	serverCmd.PersistentFlags().StringSlice("allow-list-cidr-range", []string{"0.0.0.0/0"}, "The list of CIDRs to allow communication with the private ingress.")
 
+	serverCmd.PersistentFlags().Bool("require-api-key", false, "Whether to reject API requests without a valid API key. Keys are managed with the apikey command.")
+	serverCmd.PersistentFlags().Duration("idempotency-key-retention", api.DefaultIdempotencyKeyRetention, "How long responses to create requests made with an Idempotency-Key header are kept for replay.")
+	serverCmd.PersistentFlags().Duration("idempotency-key-lease", api.DefaultIdempotencyKeyLease, "How long a create request made with an Idempotency-Key header may be in progress before a retry with the same key is served again. Must exceed the time taken to serve a create request.")
+	serverCmd.PersistentFlags().Duration("audit-retention", api.DefaultAuditRetention, "How long audit records of mutating API requests are kept.")
+	serverCmd.PersistentFlags().String("tracing-otlp-endpoint", "", "The host:port of an OTLP gRPC collector to which trace spans are exported. Spans are not exported over OTLP if unset.")
+	serverCmd.PersistentFlags().Bool("tracing-otlp-insecure", false, "Whether to connect to the OTLP collector without TLS.")
+	serverCmd.PersistentFlags().String("tracing-file", "", "The path of a file to which trace spans are appended as JSON for offline analysis. Spans are not written to a file if unset.")
 	serverCmd.PersistentFlags().Int("poll", 30, "The interval in seconds to poll for background work.")
 	serverCmd.PersistentFlags().Int("cluster-resource-threshold", 80, "The percent threshold where new installations won't be scheduled on a multi-tenant cluster.")
 	serverCmd.PersistentFlags().Int("cluster-resource-threshold-scale-value", 0, "The number of worker nodes to scale up by when the threshold is passed. Set to 0 for no scaling. Scaling will never exceed the cluster max worker configuration value.")
+	serverCmd.PersistentFlags().String("placement-strategy", supervisor.DefaultPlacementStrategy, "The default strategy used to select a cluster for new installations. Accepts bin-packing, spread, or annotation-affinity.")
+	serverCmd.PersistentFlags().Bool("cluster-on-demand", false, "Whether to create a new cluster when no existing cluster can accept a new installation.")
+	serverCmd.PersistentFlags().String("cluster-on-demand-size", "SizeAlef1000", "The size constant describing clusters created on demand.")
+	serverCmd.PersistentFlags().String("cluster-on-demand-version", "latest", "The Kubernetes version of clusters created on demand. Use 'latest' or versions such as '1.16.10'.")
+	serverCmd.PersistentFlags().String("cluster-on-demand-kops-ami", "", "The AMI to use for hosts of clusters created on demand. Leave empty for the default kops image.")
+	serverCmd.PersistentFlags().String("cluster-on-demand-zones", "us-east-1a", "The zones where clusters created on demand will be deployed. Use commas to separate multiple zones.")
+	serverCmd.PersistentFlags().StringToString("cluster-on-demand-utility-versions", map[string]string{}, "The utility versions of clusters created on demand. Accepts format: UTILITY=VERSION, for example: 'nginx=2.15.0,teleport=0.3.0'. Unset utilities use the default version.")
 	serverCmd.PersistentFlags().Bool("use-existing-aws-resources", true, "Whether to use existing AWS resources (VPCs, subnets, etc.) or not.")
 	serverCmd.PersistentFlags().Bool("keep-database-data", true, "Whether to preserve database data after installation deletion or not.")
 	serverCmd.PersistentFlags().Bool("keep-filestore-data", true, "Whether to preserve filestore data after installation deletion or not.")
@@ -113,15 +143,58 @@ var serverCmd = &cobra.Command{
 		if clusterResourceThresholdScaleValue < 0 || clusterResourceThresholdScaleValue > 10 {
 			return errors.Errorf("cluster-resource-threshold-scale-value (%d) must be set between 0 and 10", clusterResourceThresholdScaleValue)
 		}
+		placementStrategy, _ := command.Flags().GetString("placement-strategy")
+		if !model.IsSupportedPlacementStrategy(placementStrategy) {
+			return errors.Errorf("placement-strategy (%s) is not supported", placementStrategy)
+		}
+
+		clusterTemplate, err := onDemandClusterTemplate(command)
+		if err != nil {
+			return errors.Wrap(err, "invalid on-demand cluster configuration")
+		}
+
+		idempotencyKeyRetention, _ := command.Flags().GetDuration("idempotency-key-retention")
+		if idempotencyKeyRetention <= 0 {
+			return errors.Errorf("idempotency-key-retention (%s) must be positive", idempotencyKeyRetention)
+		}
+		idempotencyKeyLease, _ := command.Flags().GetDuration("idempotency-key-lease")
+		if idempotencyKeyLease <= 0 {
+			return errors.Errorf("idempotency-key-lease (%s) must be positive", idempotencyKeyLease)
+		}
+
+		auditRetention, _ := command.Flags().GetDuration("audit-retention")
+		if auditRetention <= 0 {
+			return errors.Errorf("audit-retention (%s) must be positive", auditRetention)
+		}
+
+		webhookDeliveryRetention, _ := command.Flags().GetDuration("webhook-delivery-retention")
+		if webhookDeliveryRetention <= 0 {
+			return errors.Errorf("webhook-delivery-retention (%s) must be positive", webhookDeliveryRetention)
+		}
 
 		clusterSupervisor, _ := command.Flags().GetBool("cluster-supervisor")
 		groupSupervisor, _ := command.Flags().GetBool("group-supervisor")
 		installationSupervisor, _ := command.Flags().GetBool("installation-supervisor")
 		clusterInstallationSupervisor, _ := command.Flags().GetBool("cluster-installation-supervisor")
-		if !clusterSupervisor && !installationSupervisor && !clusterInstallationSupervisor && !groupSupervisor {
+		webhookDeliverySupervisor, _ := command.Flags().GetBool("webhook-delivery-supervisor")
+		bulkOperationSupervisor, _ := command.Flags().GetBool("bulk-operation-supervisor")
+		hibernationScheduleSupervisor, _ := command.Flags().GetBool("hibernation-schedule-supervisor")
+		idleHibernationSupervisor, _ := command.Flags().GetBool("idle-hibernation-supervisor")
+		backupSupervisor, _ := command.Flags().GetBool("backup-supervisor")
+		if !clusterSupervisor && !installationSupervisor && !clusterInstallationSupervisor && !groupSupervisor && !webhookDeliverySupervisor && !bulkOperationSupervisor && !hibernationScheduleSupervisor && !idleHibernationSupervisor && !backupSupervisor {
 			logger.Warn("Server will be running with no supervisors. Only API functionality will work.")
 		}
 
+		idleHibernationPeriod, _ := command.Flags().GetDuration("idle-hibernation-period")
+		if idleHibernationPeriod <= 0 {
+			return errors.Errorf("idle-hibernation-period (%s) must be positive", idleHibernationPeriod)
+		}
+		idleHibernationCheckInterval, _ := command.Flags().GetDuration("idle-hibernation-check-interval")
+		idleHibernationMaxRequestsPerHour, _ := command.Flags().GetInt64("idle-hibernation-max-requests-per-hour")
+		if idleHibernationMaxRequestsPerHour < 0 {
+			return errors.Errorf("idle-hibernation-max-requests-per-hour (%d) must not be negative", idleHibernationMaxRequestsPerHour)
+		}
+
 		s3StateStore, _ := command.Flags().GetString("state-store")
 		keepDatabaseData, _ := command.Flags().GetBool("keep-database-data")
 		keepFilestoreData, _ := command.Flags().GetBool("keep-filestore-data")
@@ -148,11 +221,21 @@ var serverCmd = &cobra.Command{
 			"group-supervisor":                       groupSupervisor,
 			"installation-supervisor":                installationSupervisor,
 			"cluster-installation-supervisor":        clusterInstallationSupervisor,
+			"webhook-delivery-supervisor":            webhookDeliverySupervisor,
+			"webhook-delivery-retention":             webhookDeliveryRetention,
+			"bulk-operation-supervisor":              bulkOperationSupervisor,
+			"hibernation-schedule-supervisor":        hibernationScheduleSupervisor,
+			"idle-hibernation-supervisor":            idleHibernationSupervisor,
+			"idle-hibernation-period":                idleHibernationPeriod,
+			"idle-hibernation-max-requests-per-hour": idleHibernationMaxRequestsPerHour,
+			"backup-supervisor":                      backupSupervisor,
 			"store-version":                          currentVersion,
 			"state-store":                            s3StateStore,
 			"working-directory":                      wd,
 			"cluster-resource-threshold":             clusterResourceThreshold,
 			"cluster-resource-threshold-scale-value": clusterResourceThresholdScaleValue,
+			"placement-strategy":                     placementStrategy,
+			"cluster-on-demand":                      clusterTemplate != nil,
 			"use-existing-aws-resources":             useExistingResources,
 			"keep-database-data":                     keepDatabaseData,
 			"keep-filestore-data":                    keepFilestoreData,
@@ -162,6 +245,19 @@ var serverCmd = &cobra.Command{
 
 		deprecationWarnings(logger, command)
 
+		tracingOTLPEndpoint, _ := command.Flags().GetString("tracing-otlp-endpoint")
+		tracingOTLPInsecure, _ := command.Flags().GetBool("tracing-otlp-insecure")
+		tracingFile, _ := command.Flags().GetString("tracing-file")
+		shutdownTracing, err := tracing.Setup(tracing.Config{
+			OTLPEndpoint: tracingOTLPEndpoint,
+			OTLPInsecure: tracingOTLPInsecure,
+			File:         tracingFile,
+		}, instanceID, logger)
+		if err != nil {
+			return errors.Wrap(err, "failed to set up tracing")
+		}
+		defer shutdownTracing()
+
 		// Warn on settings we consider to be non-production.
 		if !useExistingResources {
 			logger.Warn("[DEV] Server is configured to not use cluster VPC claim functionality")
@@ -188,6 +284,7 @@ var serverCmd = &cobra.Command{
 		}
 
 		resourceUtil := utils.NewResourceUtil(instanceID, awsClient)
+		backupOperator := toolsAWS.NewBackupOperator(awsClient)
 
 		// Setup the provisioner for actually effecting changes to clusters.
 		kopsProvisioner := provisioner.NewKopsProvisioner(
@@ -202,16 +299,36 @@ var serverCmd = &cobra.Command{
 
 		var multiDoer supervisor.MultiDoer
 		if clusterSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewClusterSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.ClusterSupervisorName, supervisor.NewClusterSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, logger)))
 		}
 		if groupSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewGroupSupervisor(sqlStore, instanceID, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.GroupSupervisorName, supervisor.NewGroupSupervisor(sqlStore, instanceID, logger)))
 		}
 		if installationSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewInstallationSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, clusterResourceThreshold, clusterResourceThresholdScaleValue, keepDatabaseData, keepFilestoreData, resourceUtil, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.InstallationSupervisorName, supervisor.NewInstallationSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, supervisor.InstallationSchedulingConfig{
+				ClusterResourceThreshold:           clusterResourceThreshold,
+				ClusterResourceThresholdScaleValue: clusterResourceThresholdScaleValue,
+				PlacementStrategy:                  placementStrategy,
+				ClusterTemplate:                    clusterTemplate,
+			}, keepDatabaseData, keepFilestoreData, resourceUtil, backupOperator, logger)))
 		}
 		if clusterInstallationSupervisor {
-			multiDoer = append(multiDoer, supervisor.NewClusterInstallationSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, logger))
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.ClusterInstallationSupervisorName, supervisor.NewClusterInstallationSupervisor(sqlStore, kopsProvisioner, awsClient, instanceID, logger)))
+		}
+		if webhookDeliverySupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.WebhookDeliverySupervisorName, supervisor.NewWebhookDeliverySupervisor(sqlStore, instanceID, webhookDeliveryRetention, logger)))
+		}
+		if bulkOperationSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.BulkOperationSupervisorName, supervisor.NewBulkOperationSupervisor(sqlStore, instanceID, logger)))
+		}
+		if hibernationScheduleSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.HibernationScheduleSupervisorName, supervisor.NewHibernationScheduleSupervisor(sqlStore, instanceID, logger)))
+		}
+		if idleHibernationSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.IdleHibernationSupervisorName, supervisor.NewIdleHibernationSupervisor(sqlStore, kopsProvisioner, instanceID, idleHibernationPeriod, idleHibernationCheckInterval, idleHibernationMaxRequestsPerHour, logger)))
+		}
+		if backupSupervisor {
+			multiDoer = append(multiDoer, supervisor.NewInstrumentedDoer(supervisor.BackupSupervisorName, supervisor.NewBackupSupervisor(sqlStore, backupOperator, instanceID, logger)))
 		}
 
 		// Setup the supervisor to effect any requested changes. It is wrapped in a
@@ -225,15 +342,51 @@ var serverCmd = &cobra.Command{
 		supervisor := supervisor.NewScheduler(multiDoer, time.Duration(poll)*time.Second)
 		defer supervisor.Close()
 
+		requireAPIKey, _ := command.Flags().GetBool("require-api-key")
+		if !requireAPIKey {
+			logger.Warn("API keys are not required; the API is open to anyone who can reach it")
+		}
+
 		router := mux.NewRouter()
 
 		api.Register(router, &api.Context{
-			Store:       sqlStore,
-			Supervisor:  supervisor,
-			Provisioner: kopsProvisioner,
-			Logger:      logger,
+			Store:                   sqlStore,
+			Supervisor:              supervisor,
+			Provisioner:             kopsProvisioner,
+			EventBroker:             events.DefaultBroker,
+			RequireAPIKey:           requireAPIKey,
+			IdempotencyKeyRetention: idempotencyKeyRetention,
+			IdempotencyKeyLease:     idempotencyKeyLease,
+			AuditRetention:          auditRetention,
+			Logger:                  logger,
 		})
 
+		prometheus.MustRegister(metrics.NewResourceStateCollector(sqlStore, logger))
+
+		// Metrics are served on a separate internal listener, outside of the
+		// API key authentication.
+		metricsRouter := mux.NewRouter()
+		metricsRouter.Handle("/metrics", promhttp.Handler())
+
+		metricsListen, _ := command.Flags().GetString("metrics-listen")
+		metricsSrv := &http.Server{
+			Addr:           metricsListen,
+			Handler:        metricsRouter,
+			ReadTimeout:    30 * time.Second,
+			WriteTimeout:   30 * time.Second,
+			IdleTimeout:    time.Second * 180,
+			MaxHeaderBytes: 1 << 20,
+			ErrorLog:       log.New(&logrusWriter{logger}, "", 0),
+		}
+
+		go func() {
+			logger.WithField("addr", metricsSrv.Addr).Info("Serving metrics")
+			err := metricsSrv.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				logger.WithError(err).Error("Failed to serve metrics")
+			}
+		}()
+
 		listen, _ := command.Flags().GetString("listen")
 		srv := &http.Server{
 			Addr:           listen,
@@ -272,11 +425,49 @@ var serverCmd = &cobra.Command{
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 		defer cancel()
 		srv.Shutdown(ctx)
+		metricsSrv.Shutdown(ctx)
 
 		return nil
 	},
 }
 
+// onDemandClusterTemplate builds the request used as a template for clusters
+// created on demand. A nil template is returned if on-demand cluster creation
+// is disabled.
+func onDemandClusterTemplate(command *cobra.Command) (*model.CreateClusterRequest, error) {
+	enabled, _ := command.Flags().GetBool("cluster-on-demand")
+	if !enabled {
+		return nil, nil
+	}
+
+	version, _ := command.Flags().GetString("cluster-on-demand-version")
+	kopsAMI, _ := command.Flags().GetString("cluster-on-demand-kops-ami")
+	zones, _ := command.Flags().GetString("cluster-on-demand-zones")
+	utilityVersions, _ := command.Flags().GetStringToString("cluster-on-demand-utility-versions")
+
+	template := &model.CreateClusterRequest{
+		Version:                version,
+		KopsAMI:                kopsAMI,
+		Zones:                  strings.Split(zones, ","),
+		AllowInstallations:     true,
+		DesiredUtilityVersions: utilityVersions,
+	}
+
+	size, _ := command.Flags().GetString("cluster-on-demand-size")
+	err := clusterdictionary.ApplyToCreateClusterRequest(size, template)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply size values")
+	}
+
+	template.SetDefaults()
+	err = template.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
 func checkRequirements(awsConfig *sdkAWS.Config, s3StateStore string) error {
 	utilities := []string{
 		"terraform",
@@ -9,8 +9,20 @@ import (
 
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
 )
 
+// createClient creates a client to the provisioning server at the given
+// address, authenticating with the API key given by the --api-key flag if any.
+func createClient(command *cobra.Command, serverAddress string) *model.Client {
+	apiKey, _ := command.Flags().GetString("api-key")
+	if apiKey == "" {
+		return model.NewClient(serverAddress)
+	}
+
+	return model.NewClientWithAPIKey(serverAddress, apiKey)
+}
+
 func parseEnvVarInput(rawInput []string, clear bool) (model.EnvVarMap, error) {
 	if len(rawInput) != 0 && clear {
 		return nil, errors.New("both mattermost-env and mattermost-env-clear were set; use one or the other")
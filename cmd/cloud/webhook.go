@@ -6,6 +6,7 @@ package main
 
 import (
 	"os"
+	"strconv"
 
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/olekukonko/tablewriter"
@@ -18,6 +19,10 @@ func init() {
 
 	webhookCreateCmd.Flags().String("owner", "", "An opaque identifier describing the owner of the webhook.")
 	webhookCreateCmd.Flags().String("url", "", "The callback URL of the webhook.")
+	webhookCreateCmd.Flags().String("secret", "", "An optional secret used to sign the payloads sent to the webhook.")
+	webhookCreateCmd.Flags().StringSlice("event-type", []string{}, "Only send events for the given resource types (cluster, installation, cluster_installaton). Accepts multiple values.")
+	webhookCreateCmd.Flags().StringSlice("event-state", []string{}, "Only send events whose new state matches one of the given patterns, e.g. 'creation-*'. Accepts multiple values.")
+	webhookCreateCmd.Flags().String("event-owner", "", "Only send events for resources owned by the given owner.")
 	webhookCreateCmd.MarkFlagRequired("owner")
 	webhookCreateCmd.MarkFlagRequired("url")
 
@@ -30,6 +35,16 @@ func init() {
 	webhookListCmd.Flags().Bool("include-deleted", false, "Whether to include deleted webhooks.")
 	webhookListCmd.Flags().Bool("table", false, "Whether to display the returned webhook list in a table or not")
 
+	webhookDeliveriesCmd.Flags().String("webhook", "", "The id of the webhook whose deliveries are to be fetched.")
+	webhookDeliveriesCmd.Flags().String("state", "", "The delivery state by which to filter deliveries.")
+	webhookDeliveriesCmd.Flags().Int("page", 0, "The page of deliveries to fetch, starting at 0.")
+	webhookDeliveriesCmd.Flags().Int("per-page", 100, "The number of deliveries to fetch per page.")
+	webhookDeliveriesCmd.Flags().Bool("table", false, "Whether to display the returned delivery list in a table or not")
+	webhookDeliveriesCmd.MarkFlagRequired("webhook")
+
+	webhookReplayCmd.Flags().String("webhook", "", "The id of the webhook whose failed deliveries are to be replayed.")
+	webhookReplayCmd.MarkFlagRequired("webhook")
+
 	webhookDeleteCmd.Flags().String("webhook", "", "The id of the webhook to be deleted.")
 	webhookDeleteCmd.MarkFlagRequired("webhook")
 
@@ -37,6 +52,8 @@ func init() {
 	webhookCmd.AddCommand(webhookGetCmd)
 	webhookCmd.AddCommand(webhookListCmd)
 	webhookCmd.AddCommand(webhookDeleteCmd)
+	webhookCmd.AddCommand(webhookDeliveriesCmd)
+	webhookCmd.AddCommand(webhookReplayCmd)
 }
 
 var webhookCmd = &cobra.Command{
@@ -51,14 +68,22 @@ var webhookCreateCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		ownerID, _ := command.Flags().GetString("owner")
 		url, _ := command.Flags().GetString("url")
+		secret, _ := command.Flags().GetString("secret")
+		eventTypes, _ := command.Flags().GetStringSlice("event-type")
+		eventStates, _ := command.Flags().GetStringSlice("event-state")
+		eventOwnerID, _ := command.Flags().GetString("event-owner")
 
 		webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
-			OwnerID: ownerID,
-			URL:     url,
+			OwnerID:      ownerID,
+			URL:          url,
+			Secret:       secret,
+			EventTypes:   eventTypes,
+			EventStates:  eventStates,
+			EventOwnerID: eventOwnerID,
 		})
 		if err != nil {
 			return errors.Wrap(err, "failed to create webhook")
@@ -80,7 +105,7 @@ var webhookGetCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		webhookID, _ := command.Flags().GetString("webhook")
 		webhook, err := client.GetWebhook(webhookID)
@@ -107,7 +132,7 @@ var webhookListCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		owner, _ := command.Flags().GetString("owner")
 		page, _ := command.Flags().GetInt("page")
@@ -146,6 +171,76 @@ var webhookListCmd = &cobra.Command{
 	},
 }
 
+var webhookDeliveriesCmd = &cobra.Command{
+	Use:   "deliveries",
+	Short: "List deliveries to a webhook.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		webhookID, _ := command.Flags().GetString("webhook")
+		state, _ := command.Flags().GetString("state")
+		page, _ := command.Flags().GetInt("page")
+		perPage, _ := command.Flags().GetInt("per-page")
+		deliveries, err := client.GetWebhookDeliveries(webhookID, &model.GetWebhookDeliveriesRequest{
+			State:   state,
+			Page:    page,
+			PerPage: perPage,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to query webhook deliveries")
+		}
+
+		outputToTable, _ := command.Flags().GetBool("table")
+		if outputToTable {
+			table := tablewriter.NewWriter(os.Stdout)
+			table.SetAlignment(tablewriter.ALIGN_LEFT)
+			table.SetHeader([]string{"ID", "STATE", "ATTEMPTS", "RESPONSE CODE", "LAST ERROR"})
+
+			for _, delivery := range deliveries {
+				table.Append([]string{delivery.ID, delivery.State, strconv.Itoa(delivery.Attempts), strconv.Itoa(delivery.ResponseCode), delivery.LastError})
+			}
+			table.Render()
+
+			return nil
+		}
+
+		err = printJSON(deliveries)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
+var webhookReplayCmd = &cobra.Command{
+	Use:   "replay",
+	Short: "Replay failed deliveries to a webhook.",
+	RunE: func(command *cobra.Command, args []string) error {
+		command.SilenceUsage = true
+
+		serverAddress, _ := command.Flags().GetString("server")
+		client := createClient(command, serverAddress)
+
+		webhookID, _ := command.Flags().GetString("webhook")
+
+		deliveries, err := client.ReplayWebhookDeliveries(webhookID)
+		if err != nil {
+			return errors.Wrap(err, "failed to replay webhook deliveries")
+		}
+
+		err = printJSON(deliveries)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	},
+}
+
 var webhookDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a webhook.",
@@ -153,7 +248,7 @@ var webhookDeleteCmd = &cobra.Command{
 		command.SilenceUsage = true
 
 		serverAddress, _ := command.Flags().GetString("server")
-		client := model.NewClient(serverAddress)
+		client := createClient(command, serverAddress)
 
 		webhookID, _ := command.Flags().GetString("webhook")
 
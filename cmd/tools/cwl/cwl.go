@@ -5,6 +5,8 @@
 package main
 
 import (
+	"bytes"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
@@ -17,10 +19,33 @@ const (
 	DefaultPort = "8065"
 	// ListenPortEnv is the env var name for overriding the default listen port.
 	ListenPortEnv = "CWL_PORT"
+	// SecretEnv is the env var name for the webhook secret. When set, payloads
+	// with a missing or invalid signature are rejected.
+	SecretEnv = "CWL_SECRET"
 )
 
+var secret string
+
 func handler(w http.ResponseWriter, r *http.Request) {
-	webhook, err := cloud.WebhookPayloadFromReader(r.Body)
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error: failed to read webhook: %s", err)
+		return
+	}
+
+	if len(secret) != 0 {
+		err = cloud.VerifyWebhookSignature(secret, body,
+			r.Header.Get(cloud.WebhookTimestampHeader),
+			r.Header.Get(cloud.WebhookSignatureHeader),
+		)
+		if err != nil {
+			log.Printf("Error: failed to verify webhook: %s", err)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	webhook, err := cloud.WebhookPayloadFromReader(bytes.NewReader(body))
 	if err != nil {
 		log.Printf("Error: failed to parse webhook: %s", err)
 		return
@@ -49,6 +74,7 @@ func main() {
 	if len(os.Getenv(ListenPortEnv)) != 0 {
 		port = os.Getenv(ListenPortEnv)
 	}
+	secret = os.Getenv(SecretEnv)
 
 	log.Printf("Starting cloud webhook listener on port %s", port)
 
@@ -17,4 +17,9 @@ func Register(rootRouter *mux.Router, context *Context) {
 	initWebhook(apiRouter, context)
 	initDatabases(apiRouter, context)
 	initSecurity(apiRouter, context)
+	initEvents(apiRouter, context)
+	initAudit(apiRouter, context)
+	initBulkOperation(apiRouter, context)
+	initBackup(apiRouter, context)
+	initOpenAPI(apiRouter, context)
 }
@@ -0,0 +1,357 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// DefaultAuditRetention is how long audit records are kept when not
+// configured otherwise.
+const DefaultAuditRetention = 90 * 24 * time.Hour
+
+const (
+	// auditMaxRequestSize is the maximum size of a request body read for the
+	// audit log. Larger bodies are passed on to the handler unread and are not
+	// recorded.
+	auditMaxRequestSize = 1024 * 1024
+	// auditMaxBodySize is the maximum size of a sanitized request body kept
+	// in an audit record.
+	auditMaxBodySize = 64 * 1024
+	// auditMaxResponseSize is the maximum size of a response body inspected
+	// for the ID of a created resource.
+	auditMaxResponseSize = 64 * 1024
+	// auditRedacted replaces secrets in audited request bodies.
+	auditRedacted = "[redacted]"
+)
+
+// auditSensitiveFields are substrings of request fields and command line
+// flags whose values are redacted from audited request bodies.
+var auditSensitiveFields = []string{"secret", "password", "license", "token"}
+
+// auditResourceTypes maps route variables to the type of resource they identify.
+var auditResourceTypes = map[string]string{
+	"cluster":              model.TypeCluster,
+	"installation":         model.TypeInstallation,
+	"cluster_installation": model.TypeClusterInstallation,
+	"group":                model.TypeGroup,
+	"webhook":              model.TypeWebhook,
+	"bulk_operation":       model.TypeBulkOperation,
+}
+
+// initAudit registers audit endpoints on the given router.
+func initAudit(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	auditRouter := apiRouter.PathPrefix("/audit").Subrouter()
+	auditRouter.Handle("", addContext(handleGetAuditRecords)).Methods("GET")
+}
+
+// handleGetAuditRecords responds to GET /api/audit, returning the specified
+// page of audit records.
+func handleGetAuditRecords(c *Context, w http.ResponseWriter, r *http.Request) {
+	page, perPage, _, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	startTime, err := parseInt64(r.URL, "start_time", 0)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse start time")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	endTime, err := parseInt64(r.URL, "end_time", 0)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse end time")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	records, err := c.Store.GetAuditRecords(&model.AuditFilter{
+		ResourceType: r.URL.Query().Get("resource_type"),
+		ResourceID:   r.URL.Query().Get("resource_id"),
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Page:         page,
+		PerPage:      perPage,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query audit records")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if records == nil {
+		records = []*model.AuditRecord{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, records)
+}
+
+// isAuditedRequest returns whether the given request modifies resources and
+// must thus be recorded in the audit log.
+func isAuditedRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// auditResponseWriter records the status code and the beginning of the body
+// of a response for the audit log.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	if remaining := auditMaxResponseSize - w.body.Len(); remaining > 0 {
+		if len(b) < remaining {
+			remaining = len(b)
+		}
+		w.body.Write(b[:remaining])
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// auditor records a mutating API request once it has been served.
+type auditor struct {
+	writer           *auditResponseWriter
+	requestBody      []byte
+	requestTruncated bool
+}
+
+// auditRequestBody replays the beginning of a request body read for the
+// audit log before the rest of it.
+type auditRequestBody struct {
+	io.Reader
+	io.Closer
+}
+
+// newAuditor reads up to auditMaxRequestSize bytes of the body of the given
+// request, leaving it in place for the handler, and wraps the given response
+// writer to record the response.
+func newAuditor(c *Context, w http.ResponseWriter, r *http.Request) *auditor {
+	auditor := &auditor{
+		writer: &auditResponseWriter{ResponseWriter: w},
+	}
+	if r.Body == nil {
+		return auditor
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, auditMaxRequestSize+1))
+	if err != nil {
+		c.Logger.WithError(err).Warn("failed to read request body for audit")
+	}
+	r.Body = &auditRequestBody{
+		Reader: io.MultiReader(bytes.NewReader(body), r.Body),
+		Closer: r.Body,
+	}
+
+	if len(body) > auditMaxRequestSize {
+		auditor.requestTruncated = true
+	} else {
+		auditor.requestBody = body
+	}
+
+	return auditor
+}
+
+// record writes the audit record of the served request and removes the
+// records older than the audit retention. Failures are only logged, since the
+// request has already been served.
+func (a *auditor) record(c *Context, r *http.Request) {
+	statusCode := a.writer.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+
+	record := &model.AuditRecord{
+		RequestID:   c.RequestID,
+		RemoteAddr:  r.RemoteAddr,
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		RequestBody: sanitizeRequestBody(a.requestBody),
+		StatusCode:  statusCode,
+	}
+	if a.requestTruncated {
+		record.RequestBody = "[request body too large]"
+	}
+	if c.APIKey != nil {
+		record.APIKeyID = c.APIKey.ID
+	}
+	record.ResourceType, record.ResourceID = auditResource(r, statusCode, a.writer.body.Bytes())
+
+	err := c.Store.CreateAuditRecord(record)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create audit record")
+	}
+
+	retention := c.AuditRetention
+	if retention == 0 {
+		retention = DefaultAuditRetention
+	}
+	expiredBefore := time.Now().Add(-retention).UnixNano() / int64(time.Millisecond)
+	err = c.Store.DeleteAuditRecordsCreatedBefore(expiredBefore)
+	if err != nil {
+		c.Logger.WithError(err).Warn("failed to delete expired audit records")
+	}
+}
+
+// auditResource returns the type and ID of the resource targeted by the given
+// request. The ID of created resources is taken from the response.
+func auditResource(r *http.Request, statusCode int, responseBody []byte) (string, string) {
+	name := strings.TrimSuffix(requestResource(r), "s")
+	resourceType, ok := auditResourceTypes[name]
+	if !ok {
+		return "", ""
+	}
+
+	if resourceID := mux.Vars(r)[name]; resourceID != "" {
+		return resourceType, resourceID
+	}
+
+	if statusCode >= http.StatusBadRequest {
+		return resourceType, ""
+	}
+	var created struct {
+		ID string
+	}
+	_ = json.Unmarshal(responseBody, &created)
+
+	return resourceType, created.ID
+}
+
+// sanitizeRequestBody returns the given JSON request body with secrets, such
+// as passwords, licenses and environment variable values, redacted.
+func sanitizeRequestBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	err := json.Unmarshal(body, &value)
+	if err != nil {
+		return "[unparseable request body]"
+	}
+
+	sanitized, err := json.Marshal(sanitizeValue(value))
+	if err != nil {
+		return "[unparseable request body]"
+	}
+	if len(sanitized) > auditMaxBodySize {
+		return string(sanitized[:auditMaxBodySize]) + "...[truncated]"
+	}
+
+	return string(sanitized)
+}
+
+func sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, fieldValue := range v {
+			if isSensitiveField(key) {
+				v[key] = auditRedacted
+			} else if strings.EqualFold(key, "MattermostEnv") {
+				v[key] = sanitizeEnv(fieldValue)
+			} else {
+				v[key] = sanitizeValue(fieldValue)
+			}
+		}
+		return v
+	case []interface{}:
+		// Arrays of strings are command lines, such as those run via the
+		// Mattermost CLI, where secrets follow their flag.
+		redactNext := false
+		for i, element := range v {
+			s, ok := element.(string)
+			if !ok {
+				v[i] = sanitizeValue(element)
+				redactNext = false
+				continue
+			}
+			if redactNext {
+				v[i] = auditRedacted
+				redactNext = false
+				continue
+			}
+			if isSensitiveField(s) {
+				if index := strings.Index(s, "="); index != -1 {
+					v[i] = s[:index+1] + auditRedacted
+				} else {
+					redactNext = true
+				}
+			}
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// sanitizeEnv redacts the values of the given environment variables, keeping
+// their names so that changes remain visible.
+func sanitizeEnv(env interface{}) interface{} {
+	envMap, ok := env.(map[string]interface{})
+	if !ok {
+		return sanitizeValue(env)
+	}
+
+	for name, envVar := range envMap {
+		if envVar == nil {
+			continue
+		}
+		envVarMap, ok := envVar.(map[string]interface{})
+		if !ok {
+			envMap[name] = auditRedacted
+			continue
+		}
+		for key := range envVarMap {
+			if strings.EqualFold(key, "Value") {
+				envVarMap[key] = auditRedacted
+			}
+		}
+	}
+
+	return envMap
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitiveField := range auditSensitiveFields {
+		if strings.Contains(name, sensitiveField) {
+			return true
+		}
+	}
+
+	return false
+}
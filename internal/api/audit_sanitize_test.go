@@ -0,0 +1,33 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeRequestBody(t *testing.T) {
+	var testCases = []struct {
+		description string
+		body        string
+		expected    string
+	}{
+		{"empty", "", ""},
+		{"invalid", "{invalid", "[unparseable request body]"},
+		{"no secrets", `{"OwnerID":"owner","Size":"100users"}`, `{"OwnerID":"owner","Size":"100users"}`},
+		{"secret fields", `{"Secret":"s","License":"l","Nested":{"APIToken":"t"}}`, `{"License":"[redacted]","Nested":{"APIToken":"[redacted]"},"Secret":"[redacted]"}`},
+		{"env values", `{"MattermostEnv":{"KEY1":{"value":"v1"},"KEY2":null}}`, `{"MattermostEnv":{"KEY1":{"value":"[redacted]"},"KEY2":null}}`},
+		{"command line", `["user","create","--password","p1","--email","e"]`, `["user","create","--password","[redacted]","--email","e"]`},
+		{"command line with equals", `["user","create","--password=p1"]`, `["user","create","--password=[redacted]"]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, sanitizeRequestBody([]byte(tc.body)))
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// installationAdminResources are the top-level API resources that may be
+// modified with the installation-admin scope.
+var installationAdminResources = []string{
+	"installation", "installations",
+	"group", "groups",
+	"webhook", "webhooks",
+	"bulk_operation", "bulk_operations",
+}
+
+// ownerBoundResources are the top-level API resources that may be accessed
+// with an API key bound to an owner. Handlers of these resources restrict such
+// keys to the resources of their owner. API security locks are left to
+// operators and are not available to such keys. The API specification holds no
+// resource and is available to every key.
+var ownerBoundResources = []string{
+	"installation", "installations",
+	"cluster_installation", "cluster_installations",
+	"webhook", "webhooks",
+	"events",
+	"openapi.json",
+}
+
+// requestResource returns the top-level API resource targeted by the given
+// request, looking through the security prefix.
+func requestResource(r *http.Request) string {
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/"), "/")
+	resource := segments[0]
+	if resource == "security" && len(segments) > 1 {
+		resource = segments[1]
+	}
+
+	return resource
+}
+
+// requiredScope returns the API key scope required to serve the given request.
+//
+// Reads only require the read-only scope, except for the audit log which
+// requires the cluster-admin scope. Modifying installations, groups,
+// webhooks and their API locks requires the installation-admin scope. Every
+// other modification, including executing commands in cluster installations,
+// requires the cluster-admin scope. Installation clones running a scrub
+// command are further checked by canExecuteCommands.
+func requiredScope(r *http.Request) string {
+	// The audit log reveals the activity of every caller.
+	if requestResource(r) == "audit" {
+		return model.APIKeyScopeClusterAdmin
+	}
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return model.APIKeyScopeReadOnly
+	}
+
+	if containsString(installationAdminResources, requestResource(r)) {
+		return model.APIKeyScopeInstallationAdmin
+	}
+
+	return model.APIKeyScopeClusterAdmin
+}
+
+// authenticate returns the API key sent with the given request, or the
+// status code with which to reject the request.
+func authenticate(c *Context, r *http.Request) (*model.APIKey, int) {
+	header := r.Header.Get(model.APIKeyHeader)
+	if !strings.HasPrefix(header, model.APIKeyHeaderPrefix) {
+		c.Logger.Debug("Request is missing an API key")
+		return nil, http.StatusUnauthorized
+	}
+
+	key := strings.TrimPrefix(header, model.APIKeyHeaderPrefix)
+	apiKey, err := c.Store.GetAPIKeyByHash(model.HashAPIKey(key))
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query API key")
+		return nil, http.StatusInternalServerError
+	}
+	if apiKey == nil || apiKey.IsRevoked() {
+		c.Logger.Debug("Request has an unknown or revoked API key")
+		return nil, http.StatusUnauthorized
+	}
+
+	scope := requiredScope(r)
+	if !apiKey.HasScope(scope) {
+		c.Logger.WithField("apikey", apiKey.ID).Debugf("API key is missing the %s scope", scope)
+		return nil, http.StatusForbidden
+	}
+
+	resource := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/api/"), "/", 2)[0]
+	if apiKey.IsOwnerBound() && !containsString(ownerBoundResources, resource) {
+		c.Logger.WithField("apikey", apiKey.ID).Debug("API key bound to an owner cannot access this resource")
+		return nil, http.StatusForbidden
+	}
+
+	return apiKey, http.StatusOK
+}
+
+// boundOwnerID returns the owner to which the API key authenticating the
+// request is bound, or an empty string if the request is not limited to the
+// resources of a single owner.
+func boundOwnerID(c *Context) string {
+	if c.APIKey == nil {
+		return ""
+	}
+
+	return c.APIKey.OwnerID
+}
+
+// isOwnerAllowed returns whether the request may access resources of the
+// given owner.
+func isOwnerAllowed(c *Context, ownerID string) bool {
+	boundOwnerID := boundOwnerID(c)
+
+	return boundOwnerID == "" || boundOwnerID == ownerID
+}
+
+// ownerFilter returns the owner by which to filter resources listed for the
+// requested owner, or false if the request may not list resources of that
+// owner. Requests limited to a single owner only ever list that owner's
+// resources.
+func ownerFilter(c *Context, owner string) (string, bool) {
+	boundOwnerID := boundOwnerID(c)
+	if boundOwnerID == "" {
+		return owner, true
+	}
+	if owner != "" && owner != boundOwnerID {
+		return "", false
+	}
+
+	return boundOwnerID, true
+}
+
+// checkClusterInstallationOwner returns the status code with which to reject
+// the request if it may not access the given cluster installation, or 0
+// otherwise. Cluster installations of other owners are reported as not found.
+func checkClusterInstallationOwner(c *Context, clusterInstallation *model.ClusterInstallation) int {
+	if boundOwnerID(c) == "" {
+		return 0
+	}
+
+	installation, err := c.Store.GetInstallation(clusterInstallation.InstallationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		return http.StatusInternalServerError
+	}
+	if installation == nil || !isOwnerAllowed(c, installation.OwnerID) {
+		return http.StatusNotFound
+	}
+
+	return 0
+}
+
+// canExecuteCommands returns whether the request may run arbitrary commands in
+// cluster installations. This requires the cluster-admin scope, and is not
+// available to API keys bound to an owner.
+func canExecuteCommands(c *Context) bool {
+	if c.APIKey == nil {
+		return true
+	}
+
+	return c.APIKey.HasScope(model.APIKeyScopeClusterAdmin) && !c.APIKey.IsOwnerBound()
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
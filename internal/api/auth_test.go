@@ -0,0 +1,274 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		RequireAPIKey: true,
+		Logger:        logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	createAPIKey := func(scope string) string {
+		key, err := model.NewAPIKeySecret()
+		require.NoError(t, err)
+		err = sqlStore.CreateAPIKey(&model.APIKey{
+			Name:    scope,
+			KeyHash: model.HashAPIKey(key),
+			Scopes:  []string{scope},
+		})
+		require.NoError(t, err)
+
+		return key
+	}
+
+	readOnlyKey := createAPIKey(model.APIKeyScopeReadOnly)
+	installationAdminKey := createAPIKey(model.APIKeyScopeInstallationAdmin)
+	clusterAdminKey := createAPIKey(model.APIKeyScopeClusterAdmin)
+
+	revokedKey := createAPIKey(model.APIKeyScopeClusterAdmin)
+	apiKey, err := sqlStore.GetAPIKeyByHash(model.HashAPIKey(revokedKey))
+	require.NoError(t, err)
+	err = sqlStore.RevokeAPIKey(apiKey.ID)
+	require.NoError(t, err)
+
+	t.Run("missing API key", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/clusters")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		_, err = model.NewClient(ts.URL).GetClusters(&model.GetClustersRequest{PerPage: model.AllPerPage})
+		require.EqualError(t, err, "failed with status code 401")
+	})
+
+	t.Run("unknown API key", func(t *testing.T) {
+		client := model.NewClientWithAPIKey(ts.URL, "unknown")
+		_, err := client.GetClusters(&model.GetClustersRequest{PerPage: model.AllPerPage})
+		require.EqualError(t, err, "failed with status code 401")
+	})
+
+	t.Run("revoked API key", func(t *testing.T) {
+		client := model.NewClientWithAPIKey(ts.URL, revokedKey)
+		_, err := client.GetClusters(&model.GetClustersRequest{PerPage: model.AllPerPage})
+		require.EqualError(t, err, "failed with status code 401")
+	})
+
+	t.Run("read-only", func(t *testing.T) {
+		client := model.NewClientWithAPIKey(ts.URL, readOnlyKey)
+
+		_, err := client.GetClusters(&model.GetClustersRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+
+		_, err = client.CreateWebhook(&model.CreateWebhookRequest{OwnerID: "owner", URL: "http://example.com"})
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("installation-admin", func(t *testing.T) {
+		client := model.NewClientWithAPIKey(ts.URL, installationAdminKey)
+
+		_, err := client.GetClusters(&model.GetClustersRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+
+		_, err = client.CreateWebhook(&model.CreateWebhookRequest{OwnerID: "owner", URL: "http://example.com"})
+		require.NoError(t, err)
+
+		_, err = client.CreateCluster(&model.CreateClusterRequest{})
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = client.RunMattermostCLICommandOnClusterInstallation(model.NewID(), []string{"version"})
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = client.CloneInstallation(model.NewID(), &model.CloneInstallationRequest{
+			OwnerID:      "owner",
+			DNS:          "clone.example.com",
+			ScrubCommand: []string{"sh", "-c", "./scrub.sh"},
+		})
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("cluster-admin", func(t *testing.T) {
+		client := model.NewClientWithAPIKey(ts.URL, clusterAdminKey)
+
+		_, err := client.GetClusters(&model.GetClustersRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+
+		_, err = client.CreateWebhook(&model.CreateWebhookRequest{OwnerID: "owner", URL: "http://example.com/cluster-admin"})
+		require.NoError(t, err)
+
+		_, err = client.RunMattermostCLICommandOnClusterInstallation(model.NewID(), []string{"version"})
+		require.EqualError(t, err, "failed with status code 404")
+
+		_, err = client.CloneInstallation(model.NewID(), &model.CloneInstallationRequest{
+			OwnerID:      "owner",
+			DNS:          "clone.example.com",
+			ScrubCommand: []string{"sh", "-c", "./scrub.sh"},
+		})
+		require.EqualError(t, err, "failed with status code 404")
+	})
+}
+
+func TestOwnerBoundAPIKey(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:         sqlStore,
+		Supervisor:    &mockSupervisor{},
+		RequireAPIKey: true,
+		Logger:        logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	key, err := model.NewAPIKeySecret()
+	require.NoError(t, err)
+	err = sqlStore.CreateAPIKey(&model.APIKey{
+		Name:    "owner1",
+		KeyHash: model.HashAPIKey(key),
+		Scopes:  []string{model.APIKeyScopeInstallationAdmin},
+		OwnerID: "owner1",
+	})
+	require.NoError(t, err)
+	client := model.NewClientWithAPIKey(ts.URL, key)
+
+	installation1 := &model.Installation{
+		OwnerID: "owner1",
+		DNS:     "owner1.example.com",
+		State:   model.InstallationStateStable,
+	}
+	err = sqlStore.CreateInstallation(installation1, nil)
+	require.NoError(t, err)
+
+	installation2 := &model.Installation{
+		OwnerID: "owner2",
+		DNS:     "owner2.example.com",
+		State:   model.InstallationStateStable,
+	}
+	err = sqlStore.CreateInstallation(installation2, nil)
+	require.NoError(t, err)
+
+	clusterInstallation1 := &model.ClusterInstallation{
+		ClusterID:      model.NewID(),
+		InstallationID: installation1.ID,
+		Namespace:      installation1.ID,
+		State:          model.ClusterInstallationStateStable,
+	}
+	err = sqlStore.CreateClusterInstallation(clusterInstallation1)
+	require.NoError(t, err)
+
+	clusterInstallation2 := &model.ClusterInstallation{
+		ClusterID:      model.NewID(),
+		InstallationID: installation2.ID,
+		Namespace:      installation2.ID,
+		State:          model.ClusterInstallationStateStable,
+	}
+	err = sqlStore.CreateClusterInstallation(clusterInstallation2)
+	require.NoError(t, err)
+
+	t.Run("installations", func(t *testing.T) {
+		installations, err := client.GetInstallations(&model.GetInstallationsRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, installations, 1)
+		require.Equal(t, installation1.ID, installations[0].ID)
+
+		_, err = client.GetInstallations(&model.GetInstallationsRequest{OwnerID: "owner2", PerPage: model.AllPerPage})
+		require.EqualError(t, err, "failed with status code 403")
+
+		installation, err := client.GetInstallation(installation1.ID, nil)
+		require.NoError(t, err)
+		require.NotNil(t, installation)
+
+		installation, err = client.GetInstallation(installation2.ID, nil)
+		require.NoError(t, err)
+		require.Nil(t, installation)
+
+		_, err = client.HibernateInstallation(installation2.ID)
+		require.EqualError(t, err, "failed with status code 404")
+
+		_, err = client.GetInstallationsCount(false)
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = client.CloneInstallation(installation1.ID, &model.CloneInstallationRequest{
+			OwnerID:      "owner1",
+			DNS:          "owner1-clone.example.com",
+			ScrubCommand: []string{"sh", "-c", "./scrub.sh"},
+		})
+		require.EqualError(t, err, "failed with status code 403")
+	})
+
+	t.Run("cluster installations", func(t *testing.T) {
+		clusterInstallations, err := client.GetClusterInstallations(&model.GetClusterInstallationsRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Equal(t, []*model.ClusterInstallation{clusterInstallation1}, clusterInstallations)
+
+		clusterInstallation, err := client.GetClusterInstallation(clusterInstallation1.ID)
+		require.NoError(t, err)
+		require.NotNil(t, clusterInstallation)
+
+		clusterInstallation, err = client.GetClusterInstallation(clusterInstallation2.ID)
+		require.NoError(t, err)
+		require.Nil(t, clusterInstallation)
+	})
+
+	t.Run("webhooks", func(t *testing.T) {
+		webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{OwnerID: "owner1", URL: "http://example.com/owner1"})
+		require.NoError(t, err)
+		require.Equal(t, "owner1", webhook.EventOwnerID)
+
+		_, err = client.CreateWebhook(&model.CreateWebhookRequest{OwnerID: "owner2", URL: "http://example.com/owner2"})
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = client.CreateWebhook(&model.CreateWebhookRequest{OwnerID: "owner1", URL: "http://example.com/owner2", EventOwnerID: "owner2"})
+		require.EqualError(t, err, "failed with status code 403")
+
+		webhook2 := &model.Webhook{OwnerID: "owner2", URL: "http://example.com/other"}
+		err = sqlStore.CreateWebhook(webhook2)
+		require.NoError(t, err)
+
+		webhooks, err := client.GetWebhooks(&model.GetWebhooksRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, webhooks, 1)
+		require.Equal(t, webhook.ID, webhooks[0].ID)
+
+		actualWebhook, err := client.GetWebhook(webhook2.ID)
+		require.NoError(t, err)
+		require.Nil(t, actualWebhook)
+
+		err = client.DeleteWebhook(webhook2.ID)
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("other resources", func(t *testing.T) {
+		_, err := client.GetClusters(&model.GetClustersRequest{PerPage: model.AllPerPage})
+		require.EqualError(t, err, "failed with status code 403")
+
+		_, err = client.GetGroups(&model.GetGroupsRequest{PerPage: model.AllPerPage})
+		require.EqualError(t, err, "failed with status code 403")
+
+		err = client.LockAPIForInstallation(installation1.ID)
+		require.EqualError(t, err, "failed with status code 403")
+	})
+}
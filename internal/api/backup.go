@@ -0,0 +1,269 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initBackup registers installation backup endpoints on the given router.
+func initBackup(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	installationRouter := apiRouter.PathPrefix("/installation/{installation:[A-Za-z0-9]{26}}").Subrouter()
+	installationRouter.Handle("/backups", addContext(handleGetInstallationBackups)).Methods("GET")
+	installationRouter.Handle("/backups", addContext(idempotent(handleCreateInstallationBackup))).Methods("POST")
+	installationRouter.Handle("/backup/{backup:[A-Za-z0-9]{26}}", addContext(handleGetInstallationBackup)).Methods("GET")
+	installationRouter.Handle("/backup/{backup:[A-Za-z0-9]{26}}/restore", addContext(handleRestoreInstallationBackup)).Methods("POST")
+}
+
+// handleCreateInstallationBackup responds to POST
+// /api/installation/{installation}/backups, requesting a backup of the
+// installation database and filestore.
+func handleCreateInstallationBackup(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.WithField("installation", installationID)
+
+	installation, err := c.Store.GetInstallation(installationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if installation == nil || !isOwnerAllowed(c, installation.OwnerID) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if installation.State != model.InstallationStateStable && installation.State != model.InstallationStateHibernating {
+		c.Logger.Warnf("unable to back up installation while in state %s", installation.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = model.ValidateBackupSupport(installation)
+	if err != nil {
+		c.Logger.WithError(err).Warn("unable to back up installation")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	backup, err := requestBackup(c, installation)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create backup")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, backup)
+}
+
+// handleGetInstallationBackups responds to GET
+// /api/installation/{installation}/backups, returning the specified page of
+// backups of the installation, newest first.
+func handleGetInstallationBackups(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.WithField("installation", installationID)
+
+	page, perPage, _, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installation, err := c.Store.GetInstallation(installationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if installation == nil || !isOwnerAllowed(c, installation.OwnerID) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	backups, err := c.Store.GetBackups(&model.BackupFilter{
+		InstallationID: installationID,
+		State:          parseString(r.URL, "state", ""),
+		Page:           page,
+		PerPage:        perPage,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query backups")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if backups == nil {
+		backups = []*model.Backup{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, backups)
+}
+
+// handleGetInstallationBackup responds to GET
+// /api/installation/{installation}/backup/{backup}, returning the backup in
+// question.
+func handleGetInstallationBackup(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	backupID := vars["backup"]
+	c.Logger = c.Logger.WithField("installation", installationID).WithField("backup", backupID)
+
+	backup, status := getInstallationBackup(c, installationID, backupID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, backup)
+}
+
+// handleRestoreInstallationBackup responds to POST
+// /api/installation/{installation}/backup/{backup}/restore, replacing the
+// database and filestore of the hibernating installation with those of the
+// backup.
+func handleRestoreInstallationBackup(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	backupID := vars["backup"]
+	c.Logger = c.Logger.WithField("installation", installationID).WithField("backup", backupID)
+
+	installationDTO, status, unlockOnce := lockInstallation(c, installationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if installationDTO.APISecurityLock {
+		logSecurityLockConflict("installation", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	backup, status := getInstallationBackup(c, installationID, backupID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	if !backup.IsRestorable() {
+		c.Logger.Warnf("unable to restore backup in state %s", backup.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	oldState := installationDTO.State
+	newState := model.InstallationStateRestorationRequested
+
+	if !installationDTO.ValidTransitionState(newState) {
+		c.Logger.Warnf("unable to restore installation while in state %s", installationDTO.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationDTO.State = newState
+	installationDTO.RestoreBackupID = backup.ID
+	installationDTO.RestoreID = model.NewID()
+
+	err := c.Store.UpdateInstallation(installationDTO.Installation)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installationDTO.ID,
+		OwnerID:   installationDTO.OwnerID,
+		NewState:  newState,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installationDTO.DNS, "BackupID": backup.ID},
+	}
+	err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		c.Logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, installationDTO)
+}
+
+// requestBackup creates a backup of the given installation, to be taken by
+// the backup supervisor.
+func requestBackup(c *Context, installation *model.Installation) (*model.Backup, error) {
+	backup := &model.Backup{
+		InstallationID: installation.ID,
+		State:          model.BackupStateRequested,
+	}
+
+	err := c.Store.CreateBackup(backup)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeBackup,
+		ID:        backup.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  model.BackupStateRequested,
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"InstallationID": installation.ID},
+	}
+	err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		c.Logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	return backup, nil
+}
+
+// getInstallationBackup fetches the given backup of the given installation,
+// returning a non-zero status if it does not exist or cannot be accessed.
+func getInstallationBackup(c *Context, installationID, backupID string) (*model.Backup, int) {
+	installation, err := c.Store.GetInstallation(installationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		return nil, http.StatusInternalServerError
+	}
+	if installation == nil || !isOwnerAllowed(c, installation.OwnerID) {
+		return nil, http.StatusNotFound
+	}
+
+	backup, err := c.Store.GetBackup(backupID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query backup")
+		return nil, http.StatusInternalServerError
+	}
+	if backup == nil || backup.InstallationID != installationID {
+		return nil, http.StatusNotFound
+	}
+
+	return backup, 0
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallationBackups(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	createInstallation := func(t *testing.T, database, state string) *model.Installation {
+		installation := &model.Installation{
+			OwnerID:   model.NewID(),
+			DNS:       model.NewID() + ".example.com",
+			Version:   "5.30.0",
+			Database:  database,
+			Filestore: model.InstallationFilestoreAwsS3,
+			State:     state,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		return installation
+	}
+
+	installation := createInstallation(t, model.InstallationDatabaseSingleTenantRDSMySQL, model.InstallationStateStable)
+
+	t.Run("unknown installation", func(t *testing.T) {
+		_, err := client.CreateInstallationBackup(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+
+		_, err = client.GetInstallationBackups(model.NewID(), &model.GetBackupsRequest{PerPage: model.AllPerPage})
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("unsupported database", func(t *testing.T) {
+		unsupported := createInstallation(t, model.InstallationDatabaseMysqlOperator, model.InstallationStateStable)
+
+		_, err := client.CreateInstallationBackup(unsupported.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("installation not stable", func(t *testing.T) {
+		updating := createInstallation(t, model.InstallationDatabaseSingleTenantRDSMySQL, model.InstallationStateUpdateInProgress)
+
+		_, err := client.CreateInstallationBackup(updating.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	ownerWebhook := &model.Webhook{
+		OwnerID:      model.NewID(),
+		URL:          "http://127.0.0.1:1",
+		EventOwnerID: installation.OwnerID,
+	}
+	err := sqlStore.CreateWebhook(ownerWebhook)
+	require.NoError(t, err)
+
+	backup, err := client.CreateInstallationBackup(installation.ID)
+	require.NoError(t, err)
+	require.Equal(t, installation.ID, backup.InstallationID)
+	require.Equal(t, model.BackupStateRequested, backup.State)
+
+	t.Run("webhook for the installation owner", func(t *testing.T) {
+		deliveries, err := sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			WebhookID: ownerWebhook.ID,
+			PerPage:   model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+
+		payload, err := model.WebhookPayloadFromReader(strings.NewReader(deliveries[0].Payload))
+		require.NoError(t, err)
+		require.Equal(t, backup.ID, payload.ID)
+		require.Equal(t, installation.OwnerID, payload.OwnerID)
+	})
+
+	t.Run("get backup", func(t *testing.T) {
+		fetched, err := client.GetInstallationBackup(installation.ID, backup.ID)
+		require.NoError(t, err)
+		require.Equal(t, backup, fetched)
+
+		fetched, err = client.GetInstallationBackup(installation.ID, model.NewID())
+		require.NoError(t, err)
+		require.Nil(t, fetched)
+
+		other := createInstallation(t, model.InstallationDatabaseSingleTenantRDSMySQL, model.InstallationStateStable)
+		fetched, err = client.GetInstallationBackup(other.ID, backup.ID)
+		require.NoError(t, err)
+		require.Nil(t, fetched)
+	})
+
+	t.Run("get backups", func(t *testing.T) {
+		backups, err := client.GetInstallationBackups(installation.ID, &model.GetBackupsRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Backup{backup}, backups)
+
+		backups, err = client.GetInstallationBackups(installation.ID, &model.GetBackupsRequest{State: model.BackupStateSucceeded, PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Empty(t, backups)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		t.Run("backup not restorable", func(t *testing.T) {
+			_, err := client.RestoreInstallationBackup(installation.ID, backup.ID)
+			require.EqualError(t, err, "failed with status code 400")
+		})
+
+		backup.State = model.BackupStateSucceeded
+		err := sqlStore.UpdateBackup(backup)
+		require.NoError(t, err)
+
+		t.Run("installation not hibernating", func(t *testing.T) {
+			_, err := client.RestoreInstallationBackup(installation.ID, backup.ID)
+			require.EqualError(t, err, "failed with status code 400")
+		})
+
+		installation.State = model.InstallationStateHibernating
+		err = sqlStore.UpdateInstallation(installation)
+		require.NoError(t, err)
+
+		t.Run("installation API locked", func(t *testing.T) {
+			err := sqlStore.LockInstallationAPI(installation.ID)
+			require.NoError(t, err)
+			defer sqlStore.UnlockInstallationAPI(installation.ID)
+
+			_, err = client.RestoreInstallationBackup(installation.ID, backup.ID)
+			require.EqualError(t, err, "failed with status code 403")
+		})
+
+		t.Run("unknown backup", func(t *testing.T) {
+			_, err := client.RestoreInstallationBackup(installation.ID, model.NewID())
+			require.EqualError(t, err, "failed with status code 404")
+		})
+
+		installationDTO, err := client.RestoreInstallationBackup(installation.ID, backup.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateRestorationRequested, installationDTO.State)
+		require.Equal(t, backup.ID, installationDTO.RestoreBackupID)
+		require.NotEmpty(t, installationDTO.RestoreID)
+
+		t.Run("same backup restored again", func(t *testing.T) {
+			installation.State = model.InstallationStateHibernating
+			err = sqlStore.UpdateInstallation(installation)
+			require.NoError(t, err)
+
+			restoredAgainDTO, err := client.RestoreInstallationBackup(installation.ID, backup.ID)
+			require.NoError(t, err)
+			require.Equal(t, backup.ID, restoredAgainDTO.RestoreBackupID)
+			require.NotEmpty(t, restoredAgainDTO.RestoreID)
+			require.NotEqual(t, installationDTO.RestoreID, restoredAgainDTO.RestoreID)
+		})
+	})
+}
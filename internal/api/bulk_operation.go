@@ -0,0 +1,135 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// initBulkOperation registers bulk operation endpoints on the given router.
+func initBulkOperation(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	bulkOperationsRouter := apiRouter.PathPrefix("/bulk_operations").Subrouter()
+	bulkOperationsRouter.Handle("", addContext(handleGetBulkOperations)).Methods("GET")
+	bulkOperationsRouter.Handle("", addContext(idempotent(handleCreateBulkOperation))).Methods("POST")
+
+	bulkOperationRouter := apiRouter.PathPrefix("/bulk_operation/{bulk_operation:[A-Za-z0-9]{26}}").Subrouter()
+	bulkOperationRouter.Handle("", addContext(handleGetBulkOperation)).Methods("GET")
+}
+
+// handleCreateBulkOperation responds to POST /api/bulk_operations, scheduling
+// an action against every installation matching the embedded filter.
+func handleCreateBulkOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	createBulkOperationRequest, err := model.NewCreateBulkOperationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if createBulkOperationRequest.Action == model.BulkActionJoinGroup {
+		group, err := c.Store.GetGroup(createBulkOperationRequest.GroupID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query group")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if group == nil || group.IsDeleted() {
+			c.Logger.Warnf("group %s not found", createBulkOperationRequest.GroupID)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	bulkOperation := model.BulkOperation{
+		Action:      createBulkOperationRequest.Action,
+		Filter:      &createBulkOperationRequest.Filter,
+		Patch:       createBulkOperationRequest.Patch,
+		GroupID:     createBulkOperationRequest.GroupID,
+		DryRun:      createBulkOperationRequest.DryRun,
+		Concurrency: createBulkOperationRequest.Concurrency,
+		State:       model.BulkOperationStatePending,
+	}
+
+	err = c.Store.CreateBulkOperation(&bulkOperation)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create bulk operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, bulkOperation)
+}
+
+// handleGetBulkOperation responds to GET /api/bulk_operation/{bulk_operation},
+// returning the bulk operation in question along with its results so far.
+func handleGetBulkOperation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	bulkOperationID := vars["bulk_operation"]
+	c.Logger = c.Logger.WithField("bulk_operation", bulkOperationID)
+
+	bulkOperation, err := c.Store.GetBulkOperation(bulkOperationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query bulk operation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if bulkOperation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	bulkOperation.Results, err = c.Store.GetBulkOperationResults(bulkOperationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query bulk operation results")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, bulkOperation)
+}
+
+// handleGetBulkOperations responds to GET /api/bulk_operations, returning the
+// specified page of bulk operations, newest first.
+func handleGetBulkOperations(c *Context, w http.ResponseWriter, r *http.Request) {
+	page, perPage, _, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	filter := &model.BulkOperationFilter{
+		State:   parseString(r.URL, "state", ""),
+		Page:    page,
+		PerPage: perPage,
+	}
+
+	bulkOperations, err := c.Store.GetBulkOperations(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query bulk operations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if bulkOperations == nil {
+		bulkOperations = []*model.BulkOperation{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, bulkOperations)
+}
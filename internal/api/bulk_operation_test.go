@@ -0,0 +1,131 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	t.Run("unknown bulk operation", func(t *testing.T) {
+		bulkOperation, err := client.GetBulkOperation(model.NewID())
+		require.NoError(t, err)
+		require.Nil(t, bulkOperation)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		resp, err := http.Post(fmt.Sprintf("%s/api/bulk_operations", ts.URL), "application/json", bytes.NewReader([]byte("invalid")))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		for description, request := range map[string]*model.CreateBulkOperationRequest{
+			"unsupported action":   {Action: "restart", All: true},
+			"update without patch": {Action: model.BulkActionUpdate, All: true},
+			"join without group":   {Action: model.BulkActionJoinGroup, All: true},
+			"join unknown group":   {Action: model.BulkActionJoinGroup, GroupID: model.NewID(), All: true},
+			"too concurrent":       {Action: model.BulkActionHibernate, Concurrency: model.MaxBulkOperationConcurrency + 1, All: true},
+			"invalid sort":         {Action: model.BulkActionHibernate, Filter: model.InstallationFilter{SortBy: "license"}, All: true},
+			"no selector":          {Action: model.BulkActionHibernate, Filter: model.InstallationFilter{SortBy: model.SortByCreateAt}},
+		} {
+			t.Run(description, func(t *testing.T) {
+				_, err := client.CreateBulkOperation(request)
+				require.EqualError(t, err, "failed with status code 400")
+			})
+		}
+	})
+
+	var bulkOperation1, bulkOperation2 *model.BulkOperation
+
+	t.Run("create bulk operations", func(t *testing.T) {
+		var err error
+		bulkOperation1, err = client.CreateBulkOperation(&model.CreateBulkOperationRequest{
+			Action: model.BulkActionHibernate,
+			Filter: model.InstallationFilter{
+				OwnerID:     "trial",
+				States:      []string{model.InstallationStateStable},
+				Annotations: []string{"trial"},
+			},
+			DryRun: true,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, bulkOperation1.ID)
+		require.Equal(t, model.BulkOperationStatePending, bulkOperation1.State)
+		require.Equal(t, model.DefaultBulkOperationConcurrency, bulkOperation1.Concurrency)
+		require.True(t, bulkOperation1.DryRun)
+
+		group := &model.Group{Version: "5.31.0"}
+		err = sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+
+		bulkOperation2, err = client.CreateBulkOperation(&model.CreateBulkOperationRequest{
+			Action:      model.BulkActionJoinGroup,
+			GroupID:     group.ID,
+			All:         true,
+			Concurrency: 10,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 10, bulkOperation2.Concurrency)
+	})
+
+	t.Run("get bulk operation with results", func(t *testing.T) {
+		result := &model.BulkOperationResult{
+			BulkOperationID: bulkOperation1.ID,
+			InstallationID:  model.NewID(),
+			OldState:        model.InstallationStateStable,
+			NewState:        model.InstallationStateHibernationRequested,
+			Status:          model.BulkResultPlanned,
+		}
+		err := sqlStore.CreateBulkOperationResult(result)
+		require.NoError(t, err)
+
+		bulkOperation, err := client.GetBulkOperation(bulkOperation1.ID)
+		require.NoError(t, err)
+		require.Equal(t, bulkOperation1.Filter, bulkOperation.Filter)
+		require.Equal(t, []*model.BulkOperationResult{result}, bulkOperation.Results)
+	})
+
+	t.Run("get bulk operations", func(t *testing.T) {
+		bulkOperations, err := client.GetBulkOperations(&model.GetBulkOperationsRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, bulkOperations, 2)
+		require.ElementsMatch(t, []string{bulkOperation1.ID, bulkOperation2.ID}, []string{bulkOperations[0].ID, bulkOperations[1].ID})
+		require.GreaterOrEqual(t, bulkOperations[0].CreateAt, bulkOperations[1].CreateAt)
+
+		bulkOperations, err = client.GetBulkOperations(&model.GetBulkOperationsRequest{
+			State:   model.BulkOperationStateCompleted,
+			PerPage: model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Empty(t, bulkOperations)
+	})
+}
@@ -21,7 +21,7 @@ func initCluster(apiRouter *mux.Router, context *Context) {
 
 	clustersRouter := apiRouter.PathPrefix("/clusters").Subrouter()
 	clustersRouter.Handle("", addContext(handleGetClusters)).Methods("GET")
-	clustersRouter.Handle("", addContext(handleCreateCluster)).Methods("POST")
+	clustersRouter.Handle("", addContext(idempotent(handleCreateCluster))).Methods("POST")
 
 	clusterRouter := apiRouter.PathPrefix("/cluster/{cluster:[A-Za-z0-9]{26}}").Subrouter()
 	clusterRouter.Handle("", addContext(handleGetCluster)).Methods("GET")
@@ -30,7 +30,9 @@ func initCluster(apiRouter *mux.Router, context *Context) {
 	clusterRouter.Handle("/provision", addContext(handleProvisionCluster)).Methods("POST")
 	clusterRouter.Handle("/kubernetes", addContext(handleUpgradeKubernetes)).Methods("PUT")
 	clusterRouter.Handle("/size", addContext(handleResizeCluster)).Methods("PUT")
+	clusterRouter.Handle("/drain", addContext(handleDrainCluster)).Methods("POST")
 	clusterRouter.Handle("/utilities", addContext(handleGetAllUtilityMetadata)).Methods("GET")
+	clusterRouter.Handle("/events", addContext(handleGetClusterEvents)).Methods("GET")
 	clusterRouter.Handle("", addContext(handleDeleteCluster)).Methods("DELETE")
 }
 
@@ -65,10 +67,38 @@ func handleGetClusters(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	createdAfter, createdBefore, err := parseCreatedRange(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse creation time parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	sortBy, sortOrder, err := parseSort(r.URL, model.ClusterSortKeys)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse sort parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	cursor, err := parseCursor(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse cursor")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	filter := &model.ClusterFilter{
 		Page:           page,
 		PerPage:        perPage,
+		Cursor:         cursor,
 		IncludeDeleted: includeDeleted,
+		States:         r.URL.Query()["state"],
+		Annotations:    r.URL.Query()["annotation"],
+		CreatedAfter:   createdAfter,
+		CreatedBefore:  createdBefore,
+		SortBy:         sortBy,
+		SortOrder:      sortOrder,
 	}
 
 	clusters, err := c.Store.GetClusterDTOs(filter)
@@ -81,7 +111,20 @@ func handleGetClusters(c *Context, w http.ResponseWriter, r *http.Request) {
 		clusters = []*model.ClusterDTO{}
 	}
 
+	totalCount, err := c.Store.CountClusters(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to count clusters")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor *model.PageCursor
+	if hasNextPage(perPage, len(clusters)) {
+		nextCursor = model.NewClusterCursor(clusters[len(clusters)-1].Cluster, sortBy)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	setPageHeaders(w, totalCount, nextCursor)
 	w.WriteHeader(http.StatusOK)
 	outputJSON(c, w, clusters)
 }
@@ -488,6 +531,75 @@ func handleResizeCluster(c *Context, w http.ResponseWriter, r *http.Request) {
 	outputJSON(c, w, clusterDTO)
 }
 
+// handleDrainCluster responds to POST /api/cluster/{cluster}/drain,
+// beginning the process of moving all installations off of the cluster.
+func handleDrainCluster(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	c.Logger = c.Logger.WithField("cluster", clusterID)
+
+	drainClusterRequest, err := model.NewDrainClusterRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	clusterDTO, status, unlockOnce := lockCluster(c, clusterID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if clusterDTO.APISecurityLock {
+		logSecurityLockConflict("cluster", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	oldState := clusterDTO.State
+	newState := model.ClusterStateDrainRequested
+
+	if !clusterDTO.ValidTransitionState(newState) {
+		c.Logger.Warnf("unable to drain cluster while in state %s", clusterDTO.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	clusterDTO.State = newState
+	clusterDTO.AllowInstallations = false
+	clusterDTO.DrainMaxConcurrent = drainClusterRequest.MaxConcurrent
+	err = c.Store.UpdateCluster(clusterDTO.Cluster)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if oldState != newState {
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeCluster,
+			ID:        clusterDTO.ID,
+			NewState:  newState,
+			OldState:  oldState,
+			Timestamp: time.Now().UnixNano(),
+		}
+
+		err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+		if err != nil {
+			c.Logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, clusterDTO)
+}
+
 // handleDeleteCluster responds to DELETE /api/cluster/{cluster}, beginning the process of
 // deleting the cluster.
 func handleDeleteCluster(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -576,3 +688,27 @@ func handleGetAllUtilityMetadata(c *Context, w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusOK)
 	outputJSON(c, w, cluster.UtilityMetadata)
 }
+
+// handleGetClusterEvents responds to GET /api/cluster/{cluster}/events,
+// returning the specified page of the cluster's state change history.
+func handleGetClusterEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterID := vars["cluster"]
+	c.Logger = c.Logger.WithField("cluster", clusterID)
+
+	cluster, err := c.Store.GetCluster(clusterID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if cluster == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	getEvents(c, w, r, &model.EventFilter{
+		ResourceType: model.TypeCluster,
+		ResourceID:   clusterID,
+	})
+}
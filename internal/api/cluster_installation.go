@@ -27,6 +27,7 @@ func initClusterInstallation(apiRouter *mux.Router, context *Context) {
 	clusterInstallationRouter.Handle("/config", addContext(handleSetClusterInstallationConfig)).Methods("PUT")
 	clusterInstallationRouter.Handle("/exec/{command}", addContext(handleRunClusterInstallationExecCommand)).Methods("POST")
 	clusterInstallationRouter.Handle("/mattermost_cli", addContext(handleRunClusterInstallationMattermostCLI)).Methods("POST")
+	clusterInstallationRouter.Handle("/events", addContext(handleGetClusterInstallationEvents)).Methods("GET")
 }
 
 // handleGetClusterInstallations responds to GET /api/cluster_installations, returning the specified page of cluster installations.
@@ -41,11 +42,20 @@ func handleGetClusterInstallations(c *Context, w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	cursor, err := parseCursor(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse cursor")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	filter := &model.ClusterInstallationFilter{
 		ClusterID:      clusterID,
 		InstallationID: installationID,
+		OwnerID:        boundOwnerID(c),
 		Page:           page,
 		PerPage:        perPage,
+		Cursor:         cursor,
 		IncludeDeleted: includeDeleted,
 	}
 
@@ -59,7 +69,21 @@ func handleGetClusterInstallations(c *Context, w http.ResponseWriter, r *http.Re
 		clusterInstallations = []*model.ClusterInstallation{}
 	}
 
+	totalCount, err := c.Store.CountClusterInstallations(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to count cluster installations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor *model.PageCursor
+	if hasNextPage(perPage, len(clusterInstallations)) {
+		last := clusterInstallations[len(clusterInstallations)-1]
+		nextCursor = &model.PageCursor{CreateAt: last.CreateAt, ID: last.ID}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	setPageHeaders(w, totalCount, nextCursor)
 	w.WriteHeader(http.StatusOK)
 	outputJSON(c, w, clusterInstallations)
 }
@@ -80,6 +104,10 @@ func handleGetClusterInstallation(c *Context, w http.ResponseWriter, r *http.Req
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	if status := checkClusterInstallationOwner(c, clusterInstallation); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -102,6 +130,10 @@ func handleGetClusterInstallationConfig(c *Context, w http.ResponseWriter, r *ht
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	if status := checkClusterInstallationOwner(c, clusterInstallation); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
 	if clusterInstallation.IsDeleted() {
 		c.Logger.Error("cluster installation is deleted")
 		w.WriteHeader(http.StatusGone)
@@ -154,6 +186,10 @@ func handleSetClusterInstallationConfig(c *Context, w http.ResponseWriter, r *ht
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	if status := checkClusterInstallationOwner(c, clusterInstallation); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
 	if clusterInstallation.IsDeleted() {
 		c.Logger.Error("cluster installation is deleted")
 		w.WriteHeader(http.StatusGone)
@@ -264,6 +300,10 @@ func handleRunClusterInstallationExecCommand(c *Context, w http.ResponseWriter,
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	if status := checkClusterInstallationOwner(c, clusterInstallation); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
 	if clusterInstallation.IsDeleted() {
 		c.Logger.Error("cluster installation is deleted")
 		w.WriteHeader(http.StatusGone)
@@ -326,6 +366,10 @@ func handleRunClusterInstallationMattermostCLI(c *Context, w http.ResponseWriter
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	if status := checkClusterInstallationOwner(c, clusterInstallation); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
 	if clusterInstallation.IsDeleted() {
 		c.Logger.Error("cluster installation is deleted")
 		w.WriteHeader(http.StatusGone)
@@ -361,3 +405,31 @@ func handleRunClusterInstallationMattermostCLI(c *Context, w http.ResponseWriter
 	w.WriteHeader(http.StatusOK)
 	w.Write(output)
 }
+
+// handleGetClusterInstallationEvents responds to GET /api/cluster_installation/{cluster_installation}/events,
+// returning the specified page of the cluster installation's state change history.
+func handleGetClusterInstallationEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	clusterInstallationID := vars["cluster_installation"]
+	c.Logger = c.Logger.WithField("cluster_installation", clusterInstallationID)
+
+	clusterInstallation, err := c.Store.GetClusterInstallation(clusterInstallationID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if clusterInstallation == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if status := checkClusterInstallationOwner(c, clusterInstallation); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	getEvents(c, w, r, &model.EventFilter{
+		ResourceType: model.TypeClusterInstallation,
+		ResourceID:   clusterInstallationID,
+	})
+}
@@ -83,6 +83,18 @@ func TestClusters(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, http.StatusOK, resp.StatusCode)
 		})
+
+		t.Run("invalid created before", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/clusters?created_before=invalid", ts.URL))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("unsupported sort key", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/clusters?sort=dns", ts.URL))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
 	})
 
 	t.Run("clusters", func(t *testing.T) {
@@ -164,6 +176,27 @@ func TestClusters(t *testing.T) {
 			require.Equal(t, []*model.ClusterDTO{cluster3.ToDTO(nil)}, clusters)
 		})
 
+		t.Run("get clusters, filter by annotation", func(t *testing.T) {
+			clusters, err := client.GetClusters(&model.GetClustersRequest{
+				PerPage:     model.AllPerPage,
+				Annotations: []string{"my-annotation"},
+			})
+			require.NoError(t, err)
+			require.Equal(t, []*model.ClusterDTO{cluster1}, clusters)
+		})
+
+		t.Run("get clusters, filter by state and creation time, sorted descending", func(t *testing.T) {
+			clusters, err := client.GetClusters(&model.GetClustersRequest{
+				PerPage:      model.AllPerPage,
+				States:       []string{model.ClusterStateCreationRequested},
+				CreatedAfter: cluster1.CreateAt,
+				SortBy:       model.SortByCreateAt,
+				SortOrder:    model.SortOrderDescending,
+			})
+			require.NoError(t, err)
+			require.Equal(t, []*model.ClusterDTO{cluster3.ToDTO(nil), cluster2.ToDTO(nil)}, clusters)
+		})
+
 		t.Run("delete cluster", func(t *testing.T) {
 			cluster2.State = model.ClusterStateStable
 			err := sqlStore.UpdateCluster(cluster2.Cluster)
@@ -1005,6 +1038,87 @@ func TestResizeCluster(t *testing.T) {
 	})
 }
 
+func TestDrainCluster(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	cluster1, err := client.CreateCluster(&model.CreateClusterRequest{
+		Provider:           model.ProviderAWS,
+		Zones:              []string{"zone"},
+		AllowInstallations: true,
+	})
+	require.NoError(t, err)
+
+	t.Run("unknown cluster", func(t *testing.T) {
+		clusterResp, err := client.DrainCluster(model.NewID(), &model.DrainClusterRequest{})
+		require.EqualError(t, err, "failed with status code 404")
+		assert.Nil(t, clusterResp)
+	})
+
+	t.Run("invalid max concurrent", func(t *testing.T) {
+		clusterResp, err := client.DrainCluster(cluster1.ID, &model.DrainClusterRequest{MaxConcurrent: -1})
+		require.EqualError(t, err, "failed with status code 400")
+		assert.Nil(t, clusterResp)
+	})
+
+	t.Run("while api-security-locked", func(t *testing.T) {
+		err = sqlStore.LockClusterAPI(cluster1.ID)
+		require.NoError(t, err)
+
+		clusterResp, err := client.DrainCluster(cluster1.ID, &model.DrainClusterRequest{})
+		require.EqualError(t, err, "failed with status code 403")
+		assert.Nil(t, clusterResp)
+
+		err = sqlStore.UnlockClusterAPI(cluster1.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("while creating", func(t *testing.T) {
+		clusterResp, err := client.DrainCluster(cluster1.ID, &model.DrainClusterRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+		assert.Nil(t, clusterResp)
+	})
+
+	t.Run("while stable", func(t *testing.T) {
+		cluster1.State = model.ClusterStateStable
+		err = sqlStore.UpdateCluster(cluster1.Cluster)
+		require.NoError(t, err)
+
+		clusterResp, err := client.DrainCluster(cluster1.ID, &model.DrainClusterRequest{MaxConcurrent: 2})
+		require.NoError(t, err)
+		assert.NotNil(t, clusterResp)
+
+		cluster1, err = client.GetCluster(cluster1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterStateDrainRequested, cluster1.State)
+		assert.False(t, cluster1.AllowInstallations)
+		assert.Equal(t, 2, cluster1.DrainMaxConcurrent)
+	})
+
+	t.Run("default max concurrent", func(t *testing.T) {
+		clusterResp, err := client.DrainCluster(cluster1.ID, &model.DrainClusterRequest{})
+		require.NoError(t, err)
+		assert.NotNil(t, clusterResp)
+
+		cluster1, err = client.GetCluster(cluster1.ID)
+		require.NoError(t, err)
+		assert.Equal(t, model.ClusterStateDrainRequested, cluster1.State)
+		assert.Equal(t, model.DefaultClusterDrainMaxConcurrent, cluster1.DrainMaxConcurrent)
+	})
+}
+
 func TestDeleteCluster(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := store.MakeTestSQLStore(t, logger)
@@ -1088,6 +1202,7 @@ func TestDeleteCluster(t *testing.T) {
 		model.ClusterStateProvisioningFailed,
 		model.ClusterStateUpgradeRequested,
 		model.ClusterStateUpgradeFailed,
+		model.ClusterStateDrainFailed,
 		model.ClusterStateDeletionRequested,
 		model.ClusterStateDeletionFailed,
 	}
@@ -5,6 +5,9 @@
 package api
 
 import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/events"
 	"github.com/mattermost/mattermost-cloud/k8s"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/sirupsen/logrus"
@@ -22,6 +25,7 @@ type Store interface {
 	GetClusterDTO(clusterID string) (*model.ClusterDTO, error)
 	GetClusters(filter *model.ClusterFilter) ([]*model.Cluster, error)
 	GetClusterDTOs(filter *model.ClusterFilter) ([]*model.ClusterDTO, error)
+	CountClusters(filter *model.ClusterFilter) (int64, error)
 	UpdateCluster(cluster *model.Cluster) error
 	LockCluster(clusterID, lockerID string) (bool, error)
 	UnlockCluster(clusterID, lockerID string, force bool) (bool, error)
@@ -35,6 +39,7 @@ type Store interface {
 	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
 	GetInstallationDTOs(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.InstallationDTO, error)
 	GetInstallationsCount(includeDeleted bool) (int, error)
+	CountInstallations(filter *model.InstallationFilter) (int64, error)
 	UpdateInstallation(installation *model.Installation) error
 	LockInstallation(installationID, lockerID string) (bool, error)
 	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
@@ -44,12 +49,14 @@ type Store interface {
 
 	GetClusterInstallation(clusterInstallationID string) (*model.ClusterInstallation, error)
 	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+	CountClusterInstallations(filter *model.ClusterInstallationFilter) (int64, error)
 	LockClusterInstallationAPI(clusterInstallationID string) error
 	UnlockClusterInstallationAPI(clusterInstallationID string) error
 
 	CreateGroup(group *model.Group) error
 	GetGroup(groupID string) (*model.Group, error)
 	GetGroups(filter *model.GroupFilter) ([]*model.Group, error)
+	CountGroups(filter *model.GroupFilter) (int64, error)
 	UpdateGroup(group *model.Group) error
 	LockGroup(groupID, lockerID string) (bool, error)
 	UnlockGroup(groupID, lockerID string, force bool) (bool, error)
@@ -61,9 +68,37 @@ type Store interface {
 	CreateWebhook(webhook *model.Webhook) error
 	GetWebhook(webhookID string) (*model.Webhook, error)
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CountWebhooks(filter *model.WebhookFilter) (int64, error)
 	DeleteWebhook(webhookID string) error
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	GetWebhookDeliveries(filter *model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error)
+
+	GetEvents(filter *model.EventFilter) ([]*model.Event, error)
+
+	GetAPIKeyByHash(keyHash string) (*model.APIKey, error)
+
+	CreateAuditRecord(record *model.AuditRecord) error
+	GetAuditRecords(filter *model.AuditFilter) ([]*model.AuditRecord, error)
+	DeleteAuditRecordsCreatedBefore(createAt int64) error
+
+	GetIdempotencyKey(requestKey, apiKeyID string) (*model.IdempotencyKey, error)
+	CreateIdempotencyKey(idempotencyKey *model.IdempotencyKey) error
+	UpdateIdempotencyKey(idempotencyKey *model.IdempotencyKey) error
+	DeleteIdempotencyKey(id string) error
+	DeleteIdempotencyKeysCreatedBefore(createAt int64) error
+	DeleteIncompleteIdempotencyKeysCreatedBefore(createAt int64) error
 
 	GetMultitenantDatabases(filter *model.MultitenantDatabaseFilter) ([]*model.MultitenantDatabase, error)
+
+	CreateBulkOperation(bulkOperation *model.BulkOperation) error
+	GetBulkOperation(bulkOperationID string) (*model.BulkOperation, error)
+	GetBulkOperations(filter *model.BulkOperationFilter) ([]*model.BulkOperation, error)
+	GetBulkOperationResults(bulkOperationID string) ([]*model.BulkOperationResult, error)
+
+	CreateBackup(backup *model.Backup) error
+	GetBackup(backupID string) (*model.Backup, error)
+	GetBackups(filter *model.BackupFilter) ([]*model.Backup, error)
 }
 
 // Provisioner describes the interface required to communicate with the Kubernetes cluster.
@@ -73,6 +108,11 @@ type Provisioner interface {
 	GetClusterResources(*model.Cluster, bool) (*k8s.ClusterResources, error)
 }
 
+// EventBroker describes the interface required to stream events to API clients.
+type EventBroker interface {
+	Subscribe(filter model.EventStreamFilter) *events.Subscription
+}
+
 // Context provides the API with all necessary data and interfaces for responding to requests.
 //
 // It is cloned before each request, allowing per-request changes such as logger annotations.
@@ -80,16 +120,39 @@ type Context struct {
 	Store       Store
 	Supervisor  Supervisor
 	Provisioner Provisioner
-	RequestID   string
-	Logger      logrus.FieldLogger
+	EventBroker EventBroker
+	// RequireAPIKey rejects requests without a valid API key granting the
+	// scope required by the requested route.
+	RequireAPIKey bool
+	// IdempotencyKeyRetention is how long responses to create requests made
+	// with an idempotency key are kept for replay. It defaults to
+	// DefaultIdempotencyKeyRetention.
+	IdempotencyKeyRetention time.Duration
+	// IdempotencyKeyLease is how long a request made with an idempotency key
+	// may go without a recorded response before the key is considered
+	// abandoned and may be sent again. It defaults to
+	// DefaultIdempotencyKeyLease.
+	IdempotencyKeyLease time.Duration
+	// AuditRetention is how long audit records are kept. It defaults to
+	// DefaultAuditRetention.
+	AuditRetention time.Duration
+	// APIKey is the API key that authenticated the request, if any.
+	APIKey    *model.APIKey
+	RequestID string
+	Logger    logrus.FieldLogger
 }
 
 // Clone creates a shallow copy of context, allowing clones to apply per-request changes.
 func (c *Context) Clone() *Context {
 	return &Context{
-		Store:       c.Store,
-		Supervisor:  c.Supervisor,
-		Provisioner: c.Provisioner,
-		Logger:      c.Logger,
+		Store:                   c.Store,
+		Supervisor:              c.Supervisor,
+		Provisioner:             c.Provisioner,
+		EventBroker:             c.EventBroker,
+		RequireAPIKey:           c.RequireAPIKey,
+		IdempotencyKeyRetention: c.IdempotencyKeyRetention,
+		IdempotencyKeyLease:     c.IdempotencyKeyLease,
+		AuditRetention:          c.AuditRetention,
+		Logger:                  c.Logger,
 	}
 }
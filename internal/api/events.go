@@ -0,0 +1,132 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+const (
+	// eventStreamKeepaliveInterval is how often a comment is written to idle
+	// event streams to keep intermediate proxies from closing them.
+	eventStreamKeepaliveInterval = 15 * time.Second
+	// eventStreamMaxDuration is how long an event stream is kept open. Writes
+	// fail once the write timeout of the server is reached, so streams are
+	// closed before then and clients are expected to reconnect.
+	eventStreamMaxDuration = 2 * time.Minute
+)
+
+// initEvents registers event endpoints on the given router.
+func initEvents(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	eventsRouter := apiRouter.PathPrefix("/events").Subrouter()
+	eventsRouter.Handle("/stream", addContext(handleStreamEvents)).Methods("GET")
+}
+
+// handleStreamEvents responds to GET /api/events/stream, streaming state
+// transitions as server-sent events until the client disconnects or
+// eventStreamMaxDuration elapses. Only the transitions made by this server
+// are streamed, not those made by other servers sharing its database.
+func handleStreamEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		c.Logger.Error("response writer does not support streaming")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	filter := model.EventStreamFilter{
+		Type:    r.URL.Query().Get("type"),
+		ID:      r.URL.Query().Get("id"),
+		OwnerID: boundOwnerID(c),
+	}
+	switch filter.Type {
+	case "", model.TypeCluster, model.TypeInstallation, model.TypeClusterInstallation:
+	default:
+		c.Logger.Errorf("invalid event type %s", filter.Type)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	subscription := c.EventBroker.Subscribe(filter)
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepalive := time.NewTicker(eventStreamKeepaliveInterval)
+	defer keepalive.Stop()
+	expiry := time.NewTimer(eventStreamMaxDuration)
+	defer expiry.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-expiry.C:
+			return
+		case <-keepalive.C:
+			_, err := fmt.Fprint(w, ": keepalive\n\n")
+			if err != nil {
+				c.Logger.WithError(err).Debug("failed to write to event stream")
+				return
+			}
+			flusher.Flush()
+		case payload, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(payload)
+			if err != nil {
+				c.Logger.WithError(err).Error("failed to marshal event")
+				continue
+			}
+			_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+			if err != nil {
+				c.Logger.WithError(err).Debug("failed to write to event stream")
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// getEvents responds with the requested page of events matching the given filter.
+func getEvents(c *Context, w http.ResponseWriter, r *http.Request, filter *model.EventFilter) {
+	page, perPage, _, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	filter.Page = page
+	filter.PerPage = perPage
+
+	events, err := c.Store.GetEvents(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query events")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if events == nil {
+		events = []*model.Event{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, events)
+}
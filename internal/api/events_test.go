@@ -0,0 +1,208 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/events"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEvents(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	broker := events.NewBroker()
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:       sqlStore,
+		Supervisor:  &mockSupervisor{},
+		EventBroker: broker,
+		Logger:      logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	t.Run("invalid type", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/events/stream?type=unknown")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("all events", func(t *testing.T) {
+		stream, err := client.StreamEvents(&model.StreamEventsRequest{})
+		require.NoError(t, err)
+		defer stream.Close()
+
+		payload := &model.WebhookPayload{
+			Type:     model.TypeCluster,
+			ID:       model.NewID(),
+			NewState: model.ClusterStateStable,
+			OldState: model.ClusterStateCreationRequested,
+		}
+		broker.Publish(payload)
+
+		received, err := stream.Next()
+		require.NoError(t, err)
+		require.Equal(t, payload, received)
+	})
+
+	t.Run("filtered events", func(t *testing.T) {
+		installationID := model.NewID()
+		stream, err := client.StreamEvents(&model.StreamEventsRequest{
+			Type: model.TypeInstallation,
+			ID:   installationID,
+		})
+		require.NoError(t, err)
+		defer stream.Close()
+
+		broker.Publish(&model.WebhookPayload{Type: model.TypeCluster, ID: installationID})
+		broker.Publish(&model.WebhookPayload{Type: model.TypeInstallation, ID: model.NewID()})
+		payload := &model.WebhookPayload{
+			Type:     model.TypeInstallation,
+			ID:       installationID,
+			NewState: model.InstallationStateStable,
+			OldState: model.InstallationStateCreationDNS,
+		}
+		broker.Publish(payload)
+
+		received, err := stream.Next()
+		require.NoError(t, err)
+		require.Equal(t, payload, received)
+	})
+}
+
+func TestGetEvents(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	cluster := &model.Cluster{}
+	err := sqlStore.CreateCluster(cluster, nil)
+	require.NoError(t, err)
+
+	group := &model.Group{Name: "group"}
+	err = sqlStore.CreateGroup(group)
+	require.NoError(t, err)
+
+	installation := &model.Installation{
+		OwnerID: "owner",
+		DNS:     "dns.example.com",
+		GroupID: &group.ID,
+	}
+	err = sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	clusterInstallation := &model.ClusterInstallation{
+		ClusterID:      cluster.ID,
+		InstallationID: installation.ID,
+	}
+	err = sqlStore.CreateClusterInstallation(clusterInstallation)
+	require.NoError(t, err)
+
+	clusterEvent := &model.Event{
+		ResourceType: model.TypeCluster,
+		ResourceID:   cluster.ID,
+		OldState:     model.ClusterStateCreationRequested,
+		NewState:     model.ClusterStateCreationFailed,
+		InstanceID:   "instanceID",
+		Error:        "failed",
+	}
+	err = sqlStore.CreateEvent(clusterEvent)
+	require.NoError(t, err)
+
+	installationEvent := &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installation.ID,
+		OldState:     model.InstallationStateCreationRequested,
+		NewState:     model.InstallationStateStable,
+		InstanceID:   "instanceID",
+	}
+	err = sqlStore.CreateEvent(installationEvent)
+	require.NoError(t, err)
+
+	clusterInstallationEvent := &model.Event{
+		ResourceType: model.TypeClusterInstallation,
+		ResourceID:   clusterInstallation.ID,
+		OldState:     model.ClusterInstallationStateCreationRequested,
+		NewState:     model.ClusterInstallationStateReconciling,
+		InstanceID:   "instanceID",
+	}
+	err = sqlStore.CreateEvent(clusterInstallationEvent)
+	require.NoError(t, err)
+
+	request := &model.GetEventsRequest{PerPage: 10}
+
+	t.Run("unknown resources", func(t *testing.T) {
+		_, err := client.GetClusterEvents(model.NewID(), request)
+		require.EqualError(t, err, "failed with status code 404")
+		_, err = client.GetInstallationEvents(model.NewID(), request)
+		require.EqualError(t, err, "failed with status code 404")
+		_, err = client.GetClusterInstallationEvents(model.NewID(), request)
+		require.EqualError(t, err, "failed with status code 404")
+		_, err = client.GetGroupEvents(model.NewID(), request)
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("invalid paging", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/cluster/" + cluster.ID + "/events?page=invalid")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("cluster", func(t *testing.T) {
+		events, err := client.GetClusterEvents(cluster.ID, request)
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{clusterEvent}, events)
+	})
+
+	t.Run("installation", func(t *testing.T) {
+		events, err := client.GetInstallationEvents(installation.ID, request)
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{installationEvent}, events)
+	})
+
+	t.Run("cluster installation", func(t *testing.T) {
+		events, err := client.GetClusterInstallationEvents(clusterInstallation.ID, request)
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{clusterInstallationEvent}, events)
+	})
+
+	t.Run("group", func(t *testing.T) {
+		events, err := client.GetGroupEvents(group.ID, request)
+		require.NoError(t, err)
+		require.Equal(t, []*model.Event{installationEvent}, events)
+	})
+
+	t.Run("no events", func(t *testing.T) {
+		otherCluster := &model.Cluster{}
+		err := sqlStore.CreateCluster(otherCluster, nil)
+		require.NoError(t, err)
+
+		events, err := client.GetClusterEvents(otherCluster.ID, request)
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+}
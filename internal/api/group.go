@@ -19,13 +19,14 @@ func initGroup(apiRouter *mux.Router, context *Context) {
 
 	groupsRouter := apiRouter.PathPrefix("/groups").Subrouter()
 	groupsRouter.Handle("", addContext(handleGetGroups)).Methods("GET")
-	groupsRouter.Handle("", addContext(handleCreateGroup)).Methods("POST")
+	groupsRouter.Handle("", addContext(idempotent(handleCreateGroup))).Methods("POST")
 
 	groupRouter := apiRouter.PathPrefix("/group/{group:[A-Za-z0-9]{26}}").Subrouter()
 	groupRouter.Handle("", addContext(handleGetGroup)).Methods("GET")
 	groupRouter.Handle("", addContext(handleUpdateGroup)).Methods("PUT")
 	groupRouter.Handle("", addContext(handleDeleteGroup)).Methods("DELETE")
 	groupRouter.Handle("/status", addContext(handleGetGroupStatus)).Methods("GET")
+	groupRouter.Handle("/events", addContext(handleGetGroupEvents)).Methods("GET")
 }
 
 // handleGetGroup responds to GET /api/group/{group}, returning the group in question.
@@ -59,9 +60,17 @@ func handleGetGroups(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cursor, err := parseCursor(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse cursor")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	filter := &model.GroupFilter{
 		Page:           page,
 		PerPage:        perPage,
+		Cursor:         cursor,
 		IncludeDeleted: includeDeleted,
 	}
 
@@ -75,7 +84,21 @@ func handleGetGroups(c *Context, w http.ResponseWriter, r *http.Request) {
 		groups = []*model.Group{}
 	}
 
+	totalCount, err := c.Store.CountGroups(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to count groups")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor *model.PageCursor
+	if hasNextPage(perPage, len(groups)) {
+		last := groups[len(groups)-1]
+		nextCursor = &model.PageCursor{CreateAt: last.CreateAt, ID: last.ID}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	setPageHeaders(w, totalCount, nextCursor)
 	w.WriteHeader(http.StatusOK)
 	outputJSON(c, w, groups)
 }
@@ -90,13 +113,14 @@ func handleCreateGroup(c *Context, w http.ResponseWriter, r *http.Request) {
 	}
 
 	group := model.Group{
-		Name:            createGroupRequest.Name,
-		Description:     createGroupRequest.Description,
-		Version:         createGroupRequest.Version,
-		Image:           createGroupRequest.Image,
-		MaxRolling:      createGroupRequest.MaxRolling,
-		APISecurityLock: createGroupRequest.APISecurityLock,
-		MattermostEnv:   createGroupRequest.MattermostEnv,
+		Name:                createGroupRequest.Name,
+		Description:         createGroupRequest.Description,
+		Version:             createGroupRequest.Version,
+		Image:               createGroupRequest.Image,
+		MaxRolling:          createGroupRequest.MaxRolling,
+		APISecurityLock:     createGroupRequest.APISecurityLock,
+		MattermostEnv:       createGroupRequest.MattermostEnv,
+		HibernationSchedule: createGroupRequest.HibernationSchedule,
 	}
 
 	err = c.Store.CreateGroup(&group)
@@ -227,3 +251,26 @@ func handleGetGroupStatus(c *Context, w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	outputJSON(c, w, groupStatus)
 }
+
+// handleGetGroupEvents responds to GET /api/group/{group}/events, returning
+// the specified page of the state change history of the group's installations.
+func handleGetGroupEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID := vars["group"]
+	c.Logger = c.Logger.WithField("group", groupID)
+
+	group, err := c.Store.GetGroup(groupID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query group")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if group == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	getEvents(c, w, r, &model.EventFilter{
+		GroupID: groupID,
+	})
+}
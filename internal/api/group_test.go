@@ -558,3 +558,46 @@ func TestGroupStatus(t *testing.T) {
 		require.Nil(t, err)
 	})
 }
+
+func TestGroupHibernationSchedule(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	_, err := client.CreateGroup(&model.CreateGroupRequest{
+		Name:                "group",
+		HibernationSchedule: &model.HibernationSchedule{WakeUpAt: "0 8 * * 32"},
+	})
+	require.EqualError(t, err, "failed with status code 400")
+
+	schedule := &model.HibernationSchedule{HibernateAt: "0 20 * * 1-5", WakeUpAt: "0 8 * * 1-5"}
+	group, err := client.CreateGroup(&model.CreateGroupRequest{Name: "group", HibernationSchedule: schedule})
+	require.NoError(t, err)
+	require.Equal(t, schedule, group.HibernationSchedule)
+
+	group, err = client.UpdateGroup(&model.PatchGroupRequest{
+		ID:                  group.ID,
+		HibernationSchedule: &model.HibernationSchedule{HibernateAt: "0 18 * * 5"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &model.HibernationSchedule{HibernateAt: "0 18 * * 5"}, group.HibernationSchedule)
+	require.EqualValues(t, 0, group.Sequence)
+
+	group, err = client.UpdateGroup(&model.PatchGroupRequest{
+		ID:                  group.ID,
+		HibernationSchedule: &model.HibernationSchedule{},
+	})
+	require.NoError(t, err)
+	require.Nil(t, group.HibernationSchedule)
+}
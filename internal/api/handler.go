@@ -26,6 +26,26 @@ func (h contextHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		"request": context.RequestID,
 	})
 
+	if isAuditedRequest(r) {
+		auditor := newAuditor(context, w, r)
+		defer auditor.record(context, r)
+		w = auditor.writer
+	}
+
+	if !validateRequest(context, w, r) {
+		return
+	}
+
+	if context.RequireAPIKey {
+		apiKey, status := authenticate(context, r)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		context.APIKey = apiKey
+		context.Logger = context.Logger.WithField("apikey", apiKey.ID)
+	}
+
 	h.handler(context, w, r)
 }
 
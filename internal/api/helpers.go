@@ -5,9 +5,11 @@
 package api
 
 import (
+	"net/http"
 	"net/url"
 	"strconv"
 
+	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
@@ -39,6 +41,20 @@ func parseInt(u *url.URL, name string, defaultValue int) (int, error) {
 	return value, nil
 }
 
+func parseInt64(u *url.URL, name string, defaultValue int64) (int64, error) {
+	valueStr := u.Query().Get(name)
+	if valueStr == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseInt(valueStr, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse %s as integer", name)
+	}
+
+	return value, nil
+}
+
 func parseBool(u *url.URL, name string, defaultValue bool) (bool, error) {
 	valueStr := u.Query().Get(name)
 	if valueStr == "" {
@@ -72,6 +88,56 @@ func parsePaging(u *url.URL) (int, int, bool, error) {
 	return page, perPage, includeDeleted, nil
 }
 
+func parseCursor(u *url.URL) (*model.PageCursor, error) {
+	cursor := parseString(u, "cursor", "")
+	if cursor == "" {
+		return nil, nil
+	}
+
+	return model.DecodePageCursor(cursor)
+}
+
+// hasNextPage determines if a page of the given size, holding the given number
+// of items, may be followed by another one.
+func hasNextPage(perPage, count int) bool {
+	return perPage != model.AllPerPage && count > 0 && count >= perPage
+}
+
+// setPageHeaders describes the page of a list being returned: the number of
+// items across every page and, if any, the cursor of the next page.
+func setPageHeaders(w http.ResponseWriter, totalCount int64, nextCursor *model.PageCursor) {
+	w.Header().Set(model.TotalCountHeader, strconv.FormatInt(totalCount, 10))
+	if nextCursor != nil {
+		w.Header().Set(model.NextCursorHeader, nextCursor.Encode())
+	}
+}
+
+func parseCreatedRange(u *url.URL) (int64, int64, error) {
+	createdAfter, err := parseInt64(u, "created_after", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	createdBefore, err := parseInt64(u, "created_before", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return createdAfter, createdBefore, nil
+}
+
+func parseSort(u *url.URL, supportedKeys []string) (string, string, error) {
+	sortBy := parseString(u, "sort", "")
+	sortOrder := parseString(u, "order", "")
+
+	err := model.ValidateSort(sortBy, sortOrder, supportedKeys)
+	if err != nil {
+		return "", "", err
+	}
+
+	return sortBy, sortOrder, nil
+}
+
 func parseGroupConfig(u *url.URL) (bool, bool, error) {
 	includeGroupConfig, err := parseBool(u, "include_group_config", true)
 	if err != nil {
@@ -0,0 +1,175 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// DefaultIdempotencyKeyRetention is how long responses to requests made with
+// an idempotency key are kept for replay when not configured otherwise.
+const DefaultIdempotencyKeyRetention = 24 * time.Hour
+
+// DefaultIdempotencyKeyLease is how long a request made with an idempotency
+// key may be in progress before its key can be reclaimed by a retry when not
+// configured otherwise. Keys of requests interrupted by a crash or restart
+// are otherwise never released.
+const DefaultIdempotencyKeyLease = 5 * time.Minute
+
+// idempotent wraps a handler creating a resource so that a request carrying
+// an idempotency key is served at most once per key and caller. Retries of a
+// served request are answered with the original response, retries of a request
+// still being served are rejected with 409, and reuse of a key for a different
+// request is rejected with 422. Failed requests are forgotten so that they may
+// be retried with the same key, as are requests still in progress after the
+// idempotency key lease.
+func idempotent(handler contextHandlerFunc) contextHandlerFunc {
+	return func(c *Context, w http.ResponseWriter, r *http.Request) {
+		requestKey := r.Header.Get(model.IdempotencyKeyHeader)
+		if requestKey == "" {
+			handler(c, w, r)
+			return
+		}
+		if len(requestKey) > model.MaxIdempotencyKeyLength {
+			c.Logger.Errorf("idempotency key exceeds %d characters", model.MaxIdempotencyKeyLength)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to read request body")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		logger := c.Logger.WithField("idempotency-key", requestKey)
+
+		retention := c.IdempotencyKeyRetention
+		if retention == 0 {
+			retention = DefaultIdempotencyKeyRetention
+		}
+		expiredBefore := time.Now().Add(-retention).UnixNano() / int64(time.Millisecond)
+		err = c.Store.DeleteIdempotencyKeysCreatedBefore(expiredBefore)
+		if err != nil {
+			logger.WithError(err).Warn("failed to delete expired idempotency keys")
+		}
+
+		lease := c.IdempotencyKeyLease
+		if lease == 0 {
+			lease = DefaultIdempotencyKeyLease
+		}
+		abandonedBefore := time.Now().Add(-lease).UnixNano() / int64(time.Millisecond)
+		err = c.Store.DeleteIncompleteIdempotencyKeysCreatedBefore(abandonedBefore)
+		if err != nil {
+			logger.WithError(err).Warn("failed to delete abandoned idempotency keys")
+		}
+
+		idempotencyKey := &model.IdempotencyKey{
+			RequestKey:  requestKey,
+			RequestHash: model.HashIdempotentRequest(r.Method, r.URL.Path, body),
+		}
+		if c.APIKey != nil {
+			idempotencyKey.APIKeyID = c.APIKey.ID
+		}
+
+		err = c.Store.CreateIdempotencyKey(idempotencyKey)
+		if err != nil {
+			// Creation fails when the key was already sent by the caller.
+			existing, getErr := c.Store.GetIdempotencyKey(idempotencyKey.RequestKey, idempotencyKey.APIKeyID)
+			if getErr != nil {
+				logger.WithError(getErr).Error("failed to query idempotency key")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			if existing == nil {
+				logger.WithError(err).Error("failed to create idempotency key")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			replayIdempotentRequest(c, w, existing, idempotencyKey.RequestHash)
+			return
+		}
+
+		recorder := &idempotencyResponseWriter{ResponseWriter: w}
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			err := c.Store.DeleteIdempotencyKey(idempotencyKey.ID)
+			if err != nil {
+				logger.WithError(err).Error("failed to delete idempotency key of failed request")
+			}
+		}()
+
+		handler(c, recorder, r)
+
+		if recorder.statusCode < http.StatusOK || recorder.statusCode >= http.StatusMultipleChoices {
+			return
+		}
+
+		idempotencyKey.StatusCode = recorder.statusCode
+		idempotencyKey.ResponseBody = recorder.body.String()
+		err = c.Store.UpdateIdempotencyKey(idempotencyKey)
+		if err != nil {
+			logger.WithError(err).Error("failed to record response of idempotent request")
+			return
+		}
+		completed = true
+	}
+}
+
+// replayIdempotentRequest answers a request made with an idempotency key
+// that was already sent.
+func replayIdempotentRequest(c *Context, w http.ResponseWriter, idempotencyKey *model.IdempotencyKey, requestHash string) {
+	logger := c.Logger.WithField("idempotency-key", idempotencyKey.RequestKey)
+
+	if idempotencyKey.RequestHash != requestHash {
+		logger.Error("idempotency key was already used for a different request")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+	if !idempotencyKey.IsCompleted() {
+		logger.Warn("request with the same idempotency key is still in progress")
+		w.WriteHeader(http.StatusConflict)
+		return
+	}
+
+	logger.Debug("replaying response of request with the same idempotency key")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(model.IdempotentReplayedHeader, "true")
+	w.WriteHeader(idempotencyKey.StatusCode)
+	_, _ = w.Write([]byte(idempotencyKey.ResponseBody))
+}
+
+// idempotencyResponseWriter records the status code and body of a response
+// for replay.
+type idempotencyResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *idempotencyResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *idempotencyResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	w.body.Write(b)
+
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func postWithIdempotencyKey(t *testing.T, url, idempotencyKey, body string) (*http.Response, string) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+	req.Header.Set(model.IdempotencyKeyHeader, idempotencyKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp, string(respBody)
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:                   sqlStore,
+		Supervisor:              &mockSupervisor{},
+		IdempotencyKeyRetention: time.Hour,
+		Logger:                  logger,
+	})
+
+	var idempotencyKeys []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idempotencyKeys = append(idempotencyKeys, r.Header.Get(model.IdempotencyKeyHeader))
+		router.ServeHTTP(w, r)
+	}))
+	defer ts.Close()
+
+	groupsURL := ts.URL + "/api/groups"
+	groupBody := `{"Name":"group1","MaxRolling":1}`
+
+	t.Run("replay", func(t *testing.T) {
+		resp, body := postWithIdempotencyKey(t, groupsURL, "key1", groupBody)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Empty(t, resp.Header.Get(model.IdempotentReplayedHeader))
+
+		replayedResp, replayedBody := postWithIdempotencyKey(t, groupsURL, "key1", groupBody)
+		require.Equal(t, http.StatusOK, replayedResp.StatusCode)
+		require.Equal(t, "true", replayedResp.Header.Get(model.IdempotentReplayedHeader))
+		require.Equal(t, body, replayedBody)
+
+		groups, err := sqlStore.GetGroups(&model.GroupFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+	})
+
+	t.Run("different request", func(t *testing.T) {
+		resp, _ := postWithIdempotencyKey(t, groupsURL, "key1", `{"Name":"group2","MaxRolling":1}`)
+		require.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		err := sqlStore.CreateIdempotencyKey(&model.IdempotencyKey{
+			RequestKey:  "key2",
+			RequestHash: model.HashIdempotentRequest(http.MethodPost, "/api/groups", []byte(groupBody)),
+		})
+		require.NoError(t, err)
+
+		resp, _ := postWithIdempotencyKey(t, groupsURL, "key2", groupBody)
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("failed requests are not remembered", func(t *testing.T) {
+		resp, _ := postWithIdempotencyKey(t, groupsURL, "key3", `{invalid`)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		idempotencyKey, err := sqlStore.GetIdempotencyKey("key3", "")
+		require.NoError(t, err)
+		require.Nil(t, idempotencyKey)
+	})
+
+	t.Run("key too long", func(t *testing.T) {
+		resp, _ := postWithIdempotencyKey(t, groupsURL, strings.Repeat("k", model.MaxIdempotencyKeyLength+1), groupBody)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("client sets a key per create request", func(t *testing.T) {
+		client := model.NewClient(ts.URL)
+		idempotencyKeys = nil
+
+		_, err := client.CreateWebhook(&model.CreateWebhookRequest{OwnerID: "owner", URL: "http://example.com/idempotency1"})
+		require.NoError(t, err)
+		_, err = client.CreateWebhook(&model.CreateWebhookRequest{OwnerID: "owner", URL: "http://example.com/idempotency2"})
+		require.NoError(t, err)
+		_, err = client.GetWebhooks(&model.GetWebhooksRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+
+		require.Len(t, idempotencyKeys, 3)
+		require.NotEmpty(t, idempotencyKeys[0])
+		require.NotEmpty(t, idempotencyKeys[1])
+		require.NotEqual(t, idempotencyKeys[0], idempotencyKeys[1])
+		require.Empty(t, idempotencyKeys[2])
+	})
+}
+
+func TestIdempotencyKeyRetention(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:                   sqlStore,
+		Supervisor:              &mockSupervisor{},
+		IdempotencyKeyRetention: time.Millisecond,
+		Logger:                  logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	groupBody := `{"Name":"group1","MaxRolling":1}`
+	resp, body := postWithIdempotencyKey(t, ts.URL+"/api/groups", "key1", groupBody)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	time.Sleep(5 * time.Millisecond)
+
+	resp, expiredBody := postWithIdempotencyKey(t, ts.URL+"/api/groups", "key1", groupBody)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Empty(t, resp.Header.Get(model.IdempotentReplayedHeader))
+	require.NotEqual(t, body, expiredBody)
+}
+
+func TestIdempotencyKeyLease(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:               sqlStore,
+		Supervisor:          &mockSupervisor{},
+		IdempotencyKeyLease: time.Millisecond,
+		Logger:              logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	groupBody := `{"Name":"group1","MaxRolling":1}`
+	err := sqlStore.CreateIdempotencyKey(&model.IdempotencyKey{
+		RequestKey:  "key1",
+		RequestHash: model.HashIdempotentRequest(http.MethodPost, "/api/groups", []byte(groupBody)),
+	})
+	require.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	resp, body := postWithIdempotencyKey(t, ts.URL+"/api/groups", "key1", groupBody)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	replayedResp, replayedBody := postWithIdempotencyKey(t, ts.URL+"/api/groups", "key1", groupBody)
+	require.Equal(t, http.StatusOK, replayedResp.StatusCode)
+	require.Equal(t, "true", replayedResp.Header.Get(model.IdempotentReplayedHeader))
+	require.Equal(t, body, replayedBody)
+}
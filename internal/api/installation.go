@@ -22,7 +22,7 @@ func initInstallation(apiRouter *mux.Router, context *Context) {
 	installationsRouter := apiRouter.PathPrefix("/installations").Subrouter()
 	installationsRouter.Handle("", addContext(handleGetInstallations)).Methods("GET")
 	installationsRouter.Handle("/count", addContext(handleGetNumberOfInstallations)).Methods("GET")
-	installationsRouter.Handle("", addContext(handleCreateInstallation)).Methods("POST")
+	installationsRouter.Handle("", addContext(idempotent(handleCreateInstallation))).Methods("POST")
 
 	installationRouter := apiRouter.PathPrefix("/installation/{installation:[A-Za-z0-9]{26}}").Subrouter()
 	installationRouter.Handle("", addContext(handleGetInstallation)).Methods("GET")
@@ -32,6 +32,12 @@ func initInstallation(apiRouter *mux.Router, context *Context) {
 	installationRouter.Handle("/group", addContext(handleLeaveGroup)).Methods("DELETE")
 	installationRouter.Handle("/hibernate", addContext(handleHibernateInstallation)).Methods("POST")
 	installationRouter.Handle("/wakeup", addContext(handleWakeupInstallation)).Methods("POST")
+	installationRouter.Handle("/migrate", addContext(handleMigrateInstallation)).Methods("POST")
+	installationRouter.Handle("/clone", addContext(idempotent(handleCloneInstallation))).Methods("POST")
+	installationRouter.Handle("/database_migration", addContext(handleMigrateInstallationDatabase)).Methods("POST")
+	installationRouter.Handle("/hibernation_schedule", addContext(handleSetInstallationHibernationSchedule)).Methods("PUT")
+	installationRouter.Handle("/hibernation_schedule", addContext(handleDeleteInstallationHibernationSchedule)).Methods("DELETE")
+	installationRouter.Handle("/events", addContext(handleGetInstallationEvents)).Methods("GET")
 	installationRouter.Handle("", addContext(handleDeleteInstallation)).Methods("DELETE")
 }
 
@@ -54,7 +60,7 @@ func handleGetInstallation(c *Context, w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	if installation == nil {
+	if installation == nil || !isOwnerAllowed(c, installation.OwnerID) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
@@ -67,7 +73,12 @@ func handleGetInstallation(c *Context, w http.ResponseWriter, r *http.Request) {
 // handleGetInstallations responds to GET /api/installations, returning the specified page of installations.
 func handleGetInstallations(c *Context, w http.ResponseWriter, r *http.Request) {
 	var err error
-	owner := r.URL.Query().Get("owner")
+	owner, ok := ownerFilter(c, r.URL.Query().Get("owner"))
+	if !ok {
+		c.Logger.Warn("unable to list installations of another owner")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
 	group := r.URL.Query().Get("group")
 
 	page, perPage, includeDeleted, err := parsePaging(r.URL)
@@ -84,6 +95,27 @@ func handleGetInstallations(c *Context, w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	createdAfter, createdBefore, err := parseCreatedRange(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse creation time parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	sortBy, sortOrder, err := parseSort(r.URL, model.InstallationSortKeys)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse sort parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	cursor, err := parseCursor(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse cursor")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	dns := r.URL.Query().Get("dns_name")
 
 	filter := &model.InstallationFilter{
@@ -91,8 +123,21 @@ func handleGetInstallations(c *Context, w http.ResponseWriter, r *http.Request)
 		GroupID:        group,
 		Page:           page,
 		PerPage:        perPage,
+		Cursor:         cursor,
 		IncludeDeleted: includeDeleted,
 		DNS:            dns,
+		States:         r.URL.Query()["state"],
+		Annotations:    r.URL.Query()["annotation"],
+		Version:        r.URL.Query().Get("version"),
+		Image:          r.URL.Query().Get("image"),
+		Database:       r.URL.Query().Get("database"),
+		Filestore:      r.URL.Query().Get("filestore"),
+		Size:           r.URL.Query().Get("size"),
+		Affinity:       r.URL.Query().Get("affinity"),
+		CreatedAfter:   createdAfter,
+		CreatedBefore:  createdBefore,
+		SortBy:         sortBy,
+		SortOrder:      sortOrder,
 	}
 
 	installations, err := c.Store.GetInstallationDTOs(filter, includeGroupConfig, includeGroupConfigOverrides)
@@ -105,7 +150,32 @@ func handleGetInstallations(c *Context, w http.ResponseWriter, r *http.Request)
 		installations = []*model.InstallationDTO{}
 	}
 
+	totalCount, err := c.Store.CountInstallations(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to count installations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor *model.PageCursor
+	if hasNextPage(perPage, len(installations)) {
+		// Installations are paged on their stored values, which group
+		// configuration may override, so the cursor is built from the
+		// installation as stored.
+		lastInstallation := installations[len(installations)-1].Installation
+		if includeGroupConfig {
+			lastInstallation, err = c.Store.GetInstallation(lastInstallation.ID, false, false)
+			if err != nil {
+				c.Logger.WithError(err).Error("failed to query installation")
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		nextCursor = model.NewInstallationCursor(lastInstallation, sortBy)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	setPageHeaders(w, totalCount, nextCursor)
 	w.WriteHeader(http.StatusOK)
 	outputJSON(c, w, installations)
 }
@@ -113,6 +183,12 @@ func handleGetInstallations(c *Context, w http.ResponseWriter, r *http.Request)
 // handlerGetNumberOfInstallations responds to GET /api/installations/count, returning the
 // number of non-deleted installations
 func handleGetNumberOfInstallations(c *Context, w http.ResponseWriter, r *http.Request) {
+	if boundOwnerID(c) != "" {
+		c.Logger.Warn("unable to count installations of every owner")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	includeDeleted, err := parseBool(r.URL, "include_deleted", false)
 	if err != nil {
 		includeDeleted = false
@@ -139,6 +215,12 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if !isOwnerAllowed(c, createInstallationRequest.OwnerID) {
+		c.Logger.Warnf("unable to create installation for owner %s", createInstallationRequest.OwnerID)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
 	var group *model.Group
 	var status int
 	groupUnlockOnce := func() {}
@@ -157,19 +239,23 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 	}
 
 	installation := model.Installation{
-		OwnerID:         createInstallationRequest.OwnerID,
-		GroupID:         &createInstallationRequest.GroupID,
-		Version:         createInstallationRequest.Version,
-		Image:           createInstallationRequest.Image,
-		DNS:             createInstallationRequest.DNS,
-		Database:        createInstallationRequest.Database,
-		Filestore:       createInstallationRequest.Filestore,
-		License:         createInstallationRequest.License,
-		Size:            createInstallationRequest.Size,
-		Affinity:        createInstallationRequest.Affinity,
-		APISecurityLock: createInstallationRequest.APISecurityLock,
-		MattermostEnv:   createInstallationRequest.MattermostEnv,
-		State:           model.InstallationStateCreationRequested,
+		OwnerID:                     createInstallationRequest.OwnerID,
+		GroupID:                     &createInstallationRequest.GroupID,
+		Version:                     createInstallationRequest.Version,
+		Image:                       createInstallationRequest.Image,
+		DNS:                         createInstallationRequest.DNS,
+		Database:                    createInstallationRequest.Database,
+		Filestore:                   createInstallationRequest.Filestore,
+		License:                     createInstallationRequest.License,
+		Size:                        createInstallationRequest.Size,
+		Affinity:                    createInstallationRequest.Affinity,
+		PlacementStrategy:           createInstallationRequest.PlacementStrategy,
+		RequiredClusterAnnotations:  createInstallationRequest.RequiredClusterAnnotations,
+		PreferredClusterAnnotations: createInstallationRequest.PreferredClusterAnnotations,
+		APISecurityLock:             createInstallationRequest.APISecurityLock,
+		MattermostEnv:               createInstallationRequest.MattermostEnv,
+		HibernationSchedule:         createInstallationRequest.HibernationSchedule,
+		State:                       model.InstallationStateCreationRequested,
 	}
 
 	annotations, err := model.AnnotationsFromStringSlice(createInstallationRequest.Annotations)
@@ -189,6 +275,7 @@ func handleCreateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  model.InstallationStateCreationRequested,
 		OldState:  "n/a",
 		Timestamp: time.Now().UnixNano(),
@@ -236,6 +323,7 @@ func handleRetryCreateInstallation(c *Context, w http.ResponseWriter, r *http.Re
 		webhookPayload := &model.WebhookPayload{
 			Type:      model.TypeInstallation,
 			ID:        installationDTO.ID,
+			OwnerID:   installationDTO.OwnerID,
 			NewState:  newState,
 			OldState:  installationDTO.State,
 			Timestamp: time.Now().UnixNano(),
@@ -314,6 +402,7 @@ func handleUpdateInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		webhookPayload := &model.WebhookPayload{
 			Type:      model.TypeInstallation,
 			ID:        installationDTO.ID,
+			OwnerID:   installationDTO.OwnerID,
 			NewState:  newState,
 			OldState:  oldState,
 			Timestamp: time.Now().UnixNano(),
@@ -498,6 +587,7 @@ func handleHibernateInstallation(c *Context, w http.ResponseWriter, r *http.Requ
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installationDTO.ID,
+		OwnerID:   installationDTO.OwnerID,
 		NewState:  newState,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
@@ -557,6 +647,7 @@ func handleWakeupInstallation(c *Context, w http.ResponseWriter, r *http.Request
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installationDTO.ID,
+		OwnerID:   installationDTO.OwnerID,
 		NewState:  newState,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
@@ -575,6 +666,399 @@ func handleWakeupInstallation(c *Context, w http.ResponseWriter, r *http.Request
 	outputJSON(c, w, installationDTO)
 }
 
+// handleSetInstallationHibernationSchedule responds to PUT /api/installation/{installation}/hibernation_schedule,
+// replacing the hibernation schedule of the installation.
+func handleSetInstallationHibernationSchedule(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.WithField("installation", installationID)
+
+	schedule, err := model.HibernationScheduleFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	err = schedule.Validate()
+	if err != nil {
+		c.Logger.WithError(err).Error("invalid hibernation schedule")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationDTO, status, unlockOnce := lockInstallation(c, installationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if installationDTO.APISecurityLock {
+		logSecurityLockConflict("installation", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	installationDTO.HibernationSchedule = schedule
+
+	err = c.Store.UpdateInstallation(installationDTO.Installation)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, installationDTO)
+}
+
+// handleDeleteInstallationHibernationSchedule responds to DELETE /api/installation/{installation}/hibernation_schedule,
+// removing the hibernation schedule of the installation.
+func handleDeleteInstallationHibernationSchedule(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.WithField("installation", installationID)
+
+	installationDTO, status, unlockOnce := lockInstallation(c, installationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if installationDTO.APISecurityLock {
+		logSecurityLockConflict("installation", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	installationDTO.HibernationSchedule = nil
+
+	err := c.Store.UpdateInstallation(installationDTO.Installation)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleMigrateInstallation responds to POST /api/installation/{installation}/migrate,
+// moving the installation to the target cluster embedded in the request.
+func handleMigrateInstallation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.WithField("installation", installationID)
+
+	migrateInstallationRequest, err := model.NewMigrateInstallationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationDTO, status, unlockOnce := lockInstallation(c, installationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if installationDTO.APISecurityLock {
+		logSecurityLockConflict("installation", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	oldState := installationDTO.State
+	newState := model.InstallationStateMigrationRequested
+
+	if !installationDTO.ValidTransitionState(newState) {
+		c.Logger.Warnf("unable to migrate installation while in state %s", installationDTO.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Databases and filestores running inside the cluster can't be reused
+	// by a cluster installation on another cluster.
+	if installationDTO.InternalDatabase() || installationDTO.InternalFilestore() {
+		c.Logger.Warnf("unable to migrate installation with database %s and filestore %s", installationDTO.Database, installationDTO.Filestore)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	targetCluster, err := c.Store.GetCluster(migrateInstallationRequest.TargetClusterID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query target cluster")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if targetCluster == nil || targetCluster.DeleteAt != 0 {
+		c.Logger.Warnf("target cluster %s not found", migrateInstallationRequest.TargetClusterID)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	clusterInstallations, err := c.Store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		InstallationID: installationDTO.ID,
+		ClusterID:      targetCluster.ID,
+		PerPage:        model.AllPerPage,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query cluster installations")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(clusterInstallations) > 0 && oldState == model.InstallationStateStable {
+		c.Logger.Warnf("installation is already running on cluster %s", targetCluster.ID)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if installationDTO.State != newState || installationDTO.MigrationTargetClusterID != targetCluster.ID {
+		installationDTO.State = newState
+		installationDTO.MigrationTargetClusterID = targetCluster.ID
+
+		err = c.Store.UpdateInstallation(installationDTO.Installation)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to update installation")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeInstallation,
+			ID:        installationDTO.ID,
+			OwnerID:   installationDTO.OwnerID,
+			NewState:  newState,
+			OldState:  oldState,
+			Timestamp: time.Now().UnixNano(),
+			ExtraData: map[string]string{"DNS": installationDTO.DNS, "TargetClusterID": targetCluster.ID},
+		}
+		err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+		if err != nil {
+			c.Logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, installationDTO)
+}
+
+// handleMigrateInstallationDatabase responds to POST
+// /api/installation/{installation}/database_migration, moving the data of the
+// hibernating installation to the database and filestore in the request.
+func handleMigrateInstallationDatabase(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.WithField("installation", installationID)
+
+	migrateDatabaseRequest, err := model.NewMigrateInstallationDatabaseRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationDTO, status, unlockOnce := lockInstallation(c, installationID)
+	if status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+	defer unlockOnce()
+
+	if installationDTO.APISecurityLock {
+		logSecurityLockConflict("installation", c.Logger)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	oldState := installationDTO.State
+	newState := model.InstallationStateDBMigrationRequested
+
+	if !installationDTO.ValidTransitionState(newState) {
+		c.Logger.Warnf("unable to migrate installation database while in state %s", installationDTO.State)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	err = model.ValidateDatabaseMigration(installationDTO.Installation, migrateDatabaseRequest.Database, migrateDatabaseRequest.Filestore)
+	if err != nil {
+		c.Logger.WithError(err).Warn("invalid installation database migration")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	installationDTO.State = newState
+	installationDTO.DatabaseMigrationTarget = ""
+	if migrateDatabaseRequest.Database != installationDTO.Database {
+		installationDTO.DatabaseMigrationTarget = migrateDatabaseRequest.Database
+	}
+	installationDTO.FilestoreMigrationTarget = ""
+	if migrateDatabaseRequest.Filestore != installationDTO.Filestore {
+		installationDTO.FilestoreMigrationTarget = migrateDatabaseRequest.Filestore
+	}
+
+	err = c.Store.UpdateInstallation(installationDTO.Installation)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to update installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installationDTO.ID,
+		OwnerID:   installationDTO.OwnerID,
+		NewState:  newState,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{
+			"DNS":                      installationDTO.DNS,
+			"DatabaseMigrationTarget":  installationDTO.DatabaseMigrationTarget,
+			"FilestoreMigrationTarget": installationDTO.FilestoreMigrationTarget,
+		},
+	}
+	err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		c.Logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	unlockOnce()
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, installationDTO)
+}
+
+// handleCloneInstallation responds to POST /api/installation/{installation}/clone,
+// creating a new installation from a copy of the database and filestore of the installation.
+//
+// The clone is restored from the backup given in the request, or from a new backup of the
+// installation when none is given. It keeps the version, image, size and environment of the
+// installation, but not its license or group.
+func handleCloneInstallation(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.WithField("installation", installationID)
+
+	cloneInstallationRequest, err := model.NewCloneInstallationRequestFromReader(r.Body)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to decode request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !isOwnerAllowed(c, cloneInstallationRequest.OwnerID) {
+		c.Logger.Warnf("unable to create installation for owner %s", cloneInstallationRequest.OwnerID)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	// The scrub command is run like a command on the cluster installation.
+	if len(cloneInstallationRequest.ScrubCommand) != 0 && !canExecuteCommands(c) {
+		c.Logger.Warn("API key may not run a scrub command")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	source, err := c.Store.GetInstallation(installationID, true, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if source == nil || !isOwnerAllowed(c, source.OwnerID) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err = model.ValidateBackupSupport(source)
+	if err != nil {
+		c.Logger.WithError(err).Warn("unable to clone installation")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var backup *model.Backup
+	if len(cloneInstallationRequest.BackupID) != 0 {
+		backup, err = c.Store.GetBackup(cloneInstallationRequest.BackupID)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to query backup")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if backup == nil || backup.InstallationID != source.ID || backup.State == model.BackupStateFailed {
+			c.Logger.Warnf("unable to clone installation from backup %s", cloneInstallationRequest.BackupID)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	} else {
+		if source.State != model.InstallationStateStable && source.State != model.InstallationStateHibernating {
+			c.Logger.Warnf("unable to back up installation while in state %s", source.State)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		backup, err = requestBackup(c, source)
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to create backup")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	installation := model.Installation{
+		OwnerID:           cloneInstallationRequest.OwnerID,
+		Version:           source.Version,
+		Image:             source.Image,
+		DNS:               cloneInstallationRequest.DNS,
+		Database:          source.Database,
+		Filestore:         source.Filestore,
+		Size:              source.Size,
+		Affinity:          source.Affinity,
+		PlacementStrategy: source.PlacementStrategy,
+		MattermostEnv:     source.MattermostEnv,
+		State:             model.InstallationStateCreationRequested,
+		RestoreBackupID:   backup.ID,
+		RestoreID:         model.NewID(),
+		CloneScrubCommand: cloneInstallationRequest.ScrubCommand,
+	}
+
+	err = c.Store.CreateInstallation(&installation, nil)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to create installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  model.InstallationStateCreationRequested,
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installation.DNS, "ClonedFrom": source.ID, "BackupID": backup.ID},
+	}
+	err = webhook.SendToAllWebhooks(c.Store, webhookPayload, c.Logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		c.Logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, installation.ToDTO(nil))
+}
+
 // handleDeleteInstallation responds to DELETE /api/installation/{installation}, beginning the process of
 // deleting the installation.
 func handleDeleteInstallation(c *Context, w http.ResponseWriter, r *http.Request) {
@@ -607,6 +1091,7 @@ func handleDeleteInstallation(c *Context, w http.ResponseWriter, r *http.Request
 		webhookPayload := &model.WebhookPayload{
 			Type:      model.TypeInstallation,
 			ID:        installationDTO.ID,
+			OwnerID:   installationDTO.OwnerID,
 			NewState:  newState,
 			OldState:  installationDTO.State,
 			Timestamp: time.Now().UnixNano(),
@@ -632,3 +1117,27 @@ func handleDeleteInstallation(c *Context, w http.ResponseWriter, r *http.Request
 
 	w.WriteHeader(http.StatusAccepted)
 }
+
+// handleGetInstallationEvents responds to GET /api/installation/{installation}/events,
+// returning the specified page of the installation's state change history.
+func handleGetInstallationEvents(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	installationID := vars["installation"]
+	c.Logger = c.Logger.WithField("installation", installationID)
+
+	installation, err := c.Store.GetInstallation(installationID, false, false)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query installation")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if installation == nil || !isOwnerAllowed(c, installation.OwnerID) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	getEvents(c, w, r, &model.EventFilter{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installationID,
+	})
+}
@@ -117,6 +117,26 @@ func TestGetInstallations(t *testing.T) {
 		})
 	})
 
+	t.Run("filter parameter handling", func(t *testing.T) {
+		t.Run("invalid created after", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/installations?created_after=invalid", ts.URL))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("unsupported sort key", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/installations?sort=license", ts.URL))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+
+		t.Run("unsupported sort order", func(t *testing.T) {
+			resp, err := http.Get(fmt.Sprintf("%s/api/installations?sort=dns&order=sideways", ts.URL))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+
 	t.Run("results", func(t *testing.T) {
 		ownerID1 := model.NewID()
 		ownerID2 := model.NewID()
@@ -136,6 +156,7 @@ func TestGetInstallations(t *testing.T) {
 			DNS:      "dns.example.com",
 			Size:     "1000users",
 			Affinity: model.InstallationAffinityIsolated,
+			State:    model.InstallationStateStable,
 		}
 		err := sqlStore.CreateInstallation(installation1, annotations)
 		require.NoError(t, err)
@@ -262,6 +283,48 @@ func TestGetInstallations(t *testing.T) {
 					},
 					[]*model.Installation{installation1, installation3},
 				},
+				{
+					"filter by annotation",
+					&model.GetInstallationsRequest{
+						PerPage:     100,
+						Annotations: []string{"multi-tenant", "super-awesome"},
+					},
+					[]*model.Installation{installation1},
+				},
+				{
+					"filter by size",
+					&model.GetInstallationsRequest{
+						PerPage: 100,
+						Size:    "1000users",
+					},
+					[]*model.Installation{installation1},
+				},
+				{
+					"filter by state",
+					&model.GetInstallationsRequest{
+						PerPage: 100,
+						States:  []string{model.InstallationStateStable, model.InstallationStateHibernating},
+					},
+					[]*model.Installation{installation1},
+				},
+				{
+					"filter by creation time",
+					&model.GetInstallationsRequest{
+						PerPage:       100,
+						CreatedAfter:  installation1.CreateAt,
+						CreatedBefore: installation3.CreateAt,
+					},
+					[]*model.Installation{installation2},
+				},
+				{
+					"sort by DNS descending",
+					&model.GetInstallationsRequest{
+						PerPage:   100,
+						SortBy:    model.SortByDNS,
+						SortOrder: model.SortOrderDescending,
+					},
+					[]*model.Installation{installation3, installation2, installation1},
+				},
 			}
 
 			for _, testCase := range testCases {
@@ -1093,6 +1156,134 @@ func TestLeaveGroup(t *testing.T) {
 	})
 }
 
+func TestMigrateInstallation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	cluster1 := &model.Cluster{}
+	err := sqlStore.CreateCluster(cluster1, nil)
+	require.NoError(t, err)
+
+	installation1, err := client.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID:   "owner",
+		Version:   "version",
+		DNS:       "dns.example.com",
+		Affinity:  model.InstallationAffinityIsolated,
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreMultiTenantAwsS3,
+	})
+	require.NoError(t, err)
+
+	installation1.State = model.InstallationStateStable
+	err = sqlStore.UpdateInstallation(installation1.Installation)
+	require.NoError(t, err)
+
+	t.Run("unknown installation", func(t *testing.T) {
+		installationResponse, err := client.MigrateInstallation(model.NewID(), &model.MigrateInstallationRequest{TargetClusterID: cluster1.ID})
+		require.EqualError(t, err, "failed with status code 404")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("missing target cluster", func(t *testing.T) {
+		installationResponse, err := client.MigrateInstallation(installation1.ID, &model.MigrateInstallationRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("unknown target cluster", func(t *testing.T) {
+		installationResponse, err := client.MigrateInstallation(installation1.ID, &model.MigrateInstallationRequest{TargetClusterID: model.NewID()})
+		require.EqualError(t, err, "failed with status code 400")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("while api-security-locked", func(t *testing.T) {
+		err = sqlStore.LockInstallationAPI(installation1.ID)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallation(installation1.ID, &model.MigrateInstallationRequest{TargetClusterID: cluster1.ID})
+		require.EqualError(t, err, "failed with status code 403")
+		require.Nil(t, installationResponse)
+
+		err = sqlStore.UnlockInstallationAPI(installation1.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("with internal database", func(t *testing.T) {
+		installation2, err := client.CreateInstallation(&model.CreateInstallationRequest{
+			OwnerID:  "owner",
+			Version:  "version",
+			DNS:      "dns2.example.com",
+			Affinity: model.InstallationAffinityIsolated,
+		})
+		require.NoError(t, err)
+
+		installation2.State = model.InstallationStateStable
+		err = sqlStore.UpdateInstallation(installation2.Installation)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallation(installation2.ID, &model.MigrateInstallationRequest{TargetClusterID: cluster1.ID})
+		require.EqualError(t, err, "failed with status code 400")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("while creating", func(t *testing.T) {
+		installation1.State = model.InstallationStateCreationRequested
+		err = sqlStore.UpdateInstallation(installation1.Installation)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallation(installation1.ID, &model.MigrateInstallationRequest{TargetClusterID: cluster1.ID})
+		require.EqualError(t, err, "failed with status code 400")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("already on target cluster", func(t *testing.T) {
+		installation1.State = model.InstallationStateStable
+		err = sqlStore.UpdateInstallation(installation1.Installation)
+		require.NoError(t, err)
+
+		clusterInstallation := &model.ClusterInstallation{
+			ClusterID:      cluster1.ID,
+			InstallationID: installation1.ID,
+			Namespace:      installation1.ID,
+			State:          model.ClusterInstallationStateStable,
+		}
+		err = sqlStore.CreateClusterInstallation(clusterInstallation)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallation(installation1.ID, &model.MigrateInstallationRequest{TargetClusterID: cluster1.ID})
+		require.EqualError(t, err, "failed with status code 400")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("while stable", func(t *testing.T) {
+		cluster2 := &model.Cluster{}
+		err = sqlStore.CreateCluster(cluster2, nil)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallation(installation1.ID, &model.MigrateInstallationRequest{TargetClusterID: cluster2.ID})
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateMigrationRequested, installationResponse.State)
+		require.Equal(t, cluster2.ID, installationResponse.MigrationTargetClusterID)
+
+		installation, err := sqlStore.GetInstallation(installation1.ID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateMigrationRequested, installation.State)
+		require.Equal(t, cluster2.ID, installation.MigrationTargetClusterID)
+	})
+}
+
 func TestDeleteInstallation(t *testing.T) {
 	logger := testlib.MakeLogger(t)
 	sqlStore := store.MakeTestSQLStore(t, logger)
@@ -1169,6 +1360,7 @@ func TestDeleteInstallation(t *testing.T) {
 			model.InstallationStateUpdateRequested,
 			model.InstallationStateUpdateInProgress,
 			model.InstallationStateUpdateFailed,
+			model.InstallationStateMigrationFailed,
 			model.InstallationStateDeletionRequested,
 			model.InstallationStateDeletionInProgress,
 			model.InstallationStateDeletionFinalCleanup,
@@ -1199,3 +1391,330 @@ func dtosToInstallations(dtos []*model.InstallationDTO) []*model.Installation {
 	}
 	return installations
 }
+
+func TestInstallationHibernationSchedule(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	installation, err := client.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID:             "owner",
+		DNS:                 "dns.example.com",
+		HibernationSchedule: &model.HibernationSchedule{HibernateAt: "0 20 * * 1-5"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &model.HibernationSchedule{HibernateAt: "0 20 * * 1-5"}, installation.HibernationSchedule)
+
+	t.Run("invalid schedule on create", func(t *testing.T) {
+		_, err := client.CreateInstallation(&model.CreateInstallationRequest{
+			OwnerID:             "owner",
+			DNS:                 "dns2.example.com",
+			HibernationSchedule: &model.HibernationSchedule{HibernateAt: "tonight"},
+		})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("unknown installation", func(t *testing.T) {
+		_, err := client.SetInstallationHibernationSchedule(model.NewID(), &model.HibernationSchedule{WakeUpAt: "0 8 * * *"})
+		require.EqualError(t, err, "failed with status code 404")
+
+		err = client.DeleteInstallationHibernationSchedule(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		_, err := client.SetInstallationHibernationSchedule(installation.ID, &model.HibernationSchedule{})
+		require.EqualError(t, err, "failed with status code 400")
+
+		_, err = client.SetInstallationHibernationSchedule(installation.ID, &model.HibernationSchedule{WakeUpAt: "0 25 * * *"})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("while api-security-locked", func(t *testing.T) {
+		err := sqlStore.LockInstallationAPI(installation.ID)
+		require.NoError(t, err)
+
+		_, err = client.SetInstallationHibernationSchedule(installation.ID, &model.HibernationSchedule{WakeUpAt: "0 8 * * *"})
+		require.EqualError(t, err, "failed with status code 403")
+
+		err = sqlStore.UnlockInstallationAPI(installation.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("set and delete", func(t *testing.T) {
+		schedule := &model.HibernationSchedule{HibernateAt: "0 22 * * *", WakeUpAt: "0 8 * * *"}
+		updated, err := client.SetInstallationHibernationSchedule(installation.ID, schedule)
+		require.NoError(t, err)
+		require.Equal(t, schedule, updated.HibernationSchedule)
+
+		fetched, err := client.GetInstallation(installation.ID, nil)
+		require.NoError(t, err)
+		require.Equal(t, schedule, fetched.HibernationSchedule)
+
+		err = client.DeleteInstallationHibernationSchedule(installation.ID)
+		require.NoError(t, err)
+
+		fetched, err = client.GetInstallation(installation.ID, nil)
+		require.NoError(t, err)
+		require.Nil(t, fetched.HibernationSchedule)
+	})
+}
+
+func TestCloneInstallation(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	source := &model.Installation{
+		OwnerID:       model.NewID(),
+		DNS:           model.NewID() + ".example.com",
+		Version:       "5.30.0",
+		Image:         "mattermost/mattermost-enterprise-edition",
+		Size:          "1000users",
+		Affinity:      model.InstallationAffinityIsolated,
+		Database:      model.InstallationDatabaseSingleTenantRDSMySQL,
+		Filestore:     model.InstallationFilestoreAwsS3,
+		License:       "license",
+		MattermostEnv: model.EnvVarMap{"key1": {Value: "value1"}},
+		State:         model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(source, nil)
+	require.NoError(t, err)
+
+	newRequest := func() *model.CloneInstallationRequest {
+		return &model.CloneInstallationRequest{
+			OwnerID: model.NewID(),
+			DNS:     model.NewID() + ".example.com",
+		}
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		httpRequest, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/installation/%s/clone", ts.URL, source.ID), bytes.NewReader([]byte("{invalid")))
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(httpRequest)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("unknown installation", func(t *testing.T) {
+		_, err := client.CloneInstallation(model.NewID(), newRequest())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("unknown backup", func(t *testing.T) {
+		request := newRequest()
+		request.BackupID = model.NewID()
+
+		_, err := client.CloneInstallation(source.ID, request)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("unsupported database", func(t *testing.T) {
+		unsupported := &model.Installation{
+			OwnerID:   model.NewID(),
+			DNS:       model.NewID() + ".example.com",
+			Database:  model.InstallationDatabaseMysqlOperator,
+			Filestore: model.InstallationFilestoreAwsS3,
+			State:     model.InstallationStateStable,
+		}
+		err := sqlStore.CreateInstallation(unsupported, nil)
+		require.NoError(t, err)
+
+		_, err = client.CloneInstallation(unsupported.ID, newRequest())
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("from new backup", func(t *testing.T) {
+		request := newRequest()
+		request.ScrubCommand = []string{"sh", "-c", "./scrub.sh"}
+
+		clone, err := client.CloneInstallation(source.ID, request)
+		require.NoError(t, err)
+		require.NotEqual(t, source.ID, clone.ID)
+		require.Equal(t, request.OwnerID, clone.OwnerID)
+		require.Equal(t, request.DNS, clone.DNS)
+		require.Equal(t, source.Version, clone.Version)
+		require.Equal(t, source.Image, clone.Image)
+		require.Equal(t, source.Size, clone.Size)
+		require.Equal(t, source.Database, clone.Database)
+		require.Equal(t, source.Filestore, clone.Filestore)
+		require.Equal(t, source.MattermostEnv, clone.MattermostEnv)
+		require.Empty(t, clone.License)
+		require.Equal(t, request.ScrubCommand, clone.CloneScrubCommand)
+		require.Equal(t, model.InstallationStateCreationRequested, clone.State)
+
+		backup, err := client.GetInstallationBackup(source.ID, clone.RestoreBackupID)
+		require.NoError(t, err)
+		require.NotNil(t, backup)
+		require.Equal(t, model.BackupStateRequested, backup.State)
+	})
+
+	t.Run("from existing backup", func(t *testing.T) {
+		backup := &model.Backup{
+			InstallationID: source.ID,
+			State:          model.BackupStateSucceeded,
+		}
+		err := sqlStore.CreateBackup(backup)
+		require.NoError(t, err)
+
+		request := newRequest()
+		request.BackupID = backup.ID
+
+		clone, err := client.CloneInstallation(source.ID, request)
+		require.NoError(t, err)
+		require.Equal(t, backup.ID, clone.RestoreBackupID)
+
+		t.Run("failed backup", func(t *testing.T) {
+			backup.State = model.BackupStateFailed
+			err := sqlStore.UpdateBackup(backup)
+			require.NoError(t, err)
+
+			request := newRequest()
+			request.BackupID = backup.ID
+			_, err = client.CloneInstallation(source.ID, request)
+			require.EqualError(t, err, "failed with status code 400")
+		})
+	})
+
+	t.Run("source not stable without backup", func(t *testing.T) {
+		source.State = model.InstallationStateUpdateInProgress
+		err := sqlStore.UpdateInstallation(source)
+		require.NoError(t, err)
+
+		_, err = client.CloneInstallation(source.ID, newRequest())
+		require.EqualError(t, err, "failed with status code 400")
+	})
+}
+
+func TestMigrateInstallationDatabase(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	installation1, err := client.CreateInstallation(&model.CreateInstallationRequest{
+		OwnerID:   "owner",
+		Version:   "version",
+		DNS:       "dns.example.com",
+		Affinity:  model.InstallationAffinityIsolated,
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+	})
+	require.NoError(t, err)
+
+	installation1.State = model.InstallationStateHibernating
+	err = sqlStore.UpdateInstallation(installation1.Installation)
+	require.NoError(t, err)
+
+	request := &model.MigrateInstallationDatabaseRequest{
+		Database:  model.InstallationDatabaseSingleTenantRDSMySQL,
+		Filestore: model.InstallationFilestoreAwsS3,
+	}
+
+	t.Run("unknown installation", func(t *testing.T) {
+		installationResponse, err := client.MigrateInstallationDatabase(model.NewID(), request)
+		require.EqualError(t, err, "failed with status code 404")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("empty request", func(t *testing.T) {
+		installationResponse, err := client.MigrateInstallationDatabase(installation1.ID, &model.MigrateInstallationDatabaseRequest{})
+		require.EqualError(t, err, "failed with status code 400")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("unsupported migration", func(t *testing.T) {
+		installationResponse, err := client.MigrateInstallationDatabase(installation1.ID, &model.MigrateInstallationDatabaseRequest{
+			Database: model.InstallationDatabaseSingleTenantRDSPostgres,
+		})
+		require.EqualError(t, err, "failed with status code 400")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("while api-security-locked", func(t *testing.T) {
+		err = sqlStore.LockInstallationAPI(installation1.ID)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallationDatabase(installation1.ID, request)
+		require.EqualError(t, err, "failed with status code 403")
+		require.Nil(t, installationResponse)
+
+		err = sqlStore.UnlockInstallationAPI(installation1.ID)
+		require.NoError(t, err)
+	})
+
+	t.Run("while stable", func(t *testing.T) {
+		installation1.State = model.InstallationStateStable
+		err = sqlStore.UpdateInstallation(installation1.Installation)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallationDatabase(installation1.ID, request)
+		require.EqualError(t, err, "failed with status code 400")
+		require.Nil(t, installationResponse)
+	})
+
+	t.Run("while hibernating", func(t *testing.T) {
+		installation1.State = model.InstallationStateHibernating
+		err = sqlStore.UpdateInstallation(installation1.Installation)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallationDatabase(installation1.ID, &model.MigrateInstallationDatabaseRequest{
+			Database:  model.InstallationDatabaseMultiTenantRDSMySQL,
+			Filestore: model.InstallationFilestoreMinioOperator,
+		})
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateDBMigrationRequested, installationResponse.State)
+		require.Equal(t, model.InstallationDatabaseMultiTenantRDSMySQL, installationResponse.DatabaseMigrationTarget)
+		require.Empty(t, installationResponse.FilestoreMigrationTarget)
+
+		installation, err := client.GetInstallation(installation1.ID, &model.GetInstallationRequest{})
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateDBMigrationRequested, installation.State)
+		require.Equal(t, model.InstallationDatabaseMysqlOperator, installation.Database)
+	})
+
+	t.Run("after a failed migration", func(t *testing.T) {
+		installation1.State = model.InstallationStateDBMigrationFailed
+		err = sqlStore.UpdateInstallation(installation1.Installation)
+		require.NoError(t, err)
+
+		installationResponse, err := client.MigrateInstallationDatabase(installation1.ID, request)
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateDBMigrationRequested, installationResponse.State)
+		require.Equal(t, model.InstallationDatabaseSingleTenantRDSMySQL, installationResponse.DatabaseMigrationTarget)
+		require.Equal(t, model.InstallationFilestoreAwsS3, installationResponse.FilestoreMigrationTarget)
+	})
+}
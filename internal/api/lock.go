@@ -89,7 +89,7 @@ func lockInstallation(c *Context, installationID string) (*model.InstallationDTO
 		c.Logger.WithError(err).Error("failed to query installation")
 		return nil, http.StatusInternalServerError, nil
 	}
-	if installationDTO == nil {
+	if installationDTO == nil || !isOwnerAllowed(c, installationDTO.OwnerID) {
 		return nil, http.StatusNotFound, nil
 	}
 
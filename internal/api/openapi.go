@@ -0,0 +1,691 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// apiParameter describes a query string parameter of an API operation.
+type apiParameter struct {
+	name        string
+	kind        string
+	repeated    bool
+	description string
+}
+
+// apiOperation describes an API endpoint. The request and response are
+// values of the model types carried in the JSON bodies, or nil if there is
+// none.
+type apiOperation struct {
+	method      string
+	path        string
+	tag         string
+	summary     string
+	query       []apiParameter
+	request     interface{}
+	status      int
+	response    interface{}
+	contentType string
+}
+
+var (
+	pagingParameters = []apiParameter{
+		{"page", "integer", false, "The page to fetch, starting at 0."},
+		{"per_page", "integer", false, "The number of items per page, -1 for every item."},
+	}
+	includeDeletedParameter = apiParameter{"include_deleted", "boolean", false, "Whether to include deleted items."}
+	cursorParameter         = apiParameter{"cursor", "string", false, "The cursor returned with the previous page, in place of page."}
+	listFilterParameters    = []apiParameter{
+		{"state", "string", true, "Restrict to the given states."},
+		{"annotation", "string", true, "Restrict to items having every given annotation."},
+		{"created_after", "integer", false, "Restrict to items created after the given time, in milliseconds."},
+		{"created_before", "integer", false, "Restrict to items created before the given time, in milliseconds."},
+		{"sort", "string", false, "The key by which to sort."},
+		{"order", "string", false, "The sort order, asc or desc."},
+	}
+	groupConfigParameters = []apiParameter{
+		{"include_group_config", "boolean", false, "Whether to merge the group configuration."},
+		{"include_group_config_overrides", "boolean", false, "Whether to list the settings overridden by the group."},
+	}
+)
+
+// parameters concatenates the given parameter lists.
+func parameters(lists ...[]apiParameter) []apiParameter {
+	var all []apiParameter
+	for _, list := range lists {
+		all = append(all, list...)
+	}
+
+	return all
+}
+
+// apiOperations describes every endpoint registered by Register.
+var apiOperations = []*apiOperation{
+	{method: http.MethodGet, path: "/api/openapi.json", tag: "specification", summary: "Get the OpenAPI specification of the API.", status: http.StatusOK, response: map[string]interface{}{}},
+
+	{method: http.MethodGet, path: "/api/clusters", tag: "clusters", summary: "List clusters.", query: parameters(pagingParameters, []apiParameter{includeDeletedParameter, cursorParameter}, listFilterParameters), status: http.StatusOK, response: []*model.ClusterDTO{}},
+	{method: http.MethodPost, path: "/api/clusters", tag: "clusters", summary: "Create a cluster.", request: model.CreateClusterRequest{}, status: http.StatusAccepted, response: model.ClusterDTO{}},
+	{method: http.MethodGet, path: "/api/cluster/{cluster}", tag: "clusters", summary: "Get a cluster.", status: http.StatusOK, response: model.ClusterDTO{}},
+	{method: http.MethodPost, path: "/api/cluster/{cluster}", tag: "clusters", summary: "Retry creating a cluster.", status: http.StatusAccepted, response: model.ClusterDTO{}},
+	{method: http.MethodPut, path: "/api/cluster/{cluster}", tag: "clusters", summary: "Update the configuration of a cluster.", request: model.UpdateClusterRequest{}, status: http.StatusAccepted, response: model.ClusterDTO{}},
+	{method: http.MethodPost, path: "/api/cluster/{cluster}/provision", tag: "clusters", summary: "Provision a cluster.", request: model.ProvisionClusterRequest{}, status: http.StatusAccepted, response: model.ClusterDTO{}},
+	{method: http.MethodPut, path: "/api/cluster/{cluster}/kubernetes", tag: "clusters", summary: "Upgrade the Kubernetes version of a cluster.", request: model.PatchUpgradeClusterRequest{}, status: http.StatusAccepted, response: model.ClusterDTO{}},
+	{method: http.MethodPut, path: "/api/cluster/{cluster}/size", tag: "clusters", summary: "Resize a cluster.", request: model.PatchClusterSizeRequest{}, status: http.StatusAccepted, response: model.ClusterDTO{}},
+	{method: http.MethodPost, path: "/api/cluster/{cluster}/drain", tag: "clusters", summary: "Move the installations off a cluster.", request: model.DrainClusterRequest{}, status: http.StatusAccepted, response: model.ClusterDTO{}},
+	{method: http.MethodGet, path: "/api/cluster/{cluster}/utilities", tag: "clusters", summary: "Get the utilities of a cluster.", status: http.StatusOK, response: model.UtilityMetadata{}},
+	{method: http.MethodGet, path: "/api/cluster/{cluster}/events", tag: "clusters", summary: "List the events of a cluster.", query: pagingParameters, status: http.StatusOK, response: []*model.Event{}},
+	{method: http.MethodDelete, path: "/api/cluster/{cluster}", tag: "clusters", summary: "Delete a cluster.", status: http.StatusAccepted},
+
+	{method: http.MethodGet, path: "/api/installations", tag: "installations", summary: "List installations.", query: parameters(
+		[]apiParameter{
+			{"owner", "string", false, "Restrict to the installations of the given owner."},
+			{"group", "string", false, "Restrict to the installations of the given group."},
+			{"dns_name", "string", false, "Restrict to the installation with the given DNS name."},
+			{"version", "string", false, "Restrict to the given Mattermost version."},
+			{"image", "string", false, "Restrict to the given Mattermost image."},
+			{"database", "string", false, "Restrict to the given database type."},
+			{"filestore", "string", false, "Restrict to the given filestore type."},
+			{"size", "string", false, "Restrict to the given size."},
+			{"affinity", "string", false, "Restrict to the given affinity."},
+		},
+		pagingParameters, []apiParameter{includeDeletedParameter, cursorParameter}, listFilterParameters, groupConfigParameters,
+	), status: http.StatusOK, response: []*model.InstallationDTO{}},
+	{method: http.MethodGet, path: "/api/installations/count", tag: "installations", summary: "Count installations.", query: []apiParameter{includeDeletedParameter}, status: http.StatusOK, response: model.InstallationsCount{}},
+	{method: http.MethodPost, path: "/api/installations", tag: "installations", summary: "Create an installation.", request: model.CreateInstallationRequest{}, status: http.StatusAccepted, response: model.InstallationDTO{}},
+	{method: http.MethodGet, path: "/api/installation/{installation}", tag: "installations", summary: "Get an installation.", query: groupConfigParameters, status: http.StatusOK, response: model.InstallationDTO{}},
+	{method: http.MethodPost, path: "/api/installation/{installation}", tag: "installations", summary: "Retry creating an installation.", status: http.StatusAccepted, response: model.InstallationDTO{}},
+	{method: http.MethodPut, path: "/api/installation/{installation}/mattermost", tag: "installations", summary: "Update an installation.", request: model.PatchInstallationRequest{}, status: http.StatusAccepted, response: model.InstallationDTO{}},
+	{method: http.MethodPut, path: "/api/installation/{installation}/group/{group}", tag: "installations", summary: "Join an installation to a group.", status: http.StatusOK},
+	{method: http.MethodDelete, path: "/api/installation/{installation}/group", tag: "installations", summary: "Remove an installation from its group.", query: []apiParameter{{"retain_config", "boolean", false, "Whether to keep the group configuration."}}, status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/installation/{installation}/hibernate", tag: "installations", summary: "Hibernate an installation.", status: http.StatusAccepted, response: model.InstallationDTO{}},
+	{method: http.MethodPost, path: "/api/installation/{installation}/wakeup", tag: "installations", summary: "Wake up a hibernating installation.", status: http.StatusAccepted, response: model.InstallationDTO{}},
+	{method: http.MethodPost, path: "/api/installation/{installation}/migrate", tag: "installations", summary: "Migrate an installation to another cluster.", request: model.MigrateInstallationRequest{}, status: http.StatusAccepted, response: model.InstallationDTO{}},
+	{method: http.MethodPost, path: "/api/installation/{installation}/clone", tag: "installations", summary: "Create a new installation from a copy of an installation.", request: model.CloneInstallationRequest{}, status: http.StatusAccepted, response: model.InstallationDTO{}},
+	{method: http.MethodPost, path: "/api/installation/{installation}/database_migration", tag: "installations", summary: "Move the data of a hibernating installation to another database and filestore.", request: model.MigrateInstallationDatabaseRequest{}, status: http.StatusAccepted, response: model.InstallationDTO{}},
+	{method: http.MethodPut, path: "/api/installation/{installation}/hibernation_schedule", tag: "installations", summary: "Replace the hibernation schedule of an installation.", request: model.HibernationSchedule{}, status: http.StatusOK, response: model.InstallationDTO{}},
+	{method: http.MethodDelete, path: "/api/installation/{installation}/hibernation_schedule", tag: "installations", summary: "Remove the hibernation schedule of an installation.", status: http.StatusOK},
+	{method: http.MethodGet, path: "/api/installation/{installation}/events", tag: "installations", summary: "List the events of an installation.", query: pagingParameters, status: http.StatusOK, response: []*model.Event{}},
+	{method: http.MethodDelete, path: "/api/installation/{installation}", tag: "installations", summary: "Delete an installation.", status: http.StatusAccepted},
+
+	{method: http.MethodGet, path: "/api/cluster_installations", tag: "cluster installations", summary: "List cluster installations.", query: parameters(
+		[]apiParameter{
+			{"cluster", "string", false, "Restrict to the given cluster."},
+			{"installation", "string", false, "Restrict to the given installation."},
+		},
+		pagingParameters, []apiParameter{includeDeletedParameter, cursorParameter},
+	), status: http.StatusOK, response: []*model.ClusterInstallation{}},
+	{method: http.MethodGet, path: "/api/cluster_installation/{cluster_installation}", tag: "cluster installations", summary: "Get a cluster installation.", status: http.StatusOK, response: model.ClusterInstallation{}},
+	{method: http.MethodGet, path: "/api/cluster_installation/{cluster_installation}/config", tag: "cluster installations", summary: "Get the Mattermost configuration of a cluster installation.", status: http.StatusOK, response: model.ClusterInstallationConfigRequest{}},
+	{method: http.MethodPut, path: "/api/cluster_installation/{cluster_installation}/config", tag: "cluster installations", summary: "Update the Mattermost configuration of a cluster installation.", request: model.ClusterInstallationConfigRequest{}, status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/cluster_installation/{cluster_installation}/exec/{command}", tag: "cluster installations", summary: "Run a command in a cluster installation.", request: model.ClusterInstallationExecSubcommand{}, status: http.StatusOK, contentType: "text/plain"},
+	{method: http.MethodPost, path: "/api/cluster_installation/{cluster_installation}/mattermost_cli", tag: "cluster installations", summary: "Run a Mattermost CLI command in a cluster installation.", request: model.ClusterInstallationMattermostCLISubcommand{}, status: http.StatusOK, contentType: "text/plain"},
+	{method: http.MethodGet, path: "/api/cluster_installation/{cluster_installation}/events", tag: "cluster installations", summary: "List the events of a cluster installation.", query: pagingParameters, status: http.StatusOK, response: []*model.Event{}},
+
+	{method: http.MethodGet, path: "/api/groups", tag: "groups", summary: "List groups.", query: parameters(pagingParameters, []apiParameter{includeDeletedParameter, cursorParameter}), status: http.StatusOK, response: []*model.Group{}},
+	{method: http.MethodPost, path: "/api/groups", tag: "groups", summary: "Create a group.", request: model.CreateGroupRequest{}, status: http.StatusOK, response: model.Group{}},
+	{method: http.MethodGet, path: "/api/group/{group}", tag: "groups", summary: "Get a group.", status: http.StatusOK, response: model.Group{}},
+	{method: http.MethodPut, path: "/api/group/{group}", tag: "groups", summary: "Update a group.", request: model.PatchGroupRequest{}, status: http.StatusOK, response: model.Group{}},
+	{method: http.MethodDelete, path: "/api/group/{group}", tag: "groups", summary: "Delete a group.", status: http.StatusOK},
+	{method: http.MethodGet, path: "/api/group/{group}/status", tag: "groups", summary: "Get the rollout status of a group.", status: http.StatusOK, response: model.GroupStatus{}},
+	{method: http.MethodGet, path: "/api/group/{group}/events", tag: "groups", summary: "List the events of the installations of a group.", query: pagingParameters, status: http.StatusOK, response: []*model.Event{}},
+
+	{method: http.MethodGet, path: "/api/webhooks", tag: "webhooks", summary: "List webhooks.", query: parameters(
+		[]apiParameter{{"owner", "string", false, "Restrict to the webhooks of the given owner."}},
+		pagingParameters, []apiParameter{includeDeletedParameter, cursorParameter},
+	), status: http.StatusOK, response: []*model.Webhook{}},
+	{method: http.MethodPost, path: "/api/webhooks", tag: "webhooks", summary: "Create a webhook.", request: model.CreateWebhookRequest{}, status: http.StatusAccepted, response: model.Webhook{}},
+	{method: http.MethodGet, path: "/api/webhook/{webhook}", tag: "webhooks", summary: "Get a webhook.", status: http.StatusOK, response: model.Webhook{}},
+	{method: http.MethodDelete, path: "/api/webhook/{webhook}", tag: "webhooks", summary: "Delete a webhook.", status: http.StatusOK},
+	{method: http.MethodGet, path: "/api/webhook/{webhook}/deliveries", tag: "webhooks", summary: "List the deliveries of a webhook.", query: parameters([]apiParameter{{"state", "string", false, "Restrict to the given delivery state."}}, pagingParameters), status: http.StatusOK, response: []*model.WebhookDelivery{}},
+	{method: http.MethodPost, path: "/api/webhook/{webhook}/deliveries/replay", tag: "webhooks", summary: "Replay the failed deliveries of a webhook.", status: http.StatusAccepted, response: []*model.WebhookDelivery{}},
+
+	{method: http.MethodGet, path: "/api/databases", tag: "databases", summary: "List multitenant databases.", query: parameters(pagingParameters, []apiParameter{
+		{"vpc_id", "string", false, "Restrict to the given VPC."},
+		{"database_type", "string", false, "Restrict to the given database type."},
+	}), status: http.StatusOK, response: []*model.MultitenantDatabase{}},
+
+	{method: http.MethodGet, path: "/api/events/stream", tag: "events", summary: "Stream events as server-sent events.", query: []apiParameter{
+		{"type", "string", false, "Restrict to events of the given resource type."},
+		{"id", "string", false, "Restrict to events of the given resource."},
+	}, status: http.StatusOK, contentType: "text/event-stream"},
+
+	{method: http.MethodGet, path: "/api/audit", tag: "audit", summary: "List audit records.", query: parameters([]apiParameter{
+		{"resource_type", "string", false, "Restrict to the given resource type."},
+		{"resource_id", "string", false, "Restrict to the given resource."},
+		{"start_time", "integer", false, "Restrict to records at or after the given time, in milliseconds."},
+		{"end_time", "integer", false, "Restrict to records at or before the given time, in milliseconds."},
+	}, pagingParameters), status: http.StatusOK, response: []*model.AuditRecord{}},
+
+	{method: http.MethodGet, path: "/api/bulk_operations", tag: "bulk operations", summary: "List bulk operations.", query: parameters([]apiParameter{{"state", "string", false, "Restrict to the given state."}}, pagingParameters), status: http.StatusOK, response: []*model.BulkOperation{}},
+	{method: http.MethodPost, path: "/api/bulk_operations", tag: "bulk operations", summary: "Apply an action to the installations matching a filter.", request: model.CreateBulkOperationRequest{}, status: http.StatusAccepted, response: model.BulkOperation{}},
+	{method: http.MethodGet, path: "/api/bulk_operation/{bulk_operation}", tag: "bulk operations", summary: "Get a bulk operation and its results.", status: http.StatusOK, response: model.BulkOperation{}},
+
+	{method: http.MethodGet, path: "/api/installation/{installation}/backups", tag: "backups", summary: "List the backups of an installation.", query: parameters([]apiParameter{{"state", "string", false, "Restrict to the given state."}}, pagingParameters), status: http.StatusOK, response: []*model.Backup{}},
+	{method: http.MethodPost, path: "/api/installation/{installation}/backups", tag: "backups", summary: "Back up the database and filestore of an installation.", status: http.StatusAccepted, response: model.Backup{}},
+	{method: http.MethodGet, path: "/api/installation/{installation}/backup/{backup}", tag: "backups", summary: "Get a backup of an installation.", status: http.StatusOK, response: model.Backup{}},
+	{method: http.MethodPost, path: "/api/installation/{installation}/backup/{backup}/restore", tag: "backups", summary: "Restore a hibernating installation from one of its backups.", status: http.StatusAccepted, response: model.InstallationDTO{}},
+
+	{method: http.MethodPost, path: "/api/security/cluster/{cluster}/api/lock", tag: "security", summary: "Lock the API of a cluster.", status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/security/cluster/{cluster}/api/unlock", tag: "security", summary: "Unlock the API of a cluster.", status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/security/installation/{installation}/api/lock", tag: "security", summary: "Lock the API of an installation.", status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/security/installation/{installation}/api/unlock", tag: "security", summary: "Unlock the API of an installation.", status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/security/cluster_installation/{cluster_installation}/api/lock", tag: "security", summary: "Lock the API of a cluster installation.", status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/security/cluster_installation/{cluster_installation}/api/unlock", tag: "security", summary: "Unlock the API of a cluster installation.", status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/security/group/{group}/api/lock", tag: "security", summary: "Lock the API of a group.", status: http.StatusOK},
+	{method: http.MethodPost, path: "/api/security/group/{group}/api/unlock", tag: "security", summary: "Unlock the API of a group.", status: http.StatusOK},
+}
+
+// openAPISchema is the subset of the OpenAPI schema object generated from
+// model types.
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+// OpenAPIDocument is an OpenAPI 3 document describing the API.
+type OpenAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       map[string]string                      `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+	Security   []map[string][]string                  `json:"security"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema    `json:"schemas"`
+	SecuritySchemes map[string]map[string]string `json:"securitySchemes"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Tags        []string                   `json:"tags"`
+	Summary     string                     `json:"summary"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIBody               `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+// openAPISpecification is the document served by the API, generated once
+// from apiOperations.
+var openAPISpecification = NewOpenAPIDocument()
+
+// NewOpenAPIDocument generates the OpenAPI document of the API from the
+// endpoint descriptions and the model types they exchange.
+func NewOpenAPIDocument() *OpenAPIDocument {
+	generator := &schemaGenerator{schemas: make(map[string]*openAPISchema)}
+	document := &OpenAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: map[string]string{
+			"title":       "Mattermost Cloud Provisioner",
+			"description": "Provision and manage Kubernetes clusters and Mattermost installations.",
+			"version":     "1.0.0",
+		},
+		Paths: make(map[string]map[string]openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: generator.schemas,
+			SecuritySchemes: map[string]map[string]string{
+				"apiKey": {"type": "http", "scheme": "bearer"},
+			},
+		},
+		Security: []map[string][]string{{"apiKey": {}}},
+	}
+
+	for _, operation := range apiOperations {
+		if document.Paths[operation.path] == nil {
+			document.Paths[operation.path] = make(map[string]openAPIOperation)
+		}
+		document.Paths[operation.path][strings.ToLower(operation.method)] = operation.toOpenAPI(generator)
+	}
+
+	return document
+}
+
+// toOpenAPI converts the operation description to an OpenAPI operation,
+// generating the schemas of the model types it exchanges.
+func (o *apiOperation) toOpenAPI(generator *schemaGenerator) openAPIOperation {
+	operation := openAPIOperation{
+		OperationID: operationID(o.method, o.path),
+		Tags:        []string{o.tag},
+		Summary:     o.summary,
+		Responses:   make(map[string]openAPIResponse),
+	}
+
+	for _, name := range pathParameters(o.path) {
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &openAPISchema{Type: "string"},
+		})
+	}
+	for _, parameter := range o.query {
+		schema := &openAPISchema{Type: parameter.kind}
+		if parameter.repeated {
+			schema = &openAPISchema{Type: "array", Items: schema}
+		}
+		operation.Parameters = append(operation.Parameters, openAPIParameter{
+			Name:        parameter.name,
+			In:          "query",
+			Description: parameter.description,
+			Schema:      schema,
+		})
+	}
+
+	if o.request != nil {
+		operation.RequestBody = &openAPIBody{
+			Content: map[string]openAPIMediaType{
+				"application/json": {Schema: generator.schemaFor(reflect.TypeOf(o.request))},
+			},
+		}
+	}
+
+	response := openAPIResponse{Description: http.StatusText(o.status)}
+	if o.response != nil {
+		response.Content = map[string]openAPIMediaType{
+			"application/json": {Schema: generator.schemaFor(reflect.TypeOf(o.response))},
+		}
+	} else if o.contentType != "" {
+		response.Content = map[string]openAPIMediaType{
+			o.contentType: {Schema: &openAPISchema{Type: "string"}},
+		}
+	}
+	operation.Responses[fmt.Sprintf("%d", o.status)] = response
+
+	if len(o.query) > 0 || o.request != nil {
+		operation.Responses["400"] = openAPIResponse{Description: http.StatusText(http.StatusBadRequest)}
+	}
+	if len(pathParameters(o.path)) > 0 {
+		operation.Responses["404"] = openAPIResponse{Description: http.StatusText(http.StatusNotFound)}
+	}
+
+	return operation
+}
+
+// operationID derives a unique operation identifier from the method and path,
+// e.g. get_cluster_events for GET /api/cluster/{cluster}/events.
+func operationID(method, path string) string {
+	words := []string{strings.ToLower(method)}
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "/api/"), "/") {
+		if strings.HasPrefix(segment, "{") {
+			continue
+		}
+		words = append(words, strings.NewReplacer(".", "_").Replace(segment))
+	}
+
+	return strings.Join(words, "_")
+}
+
+// pathParameters returns the names of the parameters of the given path.
+func pathParameters(path string) []string {
+	var names []string
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			names = append(names, segment[1:len(segment)-1])
+		}
+	}
+
+	return names
+}
+
+// openAPIPath converts a route path template, whose variables may carry a
+// pattern, to an OpenAPI path.
+func openAPIPath(template string) string {
+	var path strings.Builder
+	depth := 0
+	skipping := false
+	for _, char := range template {
+		switch {
+		case char == '{':
+			depth++
+			if depth == 1 {
+				path.WriteRune(char)
+			}
+		case char == '}':
+			depth--
+			if depth == 0 {
+				skipping = false
+				path.WriteRune(char)
+			}
+		case char == ':' && depth == 1:
+			skipping = true
+		case !skipping && depth <= 1:
+			path.WriteRune(char)
+		}
+	}
+
+	return path.String()
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	modelPackage      = reflect.TypeOf(model.Cluster{}).PkgPath()
+)
+
+// schemaGenerator generates schemas from Go types following the rules of
+// encoding/json. Exported model structs become named components.
+type schemaGenerator struct {
+	schemas map[string]*openAPISchema
+}
+
+func (g *schemaGenerator) schemaFor(t reflect.Type) *openAPISchema {
+	if t.Kind() == reflect.Ptr {
+		schema := g.schemaFor(t.Elem())
+		if schema.Ref != "" {
+			return schema
+		}
+		nullable := *schema
+		nullable.Nullable = true
+		return &nullable
+	}
+
+	switch {
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType):
+		return &openAPISchema{}
+	case t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return &openAPISchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t.PkgPath() != modelPackage || t.Name() == "" || !isExported(t.Name()) {
+			return g.structSchema(t)
+		}
+		if _, ok := g.schemas[t.Name()]; !ok {
+			// Register the name before generating the properties so that
+			// recursive types refer to themselves.
+			g.schemas[t.Name()] = &openAPISchema{}
+			*g.schemas[t.Name()] = *g.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + t.Name()}
+	default:
+		return &openAPISchema{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *openAPISchema {
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	g.addProperties(schema, t)
+
+	return schema
+}
+
+// addProperties adds the JSON properties of the given struct type to the
+// schema, flattening embedded structs as encoding/json does.
+func (g *schemaGenerator) addProperties(schema *openAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && name == "" {
+			g.addProperties(schema, fieldType)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		schema.Properties[name] = g.schemaFor(field.Type)
+	}
+}
+
+func isExported(name string) bool {
+	return strings.ToUpper(name[:1]) == name[:1]
+}
+
+// initOpenAPI registers the API specification endpoint on the given router.
+func initOpenAPI(apiRouter *mux.Router, context *Context) {
+	addContext := func(handler contextHandlerFunc) *contextHandler {
+		return newContextHandler(context, handler)
+	}
+
+	apiRouter.Handle("/openapi.json", addContext(handleGetOpenAPI)).Methods("GET")
+}
+
+// handleGetOpenAPI responds to GET /api/openapi.json, returning the OpenAPI
+// specification of the API.
+func handleGetOpenAPI(c *Context, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, openAPISpecification)
+}
+
+// documentedOperations indexes the operations by method and path.
+var documentedOperations = func() map[string]*apiOperation {
+	operations := make(map[string]*apiOperation)
+	for _, operation := range apiOperations {
+		operations[operation.method+" "+operation.path] = operation
+	}
+
+	return operations
+}()
+
+// validateRequest rejects requests whose query parameters or JSON body do not
+// match the OpenAPI specification of the matched endpoint, returning whether
+// the request may be served. Bodies that are not JSON at all are left to the
+// handlers.
+func validateRequest(c *Context, w http.ResponseWriter, r *http.Request) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return true
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return true
+	}
+	operation, ok := documentedOperations[r.Method+" "+openAPIPath(template)]
+	if !ok {
+		return true
+	}
+
+	err = operation.validateQuery(r.URL.Query())
+	if err != nil {
+		c.Logger.WithError(err).Error("request does not match the API specification")
+		w.WriteHeader(http.StatusBadRequest)
+		return false
+	}
+
+	if operation.request != nil && r.Body != nil {
+		body, err := ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			c.Logger.WithError(err).Error("failed to read request body")
+			w.WriteHeader(http.StatusBadRequest)
+			return false
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		err = operation.validateBody(body)
+		if err != nil {
+			c.Logger.WithError(err).Error("request does not match the API specification")
+			w.WriteHeader(http.StatusBadRequest)
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateQuery checks that the typed query parameters of the operation
+// parse as their declared type.
+func (o *apiOperation) validateQuery(query url.Values) error {
+	for _, parameter := range o.query {
+		for _, value := range query[parameter.name] {
+			var err error
+			switch parameter.kind {
+			case "integer":
+				_, err = strconv.ParseInt(value, 10, 64)
+			case "boolean":
+				_, err = strconv.ParseBool(value)
+			}
+			if err != nil {
+				return errors.Wrapf(err, "query parameter %s must be of type %s", parameter.name, parameter.kind)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBody checks that the given JSON body matches the schema of the
+// request of the operation.
+func (o *apiOperation) validateBody(body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil
+	}
+
+	schema := openAPISpecification.Paths[o.path][strings.ToLower(o.method)].RequestBody.Content["application/json"].Schema
+
+	return validateValue(schema, value, "body")
+}
+
+// validateValue checks that the given decoded JSON value matches the schema.
+// As with encoding/json, null matches every schema, unknown properties are
+// ignored and property names match case-insensitively.
+func validateValue(schema *openAPISchema, value interface{}, path string) error {
+	if value == nil {
+		return nil
+	}
+	if schema.Ref != "" {
+		referenced, ok := openAPISpecification.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		if !ok {
+			return errors.Errorf("unknown schema %s", schema.Ref)
+		}
+		return validateValue(referenced, value, path)
+	}
+
+	switch schema.Type {
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return errors.Errorf("%s must be a boolean", path)
+		}
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return errors.Errorf("%s must be an integer", path)
+		}
+		if _, err := number.Int64(); err != nil {
+			return errors.Errorf("%s must be an integer", path)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return errors.Errorf("%s must be a number", path)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return errors.Errorf("%s must be a string", path)
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return errors.Errorf("%s must be an array", path)
+		}
+		for i, item := range items {
+			err := validateValue(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("%s must be an object", path)
+		}
+		for key, property := range object {
+			propertySchema := schema.AdditionalProperties
+			for name, candidate := range schema.Properties {
+				if strings.EqualFold(name, key) {
+					propertySchema = candidate
+					break
+				}
+			}
+			if propertySchema == nil {
+				continue
+			}
+			err := validateValue(propertySchema, property, path+"."+key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
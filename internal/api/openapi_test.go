@@ -0,0 +1,147 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+// routePattern matches the pattern of a route variable, e.g. the
+// ":[A-Za-z0-9]{26}" of "{cluster:[A-Za-z0-9]{26}}".
+var routePattern = regexp.MustCompile(`:[^{}]*(\{\d+\})?[^{}]*\}`)
+
+func TestOpenAPI(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/openapi.json")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var document api.OpenAPIDocument
+	err = json.NewDecoder(resp.Body).Decode(&document)
+	require.NoError(t, err)
+	require.Equal(t, "3.0.3", document.OpenAPI)
+
+	t.Run("served document matches the generated one", func(t *testing.T) {
+		expected, err := json.Marshal(api.NewOpenAPIDocument())
+		require.NoError(t, err)
+		actual, err := json.Marshal(document)
+		require.NoError(t, err)
+		require.JSONEq(t, string(expected), string(actual))
+	})
+
+	t.Run("every route is documented", func(t *testing.T) {
+		var routes []string
+		err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+			if route.GetHandler() == nil {
+				return nil
+			}
+			template, err := route.GetPathTemplate()
+			require.NoError(t, err)
+			methods, err := route.GetMethods()
+			require.NoError(t, err)
+			for _, method := range methods {
+				routes = append(routes, method+" "+routePattern.ReplaceAllString(template, "}"))
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		var documented []string
+		for path, operations := range document.Paths {
+			for method := range operations {
+				documented = append(documented, strings.ToUpper(method)+" "+path)
+			}
+		}
+
+		sort.Strings(routes)
+		sort.Strings(documented)
+		require.Equal(t, routes, documented)
+	})
+
+	t.Run("every schema reference resolves", func(t *testing.T) {
+		data, err := json.Marshal(document)
+		require.NoError(t, err)
+
+		references := regexp.MustCompile(`"\$ref":"#/components/schemas/(\w+)"`).FindAllStringSubmatch(string(data), -1)
+		require.NotEmpty(t, references)
+		for _, reference := range references {
+			require.Contains(t, document.Components.Schemas, reference[1])
+		}
+	})
+
+	t.Run("model types are documented", func(t *testing.T) {
+		for _, name := range []string{"ClusterDTO", "CreateInstallationRequest", "InstallationDTO", "Group", "Webhook", "BulkOperation"} {
+			require.Contains(t, document.Components.Schemas, name)
+		}
+
+		data, err := json.Marshal(document.Components.Schemas["CreateInstallationRequest"])
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"DNS":{"type":"string"}`)
+	})
+
+	t.Run("invalid query parameter", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/installations?page=first")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		resp, err = http.Get(ts.URL + "/api/clusters?include_deleted=maybe")
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		for _, body := range []string{
+			`{"OwnerID": 42, "DNS": "dns.example.com"}`,
+			`{"OwnerID": "owner", "DNS": "dns.example.com", "Annotations": "annotation"}`,
+			`{"OwnerID": "owner", "DNS": "dns.example.com", "MattermostEnv": {"KEY": {"Value": 1}}}`,
+		} {
+			resp, err := http.Post(ts.URL+"/api/installations", "application/json", bytes.NewReader([]byte(body)))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode, body)
+		}
+	})
+
+	t.Run("valid request body", func(t *testing.T) {
+		client := model.NewClient(ts.URL)
+
+		group, err := client.CreateGroup(&model.CreateGroupRequest{Name: "group", MaxRolling: 5})
+		require.NoError(t, err)
+
+		request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/group/%s", ts.URL, group.ID), bytes.NewReader([]byte(`{"name": "renamed", "maxrolling": 2}`)))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
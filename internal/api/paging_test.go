@@ -0,0 +1,199 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/mattermost/mattermost-cloud/internal/api"
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorPaging(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	var installationIDs []string
+	for i := 0; i < 5; i++ {
+		installation := &model.Installation{
+			OwnerID: "owner",
+			DNS:     fmt.Sprintf("dns%d.example.com", i),
+			Version: "5.31.0",
+			State:   model.InstallationStateStable,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+		installationIDs = append(installationIDs, installation.ID)
+
+		err = sqlStore.CreateGroup(&model.Group{Name: fmt.Sprintf("group%d", i)})
+		require.NoError(t, err)
+	}
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		for _, path := range []string{"clusters", "installations", "cluster_installations", "groups", "webhooks"} {
+			resp, err := http.Get(fmt.Sprintf("%s/api/%s?cursor=invalid", ts.URL, path))
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode, path)
+		}
+	})
+
+	t.Run("page headers", func(t *testing.T) {
+		installations, pageInfo, err := client.GetInstallationsPage(&model.GetInstallationsRequest{
+			PerPage:   2,
+			SortBy:    model.SortByDNS,
+			SortOrder: model.SortOrderDescending,
+		})
+		require.NoError(t, err)
+		require.Len(t, installations, 2)
+		require.Equal(t, "dns4.example.com", installations[0].DNS)
+		require.EqualValues(t, 5, pageInfo.TotalCount)
+		require.NotEmpty(t, pageInfo.NextCursor)
+
+		installations, pageInfo, err = client.GetInstallationsPage(&model.GetInstallationsRequest{
+			PerPage:   2,
+			Cursor:    pageInfo.NextCursor,
+			SortBy:    model.SortByDNS,
+			SortOrder: model.SortOrderDescending,
+		})
+		require.NoError(t, err)
+		require.Len(t, installations, 2)
+		require.Equal(t, "dns2.example.com", installations[0].DNS)
+		require.EqualValues(t, 5, pageInfo.TotalCount)
+
+		installations, pageInfo, err = client.GetInstallationsPage(&model.GetInstallationsRequest{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, installations, 5)
+		require.EqualValues(t, 5, pageInfo.TotalCount)
+		require.Empty(t, pageInfo.NextCursor)
+	})
+
+	t.Run("walk installations", func(t *testing.T) {
+		var walked []string
+		err := client.WalkInstallations(&model.GetInstallationsRequest{PerPage: 2}, func(installation *model.InstallationDTO) error {
+			walked = append(walked, installation.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, installationIDs, walked)
+	})
+
+	t.Run("walk groups", func(t *testing.T) {
+		var walked int
+		err := client.WalkGroups(&model.GetGroupsRequest{PerPage: 3}, func(group *model.Group) error {
+			walked++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5, walked)
+	})
+
+	t.Run("walk stops on error", func(t *testing.T) {
+		var walked int
+		err := client.WalkInstallations(&model.GetInstallationsRequest{PerPage: 2}, func(installation *model.InstallationDTO) error {
+			walked++
+			if walked == 3 {
+				return fmt.Errorf("stop")
+			}
+			return nil
+		})
+		require.EqualError(t, err, "stop")
+		require.Equal(t, 3, walked)
+	})
+
+	t.Run("walk empty lists", func(t *testing.T) {
+		err := client.WalkClusters(&model.GetClustersRequest{}, func(cluster *model.ClusterDTO) error {
+			return fmt.Errorf("unexpected cluster")
+		})
+		require.NoError(t, err)
+
+		err = client.WalkWebhooks(&model.GetWebhooksRequest{}, func(webhook *model.Webhook) error {
+			return fmt.Errorf("unexpected webhook")
+		})
+		require.NoError(t, err)
+
+		err = client.WalkClusterInstallations(&model.GetClusterInstallationsRequest{}, func(clusterInstallation *model.ClusterInstallation) error {
+			return fmt.Errorf("unexpected cluster installation")
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestCursorPagingWithGroupConfig(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	defer store.CloseConnection(t, sqlStore)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	// The group version sorts before every installation version, so cursors
+	// built from merged installations would restart from the first page.
+	group := &model.Group{Name: "group", Version: "4.0.0"}
+	err := sqlStore.CreateGroup(group)
+	require.NoError(t, err)
+
+	var installationIDs []string
+	for i := 0; i < 6; i++ {
+		installation := &model.Installation{
+			OwnerID: "owner",
+			DNS:     fmt.Sprintf("dns%d.example.com", i),
+			Version: fmt.Sprintf("5.3%d.0", i/2),
+			State:   model.InstallationStateStable,
+		}
+		if i%2 == 0 {
+			installation.GroupID = &group.ID
+		}
+		err = sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+		installationIDs = append(installationIDs, installation.ID)
+	}
+
+	for _, sortOrder := range []string{model.SortOrderAscending, model.SortOrderDescending} {
+		t.Run(sortOrder, func(t *testing.T) {
+			var walked []string
+			err := client.WalkInstallations(&model.GetInstallationsRequest{
+				IncludeGroupConfig: true,
+				PerPage:            2,
+				SortBy:             model.SortByVersion,
+				SortOrder:          sortOrder,
+			}, func(installation *model.InstallationDTO) error {
+				walked = append(walked, installation.ID)
+				if len(walked) > len(installationIDs) {
+					return fmt.Errorf("walked more installations than exist")
+				}
+				return nil
+			})
+			require.NoError(t, err)
+			require.ElementsMatch(t, installationIDs, walked)
+		})
+	}
+}
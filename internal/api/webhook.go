@@ -19,11 +19,13 @@ func initWebhook(apiRouter *mux.Router, context *Context) {
 
 	webhooksRouter := apiRouter.PathPrefix("/webhooks").Subrouter()
 	webhooksRouter.Handle("", addContext(handleGetWebhooks)).Methods("GET")
-	webhooksRouter.Handle("", addContext(handleCreateWebhook)).Methods("POST")
+	webhooksRouter.Handle("", addContext(idempotent(handleCreateWebhook))).Methods("POST")
 
 	webhookRouter := apiRouter.PathPrefix("/webhook/{webhook:[A-Za-z0-9]{26}}").Subrouter()
 	webhookRouter.Handle("", addContext(handleGetWebhook)).Methods("GET")
 	webhookRouter.Handle("", addContext(handleDeleteWebhook)).Methods("DELETE")
+	webhookRouter.Handle("/deliveries", addContext(handleGetWebhookDeliveries)).Methods("GET")
+	webhookRouter.Handle("/deliveries/replay", addContext(handleReplayWebhookDeliveries)).Methods("POST")
 }
 
 // handleCreateWebhook responds to POST /api/webhooks, creating a new webhook.
@@ -35,9 +37,28 @@ func handleCreateWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !isOwnerAllowed(c, createWebhookRequest.OwnerID) {
+		c.Logger.Warnf("unable to create webhook for owner %s", createWebhookRequest.OwnerID)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	// Webhooks created for a single owner must only receive that owner's events.
+	if boundOwnerID := boundOwnerID(c); boundOwnerID != "" {
+		if createWebhookRequest.EventOwnerID != "" && createWebhookRequest.EventOwnerID != boundOwnerID {
+			c.Logger.Warnf("unable to create webhook for events of owner %s", createWebhookRequest.EventOwnerID)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		createWebhookRequest.EventOwnerID = boundOwnerID
+	}
+
 	webhook := model.Webhook{
-		OwnerID: createWebhookRequest.OwnerID,
-		URL:     createWebhookRequest.URL,
+		OwnerID:      createWebhookRequest.OwnerID,
+		URL:          createWebhookRequest.URL,
+		Secret:       createWebhookRequest.Secret,
+		EventTypes:   createWebhookRequest.EventTypes,
+		EventStates:  createWebhookRequest.EventStates,
+		EventOwnerID: createWebhookRequest.EventOwnerID,
 	}
 
 	err = c.Store.CreateWebhook(&webhook)
@@ -64,7 +85,7 @@ func handleGetWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	if webhook == nil {
+	if webhook == nil || !isOwnerAllowed(c, webhook.OwnerID) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
@@ -77,7 +98,12 @@ func handleGetWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 // handleGetWebhooks responds to GET /api/webhooks, returning the specified page of webhooks.
 func handleGetWebhooks(c *Context, w http.ResponseWriter, r *http.Request) {
 	var err error
-	owner := r.URL.Query().Get("owner")
+	owner, ok := ownerFilter(c, r.URL.Query().Get("owner"))
+	if !ok {
+		c.Logger.Warn("unable to list webhooks of another owner")
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
 
 	page, perPage, includeDeleted, err := parsePaging(r.URL)
 	if err != nil {
@@ -86,10 +112,18 @@ func handleGetWebhooks(c *Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cursor, err := parseCursor(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse cursor")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	filter := &model.WebhookFilter{
 		OwnerID:        owner,
 		Page:           page,
 		PerPage:        perPage,
+		Cursor:         cursor,
 		IncludeDeleted: includeDeleted,
 	}
 
@@ -103,7 +137,21 @@ func handleGetWebhooks(c *Context, w http.ResponseWriter, r *http.Request) {
 		webhooks = []*model.Webhook{}
 	}
 
+	totalCount, err := c.Store.CountWebhooks(filter)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to count webhooks")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	var nextCursor *model.PageCursor
+	if hasNextPage(perPage, len(webhooks)) {
+		last := webhooks[len(webhooks)-1]
+		nextCursor = &model.PageCursor{CreateAt: last.CreateAt, ID: last.ID}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	setPageHeaders(w, totalCount, nextCursor)
 	w.WriteHeader(http.StatusOK)
 	outputJSON(c, w, webhooks)
 }
@@ -120,7 +168,7 @@ func handleDeleteWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	if webhook == nil {
+	if webhook == nil || !isOwnerAllowed(c, webhook.OwnerID) {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
@@ -139,3 +187,105 @@ func handleDeleteWebhook(c *Context, w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusOK)
 }
+
+// handleGetWebhookDeliveries responds to GET /api/webhook/{webhook}/deliveries,
+// returning the specified page of deliveries to the webhook.
+func handleGetWebhookDeliveries(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhookID := vars["webhook"]
+	c.Logger = c.Logger.WithField("webhook", webhookID)
+
+	page, perPage, _, err := parsePaging(r.URL)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to parse paging parameters")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	webhook, err := c.Store.GetWebhook(webhookID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query webhook")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if webhook == nil || !isOwnerAllowed(c, webhook.OwnerID) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	deliveries, err := c.Store.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+		WebhookID: webhookID,
+		State:     r.URL.Query().Get("state"),
+		Page:      page,
+		PerPage:   perPage,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query webhook deliveries")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*model.WebhookDelivery{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	outputJSON(c, w, deliveries)
+}
+
+// handleReplayWebhookDeliveries responds to POST /api/webhook/{webhook}/deliveries/replay,
+// scheduling all failed deliveries to the webhook to be attempted again.
+func handleReplayWebhookDeliveries(c *Context, w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhookID := vars["webhook"]
+	c.Logger = c.Logger.WithField("webhook", webhookID)
+
+	webhook, err := c.Store.GetWebhook(webhookID)
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query webhook")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if webhook == nil || !isOwnerAllowed(c, webhook.OwnerID) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if webhook.IsDeleted() {
+		c.Logger.Warn("unable to replay deliveries to a deleted webhook")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	deliveries, err := c.Store.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+		WebhookID: webhookID,
+		State:     model.WebhookDeliveryStateFailed,
+		PerPage:   model.AllPerPage,
+	})
+	if err != nil {
+		c.Logger.WithError(err).Error("failed to query webhook deliveries")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*model.WebhookDelivery{}
+	}
+
+	for _, delivery := range deliveries {
+		delivery.State = model.WebhookDeliveryStatePending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = 0
+		err = c.Store.UpdateWebhookDelivery(delivery)
+		if err != nil {
+			c.Logger.WithError(err).Errorf("failed to replay webhook delivery %s", delivery.ID)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	c.Logger.Infof("Replaying %d failed webhook deliveries", len(deliveries))
+	c.Supervisor.Do()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	outputJSON(c, w, deliveries)
+}
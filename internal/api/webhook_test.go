@@ -81,6 +81,53 @@ func TestCreateWebhook(t *testing.T) {
 		require.NotEqual(t, 0, webhook.CreateAt)
 		require.EqualValues(t, 0, webhook.DeleteAt)
 	})
+
+	t.Run("invalid event type", func(t *testing.T) {
+		_, err := client.CreateWebhook(&model.CreateWebhookRequest{
+			OwnerID:    "owner",
+			URL:        "https://validurl.com",
+			EventTypes: []string{"unknown"},
+		})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("invalid event state pattern", func(t *testing.T) {
+		_, err := client.CreateWebhook(&model.CreateWebhookRequest{
+			OwnerID:     "owner",
+			URL:         "https://validurl.com",
+			EventStates: []string{"creation-["},
+		})
+		require.EqualError(t, err, "failed with status code 400")
+	})
+
+	t.Run("valid with event filters", func(t *testing.T) {
+		webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
+			OwnerID:      "owner",
+			URL:          "https://validurl3.com",
+			EventTypes:   []string{model.TypeInstallation},
+			EventStates:  []string{"creation-*"},
+			EventOwnerID: "tenant",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{model.TypeInstallation}, webhook.EventTypes)
+		require.Equal(t, []string{"creation-*"}, webhook.EventStates)
+		require.Equal(t, "tenant", webhook.EventOwnerID)
+	})
+
+	t.Run("valid with secret", func(t *testing.T) {
+		webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
+			OwnerID: "owner",
+			URL:     "https://validurl2.com",
+			Secret:  "secret",
+		})
+		require.NoError(t, err)
+		require.Empty(t, webhook.Secret)
+
+		storedWebhook, err := sqlStore.GetWebhook(webhook.ID)
+		require.NoError(t, err)
+		require.Equal(t, "secret", storedWebhook.Secret)
+		require.True(t, storedWebhook.IsSigned())
+	})
 }
 
 func TestGetWebhooks(t *testing.T) {
@@ -307,3 +354,146 @@ func TestDeleteWebhook(t *testing.T) {
 		require.True(t, webhook.IsDeleted())
 	})
 }
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
+		OwnerID: "owner",
+		URL:     "https://validurl.com",
+	})
+	require.NoError(t, err)
+
+	t.Run("unknown webhook", func(t *testing.T) {
+		_, err := client.GetWebhookDeliveries(model.NewID(), &model.GetWebhookDeliveriesRequest{PerPage: 10})
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("no deliveries", func(t *testing.T) {
+		deliveries, err := client.GetWebhookDeliveries(webhook.ID, &model.GetWebhookDeliveriesRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+
+	delivery1 := &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Payload:   "payload1",
+		State:     model.WebhookDeliveryStatePending,
+	}
+	err = sqlStore.CreateWebhookDelivery(delivery1)
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	delivery2 := &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Payload:   "payload2",
+		State:     model.WebhookDeliveryStateFailed,
+	}
+	err = sqlStore.CreateWebhookDelivery(delivery2)
+	require.NoError(t, err)
+
+	t.Run("all deliveries", func(t *testing.T) {
+		deliveries, err := client.GetWebhookDeliveries(webhook.ID, &model.GetWebhookDeliveriesRequest{PerPage: 10})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{delivery2, delivery1}, deliveries)
+	})
+
+	t.Run("filter by state", func(t *testing.T) {
+		deliveries, err := client.GetWebhookDeliveries(webhook.ID, &model.GetWebhookDeliveriesRequest{
+			State:   model.WebhookDeliveryStateFailed,
+			PerPage: 10,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{delivery2}, deliveries)
+	})
+
+	t.Run("invalid paging", func(t *testing.T) {
+		resp, err := http.Get(fmt.Sprintf("%s/api/webhook/%s/deliveries?page=invalid", ts.URL, webhook.ID))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestReplayWebhookDeliveries(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+
+	router := mux.NewRouter()
+	api.Register(router, &api.Context{
+		Store:      sqlStore,
+		Supervisor: &mockSupervisor{},
+		Logger:     logger,
+	})
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	client := model.NewClient(ts.URL)
+
+	webhook, err := client.CreateWebhook(&model.CreateWebhookRequest{
+		OwnerID: "owner",
+		URL:     "https://validurl.com",
+	})
+	require.NoError(t, err)
+
+	pending := &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Payload:   "payload1",
+		State:     model.WebhookDeliveryStatePending,
+		Attempts:  1,
+	}
+	err = sqlStore.CreateWebhookDelivery(pending)
+	require.NoError(t, err)
+
+	failed := &model.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		Payload:       "payload2",
+		State:         model.WebhookDeliveryStateFailed,
+		Attempts:      10,
+		NextAttemptAt: 100,
+	}
+	err = sqlStore.CreateWebhookDelivery(failed)
+	require.NoError(t, err)
+
+	t.Run("unknown webhook", func(t *testing.T) {
+		_, err := client.ReplayWebhookDeliveries(model.NewID())
+		require.EqualError(t, err, "failed with status code 404")
+	})
+
+	t.Run("replay failed deliveries", func(t *testing.T) {
+		replayed, err := client.ReplayWebhookDeliveries(webhook.ID)
+		require.NoError(t, err)
+		require.Len(t, replayed, 1)
+		require.Equal(t, failed.ID, replayed[0].ID)
+
+		delivery, err := sqlStore.GetWebhookDelivery(failed.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliveryStatePending, delivery.State)
+		require.Equal(t, 0, delivery.Attempts)
+		require.EqualValues(t, 0, delivery.NextAttemptAt)
+
+		delivery, err = sqlStore.GetWebhookDelivery(pending.ID)
+		require.NoError(t, err)
+		require.Equal(t, 1, delivery.Attempts)
+	})
+
+	t.Run("deleted webhook", func(t *testing.T) {
+		err := client.DeleteWebhook(webhook.ID)
+		require.NoError(t, err)
+
+		_, err = client.ReplayWebhookDeliveries(webhook.ID)
+		require.EqualError(t, err, "failed with status code 400")
+	})
+}
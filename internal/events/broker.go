@@ -0,0 +1,100 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"sync"
+
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// subscriberBufferSize is the number of events buffered for each subscriber.
+// Events published to a subscriber with a full buffer are dropped rather than
+// blocking the publisher.
+const subscriberBufferSize = 100
+
+// DefaultBroker is the broker to which state transitions of this process are
+// published.
+//
+// Brokers are in-memory: subscribers only receive the events published by
+// their own process. When several provisioning servers share a database, a
+// subscriber misses the transitions made by the others, which remain
+// available from the stored event history.
+var DefaultBroker = NewBroker()
+
+// Broker fans out published events to every matching subscriber.
+type Broker struct {
+	lock        sync.RWMutex
+	subscribers map[*Subscription]struct{}
+}
+
+// Subscription is a live feed of events matching a filter.
+type Subscription struct {
+	broker *Broker
+	filter model.EventStreamFilter
+	events chan *model.WebhookPayload
+	once   sync.Once
+}
+
+// NewBroker creates a new Broker.
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish sends the given event to every subscriber whose filter matches it.
+func (b *Broker) Publish(payload *model.WebhookPayload) {
+	if payload == nil {
+		return
+	}
+
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	for subscription := range b.subscribers {
+		if !subscription.filter.Matches(payload) {
+			continue
+		}
+
+		select {
+		case subscription.events <- payload:
+		default:
+		}
+	}
+}
+
+// Subscribe starts a subscription to events matching the given filter. The
+// subscription must be closed once it is no longer needed.
+func (b *Broker) Subscribe(filter model.EventStreamFilter) *Subscription {
+	subscription := &Subscription{
+		broker: b,
+		filter: filter,
+		events: make(chan *model.WebhookPayload, subscriberBufferSize),
+	}
+
+	b.lock.Lock()
+	b.subscribers[subscription] = struct{}{}
+	b.lock.Unlock()
+
+	return subscription
+}
+
+// Events returns the channel on which matching events are received. The
+// channel is closed when the subscription is closed.
+func (s *Subscription) Events() <-chan *model.WebhookPayload {
+	return s.events
+}
+
+// Close ends the subscription.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.broker.lock.Lock()
+		delete(s.broker.subscribers, s)
+		s.broker.lock.Unlock()
+
+		close(s.events)
+	})
+}
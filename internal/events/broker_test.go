@@ -0,0 +1,53 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package events
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBroker(t *testing.T) {
+	broker := NewBroker()
+
+	all := broker.Subscribe(model.EventStreamFilter{})
+	defer all.Close()
+	installations := broker.Subscribe(model.EventStreamFilter{Type: model.TypeInstallation})
+	defer installations.Close()
+	single := broker.Subscribe(model.EventStreamFilter{Type: model.TypeInstallation, ID: "id2"})
+	defer single.Close()
+
+	cluster := &model.WebhookPayload{Type: model.TypeCluster, ID: "id1"}
+	installation := &model.WebhookPayload{Type: model.TypeInstallation, ID: "id2"}
+
+	broker.Publish(nil)
+	broker.Publish(cluster)
+	broker.Publish(installation)
+
+	require.Equal(t, cluster, <-all.Events())
+	require.Equal(t, installation, <-all.Events())
+	require.Equal(t, installation, <-installations.Events())
+	require.Equal(t, installation, <-single.Events())
+	require.Empty(t, installations.Events())
+	require.Empty(t, single.Events())
+
+	t.Run("closed subscription", func(t *testing.T) {
+		all.Close()
+		all.Close()
+
+		broker.Publish(cluster)
+		_, ok := <-all.Events()
+		require.False(t, ok)
+	})
+
+	t.Run("full buffer drops events", func(t *testing.T) {
+		for i := 0; i < subscriberBufferSize+10; i++ {
+			broker.Publish(installation)
+		}
+		require.Len(t, installations.Events(), subscriberBufferSize)
+	})
+}
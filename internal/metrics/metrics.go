@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+// Package metrics defines the Prometheus metrics exported by the
+// provisioning server.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "cloud"
+
+var (
+	// SupervisorDoDuration tracks how long each supervisor takes to do its work.
+	SupervisorDoDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "supervisor",
+			Name:      "do_duration_seconds",
+			Help:      "The duration of supervisor runs.",
+			Buckets:   []float64{0.1, 1, 10, 30, 60, 300, 600, 1800, 3600},
+		},
+		[]string{"supervisor"},
+	)
+
+	// SupervisorErrors counts the failures of supervisors: failed transitions,
+	// failed queries for pending work and runs that returned an error.
+	SupervisorErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "supervisor",
+			Name:      "errors_total",
+			Help:      "The number of failed supervisor transitions, queries for pending work and runs.",
+		},
+		[]string{"supervisor"},
+	)
+
+	// LockContention counts the attempts to lock rows already locked by
+	// another caller.
+	LockContention = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "store",
+			Name:      "lock_contention_total",
+			Help:      "The number of failed attempts to lock rows held by another locker.",
+		},
+		[]string{"table"},
+	)
+
+	// WebhookDeliveries counts webhook delivery attempts by result.
+	WebhookDeliveries = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "webhook",
+			Name:      "deliveries_total",
+			Help:      "The number of webhook delivery attempts.",
+		},
+		[]string{"result"},
+	)
+
+	// ToolCommandDuration tracks how long invocations of external tools take.
+	ToolCommandDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "tools",
+			Name:      "command_duration_seconds",
+			Help:      "The duration of kops, terraform and helm command invocations.",
+			Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800},
+		},
+		[]string{"tool", "command"},
+	)
+)
+
+// Webhook delivery results.
+const (
+	WebhookDeliverySuccess = "success"
+	WebhookDeliveryFailure = "failure"
+)
+
+func init() {
+	prometheus.MustRegister(
+		SupervisorDoDuration,
+		SupervisorErrors,
+		LockContention,
+		WebhookDeliveries,
+		ToolCommandDuration,
+	)
+}
+
+// ObserveToolCommand records the duration of the given tool invocation that
+// started at the given time. The first argument is used as the command name.
+func ObserveToolCommand(tool string, args []string, start time.Time) {
+	command := ""
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	ToolCommandDuration.WithLabelValues(tool, command).Observe(time.Since(start).Seconds())
+}
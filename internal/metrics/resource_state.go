@@ -0,0 +1,66 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package metrics
+
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// resourceStateStore abstracts the database operations required to count
+// resources by state.
+type resourceStateStore interface {
+	GetClusterStateCounts() (map[string]int64, error)
+	GetInstallationStateCounts() (map[string]int64, error)
+	GetClusterInstallationStateCounts() (map[string]int64, error)
+}
+
+var resourceStateDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(namespace, "", "resources"),
+	"The number of non-deleted resources in each state.",
+	[]string{"type", "state"},
+	nil,
+)
+
+// ResourceStateCollector exports the number of resources in each state,
+// queried from the store on every scrape.
+type ResourceStateCollector struct {
+	store  resourceStateStore
+	logger log.FieldLogger
+}
+
+// NewResourceStateCollector creates a new ResourceStateCollector.
+func NewResourceStateCollector(store resourceStateStore, logger log.FieldLogger) *ResourceStateCollector {
+	return &ResourceStateCollector{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ResourceStateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resourceStateDesc
+}
+
+// Collect implements prometheus.Collector. Resource types that fail to be
+// counted are logged and omitted from the scrape.
+func (c *ResourceStateCollector) Collect(ch chan<- prometheus.Metric) {
+	for resourceType, getCounts := range map[string]func() (map[string]int64, error){
+		model.TypeCluster:             c.store.GetClusterStateCounts,
+		model.TypeInstallation:        c.store.GetInstallationStateCounts,
+		model.TypeClusterInstallation: c.store.GetClusterInstallationStateCounts,
+	} {
+		counts, err := getCounts()
+		if err != nil {
+			c.logger.WithError(err).WithField("type", resourceType).Error("Failed to count resources by state")
+			continue
+		}
+
+		for state, count := range counts {
+			ch <- prometheus.MustNewConstMetric(resourceStateDesc, prometheus.GaugeValue, float64(count), resourceType, state)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type mockResourceStateStore struct {
+	clusterErr error
+}
+
+func (s *mockResourceStateStore) GetClusterStateCounts() (map[string]int64, error) {
+	if s.clusterErr != nil {
+		return nil, s.clusterErr
+	}
+	return map[string]int64{"stable": 2}, nil
+}
+
+func (s *mockResourceStateStore) GetInstallationStateCounts() (map[string]int64, error) {
+	return map[string]int64{"stable": 3, "creation-in-progress": 1}, nil
+}
+
+func (s *mockResourceStateStore) GetClusterInstallationStateCounts() (map[string]int64, error) {
+	return map[string]int64{}, nil
+}
+
+func TestResourceStateCollector(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	t.Run("counts", func(t *testing.T) {
+		collector := NewResourceStateCollector(&mockResourceStateStore{}, logger)
+
+		expected := `
+# HELP cloud_resources The number of non-deleted resources in each state.
+# TYPE cloud_resources gauge
+cloud_resources{state="creation-in-progress",type="installation"} 1
+cloud_resources{state="stable",type="cluster"} 2
+cloud_resources{state="stable",type="installation"} 3
+`
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected))
+		require.NoError(t, err)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		collector := NewResourceStateCollector(&mockResourceStateStore{clusterErr: errors.New("failure")}, logger)
+
+		require.Equal(t, 2, testutil.CollectAndCount(collector))
+	})
+}
@@ -0,0 +1,376 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
+	"github.com/mattermost/mattermost-cloud/internal/tools/kops"
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/mattermost/mattermost-cloud/model"
+	componentUtils "github.com/mattermost/mattermost-operator/pkg/components/utils"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	databaseMigrationMySQLImage       = "mysql:5.7"
+	databaseMigrationMinioClientImage = "minio/mc:RELEASE.2021-03-23T05-46-11Z"
+)
+
+// ciMigrationDatabase moves the data of a cluster installation from the
+// MySQL and MinIO operator backends of the source installation to the AWS
+// backends of the target installation. The data is copied by a job running
+// in the namespace of the cluster installation, next to the operator
+// backends.
+type ciMigrationDatabase struct {
+	provisioner         *KopsProvisioner
+	cluster             *model.Cluster
+	clusterInstallation *model.ClusterInstallation
+	source              *model.Installation
+	target              *model.Installation
+}
+
+// NewCIMigrationDatabase returns the migration of the data of the given
+// cluster installation from the database and filestore of the source
+// installation to the ones of the target installation.
+func (provisioner *KopsProvisioner) NewCIMigrationDatabase(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, source, target *model.Installation) model.CIMigrationDatabase {
+	return &ciMigrationDatabase{
+		provisioner:         provisioner,
+		cluster:             cluster,
+		clusterInstallation: clusterInstallation,
+		source:              source,
+		target:              target,
+	}
+}
+
+func (m *ciMigrationDatabase) migrateDatabase() bool {
+	return m.source.Database != m.target.Database
+}
+
+func (m *ciMigrationDatabase) migrateFilestore() bool {
+	return m.source.Filestore != m.target.Filestore
+}
+
+func (m *ciMigrationDatabase) resourceName() string {
+	return fmt.Sprintf("%s-db-migration", makeClusterInstallationName(m.clusterInstallation))
+}
+
+// Setup provisions the target backends and stores their credentials in a
+// secret used by the migration job. Setup is only complete once the target
+// database is available, so the job can copy the data right away.
+func (m *ciMigrationDatabase) Setup(logger log.FieldLogger) (string, error) {
+	logger = logger.WithField("cluster-installation", m.clusterInstallation.ID)
+
+	secretData := map[string]string{}
+
+	if m.migrateDatabase() {
+		if m.source.Database != model.InstallationDatabaseMysqlOperator {
+			return "", errors.Errorf("migrating data out of %s databases is not supported", m.source.Database)
+		}
+		database := m.provisioner.resourceUtil.GetDatabase(m.target)
+		err := database.Provision(m.provisioner.store, logger)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to provision target database")
+		}
+		// Multitenant databases are only assigned from available clusters,
+		// but a new single tenant one takes a while to become available.
+		if rdsDatabase, ok := database.(*aws.RDSDatabase); ok {
+			available, err := rdsDatabase.IsAvailable(logger)
+			if err != nil {
+				logger.WithError(err).Warn("Failed to check on target database")
+				return model.DatabaseMigrationStatusSetupIP, nil
+			}
+			if !available {
+				logger.Debug("Waiting for target database to be available")
+				return model.DatabaseMigrationStatusSetupIP, nil
+			}
+		}
+		_, databaseSecret, err := database.GenerateDatabaseSpecAndSecret(m.provisioner.store, logger)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to generate target database configuration")
+		}
+		if databaseSecret == nil {
+			return "", errors.Errorf("%s databases cannot be migrated to", m.target.Database)
+		}
+		config, err := parseMySQLConnectionString(databaseSecret.StringData["DB_CONNECTION_STRING"])
+		if err != nil {
+			return "", errors.Wrap(err, "failed to parse target database connection string")
+		}
+		host, port, err := net.SplitHostPort(config.Addr)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse target database address %s", config.Addr)
+		}
+
+		secretData["TARGET_DB_HOST"] = host
+		secretData["TARGET_DB_PORT"] = port
+		secretData["TARGET_DB_USER"] = config.User
+		secretData["TARGET_DB_PASSWORD"] = config.Passwd
+		secretData["TARGET_DB_NAME"] = config.DBName
+	}
+
+	if m.migrateFilestore() {
+		if m.source.Filestore != model.InstallationFilestoreMinioOperator {
+			return "", errors.Errorf("migrating data out of %s filestores is not supported", m.source.Filestore)
+		}
+		err := m.provisioner.resourceUtil.GetFilestore(m.target).Provision(m.provisioner.store, logger)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to provision target filestore")
+		}
+		filestoreSpec, filestoreSecret, err := m.provisioner.resourceUtil.GetFilestore(m.target).GenerateFilestoreSpecAndSecret(m.provisioner.store, logger)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to generate target filestore configuration")
+		}
+		if filestoreSpec == nil || filestoreSecret == nil {
+			return "", errors.Errorf("%s filestores cannot be migrated to", m.target.Filestore)
+		}
+
+		secretData["TARGET_S3_URL"] = fmt.Sprintf("https://%s", filestoreSpec.ExternalURL)
+		secretData["TARGET_S3_BUCKET"] = filestoreSpec.ExternalBucket
+		secretData["TARGET_S3_ACCESS_KEY"] = filestoreSecret.StringData["accesskey"]
+		secretData["TARGET_S3_SECRET_KEY"] = filestoreSecret.StringData["secretkey"]
+	}
+
+	if len(secretData) == 0 {
+		return "", errors.New("nothing to migrate")
+	}
+
+	k8sClient, kops, err := m.newKubeClient(logger)
+	if err != nil {
+		return "", err
+	}
+	defer kops.Close()
+
+	_, err = k8sClient.CreateOrUpdateSecret(m.clusterInstallation.Namespace, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.resourceName(),
+			Namespace: m.clusterInstallation.Namespace,
+		},
+		StringData: secretData,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create database migration secret")
+	}
+
+	logger.Info("Database migration setup complete")
+
+	return model.DatabaseMigrationStatusSetupComplete, nil
+}
+
+// Replicate starts the job copying the data to the target backends, if it
+// isn't running already, and reports its progress. An error is only returned
+// when the data couldn't be copied; failures to check on the job are logged
+// and reported as the replication still being in progress.
+func (m *ciMigrationDatabase) Replicate(logger log.FieldLogger) (string, error) {
+	logger = logger.WithField("cluster-installation", m.clusterInstallation.ID)
+
+	k8sClient, kops, err := m.newKubeClient(logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to check on database migration job")
+		return model.DatabaseMigrationStatusReplicationIP, nil
+	}
+	defer kops.Close()
+
+	ctx := context.TODO()
+	jobs := k8sClient.Clientset.BatchV1().Jobs(m.clusterInstallation.Namespace)
+	job, err := jobs.Get(ctx, m.resourceName(), metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		_, err = jobs.Create(ctx, m.makeJob(), metav1.CreateOptions{})
+		if err != nil {
+			return "", errors.Wrap(err, "failed to create database migration job")
+		}
+		logger.Info("Database migration job started")
+		return model.DatabaseMigrationStatusReplicationIP, nil
+	}
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get database migration job")
+		return model.DatabaseMigrationStatusReplicationIP, nil
+	}
+
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return "", errors.Errorf("database migration job failed: %s", condition.Message)
+		}
+	}
+	if job.Status.Succeeded > 0 {
+		logger.Info("Database migration job completed")
+		return model.DatabaseMigrationStatusReplicationComplete, nil
+	}
+
+	logger.Debugf("Database migration job still running with %d active pods", job.Status.Active)
+
+	return model.DatabaseMigrationStatusReplicationIP, nil
+}
+
+// Teardown removes the migration job and secret. The data of the source
+// backends is left untouched.
+func (m *ciMigrationDatabase) Teardown(logger log.FieldLogger) (string, error) {
+	logger = logger.WithField("cluster-installation", m.clusterInstallation.ID)
+
+	k8sClient, kops, err := m.newKubeClient(logger)
+	if err != nil {
+		return "", err
+	}
+	defer kops.Close()
+
+	ctx := context.TODO()
+	propagation := metav1.DeletePropagationBackground
+	err = k8sClient.Clientset.BatchV1().Jobs(m.clusterInstallation.Namespace).Delete(ctx, m.resourceName(), metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return "", errors.Wrap(err, "failed to delete database migration job")
+	}
+
+	err = k8sClient.Clientset.CoreV1().Secrets(m.clusterInstallation.Namespace).Delete(ctx, m.resourceName(), metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return "", errors.Wrap(err, "failed to delete database migration secret")
+	}
+
+	logger.Info("Database migration teardown complete")
+
+	return model.DatabaseMigrationStatusTeardownComplete, nil
+}
+
+func (m *ciMigrationDatabase) newKubeClient(logger log.FieldLogger) (*k8s.KubeClient, *kops.Cmd, error) {
+	kops, err := kops.New(m.provisioner.s3StateStore, logger)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create kops wrapper")
+	}
+
+	err = kops.ExportKubecfg(m.cluster.ProvisionerMetadataKops.Name)
+	if err != nil {
+		kops.Close()
+		return nil, nil, errors.Wrap(err, "failed to export kubecfg")
+	}
+
+	k8sClient, err := k8s.NewFromFile(kops.GetKubeConfigPath(), logger)
+	if err != nil {
+		kops.Close()
+		return nil, nil, errors.Wrap(err, "failed to create kubernetes client")
+	}
+
+	return k8sClient, kops, nil
+}
+
+// makeJob returns the job copying the data of the operator backends to the
+// target backends. Each backend is copied by its own container, reading the
+// source credentials from the operator secrets and the target ones from the
+// migration secret.
+func (m *ciMigrationDatabase) makeJob() *batchv1.Job {
+	name := makeClusterInstallationName(m.clusterInstallation)
+	namespace := m.clusterInstallation.Namespace
+	secretName := m.resourceName()
+
+	var containers []corev1.Container
+	if m.migrateDatabase() {
+		databaseSecret := fmt.Sprintf("%s-mysql-root-password", name)
+		containers = append(containers, corev1.Container{
+			Name:  "database",
+			Image: databaseMigrationMySQLImage,
+			Command: []string{
+				"bash", "-c",
+				strings.Join([]string{
+					"set -o pipefail",
+					`mysqldump --single-transaction --routines --triggers -h "$SOURCE_DB_HOST" -u "$SOURCE_DB_USER" -p"$SOURCE_DB_PASSWORD" "$SOURCE_DB_NAME" | mysql -h "$TARGET_DB_HOST" -P "$TARGET_DB_PORT" -u "$TARGET_DB_USER" -p"$TARGET_DB_PASSWORD" "$TARGET_DB_NAME"`,
+				}, "; "),
+			},
+			Env: []corev1.EnvVar{
+				{Name: "SOURCE_DB_HOST", Value: fmt.Sprintf("%s-mysql-master.%s", componentUtils.HashWithPrefix("db", name), namespace)},
+				secretKeyEnvVar("SOURCE_DB_USER", databaseSecret, "USER"),
+				secretKeyEnvVar("SOURCE_DB_PASSWORD", databaseSecret, "PASSWORD"),
+				secretKeyEnvVar("SOURCE_DB_NAME", databaseSecret, "DATABASE"),
+			},
+			EnvFrom: []corev1.EnvFromSource{
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secretName}}},
+			},
+		})
+	}
+	if m.migrateFilestore() {
+		filestoreSecret := fmt.Sprintf("%s-minio", name)
+		containers = append(containers, corev1.Container{
+			Name:  "filestore",
+			Image: databaseMigrationMinioClientImage,
+			Command: []string{
+				"sh", "-c",
+				strings.Join([]string{
+					`mc alias set source "$SOURCE_S3_URL" "$SOURCE_S3_ACCESS_KEY" "$SOURCE_S3_SECRET_KEY"`,
+					`mc alias set target "$TARGET_S3_URL" "$TARGET_S3_ACCESS_KEY" "$TARGET_S3_SECRET_KEY"`,
+					`mc mirror --overwrite "source/$SOURCE_S3_BUCKET" "target/$TARGET_S3_BUCKET"`,
+				}, " && "),
+			},
+			Env: []corev1.EnvVar{
+				{Name: "SOURCE_S3_URL", Value: fmt.Sprintf("http://%s-minio-hl-svc.%s:9000", name, namespace)},
+				{Name: "SOURCE_S3_BUCKET", Value: name},
+				secretKeyEnvVar("SOURCE_S3_ACCESS_KEY", filestoreSecret, "accesskey"),
+				secretKeyEnvVar("SOURCE_S3_SECRET_KEY", filestoreSecret, "secretkey"),
+			},
+			EnvFrom: []corev1.EnvFromSource{
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: secretName}}},
+			},
+		})
+	}
+
+	// Copies are not incremental, so a failed copy is left for the
+	// supervisor to roll back rather than retried.
+	backoffLimit := int32(0)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.resourceName(),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app":                  "mattermost-db-migration",
+				"installation-id":      m.clusterInstallation.InstallationID,
+				"cluster-installation": m.clusterInstallation.ID,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"app": "mattermost-db-migration",
+					},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers:    containers,
+				},
+			},
+		},
+	}
+}
+
+func secretKeyEnvVar(name, secretName, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// parseMySQLConnectionString parses the MySQL connection strings generated
+// for Mattermost, which prefix the driver DSN with a mysql:// scheme.
+func parseMySQLConnectionString(connectionString string) (*mysql.Config, error) {
+	if !strings.HasPrefix(connectionString, "mysql://") {
+		return nil, errors.New("not a MySQL connection string")
+	}
+
+	return mysql.ParseDSN(strings.TrimPrefix(connectionString, "mysql://"))
+}
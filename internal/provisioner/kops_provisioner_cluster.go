@@ -22,6 +22,7 @@ import (
 	"github.com/mattermost/mattermost-cloud/internal/tools/kops"
 	"github.com/mattermost/mattermost-cloud/internal/tools/terraform"
 	"github.com/mattermost/mattermost-cloud/internal/tools/utils"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/k8s"
 	"github.com/mattermost/mattermost-cloud/model"
 )
@@ -77,6 +78,8 @@ func (provisioner *KopsProvisioner) PrepareCluster(cluster *model.Cluster) bool
 // CreateCluster creates a cluster using kops and terraform.
 func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsClient aws.AWS) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.CreateCluster", cluster.ID)
+	defer span.End()
 
 	isAMIValid, err := awsClient.IsValidAMI(cluster.ProvisionerMetadataKops.AMI, logger)
 	if err != nil {
@@ -210,6 +213,8 @@ func (provisioner *KopsProvisioner) CreateCluster(cluster *model.Cluster, awsCli
 // to reprovision with the newest version of the resources.
 func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, awsClient aws.AWS) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.ProvisionCluster", cluster.ID)
+	defer span.End()
 
 	kops, err := kops.New(provisioner.s3StateStore, logger)
 	if err != nil {
@@ -438,6 +443,8 @@ func (provisioner *KopsProvisioner) ProvisionCluster(cluster *model.Cluster, aws
 // UpgradeCluster upgrades a cluster to the latest recommended production ready k8s version.
 func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster, awsClient aws.AWS) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.UpgradeCluster", cluster.ID)
+	defer span.End()
 
 	isAMIValid, err := awsClient.IsValidAMI(cluster.ProvisionerMetadataKops.AMI, logger)
 	if err != nil {
@@ -550,6 +557,8 @@ func (provisioner *KopsProvisioner) UpgradeCluster(cluster *model.Cluster, awsCl
 // ResizeCluster resizes a cluster.
 func (provisioner *KopsProvisioner) ResizeCluster(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.ResizeCluster", cluster.ID)
+	defer span.End()
 
 	kopsMetadata := cluster.ProvisionerMetadataKops
 
@@ -647,6 +656,8 @@ func (provisioner *KopsProvisioner) ResizeCluster(cluster *model.Cluster) error
 // DeleteCluster deletes a previously created cluster using kops and terraform.
 func (provisioner *KopsProvisioner) DeleteCluster(cluster *model.Cluster, awsClient aws.AWS) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.DeleteCluster", cluster.ID)
+	defer span.End()
 
 	kopsMetadata := cluster.ProvisionerMetadataKops
 
@@ -735,6 +746,8 @@ func (provisioner *KopsProvisioner) DeleteCluster(cluster *model.Cluster, awsCli
 // GetClusterResources returns a snapshot of resources of a given cluster.
 func (provisioner *KopsProvisioner) GetClusterResources(cluster *model.Cluster, onlySchedulable bool) (*k8s.ClusterResources, error) {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.GetClusterResources", cluster.ID)
+	defer span.End()
 
 	kops, err := kops.New(provisioner.s3StateStore, logger)
 	if err != nil {
@@ -804,6 +817,8 @@ func (provisioner *KopsProvisioner) GetClusterResources(cluster *model.Cluster,
 // values of the running cluster.
 func (provisioner *KopsProvisioner) RefreshKopsMetadata(cluster *model.Cluster) error {
 	logger := provisioner.logger.WithField("cluster", cluster.ID)
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.RefreshKopsMetadata", cluster.ID)
+	defer span.End()
 
 	logger.Info("Refreshing kops metadata")
 
@@ -12,6 +12,7 @@ import (
 
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/internal/tools/kops"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/k8s"
 	"github.com/mattermost/mattermost-cloud/model"
 	mmv1alpha1 "github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
@@ -29,6 +30,8 @@ func (provisioner *KopsProvisioner) CreateClusterInstallation(cluster *model.Clu
 		"cluster":      clusterInstallation.ClusterID,
 		"installation": clusterInstallation.InstallationID,
 	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.CreateClusterInstallation", clusterInstallation.ID, installation.ID, cluster.ID)
+	defer span.End()
 	logger.Info("Creating cluster installation")
 
 	kops, err := kops.New(provisioner.s3StateStore, logger)
@@ -168,6 +171,8 @@ func (provisioner *KopsProvisioner) HibernateClusterInstallation(cluster *model.
 		"cluster":      clusterInstallation.ClusterID,
 		"installation": clusterInstallation.InstallationID,
 	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.HibernateClusterInstallation", clusterInstallation.ID, installation.ID, cluster.ID)
+	defer span.End()
 
 	kops, err := kops.New(provisioner.s3StateStore, logger)
 	if err != nil {
@@ -216,6 +221,8 @@ func (provisioner *KopsProvisioner) UpdateClusterInstallation(cluster *model.Clu
 		"cluster":      clusterInstallation.ClusterID,
 		"installation": clusterInstallation.InstallationID,
 	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.UpdateClusterInstallation", clusterInstallation.ID, installation.ID, cluster.ID)
+	defer span.End()
 
 	kops, err := kops.New(provisioner.s3StateStore, logger)
 	if err != nil {
@@ -371,6 +378,8 @@ func (provisioner *KopsProvisioner) DeleteClusterInstallation(cluster *model.Clu
 		"cluster":      clusterInstallation.ClusterID,
 		"installation": clusterInstallation.InstallationID,
 	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.DeleteClusterInstallation", clusterInstallation.ID, installation.ID, cluster.ID)
+	defer span.End()
 
 	kops, err := kops.New(provisioner.s3StateStore, logger)
 	if err != nil {
@@ -432,6 +441,8 @@ func (provisioner *KopsProvisioner) GetClusterInstallationResource(cluster *mode
 		"cluster":      clusterInstallation.ClusterID,
 		"installation": clusterInstallation.InstallationID,
 	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.GetClusterInstallationResource", clusterInstallation.ID, installation.ID, cluster.ID)
+	defer span.End()
 
 	kops, err := kops.New(provisioner.s3StateStore, logger)
 	if err != nil {
@@ -478,6 +489,8 @@ func (provisioner *KopsProvisioner) ExecClusterInstallationCLI(cluster *model.Cl
 		"cluster":      clusterInstallation.ClusterID,
 		"installation": clusterInstallation.InstallationID,
 	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.ExecClusterInstallationCLI", clusterInstallation.ID, cluster.ID)
+	defer span.End()
 
 	kops, err := kops.New(provisioner.s3StateStore, logger)
 	if err != nil {
@@ -541,6 +554,64 @@ func (provisioner *KopsProvisioner) ExecClusterInstallationCLI(cluster *model.Cl
 	return output, err
 }
 
+// GetClusterInstallationActivity measures how much the given cluster
+// installation was used over the given period from the metrics collected by
+// the cluster utilities. Nil is returned if no metrics were collected for it.
+func (provisioner *KopsProvisioner) GetClusterInstallationActivity(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, period time.Duration) (*model.InstallationActivity, error) {
+	logger := provisioner.logger.WithFields(log.Fields{
+		"cluster":      clusterInstallation.ClusterID,
+		"installation": clusterInstallation.InstallationID,
+	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.GetClusterInstallationActivity", clusterInstallation.ID, cluster.ID)
+	defer span.End()
+
+	kops, err := kops.New(provisioner.s3StateStore, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kops wrapper")
+	}
+	defer kops.Close()
+
+	err = kops.ExportKubecfg(cluster.ProvisionerMetadataKops.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to export kubecfg")
+	}
+
+	k8sClient, err := k8s.NewFromFile(kops.GetKubeConfigPath(), logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to construct k8s client")
+	}
+
+	ctx := context.TODO()
+	selector := fmt.Sprintf(`{namespace=%q}`, clusterInstallation.Namespace)
+	duration := prometheusDuration(period)
+
+	activeUsers, found, err := queryPrometheusScalar(ctx, k8sClient, fmt.Sprintf("sum(max_over_time(mattermost_http_websockets_total%s[%s]))", selector, duration))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query active users")
+	}
+	if !found {
+		logger.Debug("No active user metrics found for cluster installation")
+		return nil, nil
+	}
+
+	requests, found, err := queryPrometheusScalar(ctx, k8sClient, fmt.Sprintf("sum(increase(mattermost_http_requests_total%s[%s]))", selector, duration))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query API requests")
+	}
+	if !found {
+		logger.Debug("No API request metrics found for cluster installation")
+		return nil, nil
+	}
+
+	activity := &model.InstallationActivity{
+		ActiveUsers: int64(activeUsers),
+		Requests:    int64(requests),
+	}
+	logger.Debugf("Cluster installation had up to %d active users and %d API requests over the last %s", activity.ActiveUsers, activity.Requests, period)
+
+	return activity, nil
+}
+
 // Set env overrides that are required from installations for function correctly
 // in the cloud environment.
 // NOTE: this should be called whenever the Mattermost custom resource is created
@@ -562,5 +633,12 @@ func getMattermostEnvWithOverrides(installation *model.Installation) model.EnvVa
 		mattermostEnv["MM_FILESETTINGS_AMAZONS3PATHPREFIX"] = model.EnvVar{Value: installation.ID}
 	}
 
+	// Cloned installations hold the user data of their source until they are
+	// scrubbed, so they must not notify those users in the meantime.
+	if len(installation.CloneScrubCommand) != 0 {
+		mattermostEnv["MM_EMAILSETTINGS_SENDEMAILNOTIFICATIONS"] = model.EnvVar{Value: "false"}
+		mattermostEnv["MM_EMAILSETTINGS_SENDPUSHNOTIFICATIONS"] = model.EnvVar{Value: "false"}
+	}
+
 	return mattermostEnv
 }
@@ -0,0 +1,44 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMattermostEnvWithOverrides(t *testing.T) {
+	t.Run("notifications left alone", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Filestore: model.InstallationFilestoreMinioOperator,
+			MattermostEnv: model.EnvVarMap{
+				"MM_EMAILSETTINGS_SENDEMAILNOTIFICATIONS": {Value: "true"},
+			},
+		}
+
+		env := getMattermostEnvWithOverrides(installation)
+		require.Equal(t, "installation1", env["MM_CLOUD_INSTALLATION_ID"].Value)
+		require.Equal(t, "true", env["MM_EMAILSETTINGS_SENDEMAILNOTIFICATIONS"].Value)
+		require.NotContains(t, env, "MM_EMAILSETTINGS_SENDPUSHNOTIFICATIONS")
+	})
+
+	t.Run("notifications disabled until scrubbed", func(t *testing.T) {
+		installation := &model.Installation{
+			ID:        "installation1",
+			Filestore: model.InstallationFilestoreMinioOperator,
+			MattermostEnv: model.EnvVarMap{
+				"MM_EMAILSETTINGS_SENDEMAILNOTIFICATIONS": {Value: "true"},
+			},
+			CloneScrubCommand: []string{"sh", "-c", "./scrub.sh"},
+		}
+
+		env := getMattermostEnvWithOverrides(installation)
+		require.Equal(t, "false", env["MM_EMAILSETTINGS_SENDEMAILNOTIFICATIONS"].Value)
+		require.Equal(t, "false", env["MM_EMAILSETTINGS_SENDPUSHNOTIFICATIONS"].Value)
+	})
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/mattermost/mattermost-cloud/internal/tools/kops"
 	"github.com/mattermost/mattermost-cloud/internal/tools/terraform"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/k8s"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
@@ -128,6 +129,8 @@ func (provisioner *KopsProvisioner) GetPublicLoadBalancerEndpoint(cluster *model
 		"cluster":         cluster.ID,
 		"nginx-namespace": namespace,
 	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.GetPublicLoadBalancerEndpoint", cluster.ID)
+	defer span.End()
 	kops, err := kops.New(provisioner.s3StateStore, logger)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to create kops wrapper")
@@ -164,6 +167,31 @@ func (provisioner *KopsProvisioner) GetPublicLoadBalancerEndpoint(cluster *model
 	return "", errors.New("failed to get NGINX load balancer endpoint")
 }
 
+// GetPrivateLoadBalancerEndpoint returns the private load balancer endpoint of the NGINX service.
+func (provisioner *KopsProvisioner) GetPrivateLoadBalancerEndpoint(cluster *model.Cluster, namespace string) (string, error) {
+	logger := provisioner.logger.WithFields(log.Fields{
+		"cluster":         cluster.ID,
+		"nginx-namespace": namespace,
+	})
+	span, logger := tracing.StartResourceChildSpan(logger, "KopsProvisioner.GetPrivateLoadBalancerEndpoint", cluster.ID)
+	defer span.End()
+	kops, err := kops.New(provisioner.s3StateStore, logger)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create kops wrapper")
+	}
+	defer kops.Close()
+
+	err = kops.ExportKubecfg(cluster.ProvisionerMetadataKops.Name)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to export kubecfg")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(120)*time.Second)
+	defer cancel()
+
+	return getPrivateLoadBalancerEndpoint(ctx, namespace, logger, kops.GetKubeConfigPath())
+}
+
 func updateKopsInstanceGroupAMIs(kops *kops.Cmd, kopsMetadata *model.KopsMetadata, logger log.FieldLogger) error {
 	if len(kopsMetadata.ChangeRequest.AMI) == 0 {
 		logger.Info("Skipping cluster AMI update")
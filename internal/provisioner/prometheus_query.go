@@ -0,0 +1,92 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/k8s"
+	"github.com/pkg/errors"
+)
+
+const (
+	// The Thanos querier deployed with the cluster utilities, queried through
+	// the Kubernetes API rather than its whitelisted ingress.
+	thanosQuerierNamespace = "prometheus"
+	thanosQuerierService   = "thanos-querier"
+	thanosQuerierPort      = "9090"
+)
+
+// prometheusQueryResponse is the response of the Prometheus instant query API.
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryPrometheusScalar runs the given instant query against the Thanos
+// querier of the cluster, returning the single value it evaluates to and
+// whether there was one at all.
+func queryPrometheusScalar(ctx context.Context, k8sClient *k8s.KubeClient, query string) (float64, bool, error) {
+	data, err := k8sClient.Clientset.CoreV1().Services(thanosQuerierNamespace).
+		ProxyGet("http", thanosQuerierService, thanosQuerierPort, "/api/v1/query", map[string]string{"query": query}).
+		DoRaw(ctx)
+	if err != nil {
+		return 0, false, errors.Wrapf(err, "failed to run query %q", query)
+	}
+
+	return parsePrometheusScalar(data)
+}
+
+// parsePrometheusScalar parses the response of an instant query evaluating to
+// at most one sample.
+func parsePrometheusScalar(data []byte) (float64, bool, error) {
+	var response prometheusQueryResponse
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to decode query response")
+	}
+	if response.Status != "success" {
+		return 0, false, errors.Errorf("query failed: %s", response.Error)
+	}
+	if response.Data.ResultType != "vector" {
+		return 0, false, errors.Errorf("unexpected query result type %q", response.Data.ResultType)
+	}
+	if len(response.Data.Result) == 0 {
+		return 0, false, nil
+	}
+	if len(response.Data.Result) > 1 {
+		return 0, false, errors.Errorf("expected a single sample, but got %d", len(response.Data.Result))
+	}
+
+	sample := response.Data.Result[0].Value
+	if len(sample) != 2 {
+		return 0, false, errors.New("malformed sample")
+	}
+	value, ok := sample[1].(string)
+	if !ok {
+		return 0, false, errors.New("malformed sample value")
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false, errors.Wrap(err, "malformed sample value")
+	}
+
+	return parsed, true, nil
+}
+
+// prometheusDuration formats the given duration as a Prometheus range.
+func prometheusDuration(period time.Duration) string {
+	return fmt.Sprintf("%ds", int64(period.Seconds()))
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package provisioner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrometheusScalar(t *testing.T) {
+	for _, testCase := range []struct {
+		description   string
+		data          string
+		expectedValue float64
+		expectedFound bool
+		expectError   bool
+	}{
+		{
+			description:   "single sample",
+			data:          `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1600000000.5,"42.7"]}]}}`,
+			expectedValue: 42.7,
+			expectedFound: true,
+		},
+		{
+			description: "no sample",
+			data:        `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		},
+		{
+			description: "failed query",
+			data:        `{"status":"error","errorType":"bad_data","error":"parse error"}`,
+			expectError: true,
+		},
+		{
+			description: "several samples",
+			data:        `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"1"]},{"metric":{},"value":[1,"2"]}]}}`,
+			expectError: true,
+		},
+		{
+			description: "matrix",
+			data:        `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			expectError: true,
+		},
+		{
+			description: "malformed value",
+			data:        `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1,"many"]}]}}`,
+			expectError: true,
+		},
+		{
+			description: "malformed response",
+			data:        `<html></html>`,
+			expectError: true,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			value, found, err := parsePrometheusScalar([]byte(testCase.data))
+			if testCase.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedFound, found)
+			assert.Equal(t, testCase.expectedValue, value)
+		})
+	}
+}
+
+func TestPrometheusDuration(t *testing.T) {
+	assert.Equal(t, "86400s", prometheusDuration(24*time.Hour))
+	assert.Equal(t, "90s", prometheusDuration(90*time.Second+500*time.Millisecond))
+}
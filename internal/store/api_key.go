@@ -0,0 +1,154 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+var apiKeySelect sq.SelectBuilder
+
+func init() {
+	apiKeySelect = sq.
+		Select("ID", "Name", "KeyHash", "ScopesRaw", "OwnerID", "CreateAt", "DeleteAt").
+		From("APIKey")
+}
+
+type rawAPIKey struct {
+	*model.APIKey
+	ScopesRaw []byte
+}
+
+type rawAPIKeys []*rawAPIKey
+
+func (r *rawAPIKey) toAPIKey() (*model.APIKey, error) {
+	// We only need to set values that are converted from a raw database format.
+	if r.ScopesRaw != nil {
+		err := json.Unmarshal(r.ScopesRaw, &r.APIKey.Scopes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal scopes")
+		}
+	}
+
+	return r.APIKey, nil
+}
+
+func (rs *rawAPIKeys) toAPIKeys() ([]*model.APIKey, error) {
+	var apiKeys []*model.APIKey
+	for _, rawAPIKey := range *rs {
+		apiKey, err := rawAPIKey.toAPIKey()
+		if err != nil {
+			return nil, err
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, nil
+}
+
+// GetAPIKey fetches the given API key by id.
+func (sqlStore *SQLStore) GetAPIKey(id string) (*model.APIKey, error) {
+	var rawAPIKey rawAPIKey
+	err := sqlStore.getBuilder(sqlStore.db, &rawAPIKey,
+		apiKeySelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get API key by id")
+	}
+
+	return rawAPIKey.toAPIKey()
+}
+
+// GetAPIKeyByHash fetches the API key matching the given key hash.
+func (sqlStore *SQLStore) GetAPIKeyByHash(keyHash string) (*model.APIKey, error) {
+	var rawAPIKey rawAPIKey
+	err := sqlStore.getBuilder(sqlStore.db, &rawAPIKey,
+		apiKeySelect.Where("KeyHash = ?", keyHash),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get API key by hash")
+	}
+
+	return rawAPIKey.toAPIKey()
+}
+
+// GetAPIKeys fetches the given page of created API keys. The first page is 0.
+func (sqlStore *SQLStore) GetAPIKeys(filter *model.APIKeyFilter) ([]*model.APIKey, error) {
+	builder := apiKeySelect.
+		OrderBy("CreateAt ASC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if !filter.IncludeDeleted {
+		builder = builder.Where("DeleteAt = 0")
+	}
+
+	var rawAPIKeys rawAPIKeys
+	err := sqlStore.selectBuilder(sqlStore.db, &rawAPIKeys, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for API keys")
+	}
+
+	return rawAPIKeys.toAPIKeys()
+}
+
+// CreateAPIKey records the given API key to the database, assigning it a
+// unique ID. The caller is responsible for setting the KeyHash.
+func (sqlStore *SQLStore) CreateAPIKey(apiKey *model.APIKey) error {
+	apiKey.ID = model.NewID()
+	apiKey.CreateAt = GetMillis()
+
+	scopesRaw, err := json.Marshal(apiKey.Scopes)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal scopes")
+	}
+
+	_, err = sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("APIKey").
+		SetMap(map[string]interface{}{
+			"ID":        apiKey.ID,
+			"Name":      apiKey.Name,
+			"KeyHash":   apiKey.KeyHash,
+			"ScopesRaw": scopesRaw,
+			"OwnerID":   apiKey.OwnerID,
+			"CreateAt":  apiKey.CreateAt,
+			"DeleteAt":  0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create API key")
+	}
+
+	return nil
+}
+
+// RevokeAPIKey marks the given API key as deleted so that it can no longer be
+// used, but does not remove the record from the database.
+func (sqlStore *SQLStore) RevokeAPIKey(id string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update("APIKey").
+		Set("DeleteAt", GetMillis()).
+		Where("ID = ?", id).
+		Where("DeleteAt = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark API key as deleted")
+	}
+
+	return nil
+}
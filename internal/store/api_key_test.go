@@ -0,0 +1,93 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+
+	t.Run("get unknown API key", func(t *testing.T) {
+		apiKey, err := sqlStore.GetAPIKey("unknown")
+		require.NoError(t, err)
+		require.Nil(t, apiKey)
+
+		apiKey, err = sqlStore.GetAPIKeyByHash("unknown")
+		require.NoError(t, err)
+		require.Nil(t, apiKey)
+	})
+
+	apiKey1 := &model.APIKey{
+		Name:    "reader",
+		KeyHash: model.HashAPIKey("key1"),
+		Scopes:  []string{model.APIKeyScopeReadOnly},
+	}
+	apiKey2 := &model.APIKey{
+		Name:    "admin",
+		KeyHash: model.HashAPIKey("key2"),
+		Scopes:  []string{model.APIKeyScopeInstallationAdmin, model.APIKeyScopeClusterAdmin},
+		OwnerID: "owner",
+	}
+
+	err := sqlStore.CreateAPIKey(apiKey1)
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	err = sqlStore.CreateAPIKey(apiKey2)
+	require.NoError(t, err)
+
+	t.Run("get API keys", func(t *testing.T) {
+		actualAPIKey1, err := sqlStore.GetAPIKey(apiKey1.ID)
+		require.NoError(t, err)
+		require.Equal(t, apiKey1, actualAPIKey1)
+
+		actualAPIKey2, err := sqlStore.GetAPIKeyByHash(model.HashAPIKey("key2"))
+		require.NoError(t, err)
+		require.Equal(t, apiKey2, actualAPIKey2)
+
+		actualAPIKeys, err := sqlStore.GetAPIKeys(&model.APIKeyFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Equal(t, []*model.APIKey{apiKey1, apiKey2}, actualAPIKeys)
+
+		actualAPIKeys, err = sqlStore.GetAPIKeys(&model.APIKeyFilter{PerPage: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*model.APIKey{apiKey1}, actualAPIKeys)
+	})
+
+	t.Run("duplicate key hash", func(t *testing.T) {
+		err := sqlStore.CreateAPIKey(&model.APIKey{
+			Name:    "duplicate",
+			KeyHash: model.HashAPIKey("key1"),
+			Scopes:  []string{model.APIKeyScopeReadOnly},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("revoke API key", func(t *testing.T) {
+		err := sqlStore.RevokeAPIKey(apiKey1.ID)
+		require.NoError(t, err)
+
+		actualAPIKey1, err := sqlStore.GetAPIKey(apiKey1.ID)
+		require.NoError(t, err)
+		require.True(t, actualAPIKey1.IsRevoked())
+
+		actualAPIKeys, err := sqlStore.GetAPIKeys(&model.APIKeyFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Equal(t, []*model.APIKey{apiKey2}, actualAPIKeys)
+
+		actualAPIKeys, err = sqlStore.GetAPIKeys(&model.APIKeyFilter{PerPage: model.AllPerPage, IncludeDeleted: true})
+		require.NoError(t, err)
+		require.Len(t, actualAPIKeys, 2)
+	})
+}
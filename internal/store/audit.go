@@ -0,0 +1,97 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+var auditSelect sq.SelectBuilder
+
+func init() {
+	auditSelect = sq.
+		Select("ID", "RequestID", "APIKeyID", "RemoteAddr", "Method", "Path",
+			"ResourceType", "ResourceID", "RequestBody", "StatusCode", "CreateAt").
+		From("Audit")
+}
+
+// GetAuditRecords fetches the given page of audit records, newest first. The
+// first page is 0.
+func (sqlStore *SQLStore) GetAuditRecords(filter *model.AuditFilter) ([]*model.AuditRecord, error) {
+	builder := auditSelect.
+		OrderBy("CreateAt DESC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if filter.ResourceType != "" {
+		builder = builder.Where("ResourceType = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		builder = builder.Where("ResourceID = ?", filter.ResourceID)
+	}
+	if filter.StartTime != 0 {
+		builder = builder.Where("CreateAt >= ?", filter.StartTime)
+	}
+	if filter.EndTime != 0 {
+		builder = builder.Where("CreateAt < ?", filter.EndTime)
+	}
+
+	var records []*model.AuditRecord
+	err := sqlStore.selectBuilder(sqlStore.db, &records, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for audit records")
+	}
+
+	return records, nil
+}
+
+// CreateAuditRecord records the given audit record to the database, assigning
+// it a unique ID.
+func (sqlStore *SQLStore) CreateAuditRecord(record *model.AuditRecord) error {
+	record.ID = model.NewID()
+	record.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("Audit").
+		SetMap(map[string]interface{}{
+			"ID":           record.ID,
+			"RequestID":    record.RequestID,
+			"APIKeyID":     record.APIKeyID,
+			"RemoteAddr":   record.RemoteAddr,
+			"Method":       record.Method,
+			"Path":         record.Path,
+			"ResourceType": record.ResourceType,
+			"ResourceID":   record.ResourceID,
+			"RequestBody":  record.RequestBody,
+			"StatusCode":   record.StatusCode,
+			"CreateAt":     record.CreateAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create audit record")
+	}
+
+	return nil
+}
+
+// DeleteAuditRecordsCreatedBefore removes the audit records created before
+// the given time, in milliseconds since the epoch.
+func (sqlStore *SQLStore) DeleteAuditRecordsCreatedBefore(createAt int64) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete("Audit").
+		Where("CreateAt < ?", createAt),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete expired audit records")
+	}
+
+	return nil
+}
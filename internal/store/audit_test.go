@@ -0,0 +1,90 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRecords(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+
+	installationID := model.NewID()
+	clusterID := model.NewID()
+
+	record1 := &model.AuditRecord{
+		RequestID:    model.NewID(),
+		APIKeyID:     model.NewID(),
+		RemoteAddr:   "127.0.0.1:1234",
+		Method:       "PUT",
+		Path:         "/api/installation/" + installationID + "/mattermost",
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installationID,
+		RequestBody:  `{"Version":"5.30.0"}`,
+		StatusCode:   202,
+	}
+	err := sqlStore.CreateAuditRecord(record1)
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	record2 := &model.AuditRecord{
+		RequestID:    model.NewID(),
+		Method:       "DELETE",
+		Path:         "/api/cluster/" + clusterID,
+		ResourceType: model.TypeCluster,
+		ResourceID:   clusterID,
+		StatusCode:   202,
+	}
+	err = sqlStore.CreateAuditRecord(record2)
+	require.NoError(t, err)
+
+	t.Run("all records, newest first", func(t *testing.T) {
+		records, err := sqlStore.GetAuditRecords(&model.AuditFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Equal(t, []*model.AuditRecord{record2, record1}, records)
+	})
+
+	t.Run("resource", func(t *testing.T) {
+		records, err := sqlStore.GetAuditRecords(&model.AuditFilter{
+			ResourceType: model.TypeInstallation,
+			ResourceID:   installationID,
+			PerPage:      model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.AuditRecord{record1}, records)
+	})
+
+	t.Run("time range", func(t *testing.T) {
+		records, err := sqlStore.GetAuditRecords(&model.AuditFilter{
+			StartTime: record2.CreateAt,
+			PerPage:   model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.AuditRecord{record2}, records)
+
+		records, err = sqlStore.GetAuditRecords(&model.AuditFilter{
+			EndTime: record2.CreateAt,
+			PerPage: model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.AuditRecord{record1}, records)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		err := sqlStore.DeleteAuditRecordsCreatedBefore(record2.CreateAt)
+		require.NoError(t, err)
+
+		records, err := sqlStore.GetAuditRecords(&model.AuditFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Equal(t, []*model.AuditRecord{record2}, records)
+	})
+}
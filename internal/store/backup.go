@@ -0,0 +1,145 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+var backupSelect sq.SelectBuilder
+
+func init() {
+	backupSelect = sq.
+		Select("ID", "InstallationID", "State", "DatabaseSnapshotID",
+			"FilestoreLocation", "Size", "Error", "CreateAt", "CompleteAt",
+			"LockAcquiredBy", "LockAcquiredAt").
+		From("Backup")
+}
+
+// GetBackup fetches the given backup by id.
+func (sqlStore *SQLStore) GetBackup(id string) (*model.Backup, error) {
+	var backup model.Backup
+	err := sqlStore.getBuilder(sqlStore.db, &backup,
+		backupSelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get backup by id")
+	}
+
+	return &backup, nil
+}
+
+// GetBackups fetches the given page of backups, newest first. The first page
+// is 0.
+func (sqlStore *SQLStore) GetBackups(filter *model.BackupFilter) ([]*model.Backup, error) {
+	builder := backupSelect.
+		OrderBy("CreateAt DESC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if filter.InstallationID != "" {
+		builder = builder.Where("InstallationID = ?", filter.InstallationID)
+	}
+	if filter.State != "" {
+		builder = builder.Where("State = ?", filter.State)
+	}
+
+	var backups []*model.Backup
+	err := sqlStore.selectBuilder(sqlStore.db, &backups, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for backups")
+	}
+
+	return backups, nil
+}
+
+// GetUnlockedBackupsPendingWork returns unlocked backups which have yet to
+// be completed.
+func (sqlStore *SQLStore) GetUnlockedBackupsPendingWork() ([]*model.Backup, error) {
+	builder := backupSelect.
+		Where(sq.Eq{
+			"State": model.AllBackupStatesPendingWork,
+		}).
+		Where("LockAcquiredAt = 0").
+		OrderBy("CreateAt ASC")
+
+	var backups []*model.Backup
+	err := sqlStore.selectBuilder(sqlStore.db, &backups, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for backups")
+	}
+
+	return backups, nil
+}
+
+// CreateBackup records the given backup to the database, assigning it a
+// unique ID.
+func (sqlStore *SQLStore) CreateBackup(backup *model.Backup) error {
+	backup.ID = model.NewID()
+	backup.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("Backup").
+		SetMap(map[string]interface{}{
+			"ID":                 backup.ID,
+			"InstallationID":     backup.InstallationID,
+			"State":              backup.State,
+			"DatabaseSnapshotID": backup.DatabaseSnapshotID,
+			"FilestoreLocation":  backup.FilestoreLocation,
+			"Size":               backup.Size,
+			"Error":              backup.Error,
+			"CreateAt":           backup.CreateAt,
+			"CompleteAt":         backup.CompleteAt,
+			"LockAcquiredBy":     nil,
+			"LockAcquiredAt":     0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create backup")
+	}
+
+	return nil
+}
+
+// UpdateBackup updates the given backup in the database.
+func (sqlStore *SQLStore) UpdateBackup(backup *model.Backup) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update("Backup").
+		SetMap(map[string]interface{}{
+			"State":              backup.State,
+			"DatabaseSnapshotID": backup.DatabaseSnapshotID,
+			"FilestoreLocation":  backup.FilestoreLocation,
+			"Size":               backup.Size,
+			"Error":              backup.Error,
+			"CompleteAt":         backup.CompleteAt,
+		}).
+		Where("ID = ?", backup.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update backup")
+	}
+
+	return nil
+}
+
+// LockBackup marks the backup as locked for exclusive use by the caller.
+func (sqlStore *SQLStore) LockBackup(backupID, lockerID string) (bool, error) {
+	return sqlStore.lockRows("Backup", []string{backupID}, lockerID)
+}
+
+// UnlockBackup releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockBackup(backupID, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows("Backup", []string{backupID}, lockerID, force)
+}
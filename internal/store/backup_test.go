@@ -0,0 +1,131 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackups(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	t.Run("get unknown backup", func(t *testing.T) {
+		backup, err := sqlStore.GetBackup("unknown")
+		require.NoError(t, err)
+		require.Nil(t, backup)
+	})
+
+	installationID1 := model.NewID()
+	installationID2 := model.NewID()
+
+	backup1 := &model.Backup{
+		InstallationID: installationID1,
+		State:          model.BackupStateRequested,
+	}
+	err := sqlStore.CreateBackup(backup1)
+	require.NoError(t, err)
+	require.NotEmpty(t, backup1.ID)
+
+	time.Sleep(1 * time.Millisecond)
+
+	backup2 := &model.Backup{
+		InstallationID: installationID1,
+		State:          model.BackupStateInProgress,
+	}
+	err = sqlStore.CreateBackup(backup2)
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	backup3 := &model.Backup{
+		InstallationID: installationID2,
+		State:          model.BackupStateFailed,
+		Error:          "database is unavailable",
+	}
+	err = sqlStore.CreateBackup(backup3)
+	require.NoError(t, err)
+
+	t.Run("get backup", func(t *testing.T) {
+		actual, err := sqlStore.GetBackup(backup1.ID)
+		require.NoError(t, err)
+		require.Equal(t, backup1, actual)
+	})
+
+	t.Run("get backups", func(t *testing.T) {
+		testCases := []struct {
+			description string
+			filter      *model.BackupFilter
+			expected    []*model.Backup
+		}{
+			{
+				"all",
+				&model.BackupFilter{PerPage: model.AllPerPage},
+				[]*model.Backup{backup3, backup2, backup1},
+			},
+			{
+				"installation",
+				&model.BackupFilter{InstallationID: installationID1, PerPage: model.AllPerPage},
+				[]*model.Backup{backup2, backup1},
+			},
+			{
+				"state",
+				&model.BackupFilter{State: model.BackupStateFailed, PerPage: model.AllPerPage},
+				[]*model.Backup{backup3},
+			},
+			{
+				"page",
+				&model.BackupFilter{Page: 1, PerPage: 2},
+				[]*model.Backup{backup1},
+			},
+		}
+
+		for _, testCase := range testCases {
+			t.Run(testCase.description, func(t *testing.T) {
+				actual, err := sqlStore.GetBackups(testCase.filter)
+				require.NoError(t, err)
+				require.Equal(t, testCase.expected, actual)
+			})
+		}
+	})
+
+	t.Run("get unlocked backups pending work", func(t *testing.T) {
+		backups, err := sqlStore.GetUnlockedBackupsPendingWork()
+		require.NoError(t, err)
+		require.Equal(t, []*model.Backup{backup1, backup2}, backups)
+
+		locked, err := sqlStore.LockBackup(backup1.ID, "locker")
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		backups, err = sqlStore.GetUnlockedBackupsPendingWork()
+		require.NoError(t, err)
+		require.Equal(t, []*model.Backup{backup2}, backups)
+
+		unlocked, err := sqlStore.UnlockBackup(backup1.ID, "locker", false)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+	})
+
+	t.Run("update backup", func(t *testing.T) {
+		backup2.State = model.BackupStateSucceeded
+		backup2.DatabaseSnapshotID = "snapshot"
+		backup2.FilestoreLocation = "s3://backups/installation/backup/"
+		backup2.Size = 1024
+		backup2.CompleteAt = GetMillis()
+		err := sqlStore.UpdateBackup(backup2)
+		require.NoError(t, err)
+
+		actual, err := sqlStore.GetBackup(backup2.ID)
+		require.NoError(t, err)
+		require.Equal(t, backup2, actual)
+	})
+}
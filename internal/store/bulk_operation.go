@@ -0,0 +1,243 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+var bulkOperationSelect sq.SelectBuilder
+var bulkOperationResultSelect sq.SelectBuilder
+
+func init() {
+	bulkOperationSelect = sq.
+		Select("ID", "Action", "FilterRaw", "PatchRaw", "GroupID", "DryRun",
+			"Concurrency", "State", "Error", "CreateAt", "CompleteAt",
+			"LockAcquiredBy", "LockAcquiredAt").
+		From("BulkOperation")
+
+	bulkOperationResultSelect = sq.
+		Select("ID", "BulkOperationID", "InstallationID", "OldState", "NewState",
+			"Status", "Message", "CreateAt").
+		From("BulkOperationResult")
+}
+
+type rawBulkOperation struct {
+	*model.BulkOperation
+	FilterRaw []byte
+	PatchRaw  []byte
+}
+
+type rawBulkOperations []*rawBulkOperation
+
+func (r *rawBulkOperation) toBulkOperation() (*model.BulkOperation, error) {
+	// We only need to set values that are converted from a raw database format.
+	r.BulkOperation.Filter = &model.InstallationFilter{}
+	err := json.Unmarshal(r.FilterRaw, r.BulkOperation.Filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal bulk operation filter")
+	}
+
+	if r.PatchRaw != nil {
+		r.BulkOperation.Patch = &model.PatchInstallationRequest{}
+		err = json.Unmarshal(r.PatchRaw, r.BulkOperation.Patch)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal bulk operation patch")
+		}
+	}
+
+	return r.BulkOperation, nil
+}
+
+func (rs *rawBulkOperations) toBulkOperations() ([]*model.BulkOperation, error) {
+	var bulkOperations []*model.BulkOperation
+	for _, rawBulkOperation := range *rs {
+		bulkOperation, err := rawBulkOperation.toBulkOperation()
+		if err != nil {
+			return nil, err
+		}
+		bulkOperations = append(bulkOperations, bulkOperation)
+	}
+
+	return bulkOperations, nil
+}
+
+// GetBulkOperation fetches the given bulk operation by id.
+func (sqlStore *SQLStore) GetBulkOperation(id string) (*model.BulkOperation, error) {
+	var rawBulkOperation rawBulkOperation
+	err := sqlStore.getBuilder(sqlStore.db, &rawBulkOperation,
+		bulkOperationSelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get bulk operation by id")
+	}
+
+	return rawBulkOperation.toBulkOperation()
+}
+
+// GetBulkOperations fetches the given page of bulk operations, newest first.
+// The first page is 0.
+func (sqlStore *SQLStore) GetBulkOperations(filter *model.BulkOperationFilter) ([]*model.BulkOperation, error) {
+	builder := bulkOperationSelect.
+		OrderBy("CreateAt DESC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if filter.State != "" {
+		builder = builder.Where("State = ?", filter.State)
+	}
+
+	var rawBulkOperations rawBulkOperations
+	err := sqlStore.selectBuilder(sqlStore.db, &rawBulkOperations, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for bulk operations")
+	}
+
+	return rawBulkOperations.toBulkOperations()
+}
+
+// GetUnlockedBulkOperationsPendingWork returns unlocked bulk operations which
+// have yet to be run.
+func (sqlStore *SQLStore) GetUnlockedBulkOperationsPendingWork() ([]*model.BulkOperation, error) {
+	builder := bulkOperationSelect.
+		Where("State = ?", model.BulkOperationStatePending).
+		Where("LockAcquiredAt = 0").
+		OrderBy("CreateAt ASC")
+
+	var rawBulkOperations rawBulkOperations
+	err := sqlStore.selectBuilder(sqlStore.db, &rawBulkOperations, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for bulk operations")
+	}
+
+	return rawBulkOperations.toBulkOperations()
+}
+
+// CreateBulkOperation records the given bulk operation to the database,
+// assigning it a unique ID.
+func (sqlStore *SQLStore) CreateBulkOperation(bulkOperation *model.BulkOperation) error {
+	filterJSON, err := json.Marshal(bulkOperation.Filter)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal bulk operation filter")
+	}
+
+	var patchJSON []byte
+	if bulkOperation.Patch != nil {
+		patchJSON, err = json.Marshal(bulkOperation.Patch)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal bulk operation patch")
+		}
+	}
+
+	bulkOperation.ID = model.NewID()
+	bulkOperation.CreateAt = GetMillis()
+
+	_, err = sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("BulkOperation").
+		SetMap(map[string]interface{}{
+			"ID":             bulkOperation.ID,
+			"Action":         bulkOperation.Action,
+			"FilterRaw":      filterJSON,
+			"PatchRaw":       patchJSON,
+			"GroupID":        bulkOperation.GroupID,
+			"DryRun":         bulkOperation.DryRun,
+			"Concurrency":    bulkOperation.Concurrency,
+			"State":          bulkOperation.State,
+			"Error":          bulkOperation.Error,
+			"CreateAt":       bulkOperation.CreateAt,
+			"CompleteAt":     bulkOperation.CompleteAt,
+			"LockAcquiredBy": nil,
+			"LockAcquiredAt": 0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create bulk operation")
+	}
+
+	return nil
+}
+
+// UpdateBulkOperation updates the state of the given bulk operation in the
+// database.
+func (sqlStore *SQLStore) UpdateBulkOperation(bulkOperation *model.BulkOperation) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update("BulkOperation").
+		SetMap(map[string]interface{}{
+			"State":      bulkOperation.State,
+			"Error":      bulkOperation.Error,
+			"CompleteAt": bulkOperation.CompleteAt,
+		}).
+		Where("ID = ?", bulkOperation.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update bulk operation")
+	}
+
+	return nil
+}
+
+// LockBulkOperation marks the bulk operation as locked for exclusive use by
+// the caller.
+func (sqlStore *SQLStore) LockBulkOperation(bulkOperationID, lockerID string) (bool, error) {
+	return sqlStore.lockRows("BulkOperation", []string{bulkOperationID}, lockerID)
+}
+
+// UnlockBulkOperation releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockBulkOperation(bulkOperationID, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows("BulkOperation", []string{bulkOperationID}, lockerID, force)
+}
+
+// GetBulkOperationResults fetches the results recorded so far for the given
+// bulk operation, oldest first.
+func (sqlStore *SQLStore) GetBulkOperationResults(bulkOperationID string) ([]*model.BulkOperationResult, error) {
+	builder := bulkOperationResultSelect.
+		Where("BulkOperationID = ?", bulkOperationID).
+		OrderBy("CreateAt ASC", "InstallationID ASC")
+
+	var results []*model.BulkOperationResult
+	err := sqlStore.selectBuilder(sqlStore.db, &results, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for bulk operation results")
+	}
+
+	return results, nil
+}
+
+// CreateBulkOperationResult records the outcome of a bulk operation for a
+// single installation, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateBulkOperationResult(result *model.BulkOperationResult) error {
+	result.ID = model.NewID()
+	result.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("BulkOperationResult").
+		SetMap(map[string]interface{}{
+			"ID":              result.ID,
+			"BulkOperationID": result.BulkOperationID,
+			"InstallationID":  result.InstallationID,
+			"OldState":        result.OldState,
+			"NewState":        result.NewState,
+			"Status":          result.Status,
+			"Message":         result.Message,
+			"CreateAt":        result.CreateAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create bulk operation result")
+	}
+
+	return nil
+}
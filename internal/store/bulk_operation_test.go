@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOperations(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	t.Run("get unknown bulk operation", func(t *testing.T) {
+		bulkOperation, err := sqlStore.GetBulkOperation("unknown")
+		require.NoError(t, err)
+		require.Nil(t, bulkOperation)
+	})
+
+	version := "5.31.0"
+	bulkOperation1 := &model.BulkOperation{
+		Action: model.BulkActionUpdate,
+		Filter: &model.InstallationFilter{
+			OwnerID: "owner",
+			States:  []string{model.InstallationStateStable},
+		},
+		Patch:       &model.PatchInstallationRequest{Version: &version},
+		Concurrency: 5,
+		State:       model.BulkOperationStatePending,
+	}
+	err := sqlStore.CreateBulkOperation(bulkOperation1)
+	require.NoError(t, err)
+	require.NotEmpty(t, bulkOperation1.ID)
+
+	time.Sleep(1 * time.Millisecond)
+
+	bulkOperation2 := &model.BulkOperation{
+		Action:      model.BulkActionHibernate,
+		Filter:      &model.InstallationFilter{Annotations: []string{"trial"}},
+		DryRun:      true,
+		Concurrency: 1,
+		State:       model.BulkOperationStatePending,
+	}
+	err = sqlStore.CreateBulkOperation(bulkOperation2)
+	require.NoError(t, err)
+
+	t.Run("get bulk operation", func(t *testing.T) {
+		actual, err := sqlStore.GetBulkOperation(bulkOperation1.ID)
+		require.NoError(t, err)
+		require.Equal(t, bulkOperation1, actual)
+	})
+
+	t.Run("get bulk operations", func(t *testing.T) {
+		actual, err := sqlStore.GetBulkOperations(&model.BulkOperationFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Equal(t, []*model.BulkOperation{bulkOperation2, bulkOperation1}, actual)
+
+		actual, err = sqlStore.GetBulkOperations(&model.BulkOperationFilter{PerPage: 1, Page: 1})
+		require.NoError(t, err)
+		require.Equal(t, []*model.BulkOperation{bulkOperation1}, actual)
+	})
+
+	t.Run("lock and complete bulk operation", func(t *testing.T) {
+		lockerID := model.NewID()
+
+		locked, err := sqlStore.LockBulkOperation(bulkOperation1.ID, lockerID)
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		pending, err := sqlStore.GetUnlockedBulkOperationsPendingWork()
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, bulkOperation2.ID, pending[0].ID)
+
+		bulkOperation1.State = model.BulkOperationStateCompleted
+		bulkOperation1.CompleteAt = GetMillis()
+		err = sqlStore.UpdateBulkOperation(bulkOperation1)
+		require.NoError(t, err)
+
+		unlocked, err := sqlStore.UnlockBulkOperation(bulkOperation1.ID, lockerID, false)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+
+		actual, err := sqlStore.GetBulkOperation(bulkOperation1.ID)
+		require.NoError(t, err)
+		require.Equal(t, bulkOperation1, actual)
+
+		completed, err := sqlStore.GetBulkOperations(&model.BulkOperationFilter{
+			State:   model.BulkOperationStateCompleted,
+			PerPage: model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.BulkOperation{bulkOperation1}, completed)
+	})
+
+	t.Run("results", func(t *testing.T) {
+		results, err := sqlStore.GetBulkOperationResults(bulkOperation1.ID)
+		require.NoError(t, err)
+		require.Empty(t, results)
+
+		result1 := &model.BulkOperationResult{
+			BulkOperationID: bulkOperation1.ID,
+			InstallationID:  model.NewID(),
+			OldState:        model.InstallationStateStable,
+			NewState:        model.InstallationStateUpdateRequested,
+			Status:          model.BulkResultSucceeded,
+		}
+		err = sqlStore.CreateBulkOperationResult(result1)
+		require.NoError(t, err)
+
+		time.Sleep(1 * time.Millisecond)
+
+		result2 := &model.BulkOperationResult{
+			BulkOperationID: bulkOperation1.ID,
+			InstallationID:  model.NewID(),
+			OldState:        model.InstallationStateHibernating,
+			NewState:        model.InstallationStateHibernating,
+			Status:          model.BulkResultSkipped,
+			Message:         "invalid transition",
+		}
+		err = sqlStore.CreateBulkOperationResult(result2)
+		require.NoError(t, err)
+
+		err = sqlStore.CreateBulkOperationResult(&model.BulkOperationResult{
+			BulkOperationID: bulkOperation2.ID,
+			InstallationID:  result1.InstallationID,
+			Status:          model.BulkResultPlanned,
+		})
+		require.NoError(t, err)
+
+		results, err = sqlStore.GetBulkOperationResults(bulkOperation1.ID)
+		require.NoError(t, err)
+		require.Equal(t, []*model.BulkOperationResult{result1, result2}, results)
+
+		t.Run("only once per installation", func(t *testing.T) {
+			err = sqlStore.CreateBulkOperationResult(&model.BulkOperationResult{
+				BulkOperationID: bulkOperation1.ID,
+				InstallationID:  result1.InstallationID,
+				Status:          model.BulkResultFailed,
+			})
+			require.Error(t, err)
+		})
+	})
+}
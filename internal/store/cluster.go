@@ -18,7 +18,7 @@ var clusterSelect sq.SelectBuilder
 func init() {
 	clusterSelect = sq.
 		Select("ID", "Provider", "Provisioner", "ProviderMetadataRaw", "ProvisionerMetadataRaw",
-			"UtilityMetadataRaw", "State", "AllowInstallations", "CreateAt", "DeleteAt",
+			"UtilityMetadataRaw", "State", "AllowInstallations", "DrainMaxConcurrent", "CreateAt", "DeleteAt",
 			"APISecurityLock", "LockAcquiredBy", "LockAcquiredAt").
 		From("Cluster")
 }
@@ -104,9 +104,7 @@ func (sqlStore *SQLStore) GetCluster(id string) (*model.Cluster, error) {
 
 // GetClusters fetches the given page of created clusters. The first page is 0.
 func (sqlStore *SQLStore) GetClusters(filter *model.ClusterFilter) ([]*model.Cluster, error) {
-	builder := clusterSelect.
-		OrderBy("CreateAt ASC")
-	builder = sqlStore.applyClustersFilter(builder, filter)
+	builder := sqlStore.applyClustersFilter(clusterSelect, filter)
 
 	var rawClusters rawClusters
 	err := sqlStore.selectBuilder(sqlStore.db, &rawClusters, builder)
@@ -117,15 +115,42 @@ func (sqlStore *SQLStore) GetClusters(filter *model.ClusterFilter) ([]*model.Clu
 	return rawClusters.toClusters()
 }
 
-func (sqlStore *SQLStore) applyClustersFilter(builder sq.SelectBuilder, filter *model.ClusterFilter) sq.SelectBuilder {
-	if filter.PerPage != model.AllPerPage {
-		builder = builder.
-			Limit(uint64(filter.PerPage)).
-			Offset(uint64(filter.Page * filter.PerPage))
+// CountClusters returns the number of clusters matching the given filter
+// across every page.
+func (sqlStore *SQLStore) CountClusters(filter *model.ClusterFilter) (int64, error) {
+	builder := sqlStore.applyClustersFilterConditions(sq.Select("Count (*)").From("Cluster"), filter)
+
+	var result countResult
+	err := sqlStore.selectBuilder(sqlStore.db, &result, builder)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count clusters")
 	}
 
+	return result.value()
+}
+
+func (sqlStore *SQLStore) applyClustersFilter(builder sq.SelectBuilder, filter *model.ClusterFilter) sq.SelectBuilder {
+	builder = sqlStore.applyClustersFilterConditions(builder, filter)
+	builder = applyPaging(builder, "Cluster", filter.Page, filter.PerPage, filter.Cursor, filter.SortBy, filter.SortOrder)
+
+	return applySort(builder, "Cluster", filter.SortBy, filter.SortOrder)
+}
+
+func (sqlStore *SQLStore) applyClustersFilterConditions(builder sq.SelectBuilder, filter *model.ClusterFilter) sq.SelectBuilder {
 	if !filter.IncludeDeleted {
-		builder = builder.Where("DeleteAt = 0")
+		builder = builder.Where("Cluster.DeleteAt = 0")
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{"Cluster.State": filter.States})
+	}
+	if len(filter.Annotations) > 0 {
+		builder = builder.Where(withAllAnnotations("Cluster", filter.Annotations))
+	}
+	if filter.CreatedAfter > 0 {
+		builder = builder.Where("Cluster.CreateAt > ?", filter.CreatedAfter)
+	}
+	if filter.CreatedBefore > 0 {
+		builder = builder.Where("Cluster.CreateAt < ?", filter.CreatedBefore)
 	}
 
 	return builder
@@ -203,6 +228,7 @@ func (sqlStore *SQLStore) createCluster(execer execer, cluster *model.Cluster) e
 			"ProvisionerMetadataRaw": rawMetadata.ProvisionerMetadataRaw,
 			"UtilityMetadataRaw":     rawMetadata.UtilityMetadataRaw,
 			"AllowInstallations":     cluster.AllowInstallations,
+			"DrainMaxConcurrent":     cluster.DrainMaxConcurrent,
 			"CreateAt":               cluster.CreateAt,
 			"DeleteAt":               0,
 			"APISecurityLock":        cluster.APISecurityLock,
@@ -234,6 +260,7 @@ func (sqlStore *SQLStore) UpdateCluster(cluster *model.Cluster) error {
 			"ProvisionerMetadataRaw": rawMetadata.ProvisionerMetadataRaw,
 			"UtilityMetadataRaw":     rawMetadata.UtilityMetadataRaw,
 			"AllowInstallations":     cluster.AllowInstallations,
+			"DrainMaxConcurrent":     cluster.DrainMaxConcurrent,
 		}).
 		Where("ID = ?", cluster.ID),
 	)
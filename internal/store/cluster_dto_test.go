@@ -6,6 +6,7 @@ package store
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -56,6 +57,8 @@ func TestClusterDTOs(t *testing.T) {
 	err = sqlStore.CreateCluster(cluster1, annotations)
 	require.NoError(t, err)
 
+	time.Sleep(1 * time.Millisecond)
+
 	err = sqlStore.CreateCluster(cluster2, nil)
 	require.NoError(t, err)
 
@@ -86,15 +86,34 @@ func (sqlStore *SQLStore) CreateClusterInstallation(clusterInstallation *model.C
 
 // GetClusterInstallations fetches the given page of created clusters. The first page is 0.
 func (sqlStore *SQLStore) GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error) {
-	builder := clusterInstallationSelect.
-		OrderBy("CreateAt ASC")
+	builder := applyClusterInstallationFilter(clusterInstallationSelect, filter)
+	builder = applyPaging(builder, "ClusterInstallation", filter.Page, filter.PerPage, filter.Cursor, "", "")
+	builder = applySort(builder, "ClusterInstallation", "", "")
 
-	if filter.PerPage != model.AllPerPage {
-		builder = builder.
-			Limit(uint64(filter.PerPage)).
-			Offset(uint64(filter.Page * filter.PerPage))
+	var clusterInstallations []*model.ClusterInstallation
+	err := sqlStore.selectBuilder(sqlStore.db, &clusterInstallations, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for clusterInstallations")
 	}
 
+	return clusterInstallations, nil
+}
+
+// CountClusterInstallations returns the number of cluster installations
+// matching the given filter across every page.
+func (sqlStore *SQLStore) CountClusterInstallations(filter *model.ClusterInstallationFilter) (int64, error) {
+	builder := applyClusterInstallationFilter(sq.Select("Count (*)").From("ClusterInstallation"), filter)
+
+	var result countResult
+	err := sqlStore.selectBuilder(sqlStore.db, &result, builder)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count cluster installations")
+	}
+
+	return result.value()
+}
+
+func applyClusterInstallationFilter(builder sq.SelectBuilder, filter *model.ClusterInstallationFilter) sq.SelectBuilder {
 	if len(filter.IDs) > 0 {
 		builder = builder.Where(sq.Eq{"ID": filter.IDs})
 	}
@@ -104,17 +123,14 @@ func (sqlStore *SQLStore) GetClusterInstallations(filter *model.ClusterInstallat
 	if filter.InstallationID != "" {
 		builder = builder.Where("InstallationID = ?", filter.InstallationID)
 	}
+	if filter.OwnerID != "" {
+		builder = builder.Where("InstallationID IN (SELECT ID FROM Installation WHERE OwnerID = ?)", filter.OwnerID)
+	}
 	if !filter.IncludeDeleted {
 		builder = builder.Where("DeleteAt = 0")
 	}
 
-	var clusterInstallations []*model.ClusterInstallation
-	err := sqlStore.selectBuilder(sqlStore.db, &clusterInstallations, builder)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to query for clusterInstallations")
-	}
-
-	return clusterInstallations, nil
+	return builder
 }
 
 // UpdateClusterInstallation updates the given cluster installation in the database.
@@ -44,6 +44,8 @@ func TestGetClusterInstallations(t *testing.T) {
 	err = sqlStore.CreateClusterInstallation(clusterInstallation2)
 	require.NoError(t, err)
 
+	time.Sleep(1 * time.Millisecond)
+
 	clusterInstallation3 := &model.ClusterInstallation{
 		ClusterID:      clusterID2,
 		InstallationID: installationID1,
@@ -54,6 +56,8 @@ func TestGetClusterInstallations(t *testing.T) {
 	err = sqlStore.CreateClusterInstallation(clusterInstallation3)
 	require.NoError(t, err)
 
+	time.Sleep(1 * time.Millisecond)
+
 	clusterInstallation4 := &model.ClusterInstallation{
 		ClusterID:      clusterID2,
 		InstallationID: installationID2,
@@ -238,6 +242,14 @@ func TestGetClusterInstallations(t *testing.T) {
 			},
 			[]*model.ClusterInstallation{clusterInstallation1, clusterInstallation4},
 		},
+		{
+			"cursor after cluster installation 2, perPage 1",
+			&model.ClusterInstallationFilter{
+				PerPage: 1,
+				Cursor:  &model.PageCursor{CreateAt: clusterInstallation2.CreateAt, ID: clusterInstallation2.ID},
+			},
+			[]*model.ClusterInstallation{clusterInstallation3},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -247,6 +259,69 @@ func TestGetClusterInstallations(t *testing.T) {
 			require.Equal(t, testCase.Expected, actual)
 		})
 	}
+
+	t.Run("count cluster installations", func(t *testing.T) {
+		count, err := sqlStore.CountClusterInstallations(&model.ClusterInstallationFilter{ClusterID: clusterID2, PerPage: 1})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, count)
+
+		count, err = sqlStore.CountClusterInstallations(&model.ClusterInstallationFilter{ClusterID: clusterID2, PerPage: 1, IncludeDeleted: true})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, count)
+	})
+}
+
+func TestGetClusterInstallationsByOwner(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+
+	installation1 := &model.Installation{
+		OwnerID: "owner1",
+		DNS:     "dns1.example.com",
+		State:   model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation1, nil)
+	require.NoError(t, err)
+
+	installation2 := &model.Installation{
+		OwnerID: "owner2",
+		DNS:     "dns2.example.com",
+		State:   model.InstallationStateStable,
+	}
+	err = sqlStore.CreateInstallation(installation2, nil)
+	require.NoError(t, err)
+
+	clusterInstallation1 := &model.ClusterInstallation{
+		ClusterID:      model.NewID(),
+		InstallationID: installation1.ID,
+		Namespace:      "namespace_1",
+		State:          model.ClusterInstallationStateStable,
+	}
+	err = sqlStore.CreateClusterInstallation(clusterInstallation1)
+	require.NoError(t, err)
+
+	clusterInstallation2 := &model.ClusterInstallation{
+		ClusterID:      model.NewID(),
+		InstallationID: installation2.ID,
+		Namespace:      "namespace_2",
+		State:          model.ClusterInstallationStateStable,
+	}
+	err = sqlStore.CreateClusterInstallation(clusterInstallation2)
+	require.NoError(t, err)
+
+	actual, err := sqlStore.GetClusterInstallations(&model.ClusterInstallationFilter{
+		OwnerID: "owner1",
+		PerPage: model.AllPerPage,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []*model.ClusterInstallation{clusterInstallation1}, actual)
+
+	actual, err = sqlStore.GetClusterInstallations(&model.ClusterInstallationFilter{
+		OwnerID: "unknown",
+		PerPage: model.AllPerPage,
+	})
+	require.NoError(t, err)
+	require.Empty(t, actual)
 }
 
 func TestGetUnlockedClusterInstallationPendingWork(t *testing.T) {
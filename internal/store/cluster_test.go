@@ -252,6 +252,7 @@ func TestGetUnlockedClustersPendingWork(t *testing.T) {
 		model.ClusterStateDeletionFailed,
 		model.ClusterStateDeleted,
 		model.ClusterStateUpgradeFailed,
+		model.ClusterStateDrainFailed,
 		model.ClusterStateStable,
 	}
 	for _, otherState := range otherStates {
@@ -0,0 +1,77 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+var eventSelect sq.SelectBuilder
+
+func init() {
+	eventSelect = sq.
+		Select("ID", "ResourceType", "ResourceID", "OldState", "NewState",
+			"InstanceID", "Error", "CreateAt").
+		From("Event")
+}
+
+// GetEvents fetches the given page of events, newest first. The first page is 0.
+func (sqlStore *SQLStore) GetEvents(filter *model.EventFilter) ([]*model.Event, error) {
+	builder := eventSelect.
+		OrderBy("CreateAt DESC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if filter.ResourceType != "" {
+		builder = builder.Where("ResourceType = ?", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		builder = builder.Where("ResourceID = ?", filter.ResourceID)
+	}
+	if filter.GroupID != "" {
+		builder = builder.
+			Where("ResourceType = ?", model.TypeInstallation).
+			Where("ResourceID IN (SELECT ID FROM Installation WHERE GroupID = ?)", filter.GroupID)
+	}
+
+	var events []*model.Event
+	err := sqlStore.selectBuilder(sqlStore.db, &events, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for events")
+	}
+
+	return events, nil
+}
+
+// CreateEvent records the given event to the database, assigning it a unique ID.
+func (sqlStore *SQLStore) CreateEvent(event *model.Event) error {
+	event.ID = model.NewID()
+	event.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("Event").
+		SetMap(map[string]interface{}{
+			"ID":           event.ID,
+			"ResourceType": event.ResourceType,
+			"ResourceID":   event.ResourceID,
+			"OldState":     event.OldState,
+			"NewState":     event.NewState,
+			"InstanceID":   event.InstanceID,
+			"Error":        event.Error,
+			"CreateAt":     event.CreateAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create event")
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+
+	group := &model.Group{Name: "group"}
+	err := sqlStore.CreateGroup(group)
+	require.NoError(t, err)
+
+	installation := &model.Installation{
+		OwnerID: "owner",
+		DNS:     "dns.example.com",
+		GroupID: &group.ID,
+		State:   model.InstallationStateCreationRequested,
+	}
+	err = sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	clusterID := model.NewID()
+
+	event1 := &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installation.ID,
+		OldState:     model.InstallationStateCreationRequested,
+		NewState:     model.InstallationStateCreationInProgress,
+		InstanceID:   "instance1",
+	}
+	err = sqlStore.CreateEvent(event1)
+	require.NoError(t, err)
+	require.NotEmpty(t, event1.ID)
+	require.NotEqual(t, 0, event1.CreateAt)
+
+	time.Sleep(1 * time.Millisecond)
+
+	event2 := &model.Event{
+		ResourceType: model.TypeCluster,
+		ResourceID:   clusterID,
+		OldState:     model.ClusterStateCreationRequested,
+		NewState:     model.ClusterStateCreationFailed,
+		InstanceID:   "instance2",
+		Error:        "failed to create cluster",
+	}
+	err = sqlStore.CreateEvent(event2)
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	event3 := &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installation.ID,
+		OldState:     model.InstallationStateCreationInProgress,
+		NewState:     model.InstallationStateUpdateFailed,
+		InstanceID:   "instance1",
+		Error:        "failed to update",
+	}
+	err = sqlStore.CreateEvent(event3)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		Description string
+		Filter      *model.EventFilter
+		Expected    []*model.Event
+	}{
+		{
+			"all",
+			&model.EventFilter{PerPage: model.AllPerPage},
+			[]*model.Event{event3, event2, event1},
+		},
+		{
+			"installation",
+			&model.EventFilter{ResourceType: model.TypeInstallation, ResourceID: installation.ID, PerPage: model.AllPerPage},
+			[]*model.Event{event3, event1},
+		},
+		{
+			"installation, paged",
+			&model.EventFilter{ResourceType: model.TypeInstallation, ResourceID: installation.ID, Page: 1, PerPage: 1},
+			[]*model.Event{event1},
+		},
+		{
+			"cluster",
+			&model.EventFilter{ResourceType: model.TypeCluster, ResourceID: clusterID, PerPage: model.AllPerPage},
+			[]*model.Event{event2},
+		},
+		{
+			"group",
+			&model.EventFilter{GroupID: group.ID, PerPage: model.AllPerPage},
+			[]*model.Event{event3, event1},
+		},
+		{
+			"unknown resource",
+			&model.EventFilter{ResourceType: model.TypeCluster, ResourceID: model.NewID(), PerPage: model.AllPerPage},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			events, err := sqlStore.GetEvents(tc.Filter)
+			require.NoError(t, err)
+			require.Equal(t, tc.Expected, events)
+		})
+	}
+}
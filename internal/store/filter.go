@@ -0,0 +1,122 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+)
+
+// withAllAnnotations builds a condition matching the rows of the given table
+// to which every one of the given annotations is assigned.
+func withAllAnnotations(table string, annotations []string) sq.Sqlizer {
+	names := make([]interface{}, 0, len(annotations))
+	seen := make(map[string]bool, len(annotations))
+	for _, annotation := range annotations {
+		if seen[annotation] {
+			continue
+		}
+		seen[annotation] = true
+		names = append(names, annotation)
+	}
+
+	mappingTable := table + "Annotation"
+	mappingColumn := fmt.Sprintf("%s.%sID", mappingTable, table)
+	query := fmt.Sprintf(
+		"%s.ID IN (SELECT %s FROM %s JOIN Annotation ON Annotation.ID = %s.AnnotationID WHERE Annotation.Name IN (%s) GROUP BY %s HAVING COUNT(DISTINCT Annotation.Name) = ?)",
+		table, mappingColumn, mappingTable, mappingTable, sq.Placeholders(len(names)), mappingColumn,
+	)
+
+	return sq.Expr(query, append(names, len(names))...)
+}
+
+// sortColumn returns the column corresponding to the given sort key.
+func sortColumn(sortBy string) string {
+	switch sortBy {
+	case model.SortByState:
+		return "State"
+	case model.SortByOwner:
+		return "OwnerID"
+	case model.SortByDNS:
+		return "DNS"
+	case model.SortByVersion:
+		return "Version"
+	default:
+		return "CreateAt"
+	}
+}
+
+// applySort orders the query by the column of the given table corresponding
+// to the given sort key, breaking ties by creation time and then ID so that
+// the order is stable across pages. Sort keys are expected to have been
+// validated against the supported ones.
+func applySort(builder sq.SelectBuilder, table, sortBy, sortOrder string) sq.SelectBuilder {
+	direction := "ASC"
+	if sortOrder == model.SortOrderDescending {
+		direction = "DESC"
+	}
+
+	column := sortColumn(sortBy)
+	orderBy := []string{fmt.Sprintf("%s.%s %s", table, column, direction)}
+	if column != "CreateAt" {
+		orderBy = append(orderBy, fmt.Sprintf("%s.CreateAt %s", table, direction))
+	}
+	orderBy = append(orderBy, fmt.Sprintf("%s.ID %s", table, direction))
+
+	return builder.OrderBy(strings.Join(orderBy, ", "))
+}
+
+// applyPaging limits the query, sorted as by applySort, to the requested page.
+// A cursor takes precedence over the page number, selecting the rows sorted
+// after the one the cursor was taken from.
+func applyPaging(builder sq.SelectBuilder, table string, page, perPage int, cursor *model.PageCursor, sortBy, sortOrder string) sq.SelectBuilder {
+	if cursor != nil {
+		builder = builder.Where(afterCursor(table, cursor, sortBy, sortOrder))
+	}
+
+	if perPage == model.AllPerPage {
+		return builder
+	}
+
+	builder = builder.Limit(uint64(perPage))
+	if cursor == nil {
+		builder = builder.Offset(uint64(page * perPage))
+	}
+
+	return builder
+}
+
+// afterCursor builds a condition matching the rows of the given table sorted
+// after the one the given cursor was taken from.
+func afterCursor(table string, cursor *model.PageCursor, sortBy, sortOrder string) sq.Sqlizer {
+	operator := ">"
+	if sortOrder == model.SortOrderDescending {
+		operator = "<"
+	}
+
+	after := sq.Or{
+		sq.Expr(fmt.Sprintf("%s.CreateAt %s ?", table, operator), cursor.CreateAt),
+		sq.And{
+			sq.Expr(fmt.Sprintf("%s.CreateAt = ?", table), cursor.CreateAt),
+			sq.Expr(fmt.Sprintf("%s.ID %s ?", table, operator), cursor.ID),
+		},
+	}
+
+	column := sortColumn(sortBy)
+	if column == "CreateAt" {
+		return after
+	}
+
+	return sq.Or{
+		sq.Expr(fmt.Sprintf("%s.%s %s ?", table, column, operator), cursor.SortValue),
+		sq.And{
+			sq.Expr(fmt.Sprintf("%s.%s = ?", table, column), cursor.SortValue),
+			after,
+		},
+	}
+}
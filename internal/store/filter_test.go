@@ -0,0 +1,351 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterFilters(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	cluster1 := &model.Cluster{State: model.ClusterStateStable}
+	err := sqlStore.CreateCluster(cluster1, []*model.Annotation{{Name: "multi-tenant"}, {Name: "large"}})
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	cluster2 := &model.Cluster{State: model.ClusterStateCreationRequested}
+	err = sqlStore.CreateCluster(cluster2, []*model.Annotation{{Name: "multi-tenant"}})
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	cluster3 := &model.Cluster{State: model.ClusterStateUpgradeRequested}
+	err = sqlStore.CreateCluster(cluster3, nil)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		Description string
+		Filter      *model.ClusterFilter
+		Expected    []*model.Cluster
+	}{
+		{
+			"states",
+			&model.ClusterFilter{PerPage: model.AllPerPage, States: []string{model.ClusterStateStable, model.ClusterStateUpgradeRequested}},
+			[]*model.Cluster{cluster1, cluster3},
+		},
+		{
+			"one annotation",
+			&model.ClusterFilter{PerPage: model.AllPerPage, Annotations: []string{"multi-tenant"}},
+			[]*model.Cluster{cluster1, cluster2},
+		},
+		{
+			"all annotations",
+			&model.ClusterFilter{PerPage: model.AllPerPage, Annotations: []string{"multi-tenant", "large", "large"}},
+			[]*model.Cluster{cluster1},
+		},
+		{
+			"unknown annotation",
+			&model.ClusterFilter{PerPage: model.AllPerPage, Annotations: []string{"unknown"}},
+			nil,
+		},
+		{
+			"created after and before",
+			&model.ClusterFilter{PerPage: model.AllPerPage, CreatedAfter: cluster1.CreateAt, CreatedBefore: cluster3.CreateAt},
+			[]*model.Cluster{cluster2},
+		},
+		{
+			"sort by creation descending",
+			&model.ClusterFilter{PerPage: model.AllPerPage, SortOrder: model.SortOrderDescending},
+			[]*model.Cluster{cluster3, cluster2, cluster1},
+		},
+		{
+			"sort by state",
+			&model.ClusterFilter{PerPage: model.AllPerPage, SortBy: model.SortByState},
+			[]*model.Cluster{cluster2, cluster1, cluster3},
+		},
+		{
+			"sort and page",
+			&model.ClusterFilter{Page: 1, PerPage: 2, SortBy: model.SortByState, SortOrder: model.SortOrderDescending},
+			[]*model.Cluster{cluster2},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Description, func(t *testing.T) {
+			actual, err := sqlStore.GetClusters(testCase.Filter)
+			require.NoError(t, err)
+			require.Equal(t, testCase.Expected, actual)
+		})
+	}
+
+	t.Run("cluster DTOs", func(t *testing.T) {
+		clusters, err := sqlStore.GetClusterDTOs(&model.ClusterFilter{PerPage: model.AllPerPage, Annotations: []string{"large"}})
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+		require.Equal(t, cluster1.ID, clusters[0].ID)
+		require.Len(t, clusters[0].Annotations, 2)
+	})
+}
+
+func TestInstallationFilters(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation1 := &model.Installation{
+		OwnerID:   "owner-b",
+		Version:   "5.30.0",
+		Image:     "mattermost/mattermost-enterprise-edition",
+		DNS:       "a.example.com",
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+		Size:      "100users",
+		Affinity:  model.InstallationAffinityIsolated,
+		State:     model.InstallationStateStable,
+	}
+	err := sqlStore.CreateInstallation(installation1, []*model.Annotation{{Name: "team"}, {Name: "trial"}})
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	installation2 := &model.Installation{
+		OwnerID:   "owner-a",
+		Version:   "5.31.0",
+		Image:     "mattermost/mattermost-team-edition",
+		DNS:       "c.example.com",
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreMultiTenantAwsS3,
+		Size:      "1000users",
+		Affinity:  model.InstallationAffinityMultiTenant,
+		State:     model.InstallationStateHibernating,
+	}
+	err = sqlStore.CreateInstallation(installation2, []*model.Annotation{{Name: "team"}})
+	require.NoError(t, err)
+
+	time.Sleep(1 * time.Millisecond)
+
+	installation3 := &model.Installation{
+		OwnerID:   "owner-a",
+		Version:   "5.30.0",
+		Image:     "mattermost/mattermost-enterprise-edition",
+		DNS:       "b.example.com",
+		Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreMultiTenantAwsS3,
+		Size:      "100users",
+		Affinity:  model.InstallationAffinityMultiTenant,
+		State:     model.InstallationStateCreationRequested,
+	}
+	err = sqlStore.CreateInstallation(installation3, nil)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		Description string
+		Filter      *model.InstallationFilter
+		Expected    []*model.Installation
+	}{
+		{
+			"states",
+			&model.InstallationFilter{PerPage: model.AllPerPage, States: []string{model.InstallationStateStable, model.InstallationStateHibernating}},
+			[]*model.Installation{installation1, installation2},
+		},
+		{
+			"all annotations",
+			&model.InstallationFilter{PerPage: model.AllPerPage, Annotations: []string{"team", "trial"}},
+			[]*model.Installation{installation1},
+		},
+		{
+			"version",
+			&model.InstallationFilter{PerPage: model.AllPerPage, Version: "5.30.0"},
+			[]*model.Installation{installation1, installation3},
+		},
+		{
+			"image",
+			&model.InstallationFilter{PerPage: model.AllPerPage, Image: "mattermost/mattermost-team-edition"},
+			[]*model.Installation{installation2},
+		},
+		{
+			"database and filestore",
+			&model.InstallationFilter{PerPage: model.AllPerPage, Database: model.InstallationDatabaseMultiTenantRDSPostgres, Filestore: model.InstallationFilestoreMultiTenantAwsS3},
+			[]*model.Installation{installation2, installation3},
+		},
+		{
+			"size and affinity",
+			&model.InstallationFilter{PerPage: model.AllPerPage, Size: "100users", Affinity: model.InstallationAffinityMultiTenant},
+			[]*model.Installation{installation3},
+		},
+		{
+			"created after",
+			&model.InstallationFilter{PerPage: model.AllPerPage, CreatedAfter: installation1.CreateAt},
+			[]*model.Installation{installation2, installation3},
+		},
+		{
+			"created before",
+			&model.InstallationFilter{PerPage: model.AllPerPage, CreatedBefore: installation2.CreateAt},
+			[]*model.Installation{installation1},
+		},
+		{
+			"sort by DNS",
+			&model.InstallationFilter{PerPage: model.AllPerPage, SortBy: model.SortByDNS},
+			[]*model.Installation{installation1, installation3, installation2},
+		},
+		{
+			"sort by owner descending",
+			&model.InstallationFilter{PerPage: model.AllPerPage, SortBy: model.SortByOwner, SortOrder: model.SortOrderDescending},
+			[]*model.Installation{installation1, installation3, installation2},
+		},
+		{
+			"sort by version",
+			&model.InstallationFilter{PerPage: model.AllPerPage, SortBy: model.SortByVersion},
+			[]*model.Installation{installation1, installation3, installation2},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Description, func(t *testing.T) {
+			actual, err := sqlStore.GetInstallations(testCase.Filter, false, false)
+			require.NoError(t, err)
+			require.Equal(t, testCase.Expected, actual)
+		})
+	}
+}
+
+func TestCursorPaging(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	// Installations are created without pausing so that some share a
+	// creation time, which cursors must still tell apart.
+	for i, owner := range []string{"owner2", "owner1", "owner2", "owner3", "owner1", "owner1", "owner2"} {
+		installation := &model.Installation{
+			OwnerID: owner,
+			DNS:     model.NewID() + ".example.com",
+			Version: fmt.Sprintf("5.3%d.0", i%3),
+			State:   model.InstallationStateStable,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+	}
+
+	walk := func(t *testing.T, filter model.InstallationFilter) []*model.Installation {
+		var walked []*model.Installation
+		for {
+			page, err := sqlStore.GetInstallations(&filter, false, false)
+			require.NoError(t, err)
+			walked = append(walked, page...)
+			if len(page) < filter.PerPage {
+				return walked
+			}
+			filter.Cursor = model.NewInstallationCursor(page[len(page)-1], filter.SortBy)
+		}
+	}
+
+	for _, sortBy := range model.InstallationSortKeys {
+		for _, sortOrder := range []string{model.SortOrderAscending, model.SortOrderDescending} {
+			t.Run(sortBy+" "+sortOrder, func(t *testing.T) {
+				all, err := sqlStore.GetInstallations(&model.InstallationFilter{
+					PerPage:   model.AllPerPage,
+					SortBy:    sortBy,
+					SortOrder: sortOrder,
+				}, false, false)
+				require.NoError(t, err)
+				require.Len(t, all, 7)
+
+				require.Equal(t, all, walk(t, model.InstallationFilter{PerPage: 2, SortBy: sortBy, SortOrder: sortOrder}))
+			})
+		}
+	}
+
+	t.Run("filtered", func(t *testing.T) {
+		filter := model.InstallationFilter{OwnerID: "owner1", PerPage: 2, SortBy: model.SortByDNS}
+		require.Len(t, walk(t, filter), 3)
+
+		count, err := sqlStore.CountInstallations(&filter)
+		require.NoError(t, err)
+		require.EqualValues(t, 3, count)
+	})
+
+	t.Run("counts ignore paging", func(t *testing.T) {
+		installations, err := sqlStore.GetInstallations(&model.InstallationFilter{PerPage: 2}, false, false)
+		require.NoError(t, err)
+
+		count, err := sqlStore.CountInstallations(&model.InstallationFilter{
+			PerPage: 2,
+			Page:    1,
+			Cursor:  model.NewInstallationCursor(installations[1], ""),
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 7, count)
+
+		count, err = sqlStore.CountClusters(&model.ClusterFilter{PerPage: 1})
+		require.NoError(t, err)
+		require.EqualValues(t, 0, count)
+	})
+}
+
+func TestSortTiebreak(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	var ids []string
+	for i := 0; i < 5; i++ {
+		installation := &model.Installation{
+			OwnerID: "owner",
+			DNS:     model.NewID() + ".example.com",
+			State:   model.InstallationStateStable,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+		ids = append(ids, installation.ID)
+	}
+
+	// Give every installation the same creation time, leaving only their
+	// IDs to order them.
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.Update("Installation").Set("CreateAt", 1))
+	require.NoError(t, err)
+
+	sort.Strings(ids)
+	reversed := make([]string, len(ids))
+	for i, id := range ids {
+		reversed[len(ids)-1-i] = id
+	}
+
+	for sortOrder, expected := range map[string][]string{
+		model.SortOrderAscending:  ids,
+		model.SortOrderDescending: reversed,
+	} {
+		t.Run(sortOrder, func(t *testing.T) {
+			filter := model.InstallationFilter{PerPage: 2, SortBy: model.SortByOwner, SortOrder: sortOrder}
+
+			var walked []string
+			for {
+				page, err := sqlStore.GetInstallations(&filter, false, false)
+				require.NoError(t, err)
+				for _, installation := range page {
+					walked = append(walked, installation.ID)
+				}
+				if len(page) < filter.PerPage {
+					break
+				}
+				filter.Cursor = model.NewInstallationCursor(page[len(page)-1], filter.SortBy)
+			}
+
+			require.Equal(t, expected, walked)
+		})
+	}
+}
@@ -17,7 +17,8 @@ var groupSelect sq.SelectBuilder
 
 type rawGroup struct {
 	*model.Group
-	MattermostEnvRaw []byte
+	MattermostEnvRaw       []byte
+	HibernationScheduleRaw []byte
 }
 
 type rawGroups []*rawGroup
@@ -26,7 +27,8 @@ func init() {
 	groupSelect = sq.
 		Select("ID", "Name", "Description", "Version", "Image", "Sequence",
 			"CreateAt", "DeleteAt", "MattermostEnvRaw", "MaxRolling",
-			"APISecurityLock", "LockAcquiredBy", "LockAcquiredAt").
+			"APISecurityLock", "LockAcquiredBy", "LockAcquiredAt",
+			"HibernationScheduleRaw").
 		From(`"Group"`)
 }
 
@@ -42,6 +44,12 @@ func (r *rawGroup) toGroup() (*model.Group, error) {
 	}
 
 	r.Group.MattermostEnv = *mattermostEnv
+
+	r.Group.HibernationSchedule, err = hibernationScheduleFromJSON(r.HibernationScheduleRaw)
+	if err != nil {
+		return nil, err
+	}
+
 	return r.Group, nil
 }
 
@@ -269,18 +277,9 @@ func (sqlStore *SQLStore) GetGroup(id string) (*model.Group, error) {
 
 // GetGroups fetches the given page of created groups. The first page is 0.
 func (sqlStore *SQLStore) GetGroups(filter *model.GroupFilter) ([]*model.Group, error) {
-	builder := groupSelect.
-		OrderBy("CreateAt ASC")
-
-	if filter.PerPage != model.AllPerPage {
-		builder = builder.
-			Limit(uint64(filter.PerPage)).
-			Offset(uint64(filter.Page * filter.PerPage))
-	}
-
-	if !filter.IncludeDeleted {
-		builder = builder.Where("DeleteAt = 0")
-	}
+	builder := applyGroupFilter(groupSelect, filter)
+	builder = applyPaging(builder, `"Group"`, filter.Page, filter.PerPage, filter.Cursor, "", "")
+	builder = applySort(builder, `"Group"`, "", "")
 
 	var rawGroups rawGroups
 	err := sqlStore.selectBuilder(sqlStore.db, &rawGroups, builder)
@@ -291,6 +290,28 @@ func (sqlStore *SQLStore) GetGroups(filter *model.GroupFilter) ([]*model.Group,
 	return rawGroups.toGroups()
 }
 
+// CountGroups returns the number of groups matching the given filter across
+// every page.
+func (sqlStore *SQLStore) CountGroups(filter *model.GroupFilter) (int64, error) {
+	builder := applyGroupFilter(sq.Select("Count (*)").From(`"Group"`), filter)
+
+	var result countResult
+	err := sqlStore.selectBuilder(sqlStore.db, &result, builder)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count groups")
+	}
+
+	return result.value()
+}
+
+func applyGroupFilter(builder sq.SelectBuilder, filter *model.GroupFilter) sq.SelectBuilder {
+	if !filter.IncludeDeleted {
+		builder = builder.Where("DeleteAt = 0")
+	}
+
+	return builder
+}
+
 // CreateGroup records the given group to the database, assigning it a unique ID.
 func (sqlStore *SQLStore) CreateGroup(group *model.Group) error {
 	group.ID = model.NewID()
@@ -299,23 +320,28 @@ func (sqlStore *SQLStore) CreateGroup(group *model.Group) error {
 	if err != nil {
 		return err
 	}
+	hibernationScheduleJSON, err := hibernationScheduleToJSON(group.HibernationSchedule)
+	if err != nil {
+		return err
+	}
 
 	_, err = sqlStore.execBuilder(sqlStore.db, sq.
 		Insert(`"Group"`).
 		SetMap(map[string]interface{}{
-			"ID":               group.ID,
-			"Sequence":         0,
-			"Name":             group.Name,
-			"Image":            group.Image,
-			"Description":      group.Description,
-			"Version":          group.Version,
-			"MattermostEnvRaw": envVarMap,
-			"MaxRolling":       group.MaxRolling,
-			"CreateAt":         group.CreateAt,
-			"DeleteAt":         0,
-			"APISecurityLock":  group.APISecurityLock,
-			"LockAcquiredBy":   nil,
-			"LockAcquiredAt":   0,
+			"ID":                     group.ID,
+			"Sequence":               0,
+			"Name":                   group.Name,
+			"Image":                  group.Image,
+			"Description":            group.Description,
+			"Version":                group.Version,
+			"MattermostEnvRaw":       envVarMap,
+			"MaxRolling":             group.MaxRolling,
+			"CreateAt":               group.CreateAt,
+			"DeleteAt":               0,
+			"APISecurityLock":        group.APISecurityLock,
+			"LockAcquiredBy":         nil,
+			"LockAcquiredAt":         0,
+			"HibernationScheduleRaw": hibernationScheduleJSON,
 		}),
 	)
 	if err != nil {
@@ -344,16 +370,21 @@ func (sqlStore *SQLStore) UpdateGroup(group *model.Group) error {
 	if err != nil {
 		return err
 	}
+	hibernationScheduleJSON, err := hibernationScheduleToJSON(group.HibernationSchedule)
+	if err != nil {
+		return err
+	}
 	_, err = sqlStore.execBuilder(sqlStore.db, sq.
 		Update(`"Group"`).
 		SetMap(map[string]interface{}{
-			"Sequence":         group.Sequence,
-			"Name":             group.Name,
-			"Description":      group.Description,
-			"Version":          group.Version,
-			"Image":            group.Image,
-			"MattermostEnvRaw": envVarMap,
-			"MaxRolling":       group.MaxRolling,
+			"Sequence":               group.Sequence,
+			"Name":                   group.Name,
+			"Description":            group.Description,
+			"Version":                group.Version,
+			"Image":                  group.Image,
+			"MattermostEnvRaw":       envVarMap,
+			"MaxRolling":             group.MaxRolling,
+			"HibernationScheduleRaw": hibernationScheduleJSON,
 		}).
 		Where("ID = ?", group.ID),
 	)
@@ -169,6 +169,14 @@ func TestGroups(t *testing.T) {
 			},
 			[]*model.Group{group1, group2, group3, group4},
 		},
+		{
+			"cursor after group 1, perPage 1",
+			&model.GroupFilter{
+				PerPage: 1,
+				Cursor:  &model.PageCursor{CreateAt: group1.CreateAt, ID: group1.ID},
+			},
+			[]*model.Group{group2},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -178,6 +186,16 @@ func TestGroups(t *testing.T) {
 			assert.Equal(t, testCase.Expected, actual)
 		})
 	}
+
+	t.Run("count groups", func(t *testing.T) {
+		count, err := sqlStore.CountGroups(&model.GroupFilter{PerPage: 1})
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+
+		count, err = sqlStore.CountGroups(&model.GroupFilter{PerPage: 1, IncludeDeleted: true})
+		require.NoError(t, err)
+		assert.EqualValues(t, 4, count)
+	})
 }
 
 func TestLockGroup(t *testing.T) {
@@ -0,0 +1,42 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"encoding/json"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// hibernationScheduleToJSON encodes the given hibernation schedule for
+// storage, returning nil if there is none.
+func hibernationScheduleToJSON(schedule *model.HibernationSchedule) ([]byte, error) {
+	if schedule == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to marshal HibernationSchedule")
+	}
+
+	return data, nil
+}
+
+// hibernationScheduleFromJSON decodes a stored hibernation schedule.
+func hibernationScheduleFromJSON(data []byte) (*model.HibernationSchedule, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var schedule model.HibernationSchedule
+	err := json.Unmarshal(data, &schedule)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal hibernation schedule")
+	}
+
+	return &schedule, nil
+}
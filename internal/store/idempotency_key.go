@@ -0,0 +1,126 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+var idempotencyKeySelect sq.SelectBuilder
+
+func init() {
+	idempotencyKeySelect = sq.
+		Select("ID", "RequestKey", "APIKeyID", "RequestHash", "StatusCode", "ResponseBody", "CreateAt").
+		From("IdempotencyKey")
+}
+
+// GetIdempotencyKey fetches the idempotency key sent by the given caller.
+func (sqlStore *SQLStore) GetIdempotencyKey(requestKey, apiKeyID string) (*model.IdempotencyKey, error) {
+	var idempotencyKey model.IdempotencyKey
+	err := sqlStore.getBuilder(sqlStore.db, &idempotencyKey,
+		idempotencyKeySelect.Where(sq.Eq{
+			"RequestKey": requestKey,
+			"APIKeyID":   apiKeyID,
+		}),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get idempotency key")
+	}
+
+	return &idempotencyKey, nil
+}
+
+// CreateIdempotencyKey records the given idempotency key before its request
+// is served. Creation fails if the caller already sent the same key.
+func (sqlStore *SQLStore) CreateIdempotencyKey(idempotencyKey *model.IdempotencyKey) error {
+	idempotencyKey.ID = model.NewID()
+	idempotencyKey.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("IdempotencyKey").
+		SetMap(map[string]interface{}{
+			"ID":           idempotencyKey.ID,
+			"RequestKey":   idempotencyKey.RequestKey,
+			"APIKeyID":     idempotencyKey.APIKeyID,
+			"RequestHash":  idempotencyKey.RequestHash,
+			"StatusCode":   idempotencyKey.StatusCode,
+			"ResponseBody": idempotencyKey.ResponseBody,
+			"CreateAt":     idempotencyKey.CreateAt,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create idempotency key")
+	}
+
+	return nil
+}
+
+// UpdateIdempotencyKey records the response to the request of the given
+// idempotency key.
+func (sqlStore *SQLStore) UpdateIdempotencyKey(idempotencyKey *model.IdempotencyKey) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update("IdempotencyKey").
+		SetMap(map[string]interface{}{
+			"StatusCode":   idempotencyKey.StatusCode,
+			"ResponseBody": idempotencyKey.ResponseBody,
+		}).
+		Where("ID = ?", idempotencyKey.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update idempotency key")
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKey removes the given idempotency key, allowing it to be
+// sent again.
+func (sqlStore *SQLStore) DeleteIdempotencyKey(id string) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete("IdempotencyKey").
+		Where("ID = ?", id),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete idempotency key")
+	}
+
+	return nil
+}
+
+// DeleteIdempotencyKeysCreatedBefore removes the idempotency keys created
+// before the given time, in milliseconds since the epoch.
+func (sqlStore *SQLStore) DeleteIdempotencyKeysCreatedBefore(createAt int64) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete("IdempotencyKey").
+		Where("CreateAt < ?", createAt),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete expired idempotency keys")
+	}
+
+	return nil
+}
+
+// DeleteIncompleteIdempotencyKeysCreatedBefore removes the idempotency keys
+// created before the given time, in milliseconds since the epoch, whose
+// response was never recorded.
+func (sqlStore *SQLStore) DeleteIncompleteIdempotencyKeysCreatedBefore(createAt int64) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete("IdempotencyKey").
+		Where("CreateAt < ?", createAt).
+		Where("StatusCode = 0"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete abandoned idempotency keys")
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	apiKeyID := model.NewID()
+
+	t.Run("unknown key", func(t *testing.T) {
+		idempotencyKey, err := sqlStore.GetIdempotencyKey("unknown", "")
+		require.NoError(t, err)
+		require.Nil(t, idempotencyKey)
+	})
+
+	idempotencyKey := &model.IdempotencyKey{
+		RequestKey:  "key1",
+		APIKeyID:    apiKeyID,
+		RequestHash: "hash",
+	}
+	err := sqlStore.CreateIdempotencyKey(idempotencyKey)
+	require.NoError(t, err)
+	require.NotEmpty(t, idempotencyKey.ID)
+
+	t.Run("scoped to the caller", func(t *testing.T) {
+		actual, err := sqlStore.GetIdempotencyKey("key1", apiKeyID)
+		require.NoError(t, err)
+		require.Equal(t, idempotencyKey, actual)
+		require.False(t, actual.IsCompleted())
+
+		actual, err = sqlStore.GetIdempotencyKey("key1", "")
+		require.NoError(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		err := sqlStore.CreateIdempotencyKey(&model.IdempotencyKey{
+			RequestKey: "key1",
+			APIKeyID:   apiKeyID,
+		})
+		require.Error(t, err)
+
+		err = sqlStore.CreateIdempotencyKey(&model.IdempotencyKey{
+			RequestKey: "key1",
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		idempotencyKey.StatusCode = 202
+		idempotencyKey.ResponseBody = `{"ID":"id"}`
+		err := sqlStore.UpdateIdempotencyKey(idempotencyKey)
+		require.NoError(t, err)
+
+		actual, err := sqlStore.GetIdempotencyKey("key1", apiKeyID)
+		require.NoError(t, err)
+		require.Equal(t, idempotencyKey, actual)
+		require.True(t, actual.IsCompleted())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := sqlStore.DeleteIdempotencyKey(idempotencyKey.ID)
+		require.NoError(t, err)
+
+		actual, err := sqlStore.GetIdempotencyKey("key1", apiKeyID)
+		require.NoError(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("delete abandoned", func(t *testing.T) {
+		completed := &model.IdempotencyKey{RequestKey: "key2", StatusCode: 201}
+		err := sqlStore.CreateIdempotencyKey(completed)
+		require.NoError(t, err)
+
+		incomplete := &model.IdempotencyKey{RequestKey: "key3"}
+		err = sqlStore.CreateIdempotencyKey(incomplete)
+		require.NoError(t, err)
+
+		err = sqlStore.DeleteIncompleteIdempotencyKeysCreatedBefore(GetMillis() + 1)
+		require.NoError(t, err)
+
+		actual, err := sqlStore.GetIdempotencyKey("key2", "")
+		require.NoError(t, err)
+		require.Equal(t, completed, actual)
+
+		actual, err = sqlStore.GetIdempotencyKey("key3", "")
+		require.NoError(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		err := sqlStore.DeleteIdempotencyKeysCreatedBefore(GetMillis() + 1)
+		require.NoError(t, err)
+
+		actual, err := sqlStore.GetIdempotencyKey("key1", "")
+		require.NoError(t, err)
+		require.Nil(t, actual)
+	})
+}
@@ -19,16 +19,23 @@ func init() {
 	installationSelect = sq.
 		Select(
 			"ID", "OwnerID", "Version", "Image", "DNS", "Database", "Filestore", "Size",
-			"Affinity", "GroupID", "GroupSequence", "State", "License",
-			"MattermostEnvRaw", "CreateAt", "DeleteAt", "APISecurityLock",
-			"LockAcquiredBy", "LockAcquiredAt",
+			"Affinity", "PlacementStrategy", "GroupID", "GroupSequence", "State", "License",
+			"MattermostEnvRaw", "RequiredClusterAnnotationsRaw",
+			"PreferredClusterAnnotationsRaw", "MigrationTargetClusterID", "CreateAt", "DeleteAt", "APISecurityLock",
+			"LockAcquiredBy", "LockAcquiredAt", "HibernationScheduleRaw", "ScheduledActionAt",
+			"RestoreBackupID", "RestoreID", "CloneScrubCommandRaw", "DatabaseMigrationTarget",
+			"FilestoreMigrationTarget",
 		).
 		From("Installation")
 }
 
 type rawInstallation struct {
 	*model.Installation
-	MattermostEnvRaw []byte
+	MattermostEnvRaw               []byte
+	RequiredClusterAnnotationsRaw  []byte
+	PreferredClusterAnnotationsRaw []byte
+	HibernationScheduleRaw         []byte
+	CloneScrubCommandRaw           []byte
 }
 
 type rawInstallations []*rawInstallation
@@ -45,6 +52,32 @@ func (r *rawInstallation) toInstallation() (*model.Installation, error) {
 	}
 
 	r.Installation.MattermostEnv = *mattermostEnv
+
+	if r.RequiredClusterAnnotationsRaw != nil {
+		err = json.Unmarshal(r.RequiredClusterAnnotationsRaw, &r.Installation.RequiredClusterAnnotations)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal required cluster annotations")
+		}
+	}
+	if r.PreferredClusterAnnotationsRaw != nil {
+		err = json.Unmarshal(r.PreferredClusterAnnotationsRaw, &r.Installation.PreferredClusterAnnotations)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal preferred cluster annotations")
+		}
+	}
+
+	r.Installation.HibernationSchedule, err = hibernationScheduleFromJSON(r.HibernationScheduleRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.CloneScrubCommandRaw != nil {
+		err = json.Unmarshal(r.CloneScrubCommandRaw, &r.Installation.CloneScrubCommand)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal clone scrub command")
+		}
+	}
+
 	return r.Installation, nil
 }
 
@@ -94,9 +127,7 @@ func (sqlStore *SQLStore) GetInstallation(id string, includeGroupConfig, include
 
 // GetInstallations fetches the given page of created installations. The first page is 0.
 func (sqlStore *SQLStore) GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error) {
-	builder := installationSelect.
-		OrderBy("CreateAt ASC")
-	builder = sqlStore.applyInstallationFilter(builder, filter)
+	builder := sqlStore.applyInstallationFilter(installationSelect, filter)
 
 	var rawInstallations rawInstallations
 	err := sqlStore.selectBuilder(sqlStore.db, &rawInstallations, builder)
@@ -126,24 +157,69 @@ func (sqlStore *SQLStore) GetInstallations(filter *model.InstallationFilter, inc
 	return installations, nil
 }
 
-func (sqlStore *SQLStore) applyInstallationFilter(builder sq.SelectBuilder, filter *model.InstallationFilter) sq.SelectBuilder {
-	if filter.PerPage != model.AllPerPage {
-		builder = builder.
-			Limit(uint64(filter.PerPage)).
-			Offset(uint64(filter.Page * filter.PerPage))
+// CountInstallations returns the number of installations matching the given
+// filter across every page.
+func (sqlStore *SQLStore) CountInstallations(filter *model.InstallationFilter) (int64, error) {
+	builder := sqlStore.applyInstallationFilterConditions(sq.Select("Count (*)").From("Installation"), filter)
+
+	var result countResult
+	err := sqlStore.selectBuilder(sqlStore.db, &result, builder)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count installations")
 	}
 
+	return result.value()
+}
+
+func (sqlStore *SQLStore) applyInstallationFilter(builder sq.SelectBuilder, filter *model.InstallationFilter) sq.SelectBuilder {
+	builder = sqlStore.applyInstallationFilterConditions(builder, filter)
+	builder = applyPaging(builder, "Installation", filter.Page, filter.PerPage, filter.Cursor, filter.SortBy, filter.SortOrder)
+
+	return applySort(builder, "Installation", filter.SortBy, filter.SortOrder)
+}
+
+func (sqlStore *SQLStore) applyInstallationFilterConditions(builder sq.SelectBuilder, filter *model.InstallationFilter) sq.SelectBuilder {
 	if filter.OwnerID != "" {
-		builder = builder.Where("OwnerID = ?", filter.OwnerID)
+		builder = builder.Where("Installation.OwnerID = ?", filter.OwnerID)
 	}
 	if filter.GroupID != "" {
-		builder = builder.Where("GroupID = ?", filter.GroupID)
+		builder = builder.Where("Installation.GroupID = ?", filter.GroupID)
 	}
 	if !filter.IncludeDeleted {
-		builder = builder.Where("DeleteAt = 0")
+		builder = builder.Where("Installation.DeleteAt = 0")
 	}
 	if filter.DNS != "" {
-		builder = builder.Where("DNS = ?", filter.DNS)
+		builder = builder.Where("Installation.DNS = ?", filter.DNS)
+	}
+	if len(filter.States) > 0 {
+		builder = builder.Where(sq.Eq{"Installation.State": filter.States})
+	}
+	if len(filter.Annotations) > 0 {
+		builder = builder.Where(withAllAnnotations("Installation", filter.Annotations))
+	}
+	if filter.Version != "" {
+		builder = builder.Where("Installation.Version = ?", filter.Version)
+	}
+	if filter.Image != "" {
+		builder = builder.Where("Installation.Image = ?", filter.Image)
+	}
+	if filter.Database != "" {
+		builder = builder.Where("Installation.Database = ?", filter.Database)
+	}
+	if filter.Filestore != "" {
+		builder = builder.Where("Installation.Filestore = ?", filter.Filestore)
+	}
+	if filter.Size != "" {
+		builder = builder.Where("Installation.Size = ?", filter.Size)
+	}
+	if filter.Affinity != "" {
+		builder = builder.Where("Installation.Affinity = ?", filter.Affinity)
+	}
+	if filter.CreatedAfter > 0 {
+		builder = builder.Where("Installation.CreateAt > ?", filter.CreatedAfter)
+	}
+	if filter.CreatedBefore > 0 {
+		builder = builder.Where("Installation.CreateAt < ?", filter.CreatedBefore)
 	}
 
 	return builder
@@ -244,29 +320,56 @@ func (sqlStore *SQLStore) createInstallation(db execer, installation *model.Inst
 	if err != nil {
 		return errors.Wrap(err, "unable to marshal MattermostEnv")
 	}
+	requiredClusterAnnotationsJSON, err := json.Marshal(installation.RequiredClusterAnnotations)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal RequiredClusterAnnotations")
+	}
+	preferredClusterAnnotationsJSON, err := json.Marshal(installation.PreferredClusterAnnotations)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal PreferredClusterAnnotations")
+	}
+	hibernationScheduleJSON, err := hibernationScheduleToJSON(installation.HibernationSchedule)
+	if err != nil {
+		return err
+	}
+	cloneScrubCommandJSON, err := json.Marshal(installation.CloneScrubCommand)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal CloneScrubCommand")
+	}
 
 	_, err = sqlStore.execBuilder(db, sq.
 		Insert("Installation").
 		SetMap(map[string]interface{}{
-			"ID":               installation.ID,
-			"OwnerID":          installation.OwnerID,
-			"GroupID":          installation.GroupID,
-			"GroupSequence":    nil,
-			"Version":          installation.Version,
-			"Image":            installation.Image,
-			"DNS":              installation.DNS,
-			"Database":         installation.Database,
-			"Filestore":        installation.Filestore,
-			"Size":             installation.Size,
-			"Affinity":         installation.Affinity,
-			"State":            installation.State,
-			"License":          installation.License,
-			"MattermostEnvRaw": []byte(envJSON),
-			"CreateAt":         installation.CreateAt,
-			"DeleteAt":         0,
-			"APISecurityLock":  installation.APISecurityLock,
-			"LockAcquiredBy":   nil,
-			"LockAcquiredAt":   0,
+			"ID":                             installation.ID,
+			"OwnerID":                        installation.OwnerID,
+			"GroupID":                        installation.GroupID,
+			"GroupSequence":                  nil,
+			"Version":                        installation.Version,
+			"Image":                          installation.Image,
+			"DNS":                            installation.DNS,
+			"Database":                       installation.Database,
+			"Filestore":                      installation.Filestore,
+			"Size":                           installation.Size,
+			"Affinity":                       installation.Affinity,
+			"PlacementStrategy":              installation.PlacementStrategy,
+			"State":                          installation.State,
+			"License":                        installation.License,
+			"MattermostEnvRaw":               []byte(envJSON),
+			"RequiredClusterAnnotationsRaw":  requiredClusterAnnotationsJSON,
+			"PreferredClusterAnnotationsRaw": preferredClusterAnnotationsJSON,
+			"MigrationTargetClusterID":       installation.MigrationTargetClusterID,
+			"CreateAt":                       installation.CreateAt,
+			"DeleteAt":                       0,
+			"APISecurityLock":                installation.APISecurityLock,
+			"LockAcquiredBy":                 nil,
+			"LockAcquiredAt":                 0,
+			"HibernationScheduleRaw":         hibernationScheduleJSON,
+			"ScheduledActionAt":              installation.ScheduledActionAt,
+			"RestoreBackupID":                installation.RestoreBackupID,
+			"RestoreID":                      installation.RestoreID,
+			"CloneScrubCommandRaw":           cloneScrubCommandJSON,
+			"DatabaseMigrationTarget":        installation.DatabaseMigrationTarget,
+			"FilestoreMigrationTarget":       installation.FilestoreMigrationTarget,
 		}),
 	)
 	if err != nil {
@@ -285,23 +388,50 @@ func (sqlStore *SQLStore) UpdateInstallation(installation *model.Installation) e
 	if err != nil {
 		return errors.Wrap(err, "unable to marshal MattermostEnv")
 	}
+	requiredClusterAnnotationsJSON, err := json.Marshal(installation.RequiredClusterAnnotations)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal RequiredClusterAnnotations")
+	}
+	preferredClusterAnnotationsJSON, err := json.Marshal(installation.PreferredClusterAnnotations)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal PreferredClusterAnnotations")
+	}
+	hibernationScheduleJSON, err := hibernationScheduleToJSON(installation.HibernationSchedule)
+	if err != nil {
+		return err
+	}
+	cloneScrubCommandJSON, err := json.Marshal(installation.CloneScrubCommand)
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal CloneScrubCommand")
+	}
 
 	_, err = sqlStore.execBuilder(sqlStore.db, sq.
 		Update("Installation").
 		SetMap(map[string]interface{}{
-			"OwnerID":          installation.OwnerID,
-			"GroupID":          installation.GroupID,
-			"GroupSequence":    installation.GroupSequence,
-			"Version":          installation.Version,
-			"Image":            installation.Image,
-			"DNS":              installation.DNS,
-			"Database":         installation.Database,
-			"Filestore":        installation.Filestore,
-			"Size":             installation.Size,
-			"Affinity":         installation.Affinity,
-			"License":          installation.License,
-			"MattermostEnvRaw": []byte(envJSON),
-			"State":            installation.State,
+			"OwnerID":                        installation.OwnerID,
+			"GroupID":                        installation.GroupID,
+			"GroupSequence":                  installation.GroupSequence,
+			"Version":                        installation.Version,
+			"Image":                          installation.Image,
+			"DNS":                            installation.DNS,
+			"Database":                       installation.Database,
+			"Filestore":                      installation.Filestore,
+			"Size":                           installation.Size,
+			"Affinity":                       installation.Affinity,
+			"PlacementStrategy":              installation.PlacementStrategy,
+			"License":                        installation.License,
+			"MattermostEnvRaw":               []byte(envJSON),
+			"RequiredClusterAnnotationsRaw":  requiredClusterAnnotationsJSON,
+			"PreferredClusterAnnotationsRaw": preferredClusterAnnotationsJSON,
+			"MigrationTargetClusterID":       installation.MigrationTargetClusterID,
+			"State":                          installation.State,
+			"HibernationScheduleRaw":         hibernationScheduleJSON,
+			"ScheduledActionAt":              installation.ScheduledActionAt,
+			"RestoreBackupID":                installation.RestoreBackupID,
+			"RestoreID":                      installation.RestoreID,
+			"CloneScrubCommandRaw":           cloneScrubCommandJSON,
+			"DatabaseMigrationTarget":        installation.DatabaseMigrationTarget,
+			"FilestoreMigrationTarget":       installation.FilestoreMigrationTarget,
 		}).
 		Where("ID = ?", installation.ID),
 	)
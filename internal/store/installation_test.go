@@ -42,15 +42,18 @@ func TestInstallations(t *testing.T) {
 	annotations := []*model.Annotation{{Name: "annotation1"}, {Name: "annotation2"}}
 
 	installation1 := &model.Installation{
-		OwnerID:   ownerID1,
-		Version:   "version",
-		DNS:       "dns.example.com",
-		Database:  model.InstallationDatabaseMysqlOperator,
-		Filestore: model.InstallationFilestoreMinioOperator,
-		Size:      mmv1alpha1.Size100String,
-		Affinity:  model.InstallationAffinityIsolated,
-		GroupID:   &groupID1,
-		State:     model.InstallationStateCreationRequested,
+		OwnerID:                     ownerID1,
+		Version:                     "version",
+		DNS:                         "dns.example.com",
+		Database:                    model.InstallationDatabaseMysqlOperator,
+		Filestore:                   model.InstallationFilestoreMinioOperator,
+		Size:                        mmv1alpha1.Size100String,
+		Affinity:                    model.InstallationAffinityIsolated,
+		PlacementStrategy:           model.PlacementStrategySpread,
+		RequiredClusterAnnotations:  []string{"regulated"},
+		PreferredClusterAnnotations: []string{"canary", "large"},
+		GroupID:                     &groupID1,
+		State:                       model.InstallationStateCreationRequested,
 	}
 
 	err = sqlStore.CreateInstallation(installation1, annotations)
@@ -796,3 +799,133 @@ func TestDeleteInstallation(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, installation1, actualInstallation1)
 }
+
+func TestInstallationHibernationSchedule(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	group := &model.Group{
+		Name:                "group",
+		HibernationSchedule: &model.HibernationSchedule{HibernateAt: "0 22 * * *", WakeUpAt: "0 6 * * *"},
+	}
+	err := sqlStore.CreateGroup(group)
+	require.NoError(t, err)
+
+	installation := &model.Installation{
+		OwnerID:             model.NewID(),
+		DNS:                 "dns.example.com",
+		State:               model.InstallationStateStable,
+		HibernationSchedule: &model.HibernationSchedule{HibernateAt: "0 20 * * 1-5"},
+	}
+	err = sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	actualInstallation, err := sqlStore.GetInstallation(installation.ID, false, false)
+	require.NoError(t, err)
+	require.Equal(t, &model.HibernationSchedule{HibernateAt: "0 20 * * 1-5"}, actualInstallation.HibernationSchedule)
+	require.Zero(t, actualInstallation.ScheduledActionAt)
+
+	t.Run("update", func(t *testing.T) {
+		installation.HibernationSchedule.WakeUpAt = "0 8 * * 1-5"
+		installation.ScheduledActionAt = 1234
+		err = sqlStore.UpdateInstallation(installation)
+		require.NoError(t, err)
+
+		actualInstallation, err := sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, &model.HibernationSchedule{HibernateAt: "0 20 * * 1-5", WakeUpAt: "0 8 * * 1-5"}, actualInstallation.HibernationSchedule)
+		require.EqualValues(t, 1234, actualInstallation.ScheduledActionAt)
+	})
+
+	t.Run("group schedule takes precedence", func(t *testing.T) {
+		installation.GroupID = &group.ID
+		err = sqlStore.UpdateInstallation(installation)
+		require.NoError(t, err)
+
+		actualInstallation, err := sqlStore.GetInstallation(installation.ID, true, true)
+		require.NoError(t, err)
+		require.Equal(t, group.HibernationSchedule, actualInstallation.HibernationSchedule)
+		require.Contains(t, actualInstallation.GroupOverrides, "Group HibernationSchedule")
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		installation.HibernationSchedule = nil
+		err = sqlStore.UpdateInstallation(installation)
+		require.NoError(t, err)
+
+		actualInstallation, err := sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		require.Nil(t, actualInstallation.HibernationSchedule)
+
+		oldSequence := group.Sequence
+		group.HibernationSchedule = nil
+		err = sqlStore.UpdateGroup(group)
+		require.NoError(t, err)
+		require.Equal(t, oldSequence, group.Sequence)
+
+		actualGroup, err := sqlStore.GetGroup(group.ID)
+		require.NoError(t, err)
+		require.Nil(t, actualGroup.HibernationSchedule)
+	})
+}
+
+func TestInstallationClone(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation := &model.Installation{
+		OwnerID:           model.NewID(),
+		DNS:               "clone.example.com",
+		State:             model.InstallationStateCreationRequested,
+		RestoreBackupID:   model.NewID(),
+		RestoreID:         model.NewID(),
+		CloneScrubCommand: []string{"sh", "-c", "./scrub.sh"},
+	}
+	err := sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	actualInstallation, err := sqlStore.GetInstallation(installation.ID, false, false)
+	require.NoError(t, err)
+	require.Equal(t, installation, actualInstallation)
+
+	installation.CloneScrubCommand = nil
+	err = sqlStore.UpdateInstallation(installation)
+	require.NoError(t, err)
+
+	actualInstallation, err = sqlStore.GetInstallation(installation.ID, false, false)
+	require.NoError(t, err)
+	require.Nil(t, actualInstallation.CloneScrubCommand)
+}
+
+func TestInstallationDatabaseMigrationTargets(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	installation := &model.Installation{
+		OwnerID:   model.NewID(),
+		DNS:       "migrate.example.com",
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+		State:     model.InstallationStateHibernating,
+	}
+	err := sqlStore.CreateInstallation(installation, nil)
+	require.NoError(t, err)
+
+	installation.DatabaseMigrationTarget = model.InstallationDatabaseSingleTenantRDSMySQL
+	installation.FilestoreMigrationTarget = model.InstallationFilestoreAwsS3
+	installation.State = model.InstallationStateDBMigrationRequested
+	err = sqlStore.UpdateInstallation(installation)
+	require.NoError(t, err)
+
+	actualInstallation, err := sqlStore.GetInstallation(installation.ID, false, false)
+	require.NoError(t, err)
+	require.Equal(t, installation, actualInstallation)
+
+	installations, err := sqlStore.GetUnlockedInstallationsPendingWork()
+	require.NoError(t, err)
+	require.Len(t, installations, 1)
+	require.Equal(t, installation.ID, installations[0].ID)
+}
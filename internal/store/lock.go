@@ -6,6 +6,7 @@ package store
 
 import (
 	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/pkg/errors"
 )
 
@@ -33,6 +34,8 @@ func (sqlStore *SQLStore) lockRows(table string, ids []string, lockerID string)
 	locked := false
 	if count > 0 {
 		locked = true
+	} else {
+		metrics.LockContention.WithLabelValues(table).Inc()
 	}
 
 	if count > 0 && int(count) < len(ids) {
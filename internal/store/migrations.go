@@ -1168,6 +1168,387 @@ var migrations = []migration{
 			return err
 		}
 
+		return nil
+	}},
+	{semver.MustParse("0.22.0"), semver.MustParse("0.23.0"), func(e execer) error {
+		// Add PlacementStrategy column to installations.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN PlacementStrategy TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.23.0"), semver.MustParse("0.24.0"), func(e execer) error {
+		// Add required and preferred cluster annotation columns to installations.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN RequiredClusterAnnotationsRaw BYTEA NULL;`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE Installation ADD COLUMN PreferredClusterAnnotationsRaw BYTEA NULL;`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.24.0"), semver.MustParse("0.25.0"), func(e execer) error {
+		// Add MigrationTargetClusterID column to installations.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN MigrationTargetClusterID TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.25.0"), semver.MustParse("0.26.0"), func(e execer) error {
+		// Add DrainMaxConcurrent column to clusters.
+		_, err := e.Exec(`ALTER TABLE Cluster ADD COLUMN DrainMaxConcurrent INT NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.26.0"), semver.MustParse("0.27.0"), func(e execer) error {
+		// Add the webhook delivery outbox.
+		_, err := e.Exec(`
+			CREATE TABLE WebhookDelivery (
+				ID TEXT PRIMARY KEY,
+				WebhookID TEXT NOT NULL,
+				Payload TEXT NOT NULL,
+				State TEXT NOT NULL,
+				Attempts INT NOT NULL,
+				NextAttemptAt BIGINT NOT NULL,
+				LastAttemptAt BIGINT NOT NULL,
+				ResponseCode INT NOT NULL,
+				ResponseBody TEXT NOT NULL,
+				LastError TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				LockAcquiredBy CHAR(26) NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE INDEX WebhookDelivery_WebhookID_CreateAt ON WebhookDelivery (WebhookID, CreateAt);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE INDEX WebhookDelivery_State_NextAttemptAt ON WebhookDelivery (State, NextAttemptAt);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.27.0"), semver.MustParse("0.28.0"), func(e execer) error {
+		// Add Secret column to webhooks for payload signing.
+		_, err := e.Exec(`ALTER TABLE Webhooks ADD COLUMN Secret TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.28.0"), semver.MustParse("0.29.0"), func(e execer) error {
+		// Add event filter columns to webhooks.
+		_, err := e.Exec(`ALTER TABLE Webhooks ADD COLUMN EventTypesRaw BYTEA NULL;`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE Webhooks ADD COLUMN EventStatesRaw BYTEA NULL;`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE Webhooks ADD COLUMN EventOwnerID TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.29.0"), semver.MustParse("0.30.0"), func(e execer) error {
+		// Add the event history of resource state changes.
+		_, err := e.Exec(`
+			CREATE TABLE Event (
+				ID TEXT PRIMARY KEY,
+				ResourceType TEXT NOT NULL,
+				ResourceID TEXT NOT NULL,
+				OldState TEXT NOT NULL,
+				NewState TEXT NOT NULL,
+				InstanceID TEXT NOT NULL,
+				Error TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE INDEX Event_ResourceType_ResourceID_CreateAt ON Event (ResourceType, ResourceID, CreateAt);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.30.0"), semver.MustParse("0.31.0"), func(e execer) error {
+		// Add API keys used to authenticate API requests.
+		_, err := e.Exec(`
+			CREATE TABLE APIKey (
+				ID TEXT PRIMARY KEY,
+				Name TEXT NOT NULL,
+				KeyHash TEXT NOT NULL,
+				ScopesRaw BYTEA NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				DeleteAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE UNIQUE INDEX APIKey_KeyHash ON APIKey (KeyHash);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.31.0"), semver.MustParse("0.32.0"), func(e execer) error {
+		// Add OwnerID column to API keys to bind them to an owner.
+		_, err := e.Exec(`ALTER TABLE APIKey ADD COLUMN OwnerID TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.32.0"), semver.MustParse("0.33.0"), func(e execer) error {
+		// Add the audit log of mutating API requests.
+		_, err := e.Exec(`
+			CREATE TABLE Audit (
+				ID TEXT PRIMARY KEY,
+				RequestID TEXT NOT NULL,
+				APIKeyID TEXT NOT NULL,
+				RemoteAddr TEXT NOT NULL,
+				Method TEXT NOT NULL,
+				Path TEXT NOT NULL,
+				ResourceType TEXT NOT NULL,
+				ResourceID TEXT NOT NULL,
+				RequestBody TEXT NOT NULL,
+				StatusCode INT NOT NULL,
+				CreateAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE INDEX Audit_CreateAt ON Audit (CreateAt);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE INDEX Audit_ResourceType_ResourceID_CreateAt ON Audit (ResourceType, ResourceID, CreateAt);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.33.0"), semver.MustParse("0.34.0"), func(e execer) error {
+		// Add idempotency keys of create requests.
+		_, err := e.Exec(`
+			CREATE TABLE IdempotencyKey (
+				ID TEXT PRIMARY KEY,
+				RequestKey TEXT NOT NULL,
+				APIKeyID TEXT NOT NULL,
+				RequestHash TEXT NOT NULL,
+				StatusCode INT NOT NULL,
+				ResponseBody TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE UNIQUE INDEX IdempotencyKey_RequestKey_APIKeyID ON IdempotencyKey (RequestKey, APIKeyID);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE INDEX IdempotencyKey_CreateAt ON IdempotencyKey (CreateAt);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.34.0"), semver.MustParse("0.35.0"), func(e execer) error {
+		// Add bulk operations across installations and their results.
+		_, err := e.Exec(`
+			CREATE TABLE BulkOperation (
+				ID TEXT PRIMARY KEY,
+				Action TEXT NOT NULL,
+				FilterRaw BYTEA NOT NULL,
+				PatchRaw BYTEA NULL,
+				GroupID TEXT NOT NULL,
+				DryRun BOOLEAN NOT NULL,
+				Concurrency INT NOT NULL,
+				State TEXT NOT NULL,
+				Error TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				LockAcquiredBy CHAR(26) NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE INDEX BulkOperation_State_CreateAt ON BulkOperation (State, CreateAt);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE TABLE BulkOperationResult (
+				ID TEXT PRIMARY KEY,
+				BulkOperationID TEXT NOT NULL,
+				InstallationID TEXT NOT NULL,
+				OldState TEXT NOT NULL,
+				NewState TEXT NOT NULL,
+				Status TEXT NOT NULL,
+				Message TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE UNIQUE INDEX BulkOperationResult_BulkOperationID_InstallationID ON BulkOperationResult (BulkOperationID, InstallationID);
+		`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.35.0"), semver.MustParse("0.36.0"), func(e execer) error {
+		// Add hibernation schedules to installations and groups.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN HibernationScheduleRaw BYTEA NULL;`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE Installation ADD COLUMN ScheduledActionAt BIGINT NOT NULL DEFAULT 0;`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE "Group" ADD COLUMN HibernationScheduleRaw BYTEA NULL;`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.36.0"), semver.MustParse("0.37.0"), func(e execer) error {
+		// Add installation backups and track the backup an installation is
+		// restored to.
+		_, err := e.Exec(`
+			CREATE TABLE Backup (
+				ID TEXT PRIMARY KEY,
+				InstallationID TEXT NOT NULL,
+				State TEXT NOT NULL,
+				DatabaseSnapshotID TEXT NOT NULL,
+				FilestoreLocation TEXT NOT NULL,
+				Size BIGINT NOT NULL,
+				Error TEXT NOT NULL,
+				CreateAt BIGINT NOT NULL,
+				CompleteAt BIGINT NOT NULL,
+				LockAcquiredBy CHAR(26) NULL,
+				LockAcquiredAt BIGINT NOT NULL
+			);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`
+			CREATE INDEX Backup_InstallationID_CreateAt ON Backup (InstallationID, CreateAt);
+		`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE Installation ADD COLUMN RestoreBackupID TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.37.0"), semver.MustParse("0.38.0"), func(e execer) error {
+		// Add the command run on installations cloned from another.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN CloneScrubCommandRaw BYTEA NULL;`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.38.0"), semver.MustParse("0.39.0"), func(e execer) error {
+		// Add the backends installation data is being migrated to.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN DatabaseMigrationTarget TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		_, err = e.Exec(`ALTER TABLE Installation ADD COLUMN FilestoreMigrationTarget TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	}},
+	{semver.MustParse("0.39.0"), semver.MustParse("0.40.0"), func(e execer) error {
+		// Add the identifier of the last backup restoration.
+		_, err := e.Exec(`ALTER TABLE Installation ADD COLUMN RestoreID TEXT NOT NULL DEFAULT '';`)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	}},
 }
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pkg/errors"
+)
+
+type stateCount struct {
+	State string
+	Count int64
+}
+
+// GetClusterStateCounts returns the number of non-deleted clusters in each state.
+func (sqlStore *SQLStore) GetClusterStateCounts() (map[string]int64, error) {
+	return sqlStore.getStateCounts("Cluster")
+}
+
+// GetInstallationStateCounts returns the number of non-deleted installations in
+// each state.
+func (sqlStore *SQLStore) GetInstallationStateCounts() (map[string]int64, error) {
+	return sqlStore.getStateCounts("Installation")
+}
+
+// GetClusterInstallationStateCounts returns the number of non-deleted cluster
+// installations in each state.
+func (sqlStore *SQLStore) GetClusterInstallationStateCounts() (map[string]int64, error) {
+	return sqlStore.getStateCounts("ClusterInstallation")
+}
+
+// getStateCounts returns the number of non-deleted rows of the given table in
+// each state.
+func (sqlStore *SQLStore) getStateCounts(table string) (map[string]int64, error) {
+	var stateCounts []stateCount
+	err := sqlStore.selectBuilder(sqlStore.db, &stateCounts, sq.
+		Select("State", "COUNT(*) AS Count").
+		From(table).
+		Where("DeleteAt = 0").
+		GroupBy("State"),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to count %s rows by state", table)
+	}
+
+	counts := make(map[string]int64, len(stateCounts))
+	for _, stateCount := range stateCounts {
+		counts[stateCount.State] = stateCount.Count
+	}
+
+	return counts, nil
+}
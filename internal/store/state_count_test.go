@@ -0,0 +1,76 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateCounts(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := MakeTestSQLStore(t, logger)
+	defer CloseConnection(t, sqlStore)
+
+	t.Run("empty", func(t *testing.T) {
+		counts, err := sqlStore.GetInstallationStateCounts()
+		require.NoError(t, err)
+		require.Empty(t, counts)
+	})
+
+	err := sqlStore.CreateCluster(&model.Cluster{State: model.ClusterStateStable}, nil)
+	require.NoError(t, err)
+
+	for i, state := range []string{
+		model.InstallationStateStable,
+		model.InstallationStateStable,
+		model.InstallationStateCreationInProgress,
+		model.InstallationStateStable,
+	} {
+		installation := &model.Installation{
+			OwnerID: model.NewID(),
+			DNS:     model.NewID() + ".example.com",
+			State:   state,
+		}
+		err = sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		if i == 3 {
+			err = sqlStore.DeleteInstallation(installation.ID)
+			require.NoError(t, err)
+		}
+
+		err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+			ClusterID:      model.NewID(),
+			InstallationID: installation.ID,
+			State:          model.ClusterInstallationStateReconciling,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("clusters", func(t *testing.T) {
+		counts, err := sqlStore.GetClusterStateCounts()
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{model.ClusterStateStable: 1}, counts)
+	})
+
+	t.Run("installations", func(t *testing.T) {
+		counts, err := sqlStore.GetInstallationStateCounts()
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{
+			model.InstallationStateStable:             2,
+			model.InstallationStateCreationInProgress: 1,
+		}, counts)
+	})
+
+	t.Run("cluster installations", func(t *testing.T) {
+		counts, err := sqlStore.GetClusterInstallationStateCounts()
+		require.NoError(t, err)
+		require.Equal(t, map[string]int64{model.ClusterInstallationStateReconciling: 4}, counts)
+	})
+}
@@ -6,6 +6,7 @@ package store
 
 import (
 	"database/sql"
+	"encoding/json"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/mattermost/mattermost-cloud/model"
@@ -16,13 +17,53 @@ var webhookSelect sq.SelectBuilder
 
 func init() {
 	webhookSelect = sq.
-		Select("ID", "OwnerID", "URL", "CreateAt", "DeleteAt").From("Webhooks")
+		Select("ID", "OwnerID", "URL", "Secret", "EventTypesRaw", "EventStatesRaw",
+			"EventOwnerID", "CreateAt", "DeleteAt").From("Webhooks")
+}
+
+type rawWebhook struct {
+	*model.Webhook
+	EventTypesRaw  []byte
+	EventStatesRaw []byte
+}
+
+type rawWebhooks []*rawWebhook
+
+func (r *rawWebhook) toWebhook() (*model.Webhook, error) {
+	// We only need to set values that are converted from a raw database format.
+	if r.EventTypesRaw != nil {
+		err := json.Unmarshal(r.EventTypesRaw, &r.Webhook.EventTypes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal event types")
+		}
+	}
+	if r.EventStatesRaw != nil {
+		err := json.Unmarshal(r.EventStatesRaw, &r.Webhook.EventStates)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal event states")
+		}
+	}
+
+	return r.Webhook, nil
+}
+
+func (rs *rawWebhooks) toWebhooks() ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	for _, rawWebhook := range *rs {
+		webhook, err := rawWebhook.toWebhook()
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
 }
 
 // GetWebhook fetches the given webhook by id.
 func (sqlStore *SQLStore) GetWebhook(id string) (*model.Webhook, error) {
-	var webhook model.Webhook
-	err := sqlStore.getBuilder(sqlStore.db, &webhook,
+	var rawWebhook rawWebhook
+	err := sqlStore.getBuilder(sqlStore.db, &rawWebhook,
 		webhookSelect.Where("ID = ?", id),
 	)
 	if err == sql.ErrNoRows {
@@ -31,20 +72,39 @@ func (sqlStore *SQLStore) GetWebhook(id string) (*model.Webhook, error) {
 		return nil, errors.Wrap(err, "failed to get webhook by id")
 	}
 
-	return &webhook, nil
+	return rawWebhook.toWebhook()
 }
 
 // GetWebhooks fetches the given page of created webhooks. The first page is 0.
 func (sqlStore *SQLStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error) {
-	builder := webhookSelect.
-		OrderBy("CreateAt ASC")
+	builder := applyWebhookFilter(webhookSelect, filter)
+	builder = applyPaging(builder, "Webhooks", filter.Page, filter.PerPage, filter.Cursor, "", "")
+	builder = applySort(builder, "Webhooks", "", "")
+
+	var rawWebhooks rawWebhooks
+	err := sqlStore.selectBuilder(sqlStore.db, &rawWebhooks, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for webhooks")
+	}
+
+	return rawWebhooks.toWebhooks()
+}
 
-	if filter.PerPage != model.AllPerPage {
-		builder = builder.
-			Limit(uint64(filter.PerPage)).
-			Offset(uint64(filter.Page * filter.PerPage))
+// CountWebhooks returns the number of webhooks matching the given filter
+// across every page.
+func (sqlStore *SQLStore) CountWebhooks(filter *model.WebhookFilter) (int64, error) {
+	builder := applyWebhookFilter(sq.Select("Count (*)").From("Webhooks"), filter)
+
+	var result countResult
+	err := sqlStore.selectBuilder(sqlStore.db, &result, builder)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to count webhooks")
 	}
 
+	return result.value()
+}
+
+func applyWebhookFilter(builder sq.SelectBuilder, filter *model.WebhookFilter) sq.SelectBuilder {
 	if filter.OwnerID != "" {
 		builder = builder.Where("OwnerID = ?", filter.OwnerID)
 	}
@@ -52,13 +112,7 @@ func (sqlStore *SQLStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Web
 		builder = builder.Where("DeleteAt = 0")
 	}
 
-	var webhooks []*model.Webhook
-	err := sqlStore.selectBuilder(sqlStore.db, &webhooks, builder)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to query for webhooks")
-	}
-
-	return webhooks, nil
+	return builder
 }
 
 // CreateWebhook records the given webhook to the database, assigning it a unique ID.
@@ -66,14 +120,33 @@ func (sqlStore *SQLStore) CreateWebhook(webhook *model.Webhook) error {
 	webhook.ID = model.NewID()
 	webhook.CreateAt = GetMillis()
 
-	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+	var eventTypesRaw, eventStatesRaw []byte
+	var err error
+	if len(webhook.EventTypes) != 0 {
+		eventTypesRaw, err = json.Marshal(webhook.EventTypes)
+		if err != nil {
+			return errors.Wrap(err, "unable to marshal event types")
+		}
+	}
+	if len(webhook.EventStates) != 0 {
+		eventStatesRaw, err = json.Marshal(webhook.EventStates)
+		if err != nil {
+			return errors.Wrap(err, "unable to marshal event states")
+		}
+	}
+
+	_, err = sqlStore.execBuilder(sqlStore.db, sq.
 		Insert("Webhooks").
 		SetMap(map[string]interface{}{
-			"ID":       webhook.ID,
-			"OwnerID":  webhook.OwnerID,
-			"URL":      webhook.URL,
-			"CreateAt": webhook.CreateAt,
-			"DeleteAt": 0,
+			"ID":             webhook.ID,
+			"OwnerID":        webhook.OwnerID,
+			"URL":            webhook.URL,
+			"Secret":         webhook.Secret,
+			"EventTypesRaw":  eventTypesRaw,
+			"EventStatesRaw": eventStatesRaw,
+			"EventOwnerID":   webhook.EventOwnerID,
+			"CreateAt":       webhook.CreateAt,
+			"DeleteAt":       0,
 		}),
 	)
 	if err != nil {
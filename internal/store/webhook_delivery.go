@@ -0,0 +1,164 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+var webhookDeliverySelect sq.SelectBuilder
+
+func init() {
+	webhookDeliverySelect = sq.
+		Select("ID", "WebhookID", "Payload", "State", "Attempts", "NextAttemptAt",
+			"LastAttemptAt", "ResponseCode", "ResponseBody", "LastError", "CreateAt",
+			"LockAcquiredBy", "LockAcquiredAt").
+		From("WebhookDelivery")
+}
+
+// GetWebhookDelivery fetches the given webhook delivery by id.
+func (sqlStore *SQLStore) GetWebhookDelivery(id string) (*model.WebhookDelivery, error) {
+	var delivery model.WebhookDelivery
+	err := sqlStore.getBuilder(sqlStore.db, &delivery,
+		webhookDeliverySelect.Where("ID = ?", id),
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook delivery by id")
+	}
+
+	return &delivery, nil
+}
+
+// GetWebhookDeliveries fetches the given page of webhook deliveries, newest
+// first. The first page is 0.
+func (sqlStore *SQLStore) GetWebhookDeliveries(filter *model.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	builder := webhookDeliverySelect.
+		OrderBy("CreateAt DESC")
+
+	if filter.PerPage != model.AllPerPage {
+		builder = builder.
+			Limit(uint64(filter.PerPage)).
+			Offset(uint64(filter.Page * filter.PerPage))
+	}
+
+	if filter.WebhookID != "" {
+		builder = builder.Where("WebhookID = ?", filter.WebhookID)
+	}
+	if filter.State != "" {
+		builder = builder.Where("State = ?", filter.State)
+	}
+
+	var deliveries []*model.WebhookDelivery
+	err := sqlStore.selectBuilder(sqlStore.db, &deliveries, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// GetUnlockedWebhookDeliveriesPendingWork returns unlocked pending webhook
+// deliveries which are due to be attempted.
+func (sqlStore *SQLStore) GetUnlockedWebhookDeliveriesPendingWork() ([]*model.WebhookDelivery, error) {
+	builder := webhookDeliverySelect.
+		Where("State = ?", model.WebhookDeliveryStatePending).
+		Where("NextAttemptAt <= ?", GetMillis()).
+		Where("LockAcquiredAt = 0").
+		OrderBy("CreateAt ASC")
+
+	var deliveries []*model.WebhookDelivery
+	err := sqlStore.selectBuilder(sqlStore.db, &deliveries, builder)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// CreateWebhookDelivery records the given webhook delivery to the database,
+// assigning it a unique ID.
+func (sqlStore *SQLStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	delivery.ID = model.NewID()
+	delivery.CreateAt = GetMillis()
+
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Insert("WebhookDelivery").
+		SetMap(map[string]interface{}{
+			"ID":             delivery.ID,
+			"WebhookID":      delivery.WebhookID,
+			"Payload":        delivery.Payload,
+			"State":          delivery.State,
+			"Attempts":       delivery.Attempts,
+			"NextAttemptAt":  delivery.NextAttemptAt,
+			"LastAttemptAt":  delivery.LastAttemptAt,
+			"ResponseCode":   delivery.ResponseCode,
+			"ResponseBody":   delivery.ResponseBody,
+			"LastError":      delivery.LastError,
+			"CreateAt":       delivery.CreateAt,
+			"LockAcquiredBy": nil,
+			"LockAcquiredAt": 0,
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook delivery")
+	}
+
+	return nil
+}
+
+// UpdateWebhookDelivery updates the given webhook delivery in the database.
+func (sqlStore *SQLStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Update("WebhookDelivery").
+		SetMap(map[string]interface{}{
+			"State":         delivery.State,
+			"Attempts":      delivery.Attempts,
+			"NextAttemptAt": delivery.NextAttemptAt,
+			"LastAttemptAt": delivery.LastAttemptAt,
+			"ResponseCode":  delivery.ResponseCode,
+			"ResponseBody":  delivery.ResponseBody,
+			"LastError":     delivery.LastError,
+		}).
+		Where("ID = ?", delivery.ID),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to update webhook delivery")
+	}
+
+	return nil
+}
+
+// LockWebhookDelivery marks the webhook delivery as locked for exclusive use
+// by the caller.
+func (sqlStore *SQLStore) LockWebhookDelivery(deliveryID, lockerID string) (bool, error) {
+	return sqlStore.lockRows("WebhookDelivery", []string{deliveryID}, lockerID)
+}
+
+// UnlockWebhookDelivery releases a lock previously acquired against a caller.
+func (sqlStore *SQLStore) UnlockWebhookDelivery(deliveryID, lockerID string, force bool) (bool, error) {
+	return sqlStore.unlockRows("WebhookDelivery", []string{deliveryID}, lockerID, force)
+}
+
+// DeleteWebhookDeliveriesCreatedBefore removes the delivered and failed
+// webhook deliveries created before the given time, in milliseconds since the
+// epoch. Pending deliveries are kept until they are settled.
+func (sqlStore *SQLStore) DeleteWebhookDeliveriesCreatedBefore(createAt int64) error {
+	_, err := sqlStore.execBuilder(sqlStore.db, sq.
+		Delete("WebhookDelivery").
+		Where("CreateAt < ?", createAt).
+		Where(sq.NotEq{"State": model.WebhookDeliveryStatePending}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete expired webhook deliveries")
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	t.Run("get unknown webhook delivery", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := MakeTestSQLStore(t, logger)
+
+		delivery, err := sqlStore.GetWebhookDelivery("unknown")
+		require.NoError(t, err)
+		require.Nil(t, delivery)
+	})
+
+	t.Run("create, update and get webhook deliveries", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := MakeTestSQLStore(t, logger)
+
+		webhookID := model.NewID()
+
+		delivery1 := &model.WebhookDelivery{
+			WebhookID: webhookID,
+			Payload:   `{"id":"1"}`,
+			State:     model.WebhookDeliveryStatePending,
+		}
+		err := sqlStore.CreateWebhookDelivery(delivery1)
+		require.NoError(t, err)
+		require.NotEmpty(t, delivery1.ID)
+
+		time.Sleep(1 * time.Millisecond)
+
+		delivery2 := &model.WebhookDelivery{
+			WebhookID:     webhookID,
+			Payload:       `{"id":"2"}`,
+			State:         model.WebhookDeliveryStatePending,
+			NextAttemptAt: GetMillis() + time.Hour.Milliseconds(),
+		}
+		err = sqlStore.CreateWebhookDelivery(delivery2)
+		require.NoError(t, err)
+
+		time.Sleep(1 * time.Millisecond)
+
+		otherDelivery := &model.WebhookDelivery{
+			WebhookID: model.NewID(),
+			Payload:   `{"id":"3"}`,
+			State:     model.WebhookDeliveryStateDelivered,
+		}
+		err = sqlStore.CreateWebhookDelivery(otherDelivery)
+		require.NoError(t, err)
+
+		actualDelivery1, err := sqlStore.GetWebhookDelivery(delivery1.ID)
+		require.NoError(t, err)
+		require.Equal(t, delivery1, actualDelivery1)
+
+		deliveries, err := sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			WebhookID: webhookID,
+			PerPage:   model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{delivery2, delivery1}, deliveries)
+
+		deliveries, err = sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			State:   model.WebhookDeliveryStateDelivered,
+			PerPage: model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{otherDelivery}, deliveries)
+
+		deliveries, err = sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			Page:    0,
+			PerPage: 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{otherDelivery}, deliveries)
+
+		deliveries, err = sqlStore.GetUnlockedWebhookDeliveriesPendingWork()
+		require.NoError(t, err)
+		require.Equal(t, []*model.WebhookDelivery{delivery1}, deliveries)
+
+		delivery1.State = model.WebhookDeliveryStateFailed
+		delivery1.Attempts = 10
+		delivery1.LastAttemptAt = GetMillis()
+		delivery1.ResponseCode = 500
+		delivery1.ResponseBody = "error"
+		delivery1.LastError = "webhook responded with status code 500"
+		err = sqlStore.UpdateWebhookDelivery(delivery1)
+		require.NoError(t, err)
+
+		actualDelivery1, err = sqlStore.GetWebhookDelivery(delivery1.ID)
+		require.NoError(t, err)
+		require.Equal(t, delivery1, actualDelivery1)
+
+		deliveries, err = sqlStore.GetUnlockedWebhookDeliveriesPendingWork()
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+	})
+
+	t.Run("lock webhook delivery", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := MakeTestSQLStore(t, logger)
+
+		delivery := &model.WebhookDelivery{
+			WebhookID: model.NewID(),
+			State:     model.WebhookDeliveryStatePending,
+		}
+		err := sqlStore.CreateWebhookDelivery(delivery)
+		require.NoError(t, err)
+
+		lockerID := model.NewID()
+
+		locked, err := sqlStore.LockWebhookDelivery(delivery.ID, lockerID)
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		deliveries, err := sqlStore.GetUnlockedWebhookDeliveriesPendingWork()
+		require.NoError(t, err)
+		require.Empty(t, deliveries)
+
+		locked, err = sqlStore.LockWebhookDelivery(delivery.ID, model.NewID())
+		require.NoError(t, err)
+		require.False(t, locked)
+
+		unlocked, err := sqlStore.UnlockWebhookDelivery(delivery.ID, lockerID, false)
+		require.NoError(t, err)
+		require.True(t, unlocked)
+
+		deliveries, err = sqlStore.GetUnlockedWebhookDeliveriesPendingWork()
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+	})
+
+	t.Run("delete expired webhook deliveries", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := MakeTestSQLStore(t, logger)
+
+		webhookID := model.NewID()
+		delivered := &model.WebhookDelivery{
+			WebhookID: webhookID,
+			State:     model.WebhookDeliveryStateDelivered,
+		}
+		err := sqlStore.CreateWebhookDelivery(delivered)
+		require.NoError(t, err)
+
+		failed := &model.WebhookDelivery{
+			WebhookID: webhookID,
+			State:     model.WebhookDeliveryStateFailed,
+		}
+		err = sqlStore.CreateWebhookDelivery(failed)
+		require.NoError(t, err)
+
+		pending := &model.WebhookDelivery{
+			WebhookID: webhookID,
+			State:     model.WebhookDeliveryStatePending,
+		}
+		err = sqlStore.CreateWebhookDelivery(pending)
+		require.NoError(t, err)
+
+		err = sqlStore.DeleteWebhookDeliveriesCreatedBefore(pending.CreateAt + 1)
+		require.NoError(t, err)
+
+		deliveries, err := sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			WebhookID: webhookID,
+			PerPage:   model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, pending.ID, deliveries[0].ID)
+	})
+}
@@ -33,8 +33,12 @@ func TestWebhooks(t *testing.T) {
 		}
 
 		webhook2 := &model.Webhook{
-			OwnerID: "owner2",
-			URL:     "https://url2.com",
+			OwnerID:      "owner2",
+			URL:          "https://url2.com",
+			Secret:       "secret2",
+			EventTypes:   []string{model.TypeInstallation},
+			EventStates:  []string{"creation-*", "stable"},
+			EventOwnerID: "tenant",
 		}
 
 		err := sqlStore.CreateWebhook(webhook1)
@@ -133,6 +137,22 @@ func TestWebhooks(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, []*model.Webhook{webhook1, webhook2}, actualWebhooks)
 
+		actualWebhooks, err = sqlStore.GetWebhooks(&model.WebhookFilter{
+			PerPage:        1,
+			Cursor:         &model.PageCursor{CreateAt: webhook1.CreateAt, ID: webhook1.ID},
+			IncludeDeleted: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*model.Webhook{webhook2}, actualWebhooks)
+
+		count, err := sqlStore.CountWebhooks(&model.WebhookFilter{PerPage: 1, IncludeDeleted: true})
+		require.NoError(t, err)
+		require.EqualValues(t, 2, count)
+
+		count, err = sqlStore.CountWebhooks(&model.WebhookFilter{OwnerID: "owner2", PerPage: 1})
+		require.NoError(t, err)
+		require.EqualValues(t, 1, count)
+
 		time.Sleep(1 * time.Millisecond)
 
 		// Deleting again shouldn't change timestamp
@@ -0,0 +1,258 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// backupStore abstracts the database operations required to take backups.
+type backupStore interface {
+	GetBackup(backupID string) (*model.Backup, error)
+	GetUnlockedBackupsPendingWork() ([]*model.Backup, error)
+	UpdateBackup(backup *model.Backup) error
+	LockBackup(backupID, lockerID string) (bool, error)
+	UnlockBackup(backupID, lockerID string, force bool) (bool, error)
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	LockInstallation(installationID, lockerID string) (bool, error)
+	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
+
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+	GetMultitenantDatabase(multitenantdatabaseID string) (*model.MultitenantDatabase, error)
+	GetMultitenantDatabases(filter *model.MultitenantDatabaseFilter) ([]*model.MultitenantDatabase, error)
+	GetMultitenantDatabaseForInstallationID(installationID string) (*model.MultitenantDatabase, error)
+	CreateMultitenantDatabase(multitenantDatabase *model.MultitenantDatabase) error
+	UpdateMultitenantDatabase(multitenantDatabase *model.MultitenantDatabase) error
+	LockMultitenantDatabase(multitenantdatabaseID, lockerID string) (bool, error)
+	UnlockMultitenantDatabase(multitenantdatabaseID, lockerID string, force bool) (bool, error)
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+
+	CreateEvent(event *model.Event) error
+}
+
+// backupOperator abstracts the operations required to back up and restore
+// the database and filestore of an installation.
+type backupOperator interface {
+	StartDatabaseBackup(installation *model.Installation, backup *model.Backup, logger log.FieldLogger) error
+	CheckDatabaseBackup(backup *model.Backup, logger log.FieldLogger) (bool, int64, error)
+	BackupFilestore(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (int64, error)
+	RestoreDatabase(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (bool, error)
+	RestoreFilestore(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error
+}
+
+// BackupSupervisor finds requested backups and takes them.
+//
+// The filestore of the installation is copied first, while holding the
+// installation lock, and the database snapshot is then awaited over the
+// following ticks.
+type BackupSupervisor struct {
+	store      backupStore
+	operator   backupOperator
+	instanceID string
+	logger     log.FieldLogger
+}
+
+// NewBackupSupervisor creates a new BackupSupervisor.
+func NewBackupSupervisor(store backupStore, operator backupOperator, instanceID string, logger log.FieldLogger) *BackupSupervisor {
+	return &BackupSupervisor{
+		store:      store,
+		operator:   operator,
+		instanceID: instanceID,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the backup supervisor.
+func (s *BackupSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down backup supervisor")
+}
+
+// Do looks for work to be done on any pending backups and attempts to
+// schedule the required work.
+func (s *BackupSupervisor) Do() error {
+	backups, err := s.store.GetUnlockedBackupsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for backups pending work")
+		recordSupervisorError(BackupSupervisorName)
+		return nil
+	}
+
+	for _, backup := range backups {
+		s.Supervise(backup)
+	}
+
+	return nil
+}
+
+// Supervise schedules the required work on the given backup.
+func (s *BackupSupervisor) Supervise(backup *model.Backup) {
+	logger := s.logger.WithFields(log.Fields{
+		"backup":       backup.ID,
+		"installation": backup.InstallationID,
+	})
+
+	lock := newBackupLock(backup.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Ensure the backup was not worked on by another provisioning server
+	// since it was selected.
+	originalState := backup.State
+	backup, err := s.store.GetBackup(backup.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed backup")
+		return
+	}
+	if backup.State != originalState {
+		logger.WithField("oldBackupState", originalState).
+			WithField("newBackupState", backup.State).
+			Warn("Another provisioner has worked on this backup; skipping...")
+		return
+	}
+
+	logger.Debugf("Supervising backup in state %s", backup.State)
+
+	newState, transitionErr := s.transitionBackup(backup, logger)
+	if transitionErr != nil {
+		recordSupervisorError(BackupSupervisorName)
+	}
+	if newState == originalState {
+		return
+	}
+
+	backup.State = newState
+	if newState == model.BackupStateFailed {
+		backup.Error = errorMessage(transitionErr)
+	}
+	if newState == model.BackupStateSucceeded || newState == model.BackupStateFailed {
+		backup.CompleteAt = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+
+	err = s.store.UpdateBackup(backup)
+	if err != nil {
+		logger.WithError(err).Warnf("Failed to set backup state to %s", newState)
+		return
+	}
+
+	// The owner is only used to filter webhooks, so a failed lookup does not
+	// prevent the event from being sent.
+	var ownerID string
+	installation, err := s.store.GetInstallation(backup.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation owner for webhook")
+	} else if installation != nil {
+		ownerID = installation.OwnerID
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeBackup,
+		ID:        backup.ID,
+		OwnerID:   ownerID,
+		NewState:  newState,
+		OldState:  originalState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"InstallationID": backup.InstallationID},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	recordEvent(s.store, &model.Event{
+		ResourceType: model.TypeBackup,
+		ResourceID:   backup.ID,
+		OldState:     originalState,
+		NewState:     newState,
+		InstanceID:   s.instanceID,
+		Error:        errorMessage(transitionErr),
+	}, logger)
+
+	logger.Debugf("Transitioned backup from %s to %s", originalState, newState)
+}
+
+// transitionBackup works with the given backup to transition it to a final
+// state.
+func (s *BackupSupervisor) transitionBackup(backup *model.Backup, logger log.FieldLogger) (string, error) {
+	switch backup.State {
+	case model.BackupStateRequested:
+		return s.startBackup(backup, logger)
+	case model.BackupStateInProgress:
+		return s.checkBackup(backup, logger)
+	default:
+		logger.Warnf("Found backup pending work in unexpected state %s", backup.State)
+		return backup.State, nil
+	}
+}
+
+func (s *BackupSupervisor) startBackup(backup *model.Backup, logger log.FieldLogger) (string, error) {
+	// Keep the installation from changing, or being restored, while its
+	// filestore is copied.
+	lock := newInstallationLock(backup.InstallationID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		logger.Debug("Installation is locked; retrying later")
+		return backup.State, nil
+	}
+	defer lock.Unlock()
+
+	installation, err := s.store.GetInstallation(backup.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return backup.State, nil
+	}
+	if installation == nil || installation.DeleteAt != 0 {
+		logger.Error("Installation no longer exists")
+		return model.BackupStateFailed, errors.New("installation no longer exists")
+	}
+
+	err = model.ValidateBackupSupport(installation)
+	if err != nil {
+		logger.WithError(err).Error("Installation cannot be backed up")
+		return model.BackupStateFailed, errors.Wrap(err, "installation cannot be backed up")
+	}
+
+	err = s.operator.StartDatabaseBackup(installation, backup, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to start database backup")
+		return model.BackupStateFailed, errors.Wrap(err, "failed to start database backup")
+	}
+
+	size, err := s.operator.BackupFilestore(installation, backup, s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to back up filestore")
+		return model.BackupStateFailed, errors.Wrap(err, "failed to back up filestore")
+	}
+	backup.Size = size
+
+	logger.Info("Filestore backed up; waiting for database backup")
+
+	return model.BackupStateInProgress, nil
+}
+
+func (s *BackupSupervisor) checkBackup(backup *model.Backup, logger log.FieldLogger) (string, error) {
+	done, size, err := s.operator.CheckDatabaseBackup(backup, logger)
+	if err != nil {
+		logger.WithError(err).Error("Database backup failed")
+		return model.BackupStateFailed, errors.Wrap(err, "database backup failed")
+	}
+	if !done {
+		return backup.State, nil
+	}
+	backup.Size += size
+
+	logger.Info("Backup succeeded")
+
+	return model.BackupStateSucceeded, nil
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+type mockBackupOperator struct {
+	databaseBackupDone bool
+	databaseBackupErr  error
+	filestoreBackupErr error
+	databaseRestored   bool
+	databaseRestoreErr error
+	filestoreRestored  bool
+}
+
+func (o *mockBackupOperator) StartDatabaseBackup(installation *model.Installation, backup *model.Backup, logger log.FieldLogger) error {
+	backup.DatabaseSnapshotID = "snapshot-" + backup.ID
+	return nil
+}
+
+func (o *mockBackupOperator) CheckDatabaseBackup(backup *model.Backup, logger log.FieldLogger) (bool, int64, error) {
+	return o.databaseBackupDone, 1 << 30, o.databaseBackupErr
+}
+
+func (o *mockBackupOperator) BackupFilestore(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (int64, error) {
+	if o.filestoreBackupErr != nil {
+		return 0, o.filestoreBackupErr
+	}
+	backup.FilestoreLocation = "s3://backups/" + installation.ID + "/" + backup.ID + "/"
+	return 1024, nil
+}
+
+func (o *mockBackupOperator) RestoreDatabase(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (bool, error) {
+	return o.databaseRestored, o.databaseRestoreErr
+}
+
+func (o *mockBackupOperator) RestoreFilestore(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
+	o.filestoreRestored = true
+	return nil
+}
+
+func TestBackupSupervisor(t *testing.T) {
+	createBackup := func(t *testing.T, sqlStore *store.SQLStore, database string) *model.Backup {
+		installation := &model.Installation{
+			OwnerID:   model.NewID(),
+			DNS:       model.NewID() + ".example.com",
+			Database:  database,
+			Filestore: model.InstallationFilestoreAwsS3,
+			State:     model.InstallationStateStable,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		backup := &model.Backup{
+			InstallationID: installation.ID,
+			State:          model.BackupStateRequested,
+		}
+		err = sqlStore.CreateBackup(backup)
+		require.NoError(t, err)
+
+		return backup
+	}
+
+	getBackup := func(t *testing.T, sqlStore *store.SQLStore, backupID string) *model.Backup {
+		backup, err := sqlStore.GetBackup(backupID)
+		require.NoError(t, err)
+
+		return backup
+	}
+
+	t.Run("success", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		operator := &mockBackupOperator{}
+		supervisor := supervisor.NewBackupSupervisor(sqlStore, operator, model.NewID(), logger)
+
+		backup := createBackup(t, sqlStore, model.InstallationDatabaseSingleTenantRDSMySQL)
+
+		err := supervisor.Do()
+		require.NoError(t, err)
+		backup = getBackup(t, sqlStore, backup.ID)
+		require.Equal(t, model.BackupStateInProgress, backup.State)
+		require.Equal(t, "snapshot-"+backup.ID, backup.DatabaseSnapshotID)
+		require.NotEmpty(t, backup.FilestoreLocation)
+		require.Equal(t, int64(1024), backup.Size)
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+		require.Equal(t, model.BackupStateInProgress, getBackup(t, sqlStore, backup.ID).State)
+
+		operator.databaseBackupDone = true
+		err = supervisor.Do()
+		require.NoError(t, err)
+		backup = getBackup(t, sqlStore, backup.ID)
+		require.Equal(t, model.BackupStateSucceeded, backup.State)
+		require.Equal(t, int64(1024+1<<30), backup.Size)
+		require.NotZero(t, backup.CompleteAt)
+		require.Empty(t, backup.Error)
+	})
+
+	t.Run("webhooks filtered by installation owner", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewBackupSupervisor(sqlStore, &mockBackupOperator{}, model.NewID(), logger)
+
+		backup := createBackup(t, sqlStore, model.InstallationDatabaseSingleTenantRDSMySQL)
+		installation, err := sqlStore.GetInstallation(backup.InstallationID, false, false)
+		require.NoError(t, err)
+
+		webhook := &model.Webhook{
+			OwnerID:      model.NewID(),
+			URL:          "http://127.0.0.1:1",
+			EventOwnerID: installation.OwnerID,
+		}
+		err = sqlStore.CreateWebhook(webhook)
+		require.NoError(t, err)
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+		require.Equal(t, model.BackupStateInProgress, getBackup(t, sqlStore, backup.ID).State)
+
+		deliveries, err := sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			WebhookID: webhook.ID,
+			PerPage:   model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+
+		payload, err := model.WebhookPayloadFromReader(strings.NewReader(deliveries[0].Payload))
+		require.NoError(t, err)
+		require.Equal(t, backup.ID, payload.ID)
+		require.Equal(t, installation.OwnerID, payload.OwnerID)
+	})
+
+	t.Run("unsupported installation", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewBackupSupervisor(sqlStore, &mockBackupOperator{}, model.NewID(), logger)
+
+		backup := createBackup(t, sqlStore, model.InstallationDatabaseMysqlOperator)
+
+		err := supervisor.Do()
+		require.NoError(t, err)
+		backup = getBackup(t, sqlStore, backup.ID)
+		require.Equal(t, model.BackupStateFailed, backup.State)
+		require.Contains(t, backup.Error, "not supported")
+		require.NotZero(t, backup.CompleteAt)
+	})
+
+	t.Run("filestore failure", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		operator := &mockBackupOperator{filestoreBackupErr: errors.New("access denied")}
+		supervisor := supervisor.NewBackupSupervisor(sqlStore, operator, model.NewID(), logger)
+
+		backup := createBackup(t, sqlStore, model.InstallationDatabaseSingleTenantRDSPostgres)
+
+		err := supervisor.Do()
+		require.NoError(t, err)
+		backup = getBackup(t, sqlStore, backup.ID)
+		require.Equal(t, model.BackupStateFailed, backup.State)
+		require.Contains(t, backup.Error, "access denied")
+	})
+
+	t.Run("database failure", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		operator := &mockBackupOperator{}
+		supervisor := supervisor.NewBackupSupervisor(sqlStore, operator, model.NewID(), logger)
+
+		backup := createBackup(t, sqlStore, model.InstallationDatabaseSingleTenantRDSMySQL)
+
+		err := supervisor.Do()
+		require.NoError(t, err)
+
+		operator.databaseBackupErr = errors.New("DB cluster snapshot is failed")
+		err = supervisor.Do()
+		require.NoError(t, err)
+		backup = getBackup(t, sqlStore, backup.ID)
+		require.Equal(t, model.BackupStateFailed, backup.State)
+		require.Contains(t, backup.Error, "snapshot is failed")
+	})
+
+	t.Run("installation locked", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewBackupSupervisor(sqlStore, &mockBackupOperator{}, model.NewID(), logger)
+
+		backup := createBackup(t, sqlStore, model.InstallationDatabaseSingleTenantRDSMySQL)
+		locked, err := sqlStore.LockInstallation(backup.InstallationID, model.NewID())
+		require.NoError(t, err)
+		require.True(t, locked)
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+		require.Equal(t, model.BackupStateRequested, getBackup(t, sqlStore, backup.ID).State)
+	})
+}
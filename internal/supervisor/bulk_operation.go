@@ -0,0 +1,320 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// bulkOperationStore abstracts the database operations required to run bulk
+// operations.
+type bulkOperationStore interface {
+	GetBulkOperation(bulkOperationID string) (*model.BulkOperation, error)
+	GetUnlockedBulkOperationsPendingWork() ([]*model.BulkOperation, error)
+	UpdateBulkOperation(bulkOperation *model.BulkOperation) error
+	LockBulkOperation(bulkOperationID, lockerID string) (bool, error)
+	UnlockBulkOperation(bulkOperationID, lockerID string, force bool) (bool, error)
+	GetBulkOperationResults(bulkOperationID string) ([]*model.BulkOperationResult, error)
+	CreateBulkOperationResult(result *model.BulkOperationResult) error
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	UpdateInstallation(installation *model.Installation) error
+	LockInstallation(installationID, lockerID string) (bool, error)
+	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
+
+	GetGroup(groupID string) (*model.Group, error)
+	LockGroup(groupID, lockerID string) (bool, error)
+	UnlockGroup(groupID, lockerID string, force bool) (bool, error)
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+}
+
+// BulkOperationSupervisor finds pending bulk operations and applies their
+// action to every installation they select.
+//
+// Installations are acted on the same way as through the single installation
+// API, leaving the actual work to the installation supervisor. An operation
+// interrupted part way is resumed from the installations it has no result
+// for yet.
+type BulkOperationSupervisor struct {
+	store      bulkOperationStore
+	instanceID string
+	logger     log.FieldLogger
+}
+
+// NewBulkOperationSupervisor creates a new BulkOperationSupervisor.
+func NewBulkOperationSupervisor(store bulkOperationStore, instanceID string, logger log.FieldLogger) *BulkOperationSupervisor {
+	return &BulkOperationSupervisor{
+		store:      store,
+		instanceID: instanceID,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the bulk operation supervisor.
+func (s *BulkOperationSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down bulk operation supervisor")
+}
+
+// Do looks for pending bulk operations and runs them.
+func (s *BulkOperationSupervisor) Do() error {
+	bulkOperations, err := s.store.GetUnlockedBulkOperationsPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for bulk operations pending work")
+		recordSupervisorError(BulkOperationSupervisorName)
+		return nil
+	}
+
+	for _, bulkOperation := range bulkOperations {
+		s.Supervise(bulkOperation)
+	}
+
+	return nil
+}
+
+// Supervise runs the given bulk operation.
+func (s *BulkOperationSupervisor) Supervise(bulkOperation *model.BulkOperation) {
+	logger := s.logger.WithFields(log.Fields{
+		"bulkOperation": bulkOperation.ID,
+		"action":        bulkOperation.Action,
+	})
+
+	lock := newBulkOperationLock(bulkOperation.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Ensure the bulk operation wasn't run by another provisioning server
+	// since it was selected.
+	bulkOperation, err := s.store.GetBulkOperation(bulkOperation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed bulk operation")
+		return
+	}
+	if !bulkOperation.IsPending() {
+		logger.Debugf("Bulk operation is now %s; skipping...", bulkOperation.State)
+		return
+	}
+
+	if bulkOperation.Action == model.BulkActionJoinGroup {
+		group, err := s.store.GetGroup(bulkOperation.GroupID)
+		if err != nil {
+			logger.WithError(err).Error("Failed to get group")
+			return
+		}
+		if group == nil || group.IsDeleted() {
+			s.complete(bulkOperation, model.BulkOperationStateFailed, fmt.Sprintf("group %s does not exist", bulkOperation.GroupID), logger)
+			return
+		}
+
+		// Keep the group from changing while installations join it.
+		groupLock := newGroupLock(group.ID, s.instanceID, s.store, logger)
+		if !groupLock.TryLock() {
+			logger.Debug("Group is locked; retrying later")
+			return
+		}
+		defer groupLock.Unlock()
+	}
+
+	filter := *bulkOperation.Filter
+	filter.Page = 0
+	filter.PerPage = model.AllPerPage
+	filter.Cursor = nil
+	filter.IncludeDeleted = false
+
+	installations, err := s.store.GetInstallations(&filter, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query for installations")
+		return
+	}
+
+	results, err := s.store.GetBulkOperationResults(bulkOperation.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to query for bulk operation results")
+		return
+	}
+	done := make(map[string]bool, len(results))
+	for _, result := range results {
+		done[result.InstallationID] = true
+	}
+
+	logger.Infof("Running bulk operation against %d installations", len(installations)-len(done))
+
+	concurrency := make(chan struct{}, bulkOperation.Concurrency)
+	var wg sync.WaitGroup
+	for _, installation := range installations {
+		if done[installation.ID] {
+			continue
+		}
+
+		wg.Add(1)
+		concurrency <- struct{}{}
+		go func(installationID string) {
+			defer wg.Done()
+			defer func() { <-concurrency }()
+
+			installationLogger := logger.WithField("installation", installationID)
+			result := s.apply(bulkOperation, installationID, installationLogger)
+
+			err := s.store.CreateBulkOperationResult(result)
+			if err != nil {
+				installationLogger.WithError(err).Error("Failed to record bulk operation result")
+			}
+		}(installation.ID)
+	}
+	wg.Wait()
+
+	s.complete(bulkOperation, model.BulkOperationStateCompleted, "", logger)
+}
+
+func (s *BulkOperationSupervisor) complete(bulkOperation *model.BulkOperation, state, message string, logger log.FieldLogger) {
+	bulkOperation.State = state
+	bulkOperation.Error = message
+	bulkOperation.CompleteAt = time.Now().UnixNano() / int64(time.Millisecond)
+	if state == model.BulkOperationStateFailed {
+		recordSupervisorError(BulkOperationSupervisorName)
+	}
+
+	err := s.store.UpdateBulkOperation(bulkOperation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update bulk operation")
+		return
+	}
+
+	logger.Infof("Bulk operation finished in state %s", state)
+}
+
+// apply applies the action of the given bulk operation to a single
+// installation, returning the outcome.
+func (s *BulkOperationSupervisor) apply(bulkOperation *model.BulkOperation, installationID string, logger log.FieldLogger) *model.BulkOperationResult {
+	result := &model.BulkOperationResult{
+		BulkOperationID: bulkOperation.ID,
+		InstallationID:  installationID,
+	}
+
+	lock := newInstallationLock(installationID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		result.Status = model.BulkResultFailed
+		result.Message = "installation is locked by another operation"
+		return result
+	}
+	defer lock.Unlock()
+
+	installation, err := s.store.GetInstallation(installationID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed installation")
+		result.Status = model.BulkResultFailed
+		result.Message = "failed to get installation"
+		return result
+	}
+	if installation == nil || installation.DeleteAt != 0 {
+		result.Status = model.BulkResultSkipped
+		result.Message = "installation no longer exists"
+		return result
+	}
+
+	oldState := installation.State
+	result.OldState = oldState
+	result.NewState = oldState
+
+	if installation.APISecurityLock {
+		result.Status = model.BulkResultSkipped
+		result.Message = "installation API is locked"
+		return result
+	}
+
+	newState, skipReason := prepareBulkAction(bulkOperation, installation)
+	if skipReason != "" {
+		result.Status = model.BulkResultSkipped
+		result.Message = skipReason
+		return result
+	}
+	if newState != oldState && !installation.ValidTransitionState(newState) {
+		result.Status = model.BulkResultSkipped
+		result.Message = fmt.Sprintf("installation cannot go from %s to %s", oldState, newState)
+		return result
+	}
+
+	result.NewState = newState
+	if bulkOperation.DryRun {
+		result.Status = model.BulkResultPlanned
+		return result
+	}
+
+	installation.State = newState
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update installation")
+		result.NewState = oldState
+		result.Status = model.BulkResultFailed
+		result.Message = "failed to update installation"
+		return result
+	}
+
+	if newState != oldState {
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeInstallation,
+			ID:        installation.ID,
+			OwnerID:   installation.OwnerID,
+			NewState:  newState,
+			OldState:  oldState,
+			Timestamp: time.Now().UnixNano(),
+			ExtraData: map[string]string{"DNS": installation.DNS},
+		}
+		err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+		if err != nil {
+			logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	result.Status = model.BulkResultSucceeded
+	return result
+}
+
+// prepareBulkAction applies the action of the given bulk operation to the
+// installation in memory, returning the state the installation should move
+// to, or the reason for which the action does not apply.
+func prepareBulkAction(bulkOperation *model.BulkOperation, installation *model.Installation) (string, string) {
+	switch bulkOperation.Action {
+	case model.BulkActionHibernate:
+		return model.InstallationStateHibernationRequested, ""
+	case model.BulkActionWakeUp:
+		if installation.State != model.InstallationStateHibernating {
+			return "", "installation is not hibernating"
+		}
+		return model.InstallationStateUpdateRequested, ""
+	case model.BulkActionUpdate:
+		if !bulkOperation.Patch.Apply(installation) {
+			return "", "installation already matches the patch"
+		}
+		return model.InstallationStateUpdateRequested, ""
+	case model.BulkActionJoinGroup:
+		if installation.GroupID != nil && *installation.GroupID == bulkOperation.GroupID {
+			return "", "installation is already in the group"
+		}
+		// The group supervisor takes care of rolling out the group
+		// configuration, as when joining a single installation.
+		groupID := bulkOperation.GroupID
+		installation.GroupID = &groupID
+		return installation.State, ""
+	case model.BulkActionDelete:
+		if installation.State == model.InstallationStateDeletionRequested {
+			return "", "installation deletion was already requested"
+		}
+		return model.InstallationStateDeletionRequested, ""
+	default:
+		return "", fmt.Sprintf("unsupported action %s", bulkOperation.Action)
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+type bulkOperationLockStore interface {
+	LockBulkOperation(bulkOperationID, lockerID string) (bool, error)
+	UnlockBulkOperation(bulkOperationID, lockerID string, force bool) (bool, error)
+}
+
+type bulkOperationLock struct {
+	bulkOperationID string
+	lockerID        string
+	store           bulkOperationLockStore
+	logger          log.FieldLogger
+}
+
+func newBulkOperationLock(bulkOperationID, lockerID string, store bulkOperationLockStore, logger log.FieldLogger) *bulkOperationLock {
+	return &bulkOperationLock{
+		bulkOperationID: bulkOperationID,
+		lockerID:        lockerID,
+		store:           store,
+		logger:          logger,
+	}
+}
+
+func (l *bulkOperationLock) TryLock() bool {
+	locked, err := l.store.LockBulkOperation(l.bulkOperationID, l.lockerID)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to lock bulk operation")
+		return false
+	}
+
+	return locked
+}
+
+func (l *bulkOperationLock) Unlock() {
+	unlocked, err := l.store.UnlockBulkOperation(l.bulkOperationID, l.lockerID, false)
+	if err != nil {
+		l.logger.WithError(err).Error("failed to unlock bulk operation")
+	} else if unlocked != true {
+		l.logger.Error("failed to release lock for bulk operation")
+	}
+}
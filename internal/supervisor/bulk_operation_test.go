@@ -0,0 +1,207 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkOperationSupervisor(t *testing.T) {
+	createInstallation := func(t *testing.T, sqlStore *store.SQLStore, ownerID, state string) *model.Installation {
+		installation := &model.Installation{
+			OwnerID: ownerID,
+			DNS:     model.NewID() + ".example.com",
+			Version: "5.30.0",
+			State:   state,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		return installation
+	}
+
+	createBulkOperation := func(t *testing.T, sqlStore *store.SQLStore, bulkOperation *model.BulkOperation) *model.BulkOperation {
+		bulkOperation.State = model.BulkOperationStatePending
+		if bulkOperation.Concurrency == 0 {
+			bulkOperation.Concurrency = 2
+		}
+		err := sqlStore.CreateBulkOperation(bulkOperation)
+		require.NoError(t, err)
+
+		return bulkOperation
+	}
+
+	resultsByInstallation := func(t *testing.T, sqlStore *store.SQLStore, bulkOperationID string) map[string]*model.BulkOperationResult {
+		results, err := sqlStore.GetBulkOperationResults(bulkOperationID)
+		require.NoError(t, err)
+
+		byInstallation := make(map[string]*model.BulkOperationResult)
+		for _, result := range results {
+			byInstallation[result.InstallationID] = result
+		}
+
+		return byInstallation
+	}
+
+	t.Run("hibernate", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewBulkOperationSupervisor(sqlStore, model.NewID(), logger)
+
+		ownerID := model.NewID()
+		stable := createInstallation(t, sqlStore, ownerID, model.InstallationStateStable)
+		creating := createInstallation(t, sqlStore, ownerID, model.InstallationStateCreationInProgress)
+		locked := createInstallation(t, sqlStore, ownerID, model.InstallationStateStable)
+		err := sqlStore.LockInstallationAPI(locked.ID)
+		require.NoError(t, err)
+		other := createInstallation(t, sqlStore, model.NewID(), model.InstallationStateStable)
+
+		bulkOperation := createBulkOperation(t, sqlStore, &model.BulkOperation{
+			Action: model.BulkActionHibernate,
+			Filter: &model.InstallationFilter{OwnerID: ownerID},
+		})
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+
+		bulkOperation, err = sqlStore.GetBulkOperation(bulkOperation.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.BulkOperationStateCompleted, bulkOperation.State)
+		require.NotZero(t, bulkOperation.CompleteAt)
+
+		results := resultsByInstallation(t, sqlStore, bulkOperation.ID)
+		require.Len(t, results, 3)
+		require.Equal(t, model.BulkResultSucceeded, results[stable.ID].Status)
+		require.Equal(t, model.InstallationStateHibernationRequested, results[stable.ID].NewState)
+		require.Equal(t, model.BulkResultSkipped, results[creating.ID].Status)
+		require.Equal(t, model.BulkResultSkipped, results[locked.ID].Status)
+
+		installation, err := sqlStore.GetInstallation(stable.ID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateHibernationRequested, installation.State)
+		require.Nil(t, installation.LockAcquiredBy)
+
+		installation, err = sqlStore.GetInstallation(other.ID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateStable, installation.State)
+
+		t.Run("completed operations are not run again", func(t *testing.T) {
+			err = supervisor.Do()
+			require.NoError(t, err)
+			require.Len(t, resultsByInstallation(t, sqlStore, bulkOperation.ID), 3)
+		})
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewBulkOperationSupervisor(sqlStore, model.NewID(), logger)
+
+		installation := createInstallation(t, sqlStore, model.NewID(), model.InstallationStateStable)
+		bulkOperation := createBulkOperation(t, sqlStore, &model.BulkOperation{
+			Action: model.BulkActionDelete,
+			Filter: &model.InstallationFilter{},
+			DryRun: true,
+		})
+
+		err := supervisor.Do()
+		require.NoError(t, err)
+
+		results := resultsByInstallation(t, sqlStore, bulkOperation.ID)
+		require.Len(t, results, 1)
+		require.Equal(t, model.BulkResultPlanned, results[installation.ID].Status)
+		require.Equal(t, model.InstallationStateDeletionRequested, results[installation.ID].NewState)
+
+		installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateStable, installation.State)
+	})
+
+	t.Run("update and wake up", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewBulkOperationSupervisor(sqlStore, model.NewID(), logger)
+
+		stable := createInstallation(t, sqlStore, model.NewID(), model.InstallationStateStable)
+		hibernating := createInstallation(t, sqlStore, model.NewID(), model.InstallationStateHibernating)
+
+		version := "5.31.0"
+		update := createBulkOperation(t, sqlStore, &model.BulkOperation{
+			Action: model.BulkActionUpdate,
+			Filter: &model.InstallationFilter{States: []string{model.InstallationStateStable}},
+			Patch:  &model.PatchInstallationRequest{Version: &version},
+		})
+		wakeUp := createBulkOperation(t, sqlStore, &model.BulkOperation{
+			Action: model.BulkActionWakeUp,
+			Filter: &model.InstallationFilter{},
+		})
+
+		err := supervisor.Do()
+		require.NoError(t, err)
+
+		results := resultsByInstallation(t, sqlStore, update.ID)
+		require.Len(t, results, 1)
+		require.Equal(t, model.BulkResultSucceeded, results[stable.ID].Status)
+
+		installation, err := sqlStore.GetInstallation(stable.ID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, version, installation.Version)
+		require.Equal(t, model.InstallationStateUpdateRequested, installation.State)
+
+		results = resultsByInstallation(t, sqlStore, wakeUp.ID)
+		require.Len(t, results, 2)
+		require.Equal(t, model.BulkResultSkipped, results[stable.ID].Status)
+		require.Equal(t, model.BulkResultSucceeded, results[hibernating.ID].Status)
+	})
+
+	t.Run("join group", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewBulkOperationSupervisor(sqlStore, model.NewID(), logger)
+
+		installation := createInstallation(t, sqlStore, model.NewID(), model.InstallationStateStable)
+
+		missingGroup := createBulkOperation(t, sqlStore, &model.BulkOperation{
+			Action:  model.BulkActionJoinGroup,
+			Filter:  &model.InstallationFilter{},
+			GroupID: model.NewID(),
+		})
+
+		group := &model.Group{Version: "5.31.0"}
+		err := sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+		joinGroup := createBulkOperation(t, sqlStore, &model.BulkOperation{
+			Action:  model.BulkActionJoinGroup,
+			Filter:  &model.InstallationFilter{},
+			GroupID: group.ID,
+		})
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+
+		missingGroup, err = sqlStore.GetBulkOperation(missingGroup.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.BulkOperationStateFailed, missingGroup.State)
+		require.NotEmpty(t, missingGroup.Error)
+
+		results := resultsByInstallation(t, sqlStore, joinGroup.ID)
+		require.Len(t, results, 1)
+		require.Equal(t, model.BulkResultSucceeded, results[installation.ID].Status)
+
+		installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, group.ID, *installation.GroupID)
+
+		group, err = sqlStore.GetGroup(group.ID)
+		require.NoError(t, err)
+		require.Nil(t, group.LockAcquiredBy)
+	})
+}
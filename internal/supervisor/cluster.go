@@ -5,11 +5,15 @@
 package supervisor
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/internal/webhook"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -23,7 +27,17 @@ type clusterStore interface {
 	UnlockCluster(clusterID string, lockerID string, force bool) (bool, error)
 	DeleteCluster(clusterID string) error
 
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	UpdateInstallation(installation *model.Installation) error
+	LockInstallation(installationID, lockerID string) (bool, error)
+	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
+
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+
+	CreateEvent(event *model.Event) error
 }
 
 // clusterProvisioner abstracts the provisioning operations required by the cluster supervisor.
@@ -70,6 +84,7 @@ func (s *ClusterSupervisor) Do() error {
 	clusters, err := s.store.GetUnlockedClustersPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for clusters pending work")
+		recordSupervisorError(ClusterSupervisorName)
 		return nil
 	}
 
@@ -109,7 +124,12 @@ func (s *ClusterSupervisor) Supervise(cluster *model.Cluster) {
 
 	logger.Debugf("Supervising cluster in state %s", cluster.State)
 
-	newState := s.transitionCluster(cluster, logger)
+	span, spanLogger := startTransitionSpan(logger, "ClusterSupervisor.transitionCluster", model.TypeCluster, cluster.ID, cluster.State)
+	newState, transitionErr := s.transitionCluster(cluster, spanLogger)
+	endTransitionSpan(span, newState, transitionErr)
+	if transitionErr != nil {
+		recordSupervisorError(ClusterSupervisorName)
+	}
 
 	cluster, err = s.store.GetCluster(cluster.ID)
 	if err != nil {
@@ -141,11 +161,20 @@ func (s *ClusterSupervisor) Supervise(cluster *model.Cluster) {
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
 
+	recordEvent(s.store, &model.Event{
+		ResourceType: model.TypeCluster,
+		ResourceID:   cluster.ID,
+		OldState:     oldState,
+		NewState:     newState,
+		InstanceID:   s.instanceID,
+		Error:        errorMessage(transitionErr),
+	}, logger)
+
 	logger.Debugf("Transitioned cluster from %s to %s", oldState, newState)
 }
 
 // Do works with the given cluster to transition it to a final state.
-func (s *ClusterSupervisor) transitionCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) transitionCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	switch cluster.State {
 	case model.ClusterStateCreationRequested:
 		return s.createCluster(cluster, logger)
@@ -157,69 +186,239 @@ func (s *ClusterSupervisor) transitionCluster(cluster *model.Cluster, logger log
 		return s.resizeCluster(cluster, logger)
 	case model.ClusterStateRefreshMetadata:
 		return s.refreshClusterMetadata(cluster, logger)
+	case model.ClusterStateDrainRequested:
+		return s.drainCluster(cluster, logger)
 	case model.ClusterStateDeletionRequested:
 		return s.deleteCluster(cluster, logger)
 	default:
 		logger.Warnf("Found cluster pending work in unexpected state %s", cluster.State)
-		return cluster.State
+		return cluster.State, nil
 	}
 }
 
-func (s *ClusterSupervisor) createCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) createCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	var err error
 
 	if s.provisioner.PrepareCluster(cluster) {
 		err = s.store.UpdateCluster(cluster)
 		if err != nil {
 			logger.WithError(err).Error("Failed to record updated cluster after creation")
-			return model.ClusterStateCreationFailed
+			return model.ClusterStateCreationFailed, errors.Wrap(err, "failed to record updated cluster after creation")
 		}
 	}
 
 	err = s.provisioner.CreateCluster(cluster, s.aws)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create cluster")
-		return model.ClusterStateCreationFailed
+		return model.ClusterStateCreationFailed, errors.Wrap(err, "failed to create cluster")
 	}
 
 	logger.Info("Finished creating cluster")
 	return s.provisionCluster(cluster, logger)
 }
 
-func (s *ClusterSupervisor) provisionCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) provisionCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	err := s.provisioner.ProvisionCluster(cluster, s.aws)
 	if err != nil {
 		logger.WithError(err).Error("Failed to provision cluster")
-		return model.ClusterStateProvisioningFailed
+		return model.ClusterStateProvisioningFailed, errors.Wrap(err, "failed to provision cluster")
 	}
 
 	logger.Info("Finished provisioning cluster")
 	return s.refreshClusterMetadata(cluster, logger)
 }
 
-func (s *ClusterSupervisor) upgradeCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) upgradeCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	err := s.provisioner.UpgradeCluster(cluster, s.aws)
 	if err != nil {
 		logger.WithError(err).Error("Failed to upgrade cluster")
-		return model.ClusterStateUpgradeFailed
+		return model.ClusterStateUpgradeFailed, errors.Wrap(err, "failed to upgrade cluster")
 	}
 
 	logger.Info("Finished upgrading cluster")
 	return s.refreshClusterMetadata(cluster, logger)
 }
 
-func (s *ClusterSupervisor) resizeCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) resizeCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	err := s.provisioner.ResizeCluster(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to resize cluster")
-		return model.ClusterStateResizeFailed
+		return model.ClusterStateResizeFailed, errors.Wrap(err, "failed to resize cluster")
 	}
 
 	logger.Info("Finished resizing cluster")
 	return s.refreshClusterMetadata(cluster, logger)
 }
 
-func (s *ClusterSupervisor) refreshClusterMetadata(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) drainCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
+	if cluster.AllowInstallations {
+		cluster.AllowInstallations = false
+		err := s.store.UpdateCluster(cluster)
+		if err != nil {
+			logger.WithError(err).Error("Failed to stop installation scheduling on cluster")
+			return model.ClusterStateDrainRequested, nil
+		}
+	}
+
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:   model.AllPerPage,
+		ClusterID: cluster.ID,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to find cluster installations")
+		return model.ClusterStateDrainRequested, nil
+	}
+
+	var remaining, migrating int
+	var pending []*model.Installation
+	var unmigratable []string
+	for _, clusterInstallation := range clusterInstallations {
+		installation, err := s.store.GetInstallation(clusterInstallation.InstallationID, false, false)
+		if err != nil {
+			logger.WithError(err).Warnf("Failed to get installation %s", clusterInstallation.InstallationID)
+			return model.ClusterStateDrainRequested, nil
+		}
+		if installation == nil || installation.DeleteAt != 0 {
+			continue
+		}
+		if installation.Affinity == model.InstallationAffinityIsolated {
+			logger.Debugf("Skipping isolated installation %s", installation.ID)
+			continue
+		}
+		if installation.InternalDatabase() || installation.InternalFilestore() {
+			// The data of these installations lives on the cluster itself and
+			// would be lost with it.
+			unmigratable = append(unmigratable, fmt.Sprintf("%s (in-cluster database or filestore)", installation.ID))
+			continue
+		}
+
+		remaining++
+
+		switch installation.State {
+		case model.InstallationStateMigrationRequested,
+			model.InstallationStateMigrationInProgress,
+			model.InstallationStateMigrationDNS,
+			model.InstallationStateMigrationCleanup:
+			migrating++
+		case model.InstallationStateStable:
+			pending = append(pending, installation)
+		default:
+			// Installations with pending work, such as an update, are
+			// migrated once stable. The others, hibernating or failed, will
+			// not get there on their own.
+			if model.InstallationStateIsSettled(installation.State) {
+				unmigratable = append(unmigratable, fmt.Sprintf("%s (%s)", installation.ID, installation.State))
+			}
+		}
+	}
+
+	if len(unmigratable) != 0 {
+		logger.Errorf("Unable to drain cluster: installations %s cannot be migrated", strings.Join(unmigratable, ", "))
+		return model.ClusterStateDrainFailed, errors.Errorf("unable to drain cluster: installations %s cannot be migrated", strings.Join(unmigratable, ", "))
+	}
+
+	if remaining == 0 {
+		logger.Info("Finished draining cluster")
+		return model.ClusterStateStable, nil
+	}
+
+	maxConcurrent := cluster.DrainMaxConcurrent
+	if maxConcurrent < 1 {
+		maxConcurrent = model.DefaultClusterDrainMaxConcurrent
+	}
+
+	var started int
+	for _, installation := range pending {
+		if migrating >= maxConcurrent {
+			break
+		}
+		if s.requestInstallationMigration(installation, logger) {
+			migrating++
+			started++
+		}
+	}
+
+	logger.Debugf("Draining cluster: %d installations remaining, %d migrating", remaining, migrating)
+
+	if started > 0 {
+		webhookPayload := &model.WebhookPayload{
+			Type:      model.TypeCluster,
+			ID:        cluster.ID,
+			NewState:  model.ClusterStateDrainRequested,
+			OldState:  model.ClusterStateDrainRequested,
+			Timestamp: time.Now().UnixNano(),
+			ExtraData: map[string]string{
+				"DrainRemaining": strconv.Itoa(remaining),
+				"DrainMigrating": strconv.Itoa(migrating),
+			},
+		}
+		err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+		if err != nil {
+			logger.WithError(err).Error("Unable to process and send webhooks")
+		}
+	}
+
+	return model.ClusterStateDrainRequested, nil
+}
+
+// requestInstallationMigration requests that the given installation is moved
+// to another cluster chosen by the installation supervisor.
+func (s *ClusterSupervisor) requestInstallationMigration(installation *model.Installation, logger log.FieldLogger) bool {
+	logger = logger.WithField("installation", installation.ID)
+
+	lock := newInstallationLock(installation.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		logger.Debug("Failed to lock installation")
+		return false
+	}
+	defer lock.Unlock()
+
+	installation, err := s.store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get refreshed installation")
+		return false
+	}
+	if installation.State != model.InstallationStateStable {
+		return false
+	}
+
+	oldState := installation.State
+	installation.State = model.InstallationStateMigrationRequested
+	installation.MigrationTargetClusterID = ""
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to request installation migration")
+		return false
+	}
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  installation.State,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installation.DNS},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	recordEvent(s.store, &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installation.ID,
+		OldState:     oldState,
+		NewState:     installation.State,
+		InstanceID:   s.instanceID,
+	}, logger)
+
+	logger.Info("Requested installation migration")
+
+	return true
+}
+
+func (s *ClusterSupervisor) refreshClusterMetadata(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	if cluster.ProvisionerMetadataKops != nil {
 		cluster.ProvisionerMetadataKops.ClearChangeRequest()
 		cluster.ProvisionerMetadataKops.ClearWarnings()
@@ -228,30 +427,30 @@ func (s *ClusterSupervisor) refreshClusterMetadata(cluster *model.Cluster, logge
 	err := s.provisioner.RefreshKopsMetadata(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to refresh cluster")
-		return model.ClusterStateRefreshMetadata
+		return model.ClusterStateRefreshMetadata, nil
 	}
 	err = s.store.UpdateCluster(cluster)
 	if err != nil {
 		logger.WithError(err).Error("Failed to save updated cluster metadata")
-		return model.ClusterStateRefreshMetadata
+		return model.ClusterStateRefreshMetadata, nil
 	}
 
-	return model.ClusterStateStable
+	return model.ClusterStateStable, nil
 }
 
-func (s *ClusterSupervisor) deleteCluster(cluster *model.Cluster, logger log.FieldLogger) string {
+func (s *ClusterSupervisor) deleteCluster(cluster *model.Cluster, logger log.FieldLogger) (string, error) {
 	err := s.provisioner.DeleteCluster(cluster, s.aws)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete cluster")
-		return model.ClusterStateDeletionFailed
+		return model.ClusterStateDeletionFailed, errors.Wrap(err, "failed to delete cluster")
 	}
 
 	err = s.store.DeleteCluster(cluster.ID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to record updated cluster after deletion")
-		return model.ClusterStateDeletionFailed
+		return model.ClusterStateDeletionFailed, errors.Wrap(err, "failed to record updated cluster after deletion")
 	}
 
 	logger.Info("Finished deleting cluster")
-	return model.ClusterStateDeleted
+	return model.ClusterStateDeleted, nil
 }
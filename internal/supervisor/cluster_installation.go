@@ -7,6 +7,7 @@ package supervisor
 import (
 	"time"
 
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
@@ -30,6 +31,10 @@ type clusterInstallationStore interface {
 	DeleteClusterInstallation(clusterInstallationID string) error
 
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+
+	CreateEvent(event *model.Event) error
 }
 
 // provisioner abstracts the provisioning operations required by the cluster installation supervisor.
@@ -73,6 +78,7 @@ func (s *ClusterInstallationSupervisor) Do() error {
 	clusterInstallations, err := s.store.GetUnlockedClusterInstallationsPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for cluster installations pending work")
+		recordSupervisorError(ClusterInstallationSupervisorName)
 		return nil
 	}
 
@@ -112,7 +118,10 @@ func (s *ClusterInstallationSupervisor) Supervise(clusterInstallation *model.Clu
 
 	logger.Debugf("Supervising cluster installation in state %s", clusterInstallation.State)
 
-	newState := s.transitionClusterInstallation(clusterInstallation, logger)
+	newState, transitionErr := s.transitionClusterInstallation(clusterInstallation, logger)
+	if transitionErr != nil {
+		recordSupervisorError(ClusterInstallationSupervisorName)
+	}
 
 	clusterInstallation, err = s.store.GetClusterInstallation(clusterInstallation.ID)
 	if err != nil {
@@ -132,9 +141,20 @@ func (s *ClusterInstallationSupervisor) Supervise(clusterInstallation *model.Clu
 		return
 	}
 
+	// The owner is only used to filter webhooks, so a failed lookup does not
+	// prevent the event from being sent.
+	var ownerID string
+	installation, err := s.store.GetInstallation(clusterInstallation.InstallationID, false, false)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation owner for webhook")
+	} else if installation != nil {
+		ownerID = installation.OwnerID
+	}
+
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeClusterInstallation,
 		ID:        clusterInstallation.ID,
+		OwnerID:   ownerID,
 		NewState:  newState,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
@@ -145,6 +165,15 @@ func (s *ClusterInstallationSupervisor) Supervise(clusterInstallation *model.Clu
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
 
+	recordEvent(s.store, &model.Event{
+		ResourceType: model.TypeClusterInstallation,
+		ResourceID:   clusterInstallation.ID,
+		OldState:     oldState,
+		NewState:     newState,
+		InstanceID:   s.instanceID,
+		Error:        errorMessage(transitionErr),
+	}, logger)
+
 	logger.Debugf("Transitioned cluster installation from %s to %s", oldState, newState)
 }
 
@@ -161,25 +190,25 @@ func failedClusterInstallationState(state string) string {
 }
 
 // transitionClusterInstallation works with the given cluster installation to transition it to a final state.
-func (s *ClusterInstallationSupervisor) transitionClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger) string {
+func (s *ClusterInstallationSupervisor) transitionClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger) (string, error) {
 	cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 	if err != nil {
 		logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
-		return clusterInstallation.State
+		return clusterInstallation.State, nil
 	}
 	if cluster == nil {
 		logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
-		return failedClusterInstallationState(clusterInstallation.State)
+		return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
 	}
 
 	installation, err := s.store.GetInstallation(clusterInstallation.InstallationID, true, false)
 	if err != nil {
 		logger.WithError(err).Warnf("Failed to query installation %s", clusterInstallation.InstallationID)
-		return clusterInstallation.State
+		return clusterInstallation.State, nil
 	}
 	if installation == nil {
 		logger.Errorf("Failed to find installation %s", clusterInstallation.InstallationID)
-		return failedClusterInstallationState(clusterInstallation.State)
+		return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find installation %s", clusterInstallation.InstallationID)
 	}
 
 	switch clusterInstallation.State {
@@ -191,58 +220,58 @@ func (s *ClusterInstallationSupervisor) transitionClusterInstallation(clusterIns
 		return s.checkReconcilingClusterInstallation(clusterInstallation, logger, installation, cluster)
 	default:
 		logger.Warnf("Found cluster installation pending work in unexpected state %s", clusterInstallation.State)
-		return clusterInstallation.State
+		return clusterInstallation.State, nil
 	}
 }
 
-func (s *ClusterInstallationSupervisor) createClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) string {
+func (s *ClusterInstallationSupervisor) createClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) (string, error) {
 	err := s.provisioner.CreateClusterInstallation(cluster, installation, clusterInstallation, s.aws)
 	if err != nil {
 		logger.WithError(err).Error("Failed to provision cluster installation")
-		return model.ClusterInstallationStateCreationRequested
+		return model.ClusterInstallationStateCreationRequested, nil
 	}
 
 	err = s.store.UpdateClusterInstallation(clusterInstallation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to record updated cluster installation after provisioning")
-		return model.ClusterInstallationStateCreationFailed
+		return model.ClusterInstallationStateCreationFailed, errors.Wrap(err, "failed to record updated cluster installation after provisioning")
 	}
 
 	logger.Info("Finished creating cluster installation")
-	return model.ClusterInstallationStateReconciling
+	return model.ClusterInstallationStateReconciling, nil
 }
 
-func (s *ClusterInstallationSupervisor) deleteClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) string {
+func (s *ClusterInstallationSupervisor) deleteClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) (string, error) {
 	err := s.provisioner.DeleteClusterInstallation(cluster, installation, clusterInstallation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete cluster installation")
-		return model.ClusterInstallationStateDeletionFailed
+		return model.ClusterInstallationStateDeletionFailed, errors.Wrap(err, "failed to delete cluster installation")
 	}
 
 	err = s.store.DeleteClusterInstallation(clusterInstallation.ID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to record deleted cluster installation after deletion")
-		return model.ClusterStateDeletionFailed
+		return model.ClusterStateDeletionFailed, errors.Wrap(err, "failed to record deleted cluster installation after deletion")
 	}
 
 	logger.Info("Finished deleting cluster installation")
-	return model.ClusterInstallationStateDeleted
+	return model.ClusterInstallationStateDeleted, nil
 }
 
-func (s *ClusterInstallationSupervisor) checkReconcilingClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) string {
+func (s *ClusterInstallationSupervisor) checkReconcilingClusterInstallation(clusterInstallation *model.ClusterInstallation, logger log.FieldLogger, installation *model.Installation, cluster *model.Cluster) (string, error) {
 	cr, err := s.provisioner.GetClusterInstallationResource(cluster, installation, clusterInstallation)
 	if err != nil {
 		logger.WithError(err).Error("Failed to get cluster installation resource")
-		return model.ClusterInstallationStateReconciling
+		return model.ClusterInstallationStateReconciling, nil
 	}
 
 	if cr.Status.State != mmv1alpha1.Stable ||
 		cr.Spec.Replicas != cr.Status.Replicas ||
 		cr.Spec.Version != cr.Status.Version {
 		logger.Info("Cluster installation is still reconciling")
-		return model.ClusterInstallationStateReconciling
+		return model.ClusterInstallationStateReconciling, nil
 	}
 
 	logger.Info("Cluster installation finished reconciling")
-	return model.ClusterInstallationStateStable
+	return model.ClusterInstallationStateStable, nil
 }
@@ -61,6 +61,18 @@ func (s *mockClusterInstallationStore) GetWebhooks(filter *model.WebhookFilter)
 	return nil, nil
 }
 
+func (s *mockClusterInstallationStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockClusterInstallationStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockClusterInstallationStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
 type mockClusterInstallationProvisioner struct{}
 
 func (p *mockClusterInstallationProvisioner) CreateClusterInstallation(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation, awsClient aws.AWS) error {
@@ -6,12 +6,16 @@ package supervisor_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/internal/store"
 	"github.com/mattermost/mattermost-cloud/internal/supervisor"
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
 	"github.com/mattermost/mattermost-cloud/internal/tools/aws"
 	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,10 +60,42 @@ func (s *mockClusterStore) DeleteCluster(clusterID string) error {
 	return nil
 }
 
+func (s *mockClusterStore) GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error) {
+	return nil, nil
+}
+
+func (s *mockClusterStore) GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error) {
+	return nil, nil
+}
+
+func (s *mockClusterStore) UpdateInstallation(installation *model.Installation) error {
+	return nil
+}
+
+func (s *mockClusterStore) LockInstallation(installationID, lockerID string) (bool, error) {
+	return true, nil
+}
+
+func (s *mockClusterStore) UnlockInstallation(installationID, lockerID string, force bool) (bool, error) {
+	return true, nil
+}
+
 func (s *mockClusterStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error) {
 	return nil, nil
 }
 
+func (s *mockClusterStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockClusterStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockClusterStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
 type mockClusterProvisioner struct{}
 
 func (p *mockClusterProvisioner) PrepareCluster(cluster *model.Cluster) bool {
@@ -90,6 +126,22 @@ func (p *mockClusterProvisioner) RefreshKopsMetadata(cluster *model.Cluster) err
 	return nil
 }
 
+type failingClusterProvisioner struct {
+	mockClusterProvisioner
+}
+
+func (p *failingClusterProvisioner) CreateCluster(cluster *model.Cluster, aws aws.AWS) error {
+	return errors.New("kops create failed")
+}
+
+type failingRefreshClusterProvisioner struct {
+	mockClusterProvisioner
+}
+
+func (p *failingRefreshClusterProvisioner) RefreshKopsMetadata(cluster *model.Cluster) error {
+	return errors.New("kops refresh failed")
+}
+
 func TestClusterSupervisorDo(t *testing.T) {
 	t.Run("no clusters pending work", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
@@ -135,6 +187,7 @@ func TestClusterSupervisorSupervise(t *testing.T) {
 		{"resize requested", model.ClusterStateResizeRequested, model.ClusterStateStable},
 		{"deletion requested", model.ClusterStateDeletionRequested, model.ClusterStateDeleted},
 		{"refresh metadata", model.ClusterStateRefreshMetadata, model.ClusterStateStable},
+		{"drain requested, no installations", model.ClusterStateDrainRequested, model.ClusterStateStable},
 	}
 
 	for _, tc := range testCases {
@@ -182,4 +235,246 @@ func TestClusterSupervisorSupervise(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, model.ClusterStateDeletionRequested, cluster.State)
 	})
+
+	t.Run("records events", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+
+		cluster := &model.Cluster{
+			Provider:                model.ProviderAWS,
+			ProvisionerMetadataKops: &model.KopsMetadata{},
+			State:                   model.ClusterStateCreationRequested,
+		}
+		err := sqlStore.CreateCluster(cluster, nil)
+		require.NoError(t, err)
+
+		supervisorErrors := metrics.SupervisorErrors.WithLabelValues(supervisor.ClusterSupervisorName)
+		errorsBefore := testutil.ToFloat64(supervisorErrors)
+
+		supervisor.NewClusterSupervisor(sqlStore, &failingClusterProvisioner{}, &mockAWS{}, "instanceID", logger).Supervise(cluster)
+
+		cluster, err = sqlStore.GetCluster(cluster.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.ClusterStateCreationFailed, cluster.State)
+		require.Equal(t, errorsBefore+1, testutil.ToFloat64(supervisorErrors))
+
+		cluster.State = model.ClusterStateCreationRequested
+		err = sqlStore.UpdateCluster(cluster)
+		require.NoError(t, err)
+
+		time.Sleep(1 * time.Millisecond)
+
+		supervisor.NewClusterSupervisor(sqlStore, &mockClusterProvisioner{}, &mockAWS{}, "instanceID", logger).Supervise(cluster)
+
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			ResourceType: model.TypeCluster,
+			ResourceID:   cluster.ID,
+			PerPage:      model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		require.Equal(t, model.ClusterStateCreationRequested, events[0].OldState)
+		require.Equal(t, model.ClusterStateStable, events[0].NewState)
+		require.Equal(t, "instanceID", events[0].InstanceID)
+		require.Empty(t, events[0].Error)
+
+		require.Equal(t, model.ClusterStateCreationRequested, events[1].OldState)
+		require.Equal(t, model.ClusterStateCreationFailed, events[1].NewState)
+		require.Equal(t, "instanceID", events[1].InstanceID)
+		require.Equal(t, "failed to create cluster: kops create failed", events[1].Error)
+	})
+
+	t.Run("records no error for errors which do not fail the transition", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+
+		cluster := &model.Cluster{
+			Provider:                model.ProviderAWS,
+			ProvisionerMetadataKops: &model.KopsMetadata{},
+			State:                   model.ClusterStateProvisioningRequested,
+		}
+		err := sqlStore.CreateCluster(cluster, nil)
+		require.NoError(t, err)
+
+		supervisor.NewClusterSupervisor(sqlStore, &failingRefreshClusterProvisioner{}, &mockAWS{}, "instanceID", logger).Supervise(cluster)
+
+		events, err := sqlStore.GetEvents(&model.EventFilter{
+			ResourceType: model.TypeCluster,
+			ResourceID:   cluster.ID,
+			PerPage:      model.AllPerPage,
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, model.ClusterStateRefreshMetadata, events[0].NewState)
+		require.Empty(t, events[0].Error)
+	})
+
+	t.Run("drain requested", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewClusterSupervisor(sqlStore, &mockClusterProvisioner{}, &mockAWS{}, "instanceID", logger)
+
+		cluster := &model.Cluster{
+			Provider:           model.ProviderAWS,
+			State:              model.ClusterStateDrainRequested,
+			AllowInstallations: true,
+			DrainMaxConcurrent: 1,
+		}
+		err := sqlStore.CreateCluster(cluster, nil)
+		require.NoError(t, err)
+
+		createInstallation := func(t *testing.T, affinity, database string) *model.Installation {
+			installation := &model.Installation{
+				OwnerID:   model.NewID(),
+				DNS:       model.NewID() + ".example.com",
+				Affinity:  affinity,
+				Database:  database,
+				Filestore: model.InstallationFilestoreMultiTenantAwsS3,
+				State:     model.InstallationStateStable,
+			}
+			err = sqlStore.CreateInstallation(installation, nil)
+			require.NoError(t, err)
+
+			err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+				ClusterID:      cluster.ID,
+				InstallationID: installation.ID,
+				Namespace:      installation.ID,
+				State:          model.ClusterInstallationStateStable,
+			})
+			require.NoError(t, err)
+
+			return installation
+		}
+
+		installation1 := createInstallation(t, model.InstallationAffinityMultiTenant, model.InstallationDatabaseMultiTenantRDSPostgres)
+		installation2 := createInstallation(t, model.InstallationAffinityMultiTenant, model.InstallationDatabaseMultiTenantRDSPostgres)
+
+		expectInstallationStates := func(t *testing.T, expected map[string]string) {
+			t.Helper()
+			for installationID, state := range expected {
+				installation, err := sqlStore.GetInstallation(installationID, false, false)
+				require.NoError(t, err)
+				require.Equal(t, state, installation.State)
+			}
+		}
+
+		t.Run("migrations limited by max concurrent", func(t *testing.T) {
+			supervisor.Supervise(cluster)
+
+			cluster, err = sqlStore.GetCluster(cluster.ID)
+			require.NoError(t, err)
+			require.Equal(t, model.ClusterStateDrainRequested, cluster.State)
+			require.False(t, cluster.AllowInstallations)
+
+			installation1, err = sqlStore.GetInstallation(installation1.ID, false, false)
+			require.NoError(t, err)
+			installation2, err = sqlStore.GetInstallation(installation2.ID, false, false)
+			require.NoError(t, err)
+
+			var migrationRequested int
+			for _, installation := range []*model.Installation{installation1, installation2} {
+				if installation.State == model.InstallationStateMigrationRequested {
+					migrationRequested++
+				}
+			}
+			require.Equal(t, 1, migrationRequested)
+		})
+
+		t.Run("failed migration fails drain", func(t *testing.T) {
+			installation1.State = model.InstallationStateMigrationFailed
+			err = sqlStore.UpdateInstallation(installation1)
+			require.NoError(t, err)
+
+			supervisor.Supervise(cluster)
+
+			cluster, err = sqlStore.GetCluster(cluster.ID)
+			require.NoError(t, err)
+			require.Equal(t, model.ClusterStateDrainFailed, cluster.State)
+		})
+
+		t.Run("hibernating installation fails drain", func(t *testing.T) {
+			cluster.State = model.ClusterStateDrainRequested
+			err = sqlStore.UpdateCluster(cluster)
+			require.NoError(t, err)
+
+			installation1.State = model.InstallationStateHibernating
+			err = sqlStore.UpdateInstallation(installation1)
+			require.NoError(t, err)
+
+			supervisor.Supervise(cluster)
+
+			cluster, err = sqlStore.GetCluster(cluster.ID)
+			require.NoError(t, err)
+			require.Equal(t, model.ClusterStateDrainFailed, cluster.State)
+		})
+
+		t.Run("installations with pending work waited on", func(t *testing.T) {
+			cluster.State = model.ClusterStateDrainRequested
+			err = sqlStore.UpdateCluster(cluster)
+			require.NoError(t, err)
+
+			installation1.State = model.InstallationStateUpdateInProgress
+			err = sqlStore.UpdateInstallation(installation1)
+			require.NoError(t, err)
+
+			supervisor.Supervise(cluster)
+
+			cluster, err = sqlStore.GetCluster(cluster.ID)
+			require.NoError(t, err)
+			require.Equal(t, model.ClusterStateDrainRequested, cluster.State)
+			expectInstallationStates(t, map[string]string{
+				installation1.ID: model.InstallationStateUpdateInProgress,
+			})
+		})
+
+		t.Run("drained once installations are moved", func(t *testing.T) {
+			cluster.State = model.ClusterStateDrainRequested
+			err = sqlStore.UpdateCluster(cluster)
+			require.NoError(t, err)
+
+			clusterInstallations, err := sqlStore.GetClusterInstallations(&model.ClusterInstallationFilter{
+				PerPage:   model.AllPerPage,
+				ClusterID: cluster.ID,
+			})
+			require.NoError(t, err)
+			for _, clusterInstallation := range clusterInstallations {
+				err = sqlStore.DeleteClusterInstallation(clusterInstallation.ID)
+				require.NoError(t, err)
+			}
+
+			supervisor.Supervise(cluster)
+
+			cluster, err = sqlStore.GetCluster(cluster.ID)
+			require.NoError(t, err)
+			require.Equal(t, model.ClusterStateStable, cluster.State)
+			require.False(t, cluster.AllowInstallations)
+		})
+
+		t.Run("in-cluster database fails drain", func(t *testing.T) {
+			cluster.State = model.ClusterStateDrainRequested
+			err = sqlStore.UpdateCluster(cluster)
+			require.NoError(t, err)
+
+			internalDatabaseInstallation := createInstallation(t, model.InstallationAffinityMultiTenant, model.InstallationDatabaseMysqlOperator)
+
+			supervisor.Supervise(cluster)
+
+			cluster, err = sqlStore.GetCluster(cluster.ID)
+			require.NoError(t, err)
+			require.Equal(t, model.ClusterStateDrainFailed, cluster.State)
+			expectInstallationStates(t, map[string]string{
+				internalDatabaseInstallation.ID: model.InstallationStateStable,
+			})
+
+			events, err := sqlStore.GetEvents(&model.EventFilter{
+				ResourceType: model.TypeCluster,
+				ResourceID:   cluster.ID,
+				PerPage:      1,
+			})
+			require.NoError(t, err)
+			require.Len(t, events, 1)
+			require.Contains(t, events[0].Error, internalDatabaseInstallation.ID)
+		})
+	})
 }
@@ -0,0 +1,33 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+type eventStore interface {
+	CreateEvent(event *model.Event) error
+}
+
+// recordEvent stores the given state change in the event history. Failing to
+// do so is logged, but does not interrupt the supervisor.
+func recordEvent(store eventStore, event *model.Event, logger log.FieldLogger) {
+	err := store.CreateEvent(event)
+	if err != nil {
+		logger.WithError(err).Error("Unable to record event")
+	}
+}
+
+// errorMessage returns the message of the error that failed a transition, if
+// any, for storage with the resulting event.
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
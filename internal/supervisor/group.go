@@ -22,6 +22,8 @@ type groupStore interface {
 	UpdateInstallationState(*model.Installation) error
 	LockInstallation(installationID, lockerID string) (bool, error)
 	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
+
+	CreateEvent(event *model.Event) error
 }
 
 // GroupSupervisor finds installations belonging to groups that need to have
@@ -55,6 +57,7 @@ func (s *GroupSupervisor) Do() error {
 	groups, err := s.store.GetUnlockedGroupsPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for groups")
+		recordSupervisorError(GroupSupervisorName)
 		return nil
 	}
 
@@ -115,12 +118,20 @@ func (s *GroupSupervisor) Supervise(group *model.Group) {
 			continue
 		}
 
+		oldState := installation.State
 		installation.State = model.InstallationStateUpdateRequested
 		err = s.store.UpdateInstallationState(installation)
 		if err != nil {
 			logger.WithError(err).Error("Unable to set new installation state")
 		} else {
 			moved++
+			recordEvent(s.store, &model.Event{
+				ResourceType: model.TypeInstallation,
+				ResourceID:   installation.ID,
+				OldState:     oldState,
+				NewState:     installation.State,
+				InstanceID:   s.instanceID,
+			}, logger)
 		}
 		installationLock.Unlock()
 	}
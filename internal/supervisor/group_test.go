@@ -46,6 +46,10 @@ func (s *mockGroupStore) UnlockGroup(groupID, lockerID string, force bool) (bool
 	return true, nil
 }
 
+func (s *mockGroupStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
 func (s *mockGroupStore) GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error) {
 	return s.Installation, nil
 }
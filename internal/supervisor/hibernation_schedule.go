@@ -0,0 +1,155 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// hibernationScheduleStore abstracts the database operations required to
+// apply hibernation schedules.
+type hibernationScheduleStore interface {
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	UpdateInstallation(installation *model.Installation) error
+	LockInstallation(installationID, lockerID string) (bool, error)
+	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+}
+
+// HibernationScheduleSupervisor hibernates and wakes up installations
+// according to their hibernation schedule, or that of their group.
+//
+// Installations are moved to hibernation-requested or update-requested as
+// through the API, leaving the actual work to the installation supervisor.
+// Each scheduled action is applied once: an installation woken up during its
+// scheduled hibernation stays up until its next scheduled hibernation.
+type HibernationScheduleSupervisor struct {
+	store      hibernationScheduleStore
+	instanceID string
+	logger     log.FieldLogger
+}
+
+// NewHibernationScheduleSupervisor creates a new HibernationScheduleSupervisor.
+func NewHibernationScheduleSupervisor(store hibernationScheduleStore, instanceID string, logger log.FieldLogger) *HibernationScheduleSupervisor {
+	return &HibernationScheduleSupervisor{
+		store:      store,
+		instanceID: instanceID,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the hibernation schedule
+// supervisor.
+func (s *HibernationScheduleSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down hibernation schedule supervisor")
+}
+
+// Do looks for scheduled installations due to hibernate or wake up and
+// transitions them.
+func (s *HibernationScheduleSupervisor) Do() error {
+	installations, err := s.store.GetInstallations(&model.InstallationFilter{
+		PerPage: model.AllPerPage,
+		States:  []string{model.InstallationStateStable, model.InstallationStateHibernating},
+	}, true, false)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for installations")
+		recordSupervisorError(HibernationScheduleSupervisorName)
+		return nil
+	}
+
+	now := time.Now()
+	for _, installation := range installations {
+		if installation.HibernationSchedule == nil {
+			continue
+		}
+		s.Supervise(installation, now)
+	}
+
+	return nil
+}
+
+// Supervise applies the last action scheduled before the given time to the
+// given installation, unless it was already applied.
+func (s *HibernationScheduleSupervisor) Supervise(installation *model.Installation, now time.Time) {
+	logger := s.logger.WithField("installation", installation.ID)
+
+	action, scheduledAt, err := installation.HibernationSchedule.LastAction(now)
+	if err != nil {
+		logger.WithError(err).Error("Failed to evaluate hibernation schedule")
+		return
+	}
+	scheduledAtMillis := scheduledAt.UnixNano() / int64(time.Millisecond)
+	if action == "" || scheduledAtMillis <= installation.ScheduledActionAt {
+		return
+	}
+	logger = logger.WithField("action", action)
+
+	lock := newInstallationLock(installation.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Refresh the installation without the group configuration so that it
+	// may be saved.
+	installation, err = s.store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed installation")
+		return
+	}
+	if installation == nil || installation.DeleteAt != 0 || scheduledAtMillis <= installation.ScheduledActionAt {
+		return
+	}
+
+	oldState := installation.State
+	newState := oldState
+	switch {
+	case installation.APISecurityLock:
+		logger.Info("Installation API is locked; skipping scheduled action")
+	case action == model.HibernationScheduleActionHibernate && oldState == model.InstallationStateStable:
+		newState = model.InstallationStateHibernationRequested
+	case action == model.HibernationScheduleActionWakeUp && oldState == model.InstallationStateHibernating:
+		newState = model.InstallationStateUpdateRequested
+	case oldState != model.InstallationStateStable && oldState != model.InstallationStateHibernating:
+		// The installation is busy; try again once it settles.
+		logger.Debugf("Installation is %s; retrying scheduled action later", oldState)
+		return
+	}
+
+	installation.State = newState
+	installation.ScheduledActionAt = scheduledAtMillis
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update installation")
+		return
+	}
+
+	if newState == oldState {
+		return
+	}
+	logger.Infof("Installation moved to %s on schedule", newState)
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  newState,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{"DNS": installation.DNS},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+}
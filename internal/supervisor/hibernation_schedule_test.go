@@ -0,0 +1,145 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHibernationScheduleSupervisor(t *testing.T) {
+	// Friday evening, after the weekday hibernation time.
+	now := time.Date(2021, time.March, 19, 21, 0, 0, 0, time.UTC)
+	hibernatedAt := time.Date(2021, time.March, 19, 20, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+	weekdays := &model.HibernationSchedule{HibernateAt: "0 20 * * 1-5", WakeUpAt: "0 8 * * 1-5"}
+
+	createInstallation := func(t *testing.T, sqlStore *store.SQLStore, state string, schedule *model.HibernationSchedule) *model.Installation {
+		installation := &model.Installation{
+			OwnerID:             model.NewID(),
+			DNS:                 model.NewID() + ".example.com",
+			State:               state,
+			HibernationSchedule: schedule,
+		}
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		return installation
+	}
+
+	getInstallation := func(t *testing.T, sqlStore *store.SQLStore, installationID string) *model.Installation {
+		installation, err := sqlStore.GetInstallation(installationID, false, false)
+		require.NoError(t, err)
+
+		return installation
+	}
+
+	t.Run("hibernate", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewHibernationScheduleSupervisor(sqlStore, model.NewID(), logger)
+
+		installation := createInstallation(t, sqlStore, model.InstallationStateStable, weekdays)
+		supervisor.Supervise(installation, now)
+
+		installation = getInstallation(t, sqlStore, installation.ID)
+		require.Equal(t, model.InstallationStateHibernationRequested, installation.State)
+		require.Equal(t, hibernatedAt, installation.ScheduledActionAt)
+	})
+
+	t.Run("wake up", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewHibernationScheduleSupervisor(sqlStore, model.NewID(), logger)
+
+		installation := createInstallation(t, sqlStore, model.InstallationStateHibernating, weekdays)
+		supervisor.Supervise(installation, now.Add(-12*time.Hour))
+
+		installation = getInstallation(t, sqlStore, installation.ID)
+		require.Equal(t, model.InstallationStateUpdateRequested, installation.State)
+	})
+
+	t.Run("already applied", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewHibernationScheduleSupervisor(sqlStore, model.NewID(), logger)
+
+		// The installation was woken up during its scheduled hibernation.
+		installation := createInstallation(t, sqlStore, model.InstallationStateStable, weekdays)
+		installation.ScheduledActionAt = hibernatedAt
+		err := sqlStore.UpdateInstallation(installation)
+		require.NoError(t, err)
+
+		supervisor.Supervise(installation, now)
+		require.Equal(t, model.InstallationStateStable, getInstallation(t, sqlStore, installation.ID).State)
+	})
+
+	t.Run("already in scheduled state", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewHibernationScheduleSupervisor(sqlStore, model.NewID(), logger)
+
+		installation := createInstallation(t, sqlStore, model.InstallationStateHibernating, weekdays)
+		supervisor.Supervise(installation, now)
+
+		installation = getInstallation(t, sqlStore, installation.ID)
+		require.Equal(t, model.InstallationStateHibernating, installation.State)
+		require.Equal(t, hibernatedAt, installation.ScheduledActionAt)
+	})
+
+	t.Run("busy installation is retried", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewHibernationScheduleSupervisor(sqlStore, model.NewID(), logger)
+
+		installation := createInstallation(t, sqlStore, model.InstallationStateUpdateInProgress, weekdays)
+		supervisor.Supervise(installation, now)
+
+		installation = getInstallation(t, sqlStore, installation.ID)
+		require.Equal(t, model.InstallationStateUpdateInProgress, installation.State)
+		require.Zero(t, installation.ScheduledActionAt)
+	})
+
+	t.Run("API security lock", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewHibernationScheduleSupervisor(sqlStore, model.NewID(), logger)
+
+		installation := createInstallation(t, sqlStore, model.InstallationStateStable, weekdays)
+		err := sqlStore.LockInstallationAPI(installation.ID)
+		require.NoError(t, err)
+
+		supervisor.Supervise(installation, now)
+		require.Equal(t, model.InstallationStateStable, getInstallation(t, sqlStore, installation.ID).State)
+	})
+
+	t.Run("group schedule", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewHibernationScheduleSupervisor(sqlStore, model.NewID(), logger)
+
+		// Every minute, so that the schedule is always due.
+		group := &model.Group{Name: "group", HibernationSchedule: &model.HibernationSchedule{HibernateAt: "* * * * *"}}
+		err := sqlStore.CreateGroup(group)
+		require.NoError(t, err)
+
+		installation := createInstallation(t, sqlStore, model.InstallationStateStable, nil)
+		installation.GroupID = &group.ID
+		err = sqlStore.UpdateInstallation(installation)
+		require.NoError(t, err)
+		unscheduled := createInstallation(t, sqlStore, model.InstallationStateStable, nil)
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+
+		require.Equal(t, model.InstallationStateHibernationRequested, getInstallation(t, sqlStore, installation.ID).State)
+		require.Equal(t, model.InstallationStateStable, getInstallation(t, sqlStore, unscheduled.ID).State)
+	})
+}
@@ -0,0 +1,252 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// idleHibernationStore abstracts the database operations required to
+// hibernate idle installations.
+type idleHibernationStore interface {
+	GetCluster(clusterID string) (*model.Cluster, error)
+
+	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
+	GetInstallations(filter *model.InstallationFilter, includeGroupConfig, includeGroupConfigOverrides bool) ([]*model.Installation, error)
+	GetAnnotationsForInstallations(filter *model.InstallationFilter) (map[string][]*model.Annotation, error)
+	UpdateInstallation(installation *model.Installation) error
+	LockInstallation(installationID, lockerID string) (bool, error)
+	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
+
+	GetClusterInstallations(filter *model.ClusterInstallationFilter) ([]*model.ClusterInstallation, error)
+
+	GetEvents(filter *model.EventFilter) ([]*model.Event, error)
+
+	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+}
+
+// idleHibernationProvisioner abstracts the provisioning operations required
+// to hibernate idle installations.
+type idleHibernationProvisioner interface {
+	GetClusterInstallationActivity(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, period time.Duration) (*model.InstallationActivity, error)
+}
+
+// IdleHibernationSupervisor hibernates installations which had no connected
+// users and no more API requests than health probes make for the configured
+// idle period.
+//
+// Installations are moved to hibernation-requested as through the API,
+// leaving the actual work to the installation supervisor. Installations
+// annotated with model.AnnotationNoIdleHibernation, API-locked installations
+// and installations without activity metrics are left alone.
+type IdleHibernationSupervisor struct {
+	store         idleHibernationStore
+	provisioner   idleHibernationProvisioner
+	instanceID    string
+	idlePeriod    time.Duration
+	checkInterval time.Duration
+	maxRequests   int64
+	lastCheck     time.Time
+	logger        log.FieldLogger
+}
+
+// NewIdleHibernationSupervisor creates a new IdleHibernationSupervisor
+// checking the activity of installations every checkInterval. Installations
+// serving at most maxRequestsPerHour API requests on average, such as the
+// ones made by liveness and readiness probes, are still considered idle.
+func NewIdleHibernationSupervisor(store idleHibernationStore, provisioner idleHibernationProvisioner, instanceID string, idlePeriod, checkInterval time.Duration, maxRequestsPerHour int64, logger log.FieldLogger) *IdleHibernationSupervisor {
+	return &IdleHibernationSupervisor{
+		store:         store,
+		provisioner:   provisioner,
+		instanceID:    instanceID,
+		idlePeriod:    idlePeriod,
+		checkInterval: checkInterval,
+		maxRequests:   int64(float64(maxRequestsPerHour) * idlePeriod.Hours()),
+		logger:        logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the idle hibernation
+// supervisor.
+func (s *IdleHibernationSupervisor) Shutdown() {
+	s.logger.Debug("Shutting down idle hibernation supervisor")
+}
+
+// Do looks for idle installations and hibernates them, at most once per
+// check interval.
+func (s *IdleHibernationSupervisor) Do() error {
+	now := time.Now()
+	if now.Sub(s.lastCheck) < s.checkInterval {
+		return nil
+	}
+	s.lastCheck = now
+
+	filter := &model.InstallationFilter{
+		PerPage: model.AllPerPage,
+		States:  []string{model.InstallationStateStable},
+	}
+	installations, err := s.store.GetInstallations(filter, false, false)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for installations")
+		recordSupervisorError(IdleHibernationSupervisorName)
+		return nil
+	}
+	annotations, err := s.store.GetAnnotationsForInstallations(filter)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for installation annotations")
+		return nil
+	}
+
+	for _, installation := range installations {
+		if installation.APISecurityLock || hasAnnotation(annotations[installation.ID], model.AnnotationNoIdleHibernation) {
+			continue
+		}
+		s.Supervise(installation, now)
+	}
+
+	return nil
+}
+
+// Supervise hibernates the given installation if it has been stable, and
+// unused, for the whole idle period before the given time.
+func (s *IdleHibernationSupervisor) Supervise(installation *model.Installation, now time.Time) {
+	logger := s.logger.WithField("installation", installation.ID)
+
+	stableSince, err := s.getStableSince(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to determine when installation became stable")
+		return
+	}
+	if now.Sub(stableSince) < s.idlePeriod {
+		return
+	}
+
+	idle, err := s.isIdle(installation, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to measure installation activity")
+		return
+	}
+	if !idle {
+		return
+	}
+
+	lock := newInstallationLock(installation.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	installation, err = s.store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed installation")
+		return
+	}
+	if installation == nil || installation.DeleteAt != 0 || installation.APISecurityLock || installation.State != model.InstallationStateStable {
+		return
+	}
+
+	oldState := installation.State
+	installation.State = model.InstallationStateHibernationRequested
+	err = s.store.UpdateInstallation(installation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update installation")
+		return
+	}
+	logger.Infof("Installation was idle for %s; moved to %s", s.idlePeriod, installation.State)
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeInstallation,
+		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
+		NewState:  installation.State,
+		OldState:  oldState,
+		Timestamp: time.Now().UnixNano(),
+		ExtraData: map[string]string{
+			"DNS":        installation.DNS,
+			"Reason":     "idle",
+			"IdlePeriod": s.idlePeriod.String(),
+		},
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+}
+
+// getStableSince returns when the installation last changed state, or was
+// created if it never did.
+func (s *IdleHibernationSupervisor) getStableSince(installation *model.Installation) (time.Time, error) {
+	changedAt := installation.CreateAt
+
+	events, err := s.store.GetEvents(&model.EventFilter{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installation.ID,
+		PerPage:      1,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(events) > 0 && events[0].CreateAt > changedAt {
+		changedAt = events[0].CreateAt
+	}
+
+	return time.Unix(0, changedAt*int64(time.Millisecond)), nil
+}
+
+// isIdle returns whether none of the cluster installations of the given
+// installation were used during the idle period. Installations without
+// metrics are not considered idle.
+func (s *IdleHibernationSupervisor) isIdle(installation *model.Installation, logger log.FieldLogger) (bool, error) {
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:        model.AllPerPage,
+		InstallationID: installation.ID,
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(clusterInstallations) == 0 {
+		return false, nil
+	}
+
+	activity := &model.InstallationActivity{}
+	for _, clusterInstallation := range clusterInstallations {
+		cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
+		if err != nil {
+			return false, err
+		}
+		if cluster == nil {
+			logger.Warnf("Failed to find cluster %s", clusterInstallation.ClusterID)
+			return false, nil
+		}
+
+		clusterInstallationActivity, err := s.provisioner.GetClusterInstallationActivity(cluster, clusterInstallation, s.idlePeriod)
+		if err != nil {
+			return false, err
+		}
+		if clusterInstallationActivity == nil {
+			logger.Debugf("No activity metrics for cluster installation %s", clusterInstallation.ID)
+			return false, nil
+		}
+		activity.Add(clusterInstallationActivity)
+	}
+
+	return activity.IsIdle(s.maxRequests), nil
+}
+
+func hasAnnotation(annotations []*model.Annotation, name string) bool {
+	for _, annotation := range annotations {
+		if annotation.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
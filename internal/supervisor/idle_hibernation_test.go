@@ -0,0 +1,167 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+type mockIdleHibernationProvisioner struct {
+	activity map[string]*model.InstallationActivity
+	calls    int
+}
+
+func (p *mockIdleHibernationProvisioner) GetClusterInstallationActivity(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, period time.Duration) (*model.InstallationActivity, error) {
+	p.calls++
+	return p.activity[clusterInstallation.InstallationID], nil
+}
+
+func TestIdleHibernationSupervisor(t *testing.T) {
+	idlePeriod := 24 * time.Hour
+
+	createInstallation := func(t *testing.T, sqlStore *store.SQLStore, annotations []*model.Annotation) *model.Installation {
+		cluster := &model.Cluster{}
+		err := sqlStore.CreateCluster(cluster, nil)
+		require.NoError(t, err)
+
+		installation := &model.Installation{
+			OwnerID: model.NewID(),
+			DNS:     model.NewID() + ".example.com",
+			State:   model.InstallationStateStable,
+		}
+		err = sqlStore.CreateInstallation(installation, annotations)
+		require.NoError(t, err)
+
+		err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+			ClusterID:      cluster.ID,
+			InstallationID: installation.ID,
+			Namespace:      installation.ID,
+			State:          model.ClusterInstallationStateStable,
+		})
+		require.NoError(t, err)
+
+		return installation
+	}
+
+	getState := func(t *testing.T, sqlStore *store.SQLStore, installationID string) string {
+		installation, err := sqlStore.GetInstallation(installationID, false, false)
+		require.NoError(t, err)
+
+		return installation.State
+	}
+
+	t.Run("idle", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		provisioner := &mockIdleHibernationProvisioner{activity: map[string]*model.InstallationActivity{}}
+		supervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, provisioner, model.NewID(), idlePeriod, time.Minute, 10, logger)
+
+		installation := createInstallation(t, sqlStore, nil)
+		provisioner.activity[installation.ID] = &model.InstallationActivity{}
+		supervisor.Supervise(installation, time.Now().Add(idlePeriod))
+
+		require.Equal(t, model.InstallationStateHibernationRequested, getState(t, sqlStore, installation.ID))
+	})
+
+	t.Run("idle with probe requests", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		provisioner := &mockIdleHibernationProvisioner{activity: map[string]*model.InstallationActivity{}}
+		supervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, provisioner, model.NewID(), idlePeriod, time.Minute, 10, logger)
+
+		installation := createInstallation(t, sqlStore, nil)
+		provisioner.activity[installation.ID] = &model.InstallationActivity{Requests: 240}
+		supervisor.Supervise(installation, time.Now().Add(idlePeriod))
+
+		require.Equal(t, model.InstallationStateHibernationRequested, getState(t, sqlStore, installation.ID))
+	})
+
+	t.Run("active", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		provisioner := &mockIdleHibernationProvisioner{activity: map[string]*model.InstallationActivity{}}
+		supervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, provisioner, model.NewID(), idlePeriod, time.Minute, 10, logger)
+
+		installation := createInstallation(t, sqlStore, nil)
+		provisioner.activity[installation.ID] = &model.InstallationActivity{Requests: 241}
+		supervisor.Supervise(installation, time.Now().Add(idlePeriod))
+
+		require.Equal(t, model.InstallationStateStable, getState(t, sqlStore, installation.ID))
+	})
+
+	t.Run("no metrics", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		provisioner := &mockIdleHibernationProvisioner{activity: map[string]*model.InstallationActivity{}}
+		supervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, provisioner, model.NewID(), idlePeriod, time.Minute, 10, logger)
+
+		installation := createInstallation(t, sqlStore, nil)
+		supervisor.Supervise(installation, time.Now().Add(idlePeriod))
+
+		require.Equal(t, model.InstallationStateStable, getState(t, sqlStore, installation.ID))
+		require.Equal(t, 1, provisioner.calls)
+	})
+
+	t.Run("recently changed state", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		provisioner := &mockIdleHibernationProvisioner{activity: map[string]*model.InstallationActivity{}}
+		supervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, provisioner, model.NewID(), idlePeriod, time.Minute, 10, logger)
+
+		installation := createInstallation(t, sqlStore, nil)
+		provisioner.activity[installation.ID] = &model.InstallationActivity{}
+		time.Sleep(1 * time.Millisecond)
+		err := sqlStore.CreateEvent(&model.Event{
+			ResourceType: model.TypeInstallation,
+			ResourceID:   installation.ID,
+			OldState:     model.InstallationStateUpdateInProgress,
+			NewState:     model.InstallationStateStable,
+		})
+		require.NoError(t, err)
+
+		createdAt := time.Unix(0, installation.CreateAt*int64(time.Millisecond))
+		supervisor.Supervise(installation, createdAt.Add(idlePeriod))
+
+		require.Equal(t, model.InstallationStateStable, getState(t, sqlStore, installation.ID))
+		require.Equal(t, 0, provisioner.calls)
+	})
+
+	t.Run("opted out", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		provisioner := &mockIdleHibernationProvisioner{activity: map[string]*model.InstallationActivity{}}
+		supervisor := supervisor.NewIdleHibernationSupervisor(sqlStore, provisioner, model.NewID(), time.Millisecond, time.Hour, 10, logger)
+
+		optedOut := createInstallation(t, sqlStore, []*model.Annotation{{Name: model.AnnotationNoIdleHibernation}})
+		provisioner.activity[optedOut.ID] = &model.InstallationActivity{}
+		idle := createInstallation(t, sqlStore, nil)
+		provisioner.activity[idle.ID] = &model.InstallationActivity{}
+		time.Sleep(2 * time.Millisecond)
+
+		err := supervisor.Do()
+		require.NoError(t, err)
+
+		require.Equal(t, model.InstallationStateStable, getState(t, sqlStore, optedOut.ID))
+		require.Equal(t, model.InstallationStateHibernationRequested, getState(t, sqlStore, idle.ID))
+		require.Equal(t, 1, provisioner.calls)
+
+		// Installations are only checked once per check interval.
+		idle.State = model.InstallationStateStable
+		err = sqlStore.UpdateInstallation(idle)
+		require.NoError(t, err)
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+		require.Equal(t, model.InstallationStateStable, getState(t, sqlStore, idle.ID))
+		require.Equal(t, 1, provisioner.calls)
+	})
+}
@@ -25,6 +25,8 @@ type installationStore interface {
 	UpdateCluster(cluster *model.Cluster) error
 	LockCluster(clusterID, lockerID string) (bool, error)
 	UnlockCluster(clusterID string, lockerID string, force bool) (bool, error)
+	CreateCluster(cluster *model.Cluster, annotations []*model.Annotation) error
+	GetAnnotationsForClusters(filter *model.ClusterFilter) (map[string][]*model.Annotation, error)
 
 	GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error)
 	GetUnlockedInstallationsPendingWork() ([]*model.Installation, error)
@@ -34,6 +36,9 @@ type installationStore interface {
 	LockInstallation(installationID, lockerID string) (bool, error)
 	UnlockInstallation(installationID, lockerID string, force bool) (bool, error)
 	DeleteInstallation(installationID string) error
+	GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error)
+
+	GetBackup(backupID string) (*model.Backup, error)
 
 	CreateClusterInstallation(clusterInstallation *model.ClusterInstallation) error
 	GetClusterInstallation(clusterInstallationID string) (*model.ClusterInstallation, error)
@@ -51,6 +56,10 @@ type installationStore interface {
 	UnlockMultitenantDatabase(multitenantdatabaseID, lockerID string, force bool) (bool, error)
 
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+
+	CreateEvent(event *model.Event) error
 }
 
 // provisioner abstracts the provisioning operations required by the installation supervisor.
@@ -62,6 +71,9 @@ type installationProvisioner interface {
 	GetClusterInstallationResource(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation) (*mmv1alpha1.ClusterInstallation, error)
 	GetClusterResources(cluster *model.Cluster, onlySchedulable bool) (*k8s.ClusterResources, error)
 	GetPublicLoadBalancerEndpoint(cluster *model.Cluster, namespace string) (string, error)
+	GetPrivateLoadBalancerEndpoint(cluster *model.Cluster, namespace string) (string, error)
+	ExecClusterInstallationCLI(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, args ...string) ([]byte, error)
+	NewCIMigrationDatabase(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, source, target *model.Installation) model.CIMigrationDatabase
 }
 
 // InstallationSupervisor finds installations pending work and effects the required changes.
@@ -75,24 +87,58 @@ type InstallationSupervisor struct {
 	instanceID                         string
 	clusterResourceThreshold           int
 	clusterResourceThresholdScaleValue int
+	placementStrategy                  string
+	clusterTemplate                    *model.CreateClusterRequest
 	keepDatabaseData                   bool
 	keepFilestoreData                  bool
 	resourceUtil                       *utils.ResourceUtil
+	backupOperator                     backupOperator
 	logger                             log.FieldLogger
 }
 
-// NewInstallationSupervisor creates a new InstallationSupervisor.
-func NewInstallationSupervisor(store installationStore, installationProvisioner installationProvisioner, aws aws.AWS, instanceID string, threshold, thresholdScaleValue int, keepDatabaseData, keepFilestoreData bool, resourceUtil *utils.ResourceUtil, logger log.FieldLogger) *InstallationSupervisor {
+// InstallationSchedulingConfig describes how the installation supervisor
+// places new installations on clusters.
+type InstallationSchedulingConfig struct {
+	// ClusterResourceThreshold is the percent of CPU or memory usage above
+	// which new installations are not scheduled on a multi-tenant cluster.
+	ClusterResourceThreshold int
+	// ClusterResourceThresholdScaleValue is the number of worker nodes to add
+	// to a cluster passing the threshold. Clusters are not scaled if zero.
+	ClusterResourceThresholdScaleValue int
+	// PlacementStrategy is the strategy used for installations not requesting
+	// one. It defaults to DefaultPlacementStrategy.
+	PlacementStrategy string
+	// ClusterTemplate, if set, is used to create a new cluster whenever no
+	// existing cluster can accept an installation.
+	ClusterTemplate *model.CreateClusterRequest
+}
+
+// DefaultPlacementStrategy is the placement strategy used when none is
+// configured. Spreading installations across the least loaded clusters
+// avoids filling the oldest cluster while newer ones sit idle.
+const DefaultPlacementStrategy = model.PlacementStrategySpread
+
+// NewInstallationSupervisor creates a new InstallationSupervisor scheduling
+// installations as described by the given scheduling config.
+func NewInstallationSupervisor(store installationStore, installationProvisioner installationProvisioner, aws aws.AWS, instanceID string, scheduling InstallationSchedulingConfig, keepDatabaseData, keepFilestoreData bool, resourceUtil *utils.ResourceUtil, backupOperator backupOperator, logger log.FieldLogger) *InstallationSupervisor {
+	placementStrategy := scheduling.PlacementStrategy
+	if placementStrategy == "" {
+		placementStrategy = DefaultPlacementStrategy
+	}
+
 	return &InstallationSupervisor{
 		store:                              store,
 		provisioner:                        installationProvisioner,
 		aws:                                aws,
 		instanceID:                         instanceID,
-		clusterResourceThreshold:           threshold,
-		clusterResourceThresholdScaleValue: thresholdScaleValue,
+		clusterResourceThreshold:           scheduling.ClusterResourceThreshold,
+		clusterResourceThresholdScaleValue: scheduling.ClusterResourceThresholdScaleValue,
+		placementStrategy:                  placementStrategy,
+		clusterTemplate:                    scheduling.ClusterTemplate,
 		keepDatabaseData:                   keepDatabaseData,
 		keepFilestoreData:                  keepFilestoreData,
 		resourceUtil:                       resourceUtil,
+		backupOperator:                     backupOperator,
 		logger:                             logger,
 	}
 }
@@ -107,6 +153,7 @@ func (s *InstallationSupervisor) Do() error {
 	installations, err := s.store.GetUnlockedInstallationsPendingWork()
 	if err != nil {
 		s.logger.WithError(err).Warn("Failed to query for installation pending work")
+		recordSupervisorError(InstallationSupervisorName)
 		return nil
 	}
 
@@ -146,7 +193,12 @@ func (s *InstallationSupervisor) Supervise(installation *model.Installation) {
 
 	logger.Debugf("Supervising installation in state %s", installation.State)
 
-	newState := s.transitionInstallation(installation, s.instanceID, logger)
+	span, spanLogger := startTransitionSpan(logger, "InstallationSupervisor.transitionInstallation", model.TypeInstallation, installation.ID, installation.State)
+	newState, transitionErr := s.transitionInstallation(installation, s.instanceID, spanLogger)
+	endTransitionSpan(span, newState, transitionErr)
+	if transitionErr != nil {
+		recordSupervisorError(InstallationSupervisorName)
+	}
 
 	installation, err = s.store.GetInstallation(installation.ID, true, false)
 	if err != nil {
@@ -179,6 +231,7 @@ func (s *InstallationSupervisor) Supervise(installation *model.Installation) {
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeInstallation,
 		ID:        installation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  newState,
 		OldState:  oldState,
 		Timestamp: time.Now().UnixNano(),
@@ -189,11 +242,20 @@ func (s *InstallationSupervisor) Supervise(installation *model.Installation) {
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
 
+	recordEvent(s.store, &model.Event{
+		ResourceType: model.TypeInstallation,
+		ResourceID:   installation.ID,
+		OldState:     oldState,
+		NewState:     newState,
+		InstanceID:   s.instanceID,
+		Error:        errorMessage(transitionErr),
+	}, logger)
+
 	logger.Debugf("Transitioned installation from %s to %s", oldState, newState)
 }
 
 // transitionInstallation works with the given installation to transition it to a final state.
-func (s *InstallationSupervisor) transitionInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) transitionInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	switch installation.State {
 	case model.InstallationStateCreationRequested,
 		model.InstallationStateCreationNoCompatibleClusters:
@@ -223,6 +285,33 @@ func (s *InstallationSupervisor) transitionInstallation(installation *model.Inst
 	case model.InstallationStateHibernationInProgress:
 		return s.waitForHibernationStable(installation, instanceID, logger)
 
+	case model.InstallationStateRestorationRequested:
+		return s.restoreInstallation(installation, instanceID, logger)
+
+	case model.InstallationStateRestorationInProgress:
+		return s.waitForRestorationComplete(installation, instanceID, logger)
+
+	case model.InstallationStateDBMigrationRequested:
+		return s.migrateInstallationDatabase(installation, instanceID, logger)
+
+	case model.InstallationStateDBMigrationInProgress:
+		return s.waitForDatabaseMigrationComplete(installation, instanceID, logger)
+
+	case model.InstallationStateDBMigrationRollbackInProgress:
+		return s.rollbackDatabaseMigration(installation, instanceID, logger)
+
+	case model.InstallationStateMigrationRequested:
+		return s.migrateInstallation(installation, instanceID, logger)
+
+	case model.InstallationStateMigrationInProgress:
+		return s.waitForMigrationStable(installation, instanceID, logger)
+
+	case model.InstallationStateMigrationDNS:
+		return s.configureMigrationDNS(installation, instanceID, logger)
+
+	case model.InstallationStateMigrationCleanup:
+		return s.cleanupMigrationSource(installation, instanceID, logger)
+
 	case model.InstallationStateDeletionRequested,
 		model.InstallationStateDeletionInProgress:
 		return s.deleteInstallation(installation, instanceID, logger)
@@ -232,18 +321,18 @@ func (s *InstallationSupervisor) transitionInstallation(installation *model.Inst
 
 	default:
 		logger.Warnf("Found installation pending work in unexpected state %s", installation.State)
-		return installation.State
+		return installation.State, nil
 	}
 }
 
-func (s *InstallationSupervisor) createInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) createInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
 		InstallationID: installation.ID,
 		PerPage:        model.AllPerPage,
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return model.InstallationStateCreationRequested
+		return model.InstallationStateCreationRequested, nil
 	}
 
 	if len(clusterInstallations) > 0 {
@@ -258,20 +347,197 @@ func (s *InstallationSupervisor) createInstallation(installation *model.Installa
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to query clusters")
-		return model.InstallationStateCreationRequested
+		return model.InstallationStateCreationRequested, nil
 	}
 
-	for _, cluster := range clusters {
-		clusterInstallation := s.createClusterInstallation(cluster, installation, instanceID, logger)
+	candidates, err := s.getPlacementClusters(clusters)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to gather cluster placement data")
+		return model.InstallationStateCreationRequested, nil
+	}
+
+	compatibleCandidates, err := s.orderPlacementClusters(installation, candidates, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to order placement clusters")
+		return model.InstallationStateCreationRequested, nil
+	}
+
+	for _, candidate := range compatibleCandidates {
+		clusterInstallation := s.createClusterInstallation(candidate.Cluster, installation, instanceID, logger)
 		if clusterInstallation != nil {
 			return s.preProvisionInstallation(installation, instanceID, logger)
 		}
 	}
 
-	// TODO: Support creating a cluster on demand if no existing cluster meets the criteria.
 	logger.Warn("No compatible clusters available for installation scheduling")
 
-	return model.InstallationStateCreationNoCompatibleClusters
+	if s.clusterTemplate != nil {
+		s.createOnDemandCluster(installation, candidates, logger)
+	}
+
+	return model.InstallationStateCreationNoCompatibleClusters, nil
+}
+
+// createOnDemandCluster requests a new cluster from the cluster template so
+// that the installation can be scheduled once the cluster is stable. No new
+// cluster is requested while another on-demand cluster has pending work, such
+// as still being created.
+func (s *InstallationSupervisor) createOnDemandCluster(installation *model.Installation, candidates []*PlacementCluster, logger log.FieldLogger) {
+	for _, candidate := range candidates {
+		if countMatchingAnnotations(candidate.Annotations, []string{model.AnnotationOnDemandCluster}) == 0 {
+			continue
+		}
+		if !model.ClusterStateIsSettled(candidate.Cluster.State) {
+			logger.Debugf("Waiting for on-demand cluster %s in state %s", candidate.Cluster.ID, candidate.Cluster.State)
+			return
+		}
+		switch candidate.Cluster.State {
+		case model.ClusterStateCreationFailed, model.ClusterStateProvisioningFailed:
+			logger.Warnf("On-demand cluster %s is in state %s and requires manual intervention", candidate.Cluster.ID, candidate.Cluster.State)
+			return
+		}
+	}
+
+	template := s.clusterTemplate
+	cluster := &model.Cluster{
+		Provider: template.Provider,
+		ProviderMetadataAWS: &model.AWSMetadata{
+			Zones: template.Zones,
+		},
+		Provisioner: "kops",
+		ProvisionerMetadataKops: &model.KopsMetadata{
+			ChangeRequest: &model.KopsMetadataRequestedState{
+				Version:            template.Version,
+				AMI:                template.KopsAMI,
+				MasterInstanceType: template.MasterInstanceType,
+				MasterCount:        template.MasterCount,
+				NodeInstanceType:   template.NodeInstanceType,
+				NodeMinCount:       template.NodeMinCount,
+				NodeMaxCount:       template.NodeMaxCount,
+			},
+		},
+		AllowInstallations: true,
+		State:              model.ClusterStateCreationRequested,
+	}
+
+	// SetUtilityDesiredVersions modifies the provided versions, so pass a copy
+	// to keep the template intact.
+	utilityVersions := make(map[string]string, len(template.DesiredUtilityVersions))
+	for utility, version := range template.DesiredUtilityVersions {
+		utilityVersions[utility] = version
+	}
+	err := cluster.SetUtilityDesiredVersions(utilityVersions)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set on-demand cluster utility versions")
+		return
+	}
+
+	// The on-demand cluster must satisfy the annotations the installation
+	// requires or it would never be scheduled there.
+	annotationNames := []string{model.AnnotationOnDemandCluster}
+	seen := map[string]bool{model.AnnotationOnDemandCluster: true}
+	for _, names := range [][]string{template.Annotations, installation.RequiredClusterAnnotations} {
+		for _, name := range names {
+			if !seen[name] {
+				seen[name] = true
+				annotationNames = append(annotationNames, name)
+			}
+		}
+	}
+	annotations, err := model.AnnotationsFromStringSlice(annotationNames)
+	if err != nil {
+		logger.WithError(err).Error("Invalid on-demand cluster annotations")
+		return
+	}
+
+	err = s.store.CreateCluster(cluster, annotations)
+	if err != nil {
+		logger.WithError(err).Error("Failed to create on-demand cluster")
+		return
+	}
+
+	logger.WithField("cluster", cluster.ID).Info("Requested creation of on-demand cluster")
+
+	webhookPayload := &model.WebhookPayload{
+		Type:      model.TypeCluster,
+		ID:        cluster.ID,
+		NewState:  model.ClusterStateCreationRequested,
+		OldState:  "n/a",
+		Timestamp: time.Now().UnixNano(),
+	}
+	err = webhook.SendToAllWebhooks(s.store, webhookPayload, logger.WithField("webhookEvent", webhookPayload.NewState))
+	if err != nil {
+		logger.WithError(err).Error("Unable to process and send webhooks")
+	}
+
+	recordEvent(s.store, &model.Event{
+		ResourceType: model.TypeCluster,
+		ResourceID:   cluster.ID,
+		OldState:     webhookPayload.OldState,
+		NewState:     webhookPayload.NewState,
+		InstanceID:   s.instanceID,
+	}, logger)
+}
+
+// orderPlacementClusters filters out the candidates which are incompatible
+// with the installation and orders the remaining ones by preference.
+func (s *InstallationSupervisor) orderPlacementClusters(installation *model.Installation, candidates []*PlacementCluster, logger log.FieldLogger) ([]*PlacementCluster, error) {
+	installationAnnotations, err := s.store.GetAnnotationsForInstallation(installation.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get installation annotations")
+	}
+
+	strategyName := s.placementStrategy
+	if installation.PlacementStrategy != "" {
+		strategyName = installation.PlacementStrategy
+	}
+	strategy, err := NewPlacementStrategy(strategyName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get placement strategy")
+	}
+
+	compatibleCandidates := filterRequiredClusterAnnotations(installation, candidates)
+	if len(compatibleCandidates) == 0 && len(installation.RequiredClusterAnnotations) > 0 {
+		logger.Warnf("No clusters have the required annotations %v", installation.RequiredClusterAnnotations)
+	}
+	compatibleCandidates = strategy.Order(installation, installationAnnotations, compatibleCandidates)
+	compatibleCandidates = sortPreferredClusterAnnotations(installation, compatibleCandidates)
+
+	return compatibleCandidates, nil
+}
+
+// getPlacementClusters gathers the data required by placement strategies for
+// the given clusters.
+func (s *InstallationSupervisor) getPlacementClusters(clusters []*model.Cluster) ([]*PlacementCluster, error) {
+	clusterAnnotations, err := s.store.GetAnnotationsForClusters(&model.ClusterFilter{
+		PerPage:        model.AllPerPage,
+		IncludeDeleted: false,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster annotations")
+	}
+
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage: model.AllPerPage,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get cluster installations")
+	}
+	clusterInstallationCounts := make(map[string]int)
+	for _, clusterInstallation := range clusterInstallations {
+		clusterInstallationCounts[clusterInstallation.ClusterID]++
+	}
+
+	candidates := make([]*PlacementCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		candidates = append(candidates, &PlacementCluster{
+			Cluster:                  cluster,
+			Annotations:              clusterAnnotations[cluster.ID],
+			ClusterInstallationCount: clusterInstallationCounts[cluster.ID],
+		})
+	}
+
+	return candidates, nil
 }
 
 // createClusterInstallation attempts to schedule a cluster installation onto the given cluster.
@@ -401,6 +667,14 @@ func (s *InstallationSupervisor) createClusterInstallation(cluster *model.Cluste
 		if err != nil {
 			logger.WithError(err).Error("Unable to process and send webhooks")
 		}
+
+		recordEvent(s.store, &model.Event{
+			ResourceType: model.TypeCluster,
+			ResourceID:   cluster.ID,
+			OldState:     webhookPayload.OldState,
+			NewState:     webhookPayload.NewState,
+			InstanceID:   s.instanceID,
+		}, logger)
 	}
 
 	// The cluster can support the cluster installation.
@@ -421,6 +695,7 @@ func (s *InstallationSupervisor) createClusterInstallation(cluster *model.Cluste
 	webhookPayload := &model.WebhookPayload{
 		Type:      model.TypeClusterInstallation,
 		ID:        clusterInstallation.ID,
+		OwnerID:   installation.OwnerID,
 		NewState:  model.ClusterInstallationStateCreationRequested,
 		OldState:  "n/a",
 		Timestamp: time.Now().UnixNano(),
@@ -430,22 +705,34 @@ func (s *InstallationSupervisor) createClusterInstallation(cluster *model.Cluste
 		logger.WithError(err).Error("Unable to process and send webhooks")
 	}
 
+	recordEvent(s.store, &model.Event{
+		ResourceType: model.TypeClusterInstallation,
+		ResourceID:   clusterInstallation.ID,
+		OldState:     webhookPayload.OldState,
+		NewState:     webhookPayload.NewState,
+		InstanceID:   s.instanceID,
+	}, logger)
+
 	logger.Infof("Requested creation of cluster installation on cluster %s. Expected resource load: CPU=%d%%, Memory=%d%%", cluster.ID, cpuPercent, memoryPercent)
 
 	return clusterInstallation
 }
 
-func (s *InstallationSupervisor) preProvisionInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) preProvisionInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	if installation.RestoreBackupID != "" {
+		return s.preProvisionClonedInstallation(installation, instanceID, logger)
+	}
+
 	err := s.resourceUtil.GetDatabase(installation).Provision(s.store, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to provision installation database")
-		return model.InstallationStateCreationPreProvisioning
+		return model.InstallationStateCreationPreProvisioning, nil
 	}
 
 	err = s.resourceUtil.GetFilestore(installation).Provision(s.store, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to provision installation filestore")
-		return model.InstallationStateCreationPreProvisioning
+		return model.InstallationStateCreationPreProvisioning, nil
 	}
 
 	logger.Info("Installation pre-provisioning complete")
@@ -453,14 +740,63 @@ func (s *InstallationSupervisor) preProvisionInstallation(installation *model.In
 	return s.configureInstallationDNS(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) waitForCreationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+// preProvisionClonedInstallation provisions the database and filestore of an
+// installation cloned from another one, restoring them from the backup of the
+// source installation once it completes.
+func (s *InstallationSupervisor) preProvisionClonedInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	if s.backupOperator == nil {
+		logger.Error("Cloning installations is not supported by this provisioner")
+		return model.InstallationStateCreationFailed, errors.New("cloning installations is not supported by this provisioner")
+	}
+
+	backup, err := s.store.GetBackup(installation.RestoreBackupID)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get backup to clone")
+		return model.InstallationStateCreationPreProvisioning, nil
+	}
+	if backup == nil || backup.State == model.BackupStateFailed {
+		logger.Errorf("Backup %s cannot be cloned", installation.RestoreBackupID)
+		return model.InstallationStateCreationFailed, errors.Errorf("backup %s cannot be cloned", installation.RestoreBackupID)
+	}
+	if !backup.IsRestorable() {
+		logger.Debugf("Waiting for backup %s to complete before cloning it", backup.ID)
+		return model.InstallationStateCreationPreProvisioning, nil
+	}
+
+	restored, err := s.backupOperator.RestoreDatabase(installation, backup, s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to restore installation database from backup")
+		return model.InstallationStateCreationFailed, errors.Wrap(err, "failed to restore installation database from backup")
+	}
+	if !restored {
+		return model.InstallationStateCreationPreProvisioning, nil
+	}
+
+	err = s.resourceUtil.GetFilestore(installation).Provision(s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to provision installation filestore")
+		return model.InstallationStateCreationPreProvisioning, nil
+	}
+
+	err = s.backupOperator.RestoreFilestore(installation, backup, s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to restore installation filestore from backup")
+		return model.InstallationStateCreationPreProvisioning, nil
+	}
+
+	logger.Infof("Installation pre-provisioning complete; cloned from backup %s", backup.ID)
+
+	return s.configureInstallationDNS(installation, instanceID, logger)
+}
+
+func (s *InstallationSupervisor) waitForCreationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	stable, err := s.checkIfClusterInstallationsAreStable(installation, logger)
 	if err != nil {
 		logger.WithError(err).Error("Installation creation failed")
-		return model.InstallationStateCreationFailed
+		return model.InstallationStateCreationFailed, errors.Wrap(err, "installation creation failed")
 	}
 	if !stable {
-		return model.InstallationStateCreationInProgress
+		return model.InstallationStateCreationInProgress, nil
 	}
 
 	logger.Info("Created cluster installations are now stable")
@@ -468,14 +804,14 @@ func (s *InstallationSupervisor) waitForCreationStable(installation *model.Insta
 	return s.finalCreationTasks(installation, logger)
 }
 
-func (s *InstallationSupervisor) configureInstallationDNS(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) configureInstallationDNS(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
 		InstallationID: installation.ID,
 		PerPage:        model.AllPerPage,
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return model.InstallationStateCreationDNS
+		return model.InstallationStateCreationDNS, nil
 	}
 
 	var endpoints []string
@@ -483,17 +819,17 @@ func (s *InstallationSupervisor) configureInstallationDNS(installation *model.In
 		cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
-			return model.InstallationStateCreationDNS
+			return model.InstallationStateCreationDNS, nil
 		}
 		if cluster == nil {
 			logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
-			return failedClusterInstallationState(clusterInstallation.State)
+			return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
 		}
 
 		endpoint, err := s.provisioner.GetPublicLoadBalancerEndpoint(cluster, "nginx")
 		if err != nil {
 			logger.WithError(err).Error("Couldn't get the load balancer endpoint (nginx) for Cluster Installation")
-			return model.InstallationStateCreationDNS
+			return model.InstallationStateCreationDNS, nil
 		}
 
 		endpoints = append(endpoints, endpoint)
@@ -502,7 +838,7 @@ func (s *InstallationSupervisor) configureInstallationDNS(installation *model.In
 	err = s.aws.CreatePublicCNAME(installation.DNS, endpoints, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to create DNS CNAME record")
-		return model.InstallationStateCreationDNS
+		return model.InstallationStateCreationDNS, nil
 	}
 
 	logger.Infof("Successfully configured DNS %s", installation.DNS)
@@ -510,14 +846,14 @@ func (s *InstallationSupervisor) configureInstallationDNS(installation *model.In
 	return s.waitForCreationStable(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) updateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) updateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
 		PerPage:        model.AllPerPage,
 		InstallationID: installation.ID,
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return installation.State
+		return installation.State, nil
 	}
 
 	var clusterInstallationIDs []string
@@ -529,7 +865,7 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 		clusterInstallationLocks := newClusterInstallationLocks(clusterInstallationIDs, instanceID, s.store, logger)
 		if !clusterInstallationLocks.TryLock() {
 			logger.Debugf("Failed to lock %d cluster installations", len(clusterInstallations))
-			return installation.State
+			return installation.State, nil
 		}
 		defer clusterInstallationLocks.Unlock()
 
@@ -540,7 +876,7 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 		})
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to fetch %d cluster installations by ids", len(clusterInstallations))
-			return installation.State
+			return installation.State, nil
 		}
 
 		if len(clusterInstallations) != len(clusterInstallationIDs) {
@@ -552,24 +888,24 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 		cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
-			return clusterInstallation.State
+			return clusterInstallation.State, nil
 		}
 		if cluster == nil {
 			logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
-			return failedClusterInstallationState(clusterInstallation.State)
+			return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
 		}
 
 		err = s.provisioner.UpdateClusterInstallation(cluster, installation, clusterInstallation)
 		if err != nil {
 			logger.Error("Failed to update cluster installation")
-			return installation.State
+			return installation.State, nil
 		}
 
 		clusterInstallation.State = model.ClusterInstallationStateReconciling
 		err = s.store.UpdateClusterInstallation(clusterInstallation)
 		if err != nil {
 			logger.Errorf("Failed to change cluster installation state to %s", model.ClusterInstallationStateReconciling)
-			return installation.State
+			return installation.State, nil
 		}
 	}
 
@@ -578,34 +914,34 @@ func (s *InstallationSupervisor) updateInstallation(installation *model.Installa
 	return s.waitForUpdateStable(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) waitForUpdateStable(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) waitForUpdateStable(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	stable, err := s.checkIfClusterInstallationsAreStable(installation, logger)
 	if err != nil {
 		logger.WithError(err).Error("Installation update failed")
-		return model.InstallationStateUpdateFailed
+		return model.InstallationStateUpdateFailed, errors.Wrap(err, "installation update failed")
 	}
 	if !stable {
-		return model.InstallationStateUpdateInProgress
+		return model.InstallationStateUpdateInProgress, nil
 	}
 
 	logger.Info("Finished updating installation")
 
-	return model.InstallationStateStable
+	return model.InstallationStateStable, nil
 }
 
-func (s *InstallationSupervisor) hibernateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) hibernateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
 		PerPage:        model.AllPerPage,
 		InstallationID: installation.ID,
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return installation.State
+		return installation.State, nil
 	}
 
 	if len(clusterInstallations) == 0 {
 		logger.Warn("Cluster installation list contained no results")
-		return installation.State
+		return installation.State, nil
 	}
 
 	var clusterInstallationIDs []string
@@ -616,7 +952,7 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 	clusterInstallationLocks := newClusterInstallationLocks(clusterInstallationIDs, instanceID, s.store, logger)
 	if !clusterInstallationLocks.TryLock() {
 		logger.Debugf("Failed to lock %d cluster installations", len(clusterInstallations))
-		return installation.State
+		return installation.State, nil
 	}
 	defer clusterInstallationLocks.Unlock()
 
@@ -627,7 +963,7 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 	})
 	if err != nil {
 		logger.WithError(err).Warnf("Failed to fetch %d cluster installations by ids", len(clusterInstallations))
-		return installation.State
+		return installation.State, nil
 	}
 
 	if len(clusterInstallations) != len(clusterInstallationIDs) {
@@ -638,24 +974,24 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 		cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
-			return clusterInstallation.State
+			return clusterInstallation.State, nil
 		}
 		if cluster == nil {
 			logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
-			return failedClusterInstallationState(clusterInstallation.State)
+			return failedClusterInstallationState(clusterInstallation.State), errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
 		}
 
 		err = s.provisioner.HibernateClusterInstallation(cluster, installation, clusterInstallation)
 		if err != nil {
 			logger.Error("Failed to update cluster installation")
-			return installation.State
+			return installation.State, nil
 		}
 
 		clusterInstallation.State = model.ClusterInstallationStateReconciling
 		err = s.store.UpdateClusterInstallation(clusterInstallation)
 		if err != nil {
 			logger.Errorf("Failed to change cluster installation state to %s", model.ClusterInstallationStateReconciling)
-			return installation.State
+			return installation.State, nil
 		}
 	}
 
@@ -664,24 +1000,613 @@ func (s *InstallationSupervisor) hibernateInstallation(installation *model.Insta
 	return s.waitForHibernationStable(installation, instanceID, logger)
 }
 
-func (s *InstallationSupervisor) waitForHibernationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) waitForHibernationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	stable, err := s.checkIfClusterInstallationsAreStable(installation, logger)
 	if err != nil {
 		// TODO: there is no real failure state for hibernating so handle this
 		// better in the future.
 		logger.WithError(err).Warn("Installation hibernation failed")
-		return model.InstallationStateHibernationInProgress
+		return model.InstallationStateHibernationInProgress, nil
 	}
 	if !stable {
-		return model.InstallationStateHibernationInProgress
+		return model.InstallationStateHibernationInProgress, nil
 	}
 
 	logger.Info("Finished updating installation")
 
-	return model.InstallationStateHibernating
+	return model.InstallationStateHibernating, nil
+}
+
+func (s *InstallationSupervisor) restoreInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	if s.backupOperator == nil {
+		logger.Error("Restoring backups is not supported by this provisioner")
+		return model.InstallationStateRestorationFailed, errors.New("restoring backups is not supported by this provisioner")
+	}
+
+	backup, err := s.store.GetBackup(installation.RestoreBackupID)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get backup to restore")
+		return installation.State, nil
+	}
+	if backup == nil || backup.InstallationID != installation.ID || !backup.IsRestorable() {
+		logger.Errorf("Backup %s cannot be restored to this installation", installation.RestoreBackupID)
+		return model.InstallationStateRestorationFailed, errors.Errorf("backup %s cannot be restored to this installation", installation.RestoreBackupID)
+	}
+
+	err = s.backupOperator.RestoreFilestore(installation, backup, s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to restore filestore")
+		return model.InstallationStateRestorationFailed, errors.Wrap(err, "failed to restore filestore")
+	}
+
+	logger.Infof("Filestore restored from backup %s; restoring database", backup.ID)
+
+	return model.InstallationStateRestorationInProgress, nil
+}
+
+func (s *InstallationSupervisor) waitForRestorationComplete(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	if s.backupOperator == nil {
+		logger.Error("Restoring backups is not supported by this provisioner")
+		return model.InstallationStateRestorationFailed, errors.New("restoring backups is not supported by this provisioner")
+	}
+
+	backup, err := s.store.GetBackup(installation.RestoreBackupID)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get backup to restore")
+		return installation.State, nil
+	}
+	if backup == nil {
+		logger.Errorf("Backup %s no longer exists", installation.RestoreBackupID)
+		return model.InstallationStateRestorationFailed, errors.Errorf("backup %s no longer exists", installation.RestoreBackupID)
+	}
+
+	restored, err := s.backupOperator.RestoreDatabase(installation, backup, s.store, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to restore database")
+		return model.InstallationStateRestorationFailed, errors.Wrap(err, "failed to restore database")
+	}
+	if !restored {
+		return model.InstallationStateRestorationInProgress, nil
+	}
+
+	logger.Infof("Finished restoring installation from backup %s", backup.ID)
+
+	return model.InstallationStateHibernating, nil
+}
+
+// migrateInstallationDatabase prepares moving the data of a hibernating
+// installation to the database and filestore it is being migrated to.
+func (s *InstallationSupervisor) migrateInstallationDatabase(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	err := model.ValidateDatabaseMigration(installation, installation.DatabaseMigrationTarget, installation.FilestoreMigrationTarget)
+	if err != nil {
+		logger.WithError(err).Error("Invalid installation database migration")
+		return model.InstallationStateDBMigrationFailed, errors.Wrap(err, "invalid installation database migration")
+	}
+
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:        model.AllPerPage,
+		InstallationID: installation.ID,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to find cluster installations")
+		return installation.State, nil
+	}
+	// The data of operator backends lives with the cluster installation, so
+	// there is no single source to copy it from when there are several.
+	if len(clusterInstallations) != 1 {
+		logger.Errorf("Expected 1 cluster installation to migrate the database of, but found %d", len(clusterInstallations))
+		return model.InstallationStateDBMigrationFailed, errors.Errorf("expected 1 cluster installation to migrate the database of, but found %d", len(clusterInstallations))
+	}
+
+	migration, err := s.getDatabaseMigration(installation)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation database migration")
+		return installation.State, nil
+	}
+
+	status, err := migration.Setup(logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to set up installation database migration")
+		return model.InstallationStateDBMigrationRollbackInProgress, errors.Wrap(err, "failed to set up installation database migration")
+	}
+	if status != model.DatabaseMigrationStatusSetupComplete {
+		logger.Debugf("Waiting for installation database migration setup; status %s", status)
+		return installation.State, nil
+	}
+
+	logger.Info("Installation database migration set up; replicating data")
+
+	return s.waitForDatabaseMigrationComplete(installation, instanceID, logger)
+}
+
+// waitForDatabaseMigrationComplete replicates the installation data to its
+// new backends and switches the installation to them once done. Cluster
+// installations pick up the new backends when the installation wakes up.
+func (s *InstallationSupervisor) waitForDatabaseMigrationComplete(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	migration, err := s.getDatabaseMigration(installation)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation database migration")
+		return model.InstallationStateDBMigrationInProgress, nil
+	}
+
+	status, err := migration.Replicate(logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to replicate installation data")
+		return model.InstallationStateDBMigrationRollbackInProgress, errors.Wrap(err, "failed to replicate installation data")
+	}
+	if status != model.DatabaseMigrationStatusReplicationComplete {
+		logger.Debugf("Waiting for installation data replication; status %s", status)
+		return model.InstallationStateDBMigrationInProgress, nil
+	}
+
+	status, err = migration.Teardown(logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to tear down installation database migration")
+		return model.InstallationStateDBMigrationInProgress, nil
+	}
+	if status != model.DatabaseMigrationStatusTeardownComplete {
+		logger.Debugf("Waiting for installation database migration teardown; status %s", status)
+		return model.InstallationStateDBMigrationInProgress, nil
+	}
+
+	source := *installation
+
+	// The installation passed in may have group configuration merged into it,
+	// so fetch a clean copy before switching its backends.
+	rawInstallation, err := s.store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation")
+		return model.InstallationStateDBMigrationInProgress, nil
+	}
+	target := databaseMigrationTarget(rawInstallation)
+	rawInstallation.Database = target.Database
+	rawInstallation.Filestore = target.Filestore
+	rawInstallation.DatabaseMigrationTarget = ""
+	rawInstallation.FilestoreMigrationTarget = ""
+	err = s.store.UpdateInstallation(rawInstallation)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to switch installation database and filestore")
+		return model.InstallationStateDBMigrationInProgress, nil
+	}
+
+	if source.Database != target.Database {
+		err = s.resourceUtil.GetDatabase(&source).Teardown(s.store, s.keepDatabaseData, logger)
+		if err != nil {
+			logger.WithError(err).Warnf("Failed to tear down previous %s database", source.Database)
+		}
+	}
+	if source.Filestore != target.Filestore {
+		err = s.resourceUtil.GetFilestore(&source).Teardown(s.keepFilestoreData, s.store, logger)
+		if err != nil {
+			logger.WithError(err).Warnf("Failed to tear down previous %s filestore", source.Filestore)
+		}
+	}
+
+	logger.Infof("Finished migrating installation to %s database and %s filestore", target.Database, target.Filestore)
+
+	return model.InstallationStateHibernating, nil
+}
+
+// rollbackDatabaseMigration removes what was created for a failed
+// installation database migration, leaving the installation on its original
+// database and filestore.
+func (s *InstallationSupervisor) rollbackDatabaseMigration(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	migration, err := s.getDatabaseMigration(installation)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation database migration")
+		return model.InstallationStateDBMigrationRollbackInProgress, nil
+	}
+
+	status, err := migration.Teardown(logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to tear down installation database migration")
+		return model.InstallationStateDBMigrationRollbackInProgress, nil
+	}
+	if status != model.DatabaseMigrationStatusTeardownComplete {
+		logger.Debugf("Waiting for installation database migration teardown; status %s", status)
+		return model.InstallationStateDBMigrationRollbackInProgress, nil
+	}
+
+	target := databaseMigrationTarget(installation)
+	if target.Database != installation.Database {
+		err = s.resourceUtil.GetDatabase(target).Teardown(s.store, false, logger)
+		if err != nil {
+			logger.WithError(err).Errorf("Failed to tear down target %s database", target.Database)
+			return model.InstallationStateDBMigrationRollbackInProgress, nil
+		}
+	}
+	if target.Filestore != installation.Filestore {
+		err = s.resourceUtil.GetFilestore(target).Teardown(false, s.store, logger)
+		if err != nil {
+			logger.WithError(err).Errorf("Failed to tear down target %s filestore", target.Filestore)
+			return model.InstallationStateDBMigrationRollbackInProgress, nil
+		}
+	}
+
+	// The installation passed in may have group configuration merged into it,
+	// so fetch a clean copy before clearing the migration targets.
+	rawInstallation, err := s.store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation")
+		return model.InstallationStateDBMigrationRollbackInProgress, nil
+	}
+	rawInstallation.DatabaseMigrationTarget = ""
+	rawInstallation.FilestoreMigrationTarget = ""
+	err = s.store.UpdateInstallation(rawInstallation)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to clear installation database migration targets")
+		return model.InstallationStateDBMigrationRollbackInProgress, nil
+	}
+
+	logger.Info("Rolled back installation database migration")
+
+	return model.InstallationStateDBMigrationFailed, nil
+}
+
+// getDatabaseMigration returns the migration of the installation data from
+// its current backends to the ones it is being migrated to.
+func (s *InstallationSupervisor) getDatabaseMigration(installation *model.Installation) (model.CIMigrationDatabase, error) {
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:        model.AllPerPage,
+		InstallationID: installation.ID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find cluster installations")
+	}
+	if len(clusterInstallations) == 0 {
+		return nil, errors.New("no cluster installations found")
+	}
+	clusterInstallation := clusterInstallations[0]
+
+	cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query cluster %s", clusterInstallation.ClusterID)
+	}
+	if cluster == nil {
+		return nil, errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
+	}
+
+	return s.provisioner.NewCIMigrationDatabase(cluster, clusterInstallation, installation, databaseMigrationTarget(installation)), nil
+}
+
+// databaseMigrationTarget returns a copy of the installation using the
+// database and filestore it is being migrated to.
+func databaseMigrationTarget(installation *model.Installation) *model.Installation {
+	target := *installation
+	if installation.DatabaseMigrationTarget != "" {
+		target.Database = installation.DatabaseMigrationTarget
+	}
+	if installation.FilestoreMigrationTarget != "" {
+		target.Filestore = installation.FilestoreMigrationTarget
+	}
+
+	return &target
+}
+
+func (s *InstallationSupervisor) migrateInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	if len(installation.MigrationTargetClusterID) == 0 {
+		return s.scheduleMigration(installation, instanceID, logger)
+	}
+
+	targetCluster, err := s.store.GetCluster(installation.MigrationTargetClusterID)
+	if err != nil {
+		logger.WithError(err).Warnf("Failed to query target cluster %s", installation.MigrationTargetClusterID)
+		return installation.State, nil
+	}
+	if targetCluster == nil || targetCluster.DeleteAt != 0 {
+		logger.Errorf("Failed to find target cluster %s", installation.MigrationTargetClusterID)
+		return s.failMigration(installation, instanceID, errors.Errorf("failed to find target cluster %s", installation.MigrationTargetClusterID), logger)
+	}
+
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:        model.AllPerPage,
+		InstallationID: installation.ID,
+		ClusterID:      targetCluster.ID,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to find cluster installations")
+		return installation.State, nil
+	}
+
+	if len(clusterInstallations) == 0 {
+		clusterInstallation := s.createClusterInstallation(targetCluster, installation, instanceID, logger)
+		if clusterInstallation == nil {
+			logger.Warnf("Unable to schedule installation on target cluster %s", targetCluster.ID)
+			return installation.State, nil
+		}
+	}
+
+	return s.waitForMigrationStable(installation, instanceID, logger)
+}
+
+// scheduleMigration picks a target cluster for an installation migration
+// which was requested without one, such as when draining a cluster.
+func (s *InstallationSupervisor) scheduleMigration(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:        model.AllPerPage,
+		InstallationID: installation.ID,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to find cluster installations")
+		return installation.State, nil
+	}
+	sourceClusterIDs := make(map[string]bool)
+	for _, clusterInstallation := range clusterInstallations {
+		sourceClusterIDs[clusterInstallation.ClusterID] = true
+	}
+
+	clusters, err := s.store.GetClusters(&model.ClusterFilter{
+		PerPage:        model.AllPerPage,
+		IncludeDeleted: false,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to query clusters")
+		return installation.State, nil
+	}
+
+	var targetClusters []*model.Cluster
+	for _, cluster := range clusters {
+		if !sourceClusterIDs[cluster.ID] {
+			targetClusters = append(targetClusters, cluster)
+		}
+	}
+
+	candidates, err := s.getPlacementClusters(targetClusters)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to gather cluster placement data")
+		return installation.State, nil
+	}
+	candidates, err = s.orderPlacementClusters(installation, candidates, logger)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to order placement clusters")
+		return installation.State, nil
+	}
+
+	for _, candidate := range candidates {
+		clusterInstallation := s.createClusterInstallation(candidate.Cluster, installation, instanceID, logger)
+		if clusterInstallation == nil {
+			continue
+		}
+
+		// The installation passed in may have group configuration merged
+		// into it, so fetch a clean copy before recording the target.
+		rawInstallation, err := s.store.GetInstallation(installation.ID, false, false)
+		if err != nil {
+			logger.WithError(err).Error("Failed to get installation")
+			return installation.State, nil
+		}
+		rawInstallation.MigrationTargetClusterID = candidate.Cluster.ID
+		err = s.store.UpdateInstallation(rawInstallation)
+		if err != nil {
+			logger.WithError(err).Error("Failed to record installation migration target")
+			return installation.State, nil
+		}
+		installation.MigrationTargetClusterID = candidate.Cluster.ID
+
+		logger.Infof("Scheduled installation migration to cluster %s", candidate.Cluster.ID)
+
+		return s.waitForMigrationStable(installation, instanceID, logger)
+	}
+
+	logger.Warn("No compatible clusters available for installation migration")
+
+	return installation.State, nil
+}
+
+func (s *InstallationSupervisor) waitForMigrationStable(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:        model.AllPerPage,
+		InstallationID: installation.ID,
+		ClusterID:      installation.MigrationTargetClusterID,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to find cluster installations")
+		return model.InstallationStateMigrationInProgress, nil
+	}
+
+	for _, clusterInstallation := range clusterInstallations {
+		switch clusterInstallation.State {
+		case model.ClusterInstallationStateStable:
+		case model.ClusterInstallationStateCreationFailed:
+			logger.Errorf("Cluster installation %s on target cluster failed to be created", clusterInstallation.ID)
+			return s.failMigration(installation, instanceID, errors.Errorf("cluster installation %s on target cluster failed to be created", clusterInstallation.ID), logger)
+		case model.ClusterInstallationStateDeletionFailed:
+			logger.Errorf("Cluster installation %s left on target cluster by a failed migration could not be deleted", clusterInstallation.ID)
+			return model.InstallationStateMigrationFailed, errors.Errorf("cluster installation %s left on target cluster by a failed migration could not be deleted", clusterInstallation.ID)
+		default:
+			// This includes cluster installations left by a failed migration
+			// which are being deleted: a new one is created once they are gone.
+			return model.InstallationStateMigrationInProgress, nil
+		}
+	}
+	if len(clusterInstallations) == 0 {
+		logger.Warn("Found no cluster installations on target cluster")
+		return model.InstallationStateMigrationRequested, nil
+	}
+
+	logger.Info("Cluster installations on target cluster are now stable")
+
+	return s.configureMigrationDNS(installation, instanceID, logger)
+}
+
+func (s *InstallationSupervisor) configureMigrationDNS(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	targetCluster, err := s.store.GetCluster(installation.MigrationTargetClusterID)
+	if err != nil {
+		logger.WithError(err).Warnf("Failed to query target cluster %s", installation.MigrationTargetClusterID)
+		return model.InstallationStateMigrationDNS, nil
+	}
+	if targetCluster == nil {
+		logger.Errorf("Failed to find target cluster %s", installation.MigrationTargetClusterID)
+		return s.failMigration(installation, instanceID, errors.Errorf("failed to find target cluster %s", installation.MigrationTargetClusterID), logger)
+	}
+
+	endpoint, err := s.provisioner.GetPublicLoadBalancerEndpoint(targetCluster, "nginx")
+	if err != nil {
+		logger.WithError(err).Error("Couldn't get the load balancer endpoint (nginx) for target cluster")
+		return model.InstallationStateMigrationDNS, nil
+	}
+
+	err = s.aws.CreatePublicCNAME(installation.DNS, []string{endpoint}, logger)
+	if err != nil {
+		logger.WithError(err).Error("Failed to update DNS CNAME record")
+		return model.InstallationStateMigrationDNS, nil
+	}
+
+	if s.aws.IsProvisionedPrivateCNAME(installation.DNS, logger) {
+		endpoint, err = s.provisioner.GetPrivateLoadBalancerEndpoint(targetCluster, "nginx")
+		if err != nil {
+			logger.WithError(err).Error("Couldn't get the private load balancer endpoint (nginx) for target cluster")
+			return model.InstallationStateMigrationDNS, nil
+		}
+
+		err = s.aws.CreatePrivateCNAME(installation.DNS, []string{endpoint}, logger)
+		if err != nil {
+			logger.WithError(err).Error("Failed to update private DNS CNAME record")
+			return model.InstallationStateMigrationDNS, nil
+		}
+	}
+
+	logger.Infof("Successfully switched DNS %s to target cluster %s", installation.DNS, targetCluster.ID)
+
+	return s.cleanupMigrationSource(installation, instanceID, logger)
+}
+
+// failMigration requests the deletion of the cluster installations created on
+// the target cluster of a migration which failed with the given error before
+// DNS was switched to them, so that the migration can be retried to the same
+// or another cluster.
+func (s *InstallationSupervisor) failMigration(installation *model.Installation, instanceID string, migrationErr error, logger log.FieldLogger) (string, error) {
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:        model.AllPerPage,
+		InstallationID: installation.ID,
+		ClusterID:      installation.MigrationTargetClusterID,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to find cluster installations on target cluster")
+		return installation.State, nil
+	}
+	if len(clusterInstallations) == 0 {
+		return model.InstallationStateMigrationFailed, migrationErr
+	}
+
+	var clusterInstallationIDs []string
+	for _, clusterInstallation := range clusterInstallations {
+		clusterInstallationIDs = append(clusterInstallationIDs, clusterInstallation.ID)
+	}
+
+	clusterInstallationLocks := newClusterInstallationLocks(clusterInstallationIDs, instanceID, s.store, logger)
+	if !clusterInstallationLocks.TryLock() {
+		logger.Debugf("Failed to lock %d cluster installations", len(clusterInstallationIDs))
+		return installation.State, nil
+	}
+	defer clusterInstallationLocks.Unlock()
+
+	// Fetch the same cluster installations again, now that we have the locks.
+	clusterInstallations, err = s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage: model.AllPerPage,
+		IDs:     clusterInstallationIDs,
+	})
+	if err != nil {
+		logger.WithError(err).Warnf("Failed to fetch %d cluster installations by ids", len(clusterInstallationIDs))
+		return installation.State, nil
+	}
+
+	for _, clusterInstallation := range clusterInstallations {
+		if clusterInstallation.State == model.ClusterInstallationStateDeletionRequested {
+			continue
+		}
+
+		clusterInstallation.State = model.ClusterInstallationStateDeletionRequested
+		err = s.store.UpdateClusterInstallation(clusterInstallation)
+		if err != nil {
+			logger.WithError(err).Warnf("Failed to mark cluster installation %s for deletion", clusterInstallation.ID)
+			return installation.State, nil
+		}
+	}
+
+	logger.Infof("Requested deletion of %d cluster installations on target cluster of failed migration", len(clusterInstallations))
+
+	return model.InstallationStateMigrationFailed, migrationErr
+}
+
+func (s *InstallationSupervisor) cleanupMigrationSource(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		PerPage:        model.AllPerPage,
+		InstallationID: installation.ID,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to find cluster installations")
+		return model.InstallationStateMigrationCleanup, nil
+	}
+
+	var clusterInstallationIDs []string
+	for _, clusterInstallation := range clusterInstallations {
+		if clusterInstallation.ClusterID != installation.MigrationTargetClusterID {
+			clusterInstallationIDs = append(clusterInstallationIDs, clusterInstallation.ID)
+		}
+	}
+
+	if len(clusterInstallationIDs) > 0 {
+		clusterInstallationLocks := newClusterInstallationLocks(clusterInstallationIDs, instanceID, s.store, logger)
+		if !clusterInstallationLocks.TryLock() {
+			logger.Debugf("Failed to lock %d cluster installations", len(clusterInstallationIDs))
+			return model.InstallationStateMigrationCleanup, nil
+		}
+		defer clusterInstallationLocks.Unlock()
+
+		// Fetch the same cluster installations again, now that we have the locks.
+		clusterInstallations, err = s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+			PerPage: model.AllPerPage,
+			IDs:     clusterInstallationIDs,
+		})
+		if err != nil {
+			logger.WithError(err).Warnf("Failed to fetch %d cluster installations by ids", len(clusterInstallationIDs))
+			return model.InstallationStateMigrationCleanup, nil
+		}
+
+		for _, clusterInstallation := range clusterInstallations {
+			switch clusterInstallation.State {
+			case model.ClusterInstallationStateDeletionRequested:
+				continue
+			case model.ClusterInstallationStateDeletionFailed:
+				logger.Errorf("Failed to delete cluster installation %s on source cluster", clusterInstallation.ID)
+				return model.InstallationStateMigrationFailed, errors.Errorf("failed to delete cluster installation %s on source cluster", clusterInstallation.ID)
+			}
+
+			clusterInstallation.State = model.ClusterInstallationStateDeletionRequested
+			err = s.store.UpdateClusterInstallation(clusterInstallation)
+			if err != nil {
+				logger.WithError(err).Warnf("Failed to mark cluster installation %s for deletion", clusterInstallation.ID)
+				return model.InstallationStateMigrationCleanup, nil
+			}
+		}
+
+		logger.Debugf("Waiting for %d cluster installations on source clusters to be deleted", len(clusterInstallations))
+
+		return model.InstallationStateMigrationCleanup, nil
+	}
+
+	// The installation passed in may have group configuration merged into it,
+	// so fetch a clean copy before clearing the migration target.
+	rawInstallation, err := s.store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to get installation")
+		return model.InstallationStateMigrationCleanup, nil
+	}
+	rawInstallation.MigrationTargetClusterID = ""
+	err = s.store.UpdateInstallation(rawInstallation)
+	if err != nil {
+		logger.WithError(err).Warn("Failed to clear installation migration target")
+		return model.InstallationStateMigrationCleanup, nil
+	}
+	installation.MigrationTargetClusterID = ""
+
+	logger.Info("Finished migrating installation")
+
+	return model.InstallationStateStable, nil
 }
 
-func (s *InstallationSupervisor) deleteInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) deleteInstallation(installation *model.Installation, instanceID string, logger log.FieldLogger) (string, error) {
 	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
 		PerPage:        model.AllPerPage,
 		InstallationID: installation.ID,
@@ -689,7 +1614,7 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 	})
 	if err != nil {
 		logger.WithError(err).Warn("Failed to find cluster installations")
-		return installation.State
+		return installation.State, nil
 	}
 
 	var clusterInstallationIDs []string
@@ -701,7 +1626,7 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 		clusterInstallationLocks := newClusterInstallationLocks(clusterInstallationIDs, instanceID, s.store, logger)
 		if !clusterInstallationLocks.TryLock() {
 			logger.Debugf("Failed to lock %d cluster installations", len(clusterInstallations))
-			return installation.State
+			return installation.State, nil
 		}
 		defer clusterInstallationLocks.Unlock()
 
@@ -713,7 +1638,7 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 		})
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to fetch %d cluster installations by ids", len(clusterInstallations))
-			return installation.State
+			return installation.State, nil
 		}
 
 		if len(clusterInstallations) != len(clusterInstallationIDs) {
@@ -753,14 +1678,14 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 
 		default:
 			logger.Errorf("Cannot delete installation with cluster installation in state %s", clusterInstallation.State)
-			return model.InstallationStateDeletionFailed
+			return model.InstallationStateDeletionFailed, errors.Errorf("cannot delete installation with cluster installation in state %s", clusterInstallation.State)
 		}
 
 		clusterInstallation.State = model.ClusterInstallationStateDeletionRequested
 		err = s.store.UpdateClusterInstallation(clusterInstallation)
 		if err != nil {
 			logger.WithError(err).Warnf("Failed to mark cluster installation %s for deletion", clusterInstallation.ID)
-			return installation.State
+			return installation.State, nil
 		}
 
 		deletingClusterInstallations++
@@ -776,49 +1701,110 @@ func (s *InstallationSupervisor) deleteInstallation(installation *model.Installa
 
 	if failedClusterInstallations > 0 {
 		logger.Infof("Found %d failed cluster installations", failedClusterInstallations)
-		return model.InstallationStateDeletionFailed
+		return model.InstallationStateDeletionFailed, errors.Errorf("found %d failed cluster installations", failedClusterInstallations)
 	}
 
 	if deletedClusterInstallations < len(clusterInstallations) {
-		return model.InstallationStateDeletionInProgress
+		return model.InstallationStateDeletionInProgress, nil
 	}
 
 	return s.finalDeletionCleanup(installation, logger)
 }
 
-func (s *InstallationSupervisor) finalDeletionCleanup(installation *model.Installation, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) finalDeletionCleanup(installation *model.Installation, logger log.FieldLogger) (string, error) {
 	err := s.aws.DeletePublicCNAME(installation.DNS, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete installation DNS")
-		return model.InstallationStateDeletionFinalCleanup
+		return model.InstallationStateDeletionFinalCleanup, nil
 	}
 
 	err = s.resourceUtil.GetDatabase(installation).Teardown(s.store, s.keepDatabaseData, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete database")
-		return model.InstallationStateDeletionFinalCleanup
+		return model.InstallationStateDeletionFinalCleanup, nil
 	}
 
 	err = s.resourceUtil.GetFilestore(installation).Teardown(s.keepFilestoreData, s.store, logger)
 	if err != nil {
 		logger.WithError(err).Error("Failed to delete filestore")
-		return model.InstallationStateDeletionFinalCleanup
+		return model.InstallationStateDeletionFinalCleanup, nil
 	}
 
 	err = s.store.DeleteInstallation(installation.ID)
 	if err != nil {
 		logger.WithError(err).Warn("Failed to mark installation as deleted")
-		return model.InstallationStateDeletionFinalCleanup
+		return model.InstallationStateDeletionFinalCleanup, nil
 	}
 
 	logger.Info("Finished deleting installation")
 
-	return model.InstallationStateDeleted
+	return model.InstallationStateDeleted, nil
 }
 
-func (s *InstallationSupervisor) finalCreationTasks(installation *model.Installation, logger log.FieldLogger) string {
+func (s *InstallationSupervisor) finalCreationTasks(installation *model.Installation, logger log.FieldLogger) (string, error) {
+	if len(installation.CloneScrubCommand) != 0 {
+		return s.scrubClonedInstallation(installation, logger)
+	}
+
 	logger.Info("Finished final creation tasks")
-	return model.InstallationStateStable
+	return model.InstallationStateStable, nil
+}
+
+// scrubClonedInstallation runs the scrub command of a cloned installation on
+// one of its cluster installations. The data being shared, running it once is
+// enough. The command is not retried, as it may not be safe to run twice.
+// Once it succeeded, the cluster installations are updated to enable the
+// notifications disabled until then.
+func (s *InstallationSupervisor) scrubClonedInstallation(installation *model.Installation, logger log.FieldLogger) (string, error) {
+	clusterInstallations, err := s.store.GetClusterInstallations(&model.ClusterInstallationFilter{
+		InstallationID: installation.ID,
+		PerPage:        model.AllPerPage,
+	})
+	if err != nil {
+		logger.WithError(err).Warn("Failed to find cluster installations")
+		return model.InstallationStateCreationFinalTasks, nil
+	}
+	if len(clusterInstallations) == 0 {
+		logger.Error("Found no cluster installations to scrub")
+		return model.InstallationStateCreationFailed, errors.New("found no cluster installations to scrub")
+	}
+	clusterInstallation := clusterInstallations[0]
+
+	cluster, err := s.store.GetCluster(clusterInstallation.ClusterID)
+	if err != nil {
+		logger.WithError(err).Warnf("Failed to query cluster %s", clusterInstallation.ClusterID)
+		return model.InstallationStateCreationFinalTasks, nil
+	}
+	if cluster == nil {
+		logger.Errorf("Failed to find cluster %s", clusterInstallation.ClusterID)
+		return model.InstallationStateCreationFailed, errors.Errorf("failed to find cluster %s", clusterInstallation.ClusterID)
+	}
+
+	output, err := s.provisioner.ExecClusterInstallationCLI(cluster, clusterInstallation, installation.CloneScrubCommand...)
+	if err != nil {
+		logger.WithError(err).Errorf("Failed to scrub cloned installation: %s", string(output))
+		return model.InstallationStateCreationFailed, errors.Wrapf(err, "failed to scrub cloned installation: %s", string(output))
+	}
+
+	logger.WithField("cluster-installation", clusterInstallation.ID).Infof("Cloned installation scrubbed: %s", string(output))
+
+	// The installation passed in may have group configuration merged into it,
+	// so fetch a clean copy before clearing the scrub command.
+	rawInstallation, err := s.store.GetInstallation(installation.ID, false, false)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get installation")
+		return model.InstallationStateCreationFailed, errors.Wrap(err, "failed to get installation")
+	}
+	rawInstallation.CloneScrubCommand = nil
+	err = s.store.UpdateInstallation(rawInstallation)
+	if err != nil {
+		logger.WithError(err).Error("Failed to clear installation scrub command")
+		return model.InstallationStateCreationFailed, errors.Wrap(err, "failed to clear installation scrub command")
+	}
+
+	logger.Info("Finished final creation tasks; enabling notifications")
+
+	return model.InstallationStateUpdateRequested, nil
 }
 
 // Helper funcs
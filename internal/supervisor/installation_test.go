@@ -18,6 +18,7 @@ import (
 	"github.com/mattermost/mattermost-cloud/k8s"
 	"github.com/mattermost/mattermost-cloud/model"
 	mmv1alpha1 "github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
@@ -25,6 +26,7 @@ import (
 type mockInstallationStore struct {
 	Installation                     *model.Installation
 	UnlockedInstallationsPendingWork []*model.Installation
+	Backup                           *model.Backup
 
 	UnlockChan              chan interface{}
 	UpdateInstallationCalls int
@@ -50,6 +52,14 @@ func (s *mockInstallationStore) UnlockCluster(clusterID string, lockerID string,
 	return true, nil
 }
 
+func (s *mockInstallationStore) CreateCluster(cluster *model.Cluster, annotations []*model.Annotation) error {
+	return nil
+}
+
+func (s *mockInstallationStore) GetAnnotationsForClusters(filter *model.ClusterFilter) (map[string][]*model.Annotation, error) {
+	return nil, nil
+}
+
 func (s *mockInstallationStore) GetInstallation(installationID string, includeGroupConfig, includeGroupConfigOverrides bool) (*model.Installation, error) {
 	return s.Installation, nil
 }
@@ -87,6 +97,14 @@ func (s *mockInstallationStore) DeleteInstallation(installationID string) error
 	return nil
 }
 
+func (s *mockInstallationStore) GetAnnotationsForInstallation(installationID string) ([]*model.Annotation, error) {
+	return nil, nil
+}
+
+func (s *mockInstallationStore) GetBackup(backupID string) (*model.Backup, error) {
+	return s.Backup, nil
+}
+
 func (s *mockInstallationStore) CreateClusterInstallation(clusterInstallation *model.ClusterInstallation) error {
 	return nil
 }
@@ -115,6 +133,18 @@ func (s *mockInstallationStore) GetWebhooks(filter *model.WebhookFilter) ([]*mod
 	return nil, nil
 }
 
+func (s *mockInstallationStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockInstallationStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
+func (s *mockInstallationStore) CreateEvent(event *model.Event) error {
+	return nil
+}
+
 func (s *mockInstallationStore) GetMultitenantDatabase(multitenantdatabaseID string) (*model.MultitenantDatabase, error) {
 	return nil, nil
 }
@@ -146,6 +176,9 @@ func (s *mockInstallationStore) GetMultitenantDatabaseForInstallationID(installa
 type mockInstallationProvisioner struct {
 	UseCustomClusterResources bool
 	CustomClusterResources    *k8s.ClusterResources
+	ExecutedCommands          [][]string
+	ExecError                 error
+	DatabaseMigration         *mockCIMigrationDatabase
 }
 
 func (p *mockInstallationProvisioner) CreateClusterInstallation(cluster *model.Cluster, installation *model.Installation, clusterInstallation *model.ClusterInstallation, awsClient aws.AWS) error {
@@ -193,6 +226,55 @@ func (p *mockInstallationProvisioner) GetPublicLoadBalancerEndpoint(cluster *mod
 	return "example.elb.us-east-1.amazonaws.com", nil
 }
 
+func (p *mockInstallationProvisioner) GetPrivateLoadBalancerEndpoint(cluster *model.Cluster, namespace string) (string, error) {
+	return "internal-example.elb.us-east-1.amazonaws.com", nil
+}
+
+func (p *mockInstallationProvisioner) ExecClusterInstallationCLI(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, args ...string) ([]byte, error) {
+	p.ExecutedCommands = append(p.ExecutedCommands, args)
+	return []byte("output"), p.ExecError
+}
+
+func (p *mockInstallationProvisioner) NewCIMigrationDatabase(cluster *model.Cluster, clusterInstallation *model.ClusterInstallation, source, target *model.Installation) model.CIMigrationDatabase {
+	if p.DatabaseMigration == nil {
+		p.DatabaseMigration = &mockCIMigrationDatabase{}
+	}
+	p.DatabaseMigration.target = target
+	return p.DatabaseMigration
+}
+
+type mockCIMigrationDatabase struct {
+	target            *model.Installation
+	setupErr          error
+	replicateErr      error
+	replicationStatus string
+	setUp             bool
+	tornDown          bool
+}
+
+func (m *mockCIMigrationDatabase) Setup(logger log.FieldLogger) (string, error) {
+	if m.setupErr != nil {
+		return "", m.setupErr
+	}
+	m.setUp = true
+	return model.DatabaseMigrationStatusSetupComplete, nil
+}
+
+func (m *mockCIMigrationDatabase) Replicate(logger log.FieldLogger) (string, error) {
+	if m.replicateErr != nil {
+		return "", m.replicateErr
+	}
+	if m.replicationStatus == "" {
+		return model.DatabaseMigrationStatusReplicationComplete, nil
+	}
+	return m.replicationStatus, nil
+}
+
+func (m *mockCIMigrationDatabase) Teardown(logger log.FieldLogger) (string, error) {
+	m.tornDown = true
+	return model.DatabaseMigrationStatusTeardownComplete, nil
+}
+
 // TODO(gsagula): this can be replaced with /internal/mocks/aws-tools/AWS.go so that inputs and other variants
 // can be tested.
 type mockAWS struct{}
@@ -296,7 +378,7 @@ func TestInstallationSupervisorDo(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		mockStore := &mockInstallationStore{}
 
-		supervisor := supervisor.NewInstallationSupervisor(mockStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(mockStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 		err := supervisor.Do()
 		require.NoError(t, err)
 
@@ -314,7 +396,7 @@ func TestInstallationSupervisorDo(t *testing.T) {
 		mockStore.Installation = mockStore.UnlockedInstallationsPendingWork[0]
 		mockStore.UnlockChan = make(chan interface{})
 
-		supervisor := supervisor.NewInstallationSupervisor(mockStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(mockStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 		err := supervisor.Do()
 		require.NoError(t, err)
 
@@ -370,7 +452,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("unexpected state", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -408,7 +490,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("state has changed since installation was selected to be worked on", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -441,7 +523,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation requested, cluster installations not yet created, no clusters", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		owner := model.NewID()
 		groupID := model.NewID()
@@ -466,7 +548,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation requested, cluster installations not yet created, cluster doesn't allow scheduling", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		cluster.AllowInstallations = false
@@ -496,7 +578,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation requested, cluster installations not yet created, no empty clusters", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -534,7 +616,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation requested, cluster installations reconciling", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -572,7 +654,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation requested, cluster installations reconciling", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -610,7 +692,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation DNS, cluster installations reconciling", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -648,7 +730,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation requested, cluster installations stable", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -686,7 +768,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("pre provisioning requested, cluster installations reconciling", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -724,7 +806,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation requested, cluster installations failed", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -762,7 +844,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation in progress, cluster installations reconciling", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -800,7 +882,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation in progress, cluster installations stable", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -838,7 +920,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation in progress, cluster installations failed", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -876,7 +958,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation final tasks, cluster installations stable", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -914,7 +996,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("no compatible clusters, cluster installations not yet created, no clusters", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		owner := model.NewID()
 		groupID := model.NewID()
@@ -939,7 +1021,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("no compatible clusters, cluster installations not yet created, no available clusters", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -977,7 +1059,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("no compatible clusters, cluster installations not yet created, available cluster", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1003,10 +1085,79 @@ func TestInstallationSupervisor(t *testing.T) {
 		expectClusterInstallations(t, sqlStore, installation, 1, model.ClusterInstallationStateCreationRequested)
 	})
 
+	t.Run("no compatible clusters, cluster installations not yet created, cluster created on demand", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		clusterTemplate := &model.CreateClusterRequest{Annotations: []string{"template"}}
+		clusterTemplate.SetDefaults()
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking, ClusterTemplate: clusterTemplate}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+		installation := &model.Installation{
+			OwnerID:                    model.NewID(),
+			Version:                    "version",
+			DNS:                        "dns.example.com",
+			Size:                       mmv1alpha1.Size100String,
+			Affinity:                   model.InstallationAffinityIsolated,
+			RequiredClusterAnnotations: []string{"regulated"},
+			State:                      model.InstallationStateCreationNoCompatibleClusters,
+		}
+
+		err := sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		supervisor.Supervise(installation)
+		expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationNoCompatibleClusters)
+		expectClusterInstallations(t, sqlStore, installation, 0, "")
+
+		clusters, err := sqlStore.GetClusterDTOs(&model.ClusterFilter{PerPage: model.AllPerPage})
+		require.NoError(t, err)
+		require.Len(t, clusters, 1)
+		cluster := clusters[0]
+		require.Equal(t, model.ClusterStateCreationRequested, cluster.State)
+		require.Equal(t, clusterTemplate.NodeMinCount, cluster.ProvisionerMetadataKops.ChangeRequest.NodeMinCount)
+		var annotationNames []string
+		for _, annotation := range cluster.Annotations {
+			annotationNames = append(annotationNames, annotation.Name)
+		}
+		require.ElementsMatch(t, []string{model.AnnotationOnDemandCluster, "template", "regulated"}, annotationNames)
+
+		t.Run("no additional cluster while creating", func(t *testing.T) {
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationNoCompatibleClusters)
+
+			clusters, err := sqlStore.GetClusters(&model.ClusterFilter{PerPage: model.AllPerPage})
+			require.NoError(t, err)
+			require.Len(t, clusters, 1)
+		})
+
+		t.Run("no additional cluster while refreshing metadata", func(t *testing.T) {
+			cluster.State = model.ClusterStateRefreshMetadata
+			err = sqlStore.UpdateCluster(cluster.Cluster)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationNoCompatibleClusters)
+
+			clusters, err := sqlStore.GetClusters(&model.ClusterFilter{PerPage: model.AllPerPage})
+			require.NoError(t, err)
+			require.Len(t, clusters, 1)
+		})
+
+		t.Run("scheduled once cluster is stable", func(t *testing.T) {
+			cluster.State = model.ClusterStateStable
+			err = sqlStore.UpdateCluster(cluster.Cluster)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationInProgress)
+			expectClusterInstallationsOnCluster(t, sqlStore, cluster.Cluster, 1)
+		})
+	})
+
 	t.Run("update requested, cluster installations stable", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1044,7 +1195,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("update in progress, cluster installations reconciling", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1082,7 +1233,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("update in progress, cluster installations stable", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1120,7 +1271,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("hibernation requested, cluster installations stable", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1158,7 +1309,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("hibernation in progress, cluster installations reconciling", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1196,7 +1347,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("hibernation in progress, cluster installations stable", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1231,10 +1382,532 @@ func TestInstallationSupervisor(t *testing.T) {
 		expectClusterInstallations(t, sqlStore, installation, 1, model.ClusterInstallationStateStable)
 	})
 
+	t.Run("restoration", func(t *testing.T) {
+		createRestoringInstallation := func(t *testing.T, sqlStore *store.SQLStore, backupState string) *model.Installation {
+			installation := &model.Installation{
+				OwnerID:   model.NewID(),
+				Version:   "version",
+				DNS:       model.NewID() + ".example.com",
+				Database:  model.InstallationDatabaseSingleTenantRDSMySQL,
+				Filestore: model.InstallationFilestoreAwsS3,
+				Size:      mmv1alpha1.Size100String,
+				Affinity:  model.InstallationAffinityIsolated,
+				State:     model.InstallationStateRestorationRequested,
+			}
+			err := sqlStore.CreateInstallation(installation, nil)
+			require.NoError(t, err)
+
+			backup := &model.Backup{
+				InstallationID: installation.ID,
+				State:          backupState,
+			}
+			err = sqlStore.CreateBackup(backup)
+			require.NoError(t, err)
+
+			installation.RestoreBackupID = backup.ID
+			err = sqlStore.UpdateInstallation(installation)
+			require.NoError(t, err)
+
+			return installation
+		}
+
+		t.Run("success", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			backupOperator := &mockBackupOperator{}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, backupOperator, logger)
+
+			installation := createRestoringInstallation(t, sqlStore, model.BackupStateSucceeded)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateRestorationInProgress)
+			require.True(t, backupOperator.filestoreRestored)
+
+			installation.State = model.InstallationStateRestorationInProgress
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateRestorationInProgress)
+
+			backupOperator.databaseRestored = true
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateHibernating)
+		})
+
+		t.Run("backup not restorable", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			backupOperator := &mockBackupOperator{}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, backupOperator, logger)
+
+			installation := createRestoringInstallation(t, sqlStore, model.BackupStateFailed)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateRestorationFailed)
+			require.False(t, backupOperator.filestoreRestored)
+		})
+
+		t.Run("database restoration failed", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			backupOperator := &mockBackupOperator{databaseRestoreErr: errors.New("snapshot not found")}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, backupOperator, logger)
+
+			installation := createRestoringInstallation(t, sqlStore, model.BackupStateSucceeded)
+			installation.State = model.InstallationStateRestorationInProgress
+			err := sqlStore.UpdateInstallation(installation)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateRestorationFailed)
+		})
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		createCloningInstallation := func(t *testing.T, sqlStore *store.SQLStore, state, backupState string) *model.Installation {
+			cluster := standardStableTestCluster()
+			err := sqlStore.CreateCluster(cluster, nil)
+			require.NoError(t, err)
+
+			backup := &model.Backup{
+				InstallationID: model.NewID(),
+				State:          backupState,
+			}
+			err = sqlStore.CreateBackup(backup)
+			require.NoError(t, err)
+
+			installation := &model.Installation{
+				OwnerID:           model.NewID(),
+				Version:           "version",
+				DNS:               model.NewID() + ".example.com",
+				Size:              mmv1alpha1.Size100String,
+				Affinity:          model.InstallationAffinityIsolated,
+				State:             state,
+				RestoreBackupID:   backup.ID,
+				CloneScrubCommand: []string{"sh", "-c", "./scrub.sh"},
+			}
+			err = sqlStore.CreateInstallation(installation, nil)
+			require.NoError(t, err)
+
+			clusterInstallation := &model.ClusterInstallation{
+				ClusterID:      cluster.ID,
+				InstallationID: installation.ID,
+				Namespace:      "namespace",
+				State:          model.ClusterInstallationStateStable,
+			}
+			err = sqlStore.CreateClusterInstallation(clusterInstallation)
+			require.NoError(t, err)
+
+			return installation
+		}
+
+		t.Run("pre provisioning from backup", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			provisioner := &mockInstallationProvisioner{}
+			backupOperator := &mockBackupOperator{}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, provisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, backupOperator, logger)
+
+			installation := createCloningInstallation(t, sqlStore, model.InstallationStateCreationPreProvisioning, model.BackupStateInProgress)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationPreProvisioning)
+
+			backup, err := sqlStore.GetBackup(installation.RestoreBackupID)
+			require.NoError(t, err)
+			backup.State = model.BackupStateSucceeded
+			err = sqlStore.UpdateBackup(backup)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationPreProvisioning)
+			require.False(t, backupOperator.filestoreRestored)
+
+			backupOperator.databaseRestored = true
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateUpdateRequested)
+			require.True(t, backupOperator.filestoreRestored)
+			require.Equal(t, [][]string{installation.CloneScrubCommand}, provisioner.ExecutedCommands)
+		})
+
+		t.Run("database restore failed", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			backupOperator := &mockBackupOperator{databaseRestoreErr: errors.New("restoring mysql-operator databases is not supported")}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, backupOperator, logger)
+
+			installation := createCloningInstallation(t, sqlStore, model.InstallationStateCreationPreProvisioning, model.BackupStateSucceeded)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationFailed)
+			require.False(t, backupOperator.filestoreRestored)
+		})
+
+		t.Run("backup failed", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+			installation := createCloningInstallation(t, sqlStore, model.InstallationStateCreationPreProvisioning, model.BackupStateFailed)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationFailed)
+		})
+
+		t.Run("scrub command failed", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			provisioner := &mockInstallationProvisioner{ExecError: errors.New("command terminated with exit code 1")}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, provisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+			installation := createCloningInstallation(t, sqlStore, model.InstallationStateCreationFinalTasks, model.BackupStateSucceeded)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationFailed)
+			require.Equal(t, [][]string{installation.CloneScrubCommand}, provisioner.ExecutedCommands)
+
+			installation, err := sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+			require.NotEmpty(t, installation.CloneScrubCommand)
+		})
+
+		t.Run("scrub command cleared once run", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			provisioner := &mockInstallationProvisioner{}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, provisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+			installation := createCloningInstallation(t, sqlStore, model.InstallationStateCreationFinalTasks, model.BackupStateSucceeded)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateUpdateRequested)
+			require.Equal(t, [][]string{installation.CloneScrubCommand}, provisioner.ExecutedCommands)
+
+			installation, err := sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+			require.Empty(t, installation.CloneScrubCommand)
+		})
+	})
+
+	t.Run("database migration", func(t *testing.T) {
+		createMigratingInstallation := func(t *testing.T, sqlStore *store.SQLStore, database, filestore string) *model.Installation {
+			cluster := standardStableTestCluster()
+			err := sqlStore.CreateCluster(cluster, nil)
+			require.NoError(t, err)
+
+			installation := &model.Installation{
+				OwnerID:                  model.NewID(),
+				Version:                  "version",
+				DNS:                      model.NewID() + ".example.com",
+				Database:                 database,
+				Filestore:                filestore,
+				Size:                     mmv1alpha1.Size100String,
+				Affinity:                 model.InstallationAffinityIsolated,
+				State:                    model.InstallationStateDBMigrationRequested,
+				DatabaseMigrationTarget:  model.InstallationDatabaseSingleTenantRDSMySQL,
+				FilestoreMigrationTarget: model.InstallationFilestoreAwsS3,
+			}
+			err = sqlStore.CreateInstallation(installation, nil)
+			require.NoError(t, err)
+
+			clusterInstallation := &model.ClusterInstallation{
+				ClusterID:      cluster.ID,
+				InstallationID: installation.ID,
+				Namespace:      "namespace",
+				State:          model.ClusterInstallationStateStable,
+			}
+			err = sqlStore.CreateClusterInstallation(clusterInstallation)
+			require.NoError(t, err)
+
+			return installation
+		}
+
+		t.Run("success", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			migration := &mockCIMigrationDatabase{replicationStatus: model.DatabaseMigrationStatusReplicationIP}
+			provisioner := &mockInstallationProvisioner{DatabaseMigration: migration}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, provisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+			installation := createMigratingInstallation(t, sqlStore, model.InstallationDatabaseMysqlOperator, model.InstallationFilestoreMinioOperator)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateDBMigrationInProgress)
+			require.True(t, migration.setUp)
+			require.False(t, migration.tornDown)
+			require.Equal(t, model.InstallationDatabaseSingleTenantRDSMySQL, migration.target.Database)
+			require.Equal(t, model.InstallationFilestoreAwsS3, migration.target.Filestore)
+
+			installation.State = model.InstallationStateDBMigrationInProgress
+			migration.replicationStatus = model.DatabaseMigrationStatusReplicationComplete
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateHibernating)
+			require.True(t, migration.tornDown)
+
+			installation, err := sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+			require.Equal(t, model.InstallationDatabaseSingleTenantRDSMySQL, installation.Database)
+			require.Equal(t, model.InstallationFilestoreAwsS3, installation.Filestore)
+			require.Empty(t, installation.DatabaseMigrationTarget)
+			require.Empty(t, installation.FilestoreMigrationTarget)
+		})
+
+		t.Run("setup failed", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			provisioner := &mockInstallationProvisioner{DatabaseMigration: &mockCIMigrationDatabase{setupErr: errors.New("failed to provision target database")}}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, provisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+			installation := createMigratingInstallation(t, sqlStore, model.InstallationDatabaseMysqlOperator, model.InstallationFilestoreMinioOperator)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateDBMigrationRollbackInProgress)
+		})
+
+		t.Run("replication failed", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			provisioner := &mockInstallationProvisioner{DatabaseMigration: &mockCIMigrationDatabase{replicateErr: errors.New("database migration job failed")}}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, provisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+			installation := createMigratingInstallation(t, sqlStore, model.InstallationDatabaseMysqlOperator, model.InstallationFilestoreMinioOperator)
+			installation.State = model.InstallationStateDBMigrationInProgress
+			err := sqlStore.UpdateInstallation(installation)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateDBMigrationRollbackInProgress)
+
+			installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+			require.Equal(t, model.InstallationDatabaseMysqlOperator, installation.Database)
+			require.Equal(t, model.InstallationFilestoreMinioOperator, installation.Filestore)
+		})
+
+		t.Run("unsupported source", func(t *testing.T) {
+			logger := testlib.MakeLogger(t)
+			sqlStore := store.MakeTestSQLStore(t, logger)
+			provisioner := &mockInstallationProvisioner{}
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, provisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+			installation := createMigratingInstallation(t, sqlStore, model.InstallationDatabaseMultiTenantRDSMySQL, model.InstallationFilestoreMultiTenantAwsS3)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateDBMigrationFailed)
+			require.Nil(t, provisioner.DatabaseMigration)
+		})
+	})
+
+	t.Run("migration", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+		sourceCluster := standardStableTestCluster()
+		err := sqlStore.CreateCluster(sourceCluster, nil)
+		require.NoError(t, err)
+
+		targetCluster := standardStableTestCluster()
+		err = sqlStore.CreateCluster(targetCluster, nil)
+		require.NoError(t, err)
+
+		installation := &model.Installation{
+			OwnerID:                  model.NewID(),
+			Version:                  "version",
+			DNS:                      "dns.example.com",
+			Size:                     mmv1alpha1.Size100String,
+			Affinity:                 model.InstallationAffinityIsolated,
+			Database:                 model.InstallationDatabaseMultiTenantRDSPostgres,
+			Filestore:                model.InstallationFilestoreMultiTenantAwsS3,
+			State:                    model.InstallationStateMigrationRequested,
+			MigrationTargetClusterID: targetCluster.ID,
+		}
+		err = sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		sourceClusterInstallation := &model.ClusterInstallation{
+			ClusterID:      sourceCluster.ID,
+			InstallationID: installation.ID,
+			Namespace:      installation.ID,
+			State:          model.ClusterInstallationStateStable,
+		}
+		err = sqlStore.CreateClusterInstallation(sourceClusterInstallation)
+		require.NoError(t, err)
+
+		t.Run("cluster installation created on target cluster", func(t *testing.T) {
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateMigrationInProgress)
+			expectClusterInstallationsOnCluster(t, sqlStore, targetCluster, 1)
+			expectClusterInstallationsOnCluster(t, sqlStore, sourceCluster, 1)
+		})
+
+		t.Run("target cluster installation reconciling", func(t *testing.T) {
+			installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateMigrationInProgress)
+		})
+
+		t.Run("target cluster installation stable, source cleanup requested", func(t *testing.T) {
+			targetClusterInstallations, err := sqlStore.GetClusterInstallations(&model.ClusterInstallationFilter{
+				PerPage:   model.AllPerPage,
+				ClusterID: targetCluster.ID,
+			})
+			require.NoError(t, err)
+			require.Len(t, targetClusterInstallations, 1)
+			targetClusterInstallations[0].State = model.ClusterInstallationStateStable
+			err = sqlStore.UpdateClusterInstallation(targetClusterInstallations[0])
+			require.NoError(t, err)
+
+			installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateMigrationCleanup)
+
+			sourceClusterInstallation, err = sqlStore.GetClusterInstallation(sourceClusterInstallation.ID)
+			require.NoError(t, err)
+			require.Equal(t, model.ClusterInstallationStateDeletionRequested, sourceClusterInstallation.State)
+		})
+
+		t.Run("source cluster installation deleted", func(t *testing.T) {
+			sourceClusterInstallation.State = model.ClusterInstallationStateDeleted
+			err = sqlStore.UpdateClusterInstallation(sourceClusterInstallation)
+			require.NoError(t, err)
+			err = sqlStore.DeleteClusterInstallation(sourceClusterInstallation.ID)
+			require.NoError(t, err)
+
+			installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateStable)
+			expectClusterInstallations(t, sqlStore, installation, 1, model.ClusterInstallationStateStable)
+			expectClusterInstallationsOnCluster(t, sqlStore, targetCluster, 1)
+
+			installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+			require.Empty(t, installation.MigrationTargetClusterID)
+		})
+	})
+
+	t.Run("migration requested without target cluster", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+		sourceCluster := standardStableTestCluster()
+		sourceCluster.AllowInstallations = false
+		err := sqlStore.CreateCluster(sourceCluster, nil)
+		require.NoError(t, err)
+
+		targetCluster := standardStableTestCluster()
+		err = sqlStore.CreateCluster(targetCluster, nil)
+		require.NoError(t, err)
+
+		installation := &model.Installation{
+			OwnerID:   model.NewID(),
+			Version:   "version",
+			DNS:       "dns.example.com",
+			Size:      mmv1alpha1.Size100String,
+			Affinity:  model.InstallationAffinityMultiTenant,
+			Database:  model.InstallationDatabaseMultiTenantRDSPostgres,
+			Filestore: model.InstallationFilestoreMultiTenantAwsS3,
+			State:     model.InstallationStateMigrationRequested,
+		}
+		err = sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+			ClusterID:      sourceCluster.ID,
+			InstallationID: installation.ID,
+			Namespace:      installation.ID,
+			State:          model.ClusterInstallationStateStable,
+		})
+		require.NoError(t, err)
+
+		supervisor.Supervise(installation)
+		expectInstallationState(t, sqlStore, installation, model.InstallationStateMigrationInProgress)
+		expectClusterInstallationsOnCluster(t, sqlStore, targetCluster, 1)
+
+		installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+		require.NoError(t, err)
+		require.Equal(t, targetCluster.ID, installation.MigrationTargetClusterID)
+	})
+
+	t.Run("migration in progress, target cluster installation failed", func(t *testing.T) {
+		logger := testlib.MakeLogger(t)
+		sqlStore := store.MakeTestSQLStore(t, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+		targetCluster := standardStableTestCluster()
+		err := sqlStore.CreateCluster(targetCluster, nil)
+		require.NoError(t, err)
+
+		installation := &model.Installation{
+			OwnerID:                  model.NewID(),
+			Version:                  "version",
+			DNS:                      "dns.example.com",
+			Size:                     mmv1alpha1.Size100String,
+			Affinity:                 model.InstallationAffinityIsolated,
+			State:                    model.InstallationStateMigrationInProgress,
+			MigrationTargetClusterID: targetCluster.ID,
+		}
+		err = sqlStore.CreateInstallation(installation, nil)
+		require.NoError(t, err)
+
+		clusterInstallation := &model.ClusterInstallation{
+			ClusterID:      targetCluster.ID,
+			InstallationID: installation.ID,
+			Namespace:      installation.ID,
+			State:          model.ClusterInstallationStateCreationFailed,
+		}
+		err = sqlStore.CreateClusterInstallation(clusterInstallation)
+		require.NoError(t, err)
+
+		supervisor.Supervise(installation)
+		expectInstallationState(t, sqlStore, installation, model.InstallationStateMigrationFailed)
+
+		clusterInstallation, err = sqlStore.GetClusterInstallation(clusterInstallation.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.ClusterInstallationStateDeletionRequested, clusterInstallation.State)
+
+		t.Run("retried once target cluster installation deleted", func(t *testing.T) {
+			installation.State = model.InstallationStateMigrationRequested
+			err = sqlStore.UpdateInstallation(installation)
+			require.NoError(t, err)
+
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateMigrationInProgress)
+
+			err = sqlStore.DeleteClusterInstallation(clusterInstallation.ID)
+			require.NoError(t, err)
+
+			installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateMigrationRequested)
+
+			installation, err = sqlStore.GetInstallation(installation.ID, false, false)
+			require.NoError(t, err)
+			supervisor.Supervise(installation)
+			expectInstallationState(t, sqlStore, installation, model.InstallationStateMigrationInProgress)
+
+			clusterInstallations, err := sqlStore.GetClusterInstallations(&model.ClusterInstallationFilter{
+				PerPage:        model.AllPerPage,
+				InstallationID: installation.ID,
+				ClusterID:      targetCluster.ID,
+			})
+			require.NoError(t, err)
+			require.Len(t, clusterInstallations, 1)
+			require.NotEqual(t, clusterInstallation.ID, clusterInstallations[0].ID)
+			require.Equal(t, model.ClusterInstallationStateCreationRequested, clusterInstallations[0].State)
+		})
+	})
+
 	t.Run("deletion requested, cluster installations stable", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1272,7 +1945,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("deletion requested, cluster installations deleting", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1310,7 +1983,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("deletion in progress, cluster installations failed", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1348,7 +2021,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("deletion requested, cluster installations failed, so retry", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1386,7 +2059,7 @@ func TestInstallationSupervisor(t *testing.T) {
 	t.Run("creation requested, cluster installations deleted", func(t *testing.T) {
 		logger := testlib.MakeLogger(t)
 		sqlStore := store.MakeTestSQLStore(t, logger)
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
@@ -1425,7 +2098,7 @@ func TestInstallationSupervisor(t *testing.T) {
 		t.Run("creation requested, cluster installations not yet created, available cluster", func(t *testing.T) {
 			logger := testlib.MakeLogger(t)
 			sqlStore := store.MakeTestSQLStore(t, logger)
-			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 			cluster := standardStableTestCluster()
 			err := sqlStore.CreateCluster(cluster, nil)
@@ -1455,7 +2128,7 @@ func TestInstallationSupervisor(t *testing.T) {
 		t.Run("creation requested, cluster installations not yet created, 3 installations, available cluster", func(t *testing.T) {
 			logger := testlib.MakeLogger(t)
 			sqlStore := store.MakeTestSQLStore(t, logger)
-			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 			cluster := standardStableTestCluster()
 			err := sqlStore.CreateCluster(cluster, nil)
@@ -1486,10 +2159,143 @@ func TestInstallationSupervisor(t *testing.T) {
 			}
 		})
 
+		t.Run("creation requested, cluster installations not yet created, placement strategies", func(t *testing.T) {
+			for _, tc := range []struct {
+				serverStrategy       string
+				installationStrategy string
+				expectLoadedCluster  bool
+			}{
+				{"", "", false},
+				{model.PlacementStrategyBinPacking, "", true},
+				{model.PlacementStrategySpread, "", false},
+				{model.PlacementStrategyBinPacking, model.PlacementStrategySpread, false},
+				{model.PlacementStrategySpread, model.PlacementStrategyBinPacking, true},
+			} {
+				t.Run(fmt.Sprintf("server %s, installation %s", tc.serverStrategy, tc.installationStrategy), func(t *testing.T) {
+					logger := testlib.MakeLogger(t)
+					sqlStore := store.MakeTestSQLStore(t, logger)
+					supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: tc.serverStrategy}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+					loadedCluster := standardStableTestCluster()
+					err := sqlStore.CreateCluster(loadedCluster, nil)
+					require.NoError(t, err)
+
+					emptyCluster := standardStableTestCluster()
+					err = sqlStore.CreateCluster(emptyCluster, nil)
+					require.NoError(t, err)
+
+					existingInstallation := &model.Installation{
+						OwnerID:  model.NewID(),
+						Version:  "version",
+						DNS:      "existing.example.com",
+						Size:     mmv1alpha1.Size100String,
+						Affinity: model.InstallationAffinityMultiTenant,
+						State:    model.InstallationStateStable,
+					}
+					err = sqlStore.CreateInstallation(existingInstallation, nil)
+					require.NoError(t, err)
+
+					err = sqlStore.CreateClusterInstallation(&model.ClusterInstallation{
+						ClusterID:      loadedCluster.ID,
+						InstallationID: existingInstallation.ID,
+						Namespace:      "namespace",
+						State:          model.ClusterInstallationStateStable,
+					})
+					require.NoError(t, err)
+
+					installation := &model.Installation{
+						OwnerID:           model.NewID(),
+						Version:           "version",
+						DNS:               "dns.example.com",
+						Size:              mmv1alpha1.Size100String,
+						Affinity:          model.InstallationAffinityMultiTenant,
+						PlacementStrategy: tc.installationStrategy,
+						State:             model.InstallationStateCreationRequested,
+					}
+
+					err = sqlStore.CreateInstallation(installation, nil)
+					require.NoError(t, err)
+
+					supervisor.Supervise(installation)
+					expectInstallationState(t, sqlStore, installation, model.InstallationStateCreationInProgress)
+					expectClusterInstallations(t, sqlStore, installation, 1, model.ClusterInstallationStateCreationRequested)
+					if tc.expectLoadedCluster {
+						expectClusterInstallationsOnCluster(t, sqlStore, loadedCluster, 2)
+						expectClusterInstallationsOnCluster(t, sqlStore, emptyCluster, 0)
+					} else {
+						expectClusterInstallationsOnCluster(t, sqlStore, loadedCluster, 1)
+						expectClusterInstallationsOnCluster(t, sqlStore, emptyCluster, 1)
+					}
+				})
+			}
+		})
+
+		t.Run("creation requested, cluster installations not yet created, cluster annotations", func(t *testing.T) {
+			for _, tc := range []struct {
+				name                string
+				required            []string
+				preferred           []string
+				expectedState       string
+				expectCanaryCluster bool
+				expectFirstCluster  bool
+			}{
+				{"required annotation present", []string{"canary"}, nil, model.InstallationStateCreationInProgress, true, false},
+				{"required annotation missing", []string{"regulated"}, nil, model.InstallationStateCreationNoCompatibleClusters, false, false},
+				{"preferred annotation present", nil, []string{"canary"}, model.InstallationStateCreationInProgress, true, false},
+				{"preferred annotation missing", nil, []string{"regulated"}, model.InstallationStateCreationInProgress, false, true},
+			} {
+				t.Run(tc.name, func(t *testing.T) {
+					logger := testlib.MakeLogger(t)
+					sqlStore := store.MakeTestSQLStore(t, logger)
+					supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
+
+					defaultCluster := standardStableTestCluster()
+					err := sqlStore.CreateCluster(defaultCluster, nil)
+					require.NoError(t, err)
+
+					canaryCluster := standardStableTestCluster()
+					err = sqlStore.CreateCluster(canaryCluster, []*model.Annotation{{Name: "canary"}})
+					require.NoError(t, err)
+
+					installation := &model.Installation{
+						OwnerID:                     model.NewID(),
+						Version:                     "version",
+						DNS:                         "dns.example.com",
+						Size:                        mmv1alpha1.Size100String,
+						Affinity:                    model.InstallationAffinityMultiTenant,
+						RequiredClusterAnnotations:  tc.required,
+						PreferredClusterAnnotations: tc.preferred,
+						State:                       model.InstallationStateCreationRequested,
+					}
+
+					err = sqlStore.CreateInstallation(installation, nil)
+					require.NoError(t, err)
+
+					supervisor.Supervise(installation)
+					expectInstallationState(t, sqlStore, installation, tc.expectedState)
+					switch {
+					case tc.expectCanaryCluster:
+						expectClusterInstallationsOnCluster(t, sqlStore, canaryCluster, 1)
+						expectClusterInstallationsOnCluster(t, sqlStore, defaultCluster, 0)
+					case tc.expectFirstCluster:
+						// With no preferred cluster, the installation lands on the
+						// first cluster listed, which may be either of them.
+						clusters, err := sqlStore.GetClusters(&model.ClusterFilter{PerPage: model.AllPerPage})
+						require.NoError(t, err)
+						expectClusterInstallationsOnCluster(t, sqlStore, clusters[0], 1)
+						expectClusterInstallationsOnCluster(t, sqlStore, clusters[1], 0)
+					default:
+						expectClusterInstallationsOnCluster(t, sqlStore, canaryCluster, 0)
+						expectClusterInstallationsOnCluster(t, sqlStore, defaultCluster, 0)
+					}
+				})
+			}
+		})
+
 		t.Run("creation requested, cluster installations not yet created, 1 isolated and 1 multitenant, available cluster", func(t *testing.T) {
 			logger := testlib.MakeLogger(t)
 			sqlStore := store.MakeTestSQLStore(t, logger)
-			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, &mockInstallationProvisioner{}, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 			cluster := standardStableTestCluster()
 			err := sqlStore.CreateCluster(cluster, nil)
@@ -1548,7 +2354,7 @@ func TestInstallationSupervisor(t *testing.T) {
 					MilliUsedMemory:  100,
 				},
 			}
-			supervisor := supervisor.NewInstallationSupervisor(sqlStore, mockInstallationProvisioner, &mockAWS{}, "instanceID", 80, 0, false, false, &utils.ResourceUtil{}, logger)
+			supervisor := supervisor.NewInstallationSupervisor(sqlStore, mockInstallationProvisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 			cluster := standardStableTestCluster()
 			err := sqlStore.CreateCluster(cluster, nil)
@@ -1588,7 +2394,7 @@ func TestInstallationSupervisor(t *testing.T) {
 				MilliUsedMemory:  100,
 			},
 		}
-		supervisor := supervisor.NewInstallationSupervisor(sqlStore, mockInstallationProvisioner, &mockAWS{}, "instanceID", 80, 2, false, false, &utils.ResourceUtil{}, logger)
+		supervisor := supervisor.NewInstallationSupervisor(sqlStore, mockInstallationProvisioner, &mockAWS{}, "instanceID", supervisor.InstallationSchedulingConfig{ClusterResourceThreshold: 80, ClusterResourceThresholdScaleValue: 2, PlacementStrategy: model.PlacementStrategyBinPacking}, false, false, &utils.ResourceUtil{}, &mockBackupOperator{}, logger)
 
 		cluster := standardStableTestCluster()
 		err := sqlStore.CreateCluster(cluster, nil)
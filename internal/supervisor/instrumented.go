@@ -0,0 +1,56 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
+)
+
+// The names of the supervisors, labelling their metrics.
+const (
+	ClusterSupervisorName             = "cluster"
+	GroupSupervisorName               = "group"
+	InstallationSupervisorName        = "installation"
+	ClusterInstallationSupervisorName = "cluster_installation"
+	WebhookDeliverySupervisorName     = "webhook_delivery"
+	BulkOperationSupervisorName       = "bulk_operation"
+	HibernationScheduleSupervisorName = "hibernation_schedule"
+	IdleHibernationSupervisorName     = "idle_hibernation"
+	BackupSupervisorName              = "backup"
+)
+
+// recordSupervisorError counts a failure of the named supervisor.
+func recordSupervisorError(name string) {
+	metrics.SupervisorErrors.WithLabelValues(name).Inc()
+}
+
+// InstrumentedDoer records the duration and errors of the wrapped doer's
+// runs as metrics labelled with its name.
+type InstrumentedDoer struct {
+	Doer
+	name string
+}
+
+// NewInstrumentedDoer wraps the given doer to record metrics under the given name.
+func NewInstrumentedDoer(name string, doer Doer) *InstrumentedDoer {
+	return &InstrumentedDoer{
+		Doer: doer,
+		name: name,
+	}
+}
+
+// Do executes the wrapped doer, recording how long it took and whether it failed.
+func (d *InstrumentedDoer) Do() error {
+	start := time.Now()
+	err := d.Doer.Do()
+	metrics.SupervisorDoDuration.WithLabelValues(d.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		recordSupervisorError(d.name)
+	}
+
+	return err
+}
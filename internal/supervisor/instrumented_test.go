@@ -0,0 +1,38 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentedDoer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		d := &testDoer{calls: make(chan bool, 1)}
+		doer := supervisor.NewInstrumentedDoer("instrumented-success", d)
+
+		err := doer.Do()
+		require.NoError(t, err)
+		<-d.calls
+
+		require.Equal(t, float64(0), testutil.ToFloat64(metrics.SupervisorErrors.WithLabelValues("instrumented-success")))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		doer := supervisor.NewInstrumentedDoer("instrumented-failure", &failDoer{})
+
+		err := doer.Do()
+		require.EqualError(t, err, "failed")
+		err = doer.Do()
+		require.EqualError(t, err, "failed")
+
+		require.Equal(t, float64(2), testutil.ToFloat64(metrics.SupervisorErrors.WithLabelValues("instrumented-failure")))
+	})
+}
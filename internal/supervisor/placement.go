@@ -0,0 +1,151 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"sort"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+)
+
+// PlacementCluster is a cluster being considered for scheduling a new
+// installation along with the data placement strategies rely on.
+type PlacementCluster struct {
+	Cluster                  *model.Cluster
+	Annotations              []*model.Annotation
+	ClusterInstallationCount int
+}
+
+// PlacementStrategy determines the order in which clusters are considered
+// when scheduling a new installation. The installation supervisor attempts to
+// create a cluster installation on each cluster in turn until one succeeds.
+type PlacementStrategy interface {
+	// Order returns the candidate clusters sorted from most to least preferred.
+	Order(installation *model.Installation, installationAnnotations []*model.Annotation, candidates []*PlacementCluster) []*PlacementCluster
+}
+
+// NewPlacementStrategy returns the built-in placement strategy with the given
+// name.
+func NewPlacementStrategy(name string) (PlacementStrategy, error) {
+	switch name {
+	case model.PlacementStrategyBinPacking:
+		return &binPackingPlacementStrategy{}, nil
+	case model.PlacementStrategySpread:
+		return &spreadPlacementStrategy{}, nil
+	case model.PlacementStrategyAnnotationAffinity:
+		return &annotationAffinityPlacementStrategy{}, nil
+	}
+
+	return nil, errors.Errorf("unsupported placement strategy %s", name)
+}
+
+// binPackingPlacementStrategy prefers the clusters with the most cluster
+// installations so that existing clusters are filled up first.
+type binPackingPlacementStrategy struct{}
+
+// Order sorts the candidates from most to least loaded.
+func (p *binPackingPlacementStrategy) Order(installation *model.Installation, installationAnnotations []*model.Annotation, candidates []*PlacementCluster) []*PlacementCluster {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].ClusterInstallationCount > candidates[j].ClusterInstallationCount
+	})
+
+	return candidates
+}
+
+// spreadPlacementStrategy prefers the clusters with the fewest cluster
+// installations so that load is distributed evenly.
+type spreadPlacementStrategy struct{}
+
+// Order sorts the candidates from least to most loaded.
+func (p *spreadPlacementStrategy) Order(installation *model.Installation, installationAnnotations []*model.Annotation, candidates []*PlacementCluster) []*PlacementCluster {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].ClusterInstallationCount < candidates[j].ClusterInstallationCount
+	})
+
+	return candidates
+}
+
+// annotationAffinityPlacementStrategy prefers the clusters sharing the most
+// annotations with the installation. Clusters with the same number of
+// matching annotations are ordered from least to most loaded.
+type annotationAffinityPlacementStrategy struct{}
+
+// Order sorts the candidates by the number of matching annotations.
+func (p *annotationAffinityPlacementStrategy) Order(installation *model.Installation, installationAnnotations []*model.Annotation, candidates []*PlacementCluster) []*PlacementCluster {
+	wanted := make(map[string]bool, len(installationAnnotations))
+	for _, annotation := range installationAnnotations {
+		wanted[annotation.Name] = true
+	}
+
+	matches := make(map[*PlacementCluster]int, len(candidates))
+	for _, candidate := range candidates {
+		for _, annotation := range candidate.Annotations {
+			if wanted[annotation.Name] {
+				matches[candidate]++
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if matches[candidates[i]] != matches[candidates[j]] {
+			return matches[candidates[i]] > matches[candidates[j]]
+		}
+		return candidates[i].ClusterInstallationCount < candidates[j].ClusterInstallationCount
+	})
+
+	return candidates
+}
+
+// filterRequiredClusterAnnotations returns the candidates which have every
+// cluster annotation required by the installation.
+func filterRequiredClusterAnnotations(installation *model.Installation, candidates []*PlacementCluster) []*PlacementCluster {
+	if len(installation.RequiredClusterAnnotations) == 0 {
+		return candidates
+	}
+
+	var filtered []*PlacementCluster
+	for _, candidate := range candidates {
+		if countMatchingAnnotations(candidate.Annotations, installation.RequiredClusterAnnotations) == len(installation.RequiredClusterAnnotations) {
+			filtered = append(filtered, candidate)
+		}
+	}
+
+	return filtered
+}
+
+// sortPreferredClusterAnnotations moves the candidates with the most cluster
+// annotations preferred by the installation to the front. The existing order
+// is otherwise preserved.
+func sortPreferredClusterAnnotations(installation *model.Installation, candidates []*PlacementCluster) []*PlacementCluster {
+	if len(installation.PreferredClusterAnnotations) == 0 {
+		return candidates
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return countMatchingAnnotations(candidates[i].Annotations, installation.PreferredClusterAnnotations) >
+			countMatchingAnnotations(candidates[j].Annotations, installation.PreferredClusterAnnotations)
+	})
+
+	return candidates
+}
+
+// countMatchingAnnotations returns the number of the given names which are
+// present in the annotations.
+func countMatchingAnnotations(annotations []*model.Annotation, names []string) int {
+	present := make(map[string]bool, len(annotations))
+	for _, annotation := range annotations {
+		present[annotation.Name] = true
+	}
+
+	var count int
+	for _, name := range names {
+		if present[name] {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlacementStrategies(t *testing.T) {
+	makeCandidates := func() []*supervisor.PlacementCluster {
+		return []*supervisor.PlacementCluster{
+			{
+				Cluster:                  &model.Cluster{ID: "medium"},
+				ClusterInstallationCount: 5,
+			},
+			{
+				Cluster:                  &model.Cluster{ID: "empty"},
+				ClusterInstallationCount: 0,
+				Annotations:              []*model.Annotation{{Name: "canary"}},
+			},
+			{
+				Cluster:                  &model.Cluster{ID: "full"},
+				ClusterInstallationCount: 10,
+				Annotations:              []*model.Annotation{{Name: "canary"}, {Name: "regulated"}},
+			},
+		}
+	}
+
+	clusterIDs := func(candidates []*supervisor.PlacementCluster) []string {
+		var ids []string
+		for _, candidate := range candidates {
+			ids = append(ids, candidate.Cluster.ID)
+		}
+		return ids
+	}
+
+	installation := &model.Installation{ID: model.NewID()}
+
+	t.Run("unsupported", func(t *testing.T) {
+		strategy, err := supervisor.NewPlacementStrategy("unknown")
+		require.Error(t, err)
+		assert.Nil(t, strategy)
+	})
+
+	t.Run("bin-packing", func(t *testing.T) {
+		strategy, err := supervisor.NewPlacementStrategy(model.PlacementStrategyBinPacking)
+		require.NoError(t, err)
+
+		ordered := strategy.Order(installation, nil, makeCandidates())
+		assert.Equal(t, []string{"full", "medium", "empty"}, clusterIDs(ordered))
+	})
+
+	t.Run("spread", func(t *testing.T) {
+		strategy, err := supervisor.NewPlacementStrategy(model.PlacementStrategySpread)
+		require.NoError(t, err)
+
+		ordered := strategy.Order(installation, nil, makeCandidates())
+		assert.Equal(t, []string{"empty", "medium", "full"}, clusterIDs(ordered))
+	})
+
+	t.Run("annotation-affinity", func(t *testing.T) {
+		strategy, err := supervisor.NewPlacementStrategy(model.PlacementStrategyAnnotationAffinity)
+		require.NoError(t, err)
+
+		t.Run("no installation annotations", func(t *testing.T) {
+			ordered := strategy.Order(installation, nil, makeCandidates())
+			assert.Equal(t, []string{"empty", "medium", "full"}, clusterIDs(ordered))
+		})
+
+		t.Run("single matching annotation", func(t *testing.T) {
+			ordered := strategy.Order(installation, []*model.Annotation{{Name: "canary"}}, makeCandidates())
+			assert.Equal(t, []string{"empty", "full", "medium"}, clusterIDs(ordered))
+		})
+
+		t.Run("most matching annotations", func(t *testing.T) {
+			ordered := strategy.Order(installation, []*model.Annotation{{Name: "canary"}, {Name: "regulated"}}, makeCandidates())
+			assert.Equal(t, []string{"full", "empty", "medium"}, clusterIDs(ordered))
+		})
+	})
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startTransitionSpan starts the span of a transition of the given resource
+// out of the given state. Work done for the resource by the provisioner while
+// the span is open is recorded as its children.
+func startTransitionSpan(logger log.FieldLogger, name, resourceType, resourceID, state string) (trace.Span, *log.Entry) {
+	return tracing.StartResourceSpan(logger, resourceID, name,
+		attribute.String("resource.type", resourceType),
+		attribute.String("resource.id", resourceID),
+		attribute.String("state.old", state),
+	)
+}
+
+// endTransitionSpan records the outcome of a transition, including the error
+// that failed it, if any, and ends its span.
+func endTransitionSpan(span trace.Span, newState string, err error) {
+	span.SetAttributes(attribute.String("state.new", newState))
+	tracing.RecordError(span, err)
+	span.End()
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor
+
+import (
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/webhook"
+	"github.com/mattermost/mattermost-cloud/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// webhookDeliveryStore abstracts the database operations required to retry
+// webhook deliveries.
+type webhookDeliveryStore interface {
+	GetWebhook(webhookID string) (*model.Webhook, error)
+
+	GetWebhookDelivery(deliveryID string) (*model.WebhookDelivery, error)
+	GetUnlockedWebhookDeliveriesPendingWork() ([]*model.WebhookDelivery, error)
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
+	LockWebhookDelivery(deliveryID, lockerID string) (bool, error)
+	UnlockWebhookDelivery(deliveryID, lockerID string, force bool) (bool, error)
+	DeleteWebhookDeliveriesCreatedBefore(createAt int64) error
+}
+
+// DefaultWebhookDeliveryRetention is how long delivered and failed webhook
+// deliveries are kept before being deleted.
+const DefaultWebhookDeliveryRetention = 30 * 24 * time.Hour
+
+// webhookDeliveryCleanupInterval is how often expired webhook deliveries are
+// deleted.
+const webhookDeliveryCleanupInterval = time.Hour
+
+// WebhookDeliverySupervisor finds webhook deliveries which are due to be
+// retried and attempts them again.
+type WebhookDeliverySupervisor struct {
+	store       webhookDeliveryStore
+	instanceID  string
+	retention   time.Duration
+	lastCleanup time.Time
+	logger      log.FieldLogger
+}
+
+// NewWebhookDeliverySupervisor creates a new WebhookDeliverySupervisor.
+// Delivered and failed webhook deliveries older than retention are deleted.
+func NewWebhookDeliverySupervisor(store webhookDeliveryStore, instanceID string, retention time.Duration, logger log.FieldLogger) *WebhookDeliverySupervisor {
+	return &WebhookDeliverySupervisor{
+		store:      store,
+		instanceID: instanceID,
+		retention:  retention,
+		logger:     logger,
+	}
+}
+
+// Shutdown performs graceful shutdown tasks for the webhook delivery supervisor.
+func (s *WebhookDeliverySupervisor) Shutdown() {
+	s.logger.Debug("Shutting down webhook delivery supervisor")
+}
+
+// Do looks for webhook deliveries which are due and attempts them. Expired
+// webhook deliveries are deleted at most once per cleanup interval.
+func (s *WebhookDeliverySupervisor) Do() error {
+	s.deleteExpired()
+
+	deliveries, err := s.store.GetUnlockedWebhookDeliveriesPendingWork()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to query for webhook deliveries pending work")
+		recordSupervisorError(WebhookDeliverySupervisorName)
+		return nil
+	}
+
+	for _, delivery := range deliveries {
+		s.Supervise(delivery)
+	}
+
+	return nil
+}
+
+// deleteExpired removes the settled webhook deliveries older than the
+// retention period.
+func (s *WebhookDeliverySupervisor) deleteExpired() {
+	now := time.Now()
+	if now.Sub(s.lastCleanup) < webhookDeliveryCleanupInterval {
+		return
+	}
+	s.lastCleanup = now
+
+	expiredBefore := now.Add(-s.retention).UnixNano() / int64(time.Millisecond)
+	err := s.store.DeleteWebhookDeliveriesCreatedBefore(expiredBefore)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to delete expired webhook deliveries")
+	}
+}
+
+// Supervise attempts the given webhook delivery.
+func (s *WebhookDeliverySupervisor) Supervise(delivery *model.WebhookDelivery) {
+	logger := s.logger.WithFields(log.Fields{
+		"webhook":         delivery.WebhookID,
+		"webhookDelivery": delivery.ID,
+	})
+
+	lock := newWebhookDeliveryLock(delivery.ID, s.instanceID, s.store, logger)
+	if !lock.TryLock() {
+		return
+	}
+	defer lock.Unlock()
+
+	// Ensure the delivery wasn't attempted by another provisioning server
+	// since it was selected.
+	delivery, err := s.store.GetWebhookDelivery(delivery.ID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get refreshed webhook delivery")
+		return
+	}
+	if delivery.State != model.WebhookDeliveryStatePending {
+		logger.Debugf("Webhook delivery is now %s; skipping...", delivery.State)
+		return
+	}
+
+	hook, err := s.store.GetWebhook(delivery.WebhookID)
+	if err != nil {
+		logger.WithError(err).Error("Failed to get webhook")
+		return
+	}
+
+	if hook == nil || hook.IsDeleted() {
+		logger.Warn("Webhook no longer exists; marking delivery as failed")
+		delivery.State = model.WebhookDeliveryStateFailed
+		delivery.LastError = "webhook was deleted"
+	} else {
+		webhook.AttemptDelivery(hook, delivery, logger)
+	}
+	if delivery.State == model.WebhookDeliveryStateFailed {
+		recordSupervisorError(WebhookDeliverySupervisorName)
+	}
+
+	err = s.store.UpdateWebhookDelivery(delivery)
+	if err != nil {
+		logger.WithError(err).Error("Failed to record webhook delivery attempt")
+		return
+	}
+
+	logger.Debugf("Webhook delivery attempt %d finished in state %s", delivery.Attempts, delivery.State)
+}
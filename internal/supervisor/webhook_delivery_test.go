@@ -0,0 +1,147 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package supervisor_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/store"
+	"github.com/mattermost/mattermost-cloud/internal/supervisor"
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDeliverySupervisor(t *testing.T) {
+	var status int
+	var received int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	supervisor := supervisor.NewWebhookDeliverySupervisor(sqlStore, "instanceID", supervisor.DefaultWebhookDeliveryRetention, logger)
+
+	webhook := &model.Webhook{
+		OwnerID: model.NewID(),
+		URL:     ts.URL,
+	}
+	err := sqlStore.CreateWebhook(webhook)
+	require.NoError(t, err)
+
+	delivery := &model.WebhookDelivery{
+		WebhookID: webhook.ID,
+		Payload:   `{"id":"test"}`,
+		State:     model.WebhookDeliveryStatePending,
+	}
+	err = sqlStore.CreateWebhookDelivery(delivery)
+	require.NoError(t, err)
+
+	t.Run("failed attempt is rescheduled", func(t *testing.T) {
+		status = http.StatusBadGateway
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+		require.Equal(t, 1, received)
+
+		delivery, err = sqlStore.GetWebhookDelivery(delivery.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliveryStatePending, delivery.State)
+		require.Equal(t, 1, delivery.Attempts)
+		require.Equal(t, http.StatusBadGateway, delivery.ResponseCode)
+		require.Greater(t, delivery.NextAttemptAt, delivery.LastAttemptAt)
+
+		// Not due yet, so nothing is sent.
+		err = supervisor.Do()
+		require.NoError(t, err)
+		require.Equal(t, 1, received)
+	})
+
+	t.Run("successful attempt", func(t *testing.T) {
+		status = http.StatusOK
+
+		supervisor.Supervise(delivery)
+		require.Equal(t, 2, received)
+
+		delivery, err = sqlStore.GetWebhookDelivery(delivery.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliveryStateDelivered, delivery.State)
+		require.Equal(t, 2, delivery.Attempts)
+		require.Equal(t, http.StatusOK, delivery.ResponseCode)
+		require.Empty(t, delivery.LastError)
+	})
+
+	t.Run("deleted webhook", func(t *testing.T) {
+		err = sqlStore.DeleteWebhook(webhook.ID)
+		require.NoError(t, err)
+
+		delivery2 := &model.WebhookDelivery{
+			WebhookID: webhook.ID,
+			Payload:   `{"id":"test2"}`,
+			State:     model.WebhookDeliveryStatePending,
+		}
+		err = sqlStore.CreateWebhookDelivery(delivery2)
+		require.NoError(t, err)
+
+		err = supervisor.Do()
+		require.NoError(t, err)
+		require.Equal(t, 2, received)
+
+		delivery2, err = sqlStore.GetWebhookDelivery(delivery2.ID)
+		require.NoError(t, err)
+		require.Equal(t, model.WebhookDeliveryStateFailed, delivery2.State)
+	})
+}
+
+func TestWebhookDeliverySupervisorRetention(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+	sqlStore := store.MakeTestSQLStore(t, logger)
+	supervisor := supervisor.NewWebhookDeliverySupervisor(sqlStore, "instanceID", time.Millisecond, logger)
+
+	webhookID := model.NewID()
+	createDelivery := func(t *testing.T, state string) *model.WebhookDelivery {
+		delivery := &model.WebhookDelivery{
+			WebhookID: webhookID,
+			State:     state,
+		}
+		err := sqlStore.CreateWebhookDelivery(delivery)
+		require.NoError(t, err)
+
+		return delivery
+	}
+	getDeliveries := func(t *testing.T) []*model.WebhookDelivery {
+		deliveries, err := sqlStore.GetWebhookDeliveries(&model.WebhookDeliveryFilter{
+			WebhookID: webhookID,
+			PerPage:   model.AllPerPage,
+		})
+		require.NoError(t, err)
+
+		return deliveries
+	}
+
+	createDelivery(t, model.WebhookDeliveryStateDelivered)
+	createDelivery(t, model.WebhookDeliveryStateFailed)
+	time.Sleep(2 * time.Millisecond)
+
+	err := supervisor.Do()
+	require.NoError(t, err)
+	require.Empty(t, getDeliveries(t))
+
+	// Expired deliveries are only deleted once per cleanup interval.
+	delivered := createDelivery(t, model.WebhookDeliveryStateDelivered)
+	time.Sleep(2 * time.Millisecond)
+
+	err = supervisor.Do()
+	require.NoError(t, err)
+	deliveries := getDeliveries(t)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, delivered.ID, deliveries[0].ID)
+}
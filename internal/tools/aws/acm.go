@@ -6,6 +6,7 @@ package aws
 
 import (
 	"github.com/aws/aws-sdk-go/service/acm"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -17,7 +18,7 @@ func (a *Client) GetCertificateSummaryByTag(key, value string, logger log.FieldL
 
 	var next *string
 	for {
-		out, err := a.Service().acm.ListCertificates(&acm.ListCertificatesInput{
+		out, err := a.Service().acm.ListCertificatesWithContext(tracing.Context(logger), &acm.ListCertificatesInput{
 			NextToken: next,
 		})
 		if err != nil {
@@ -25,7 +26,7 @@ func (a *Client) GetCertificateSummaryByTag(key, value string, logger log.FieldL
 		}
 
 		for _, cert := range out.CertificateSummaryList {
-			list, err := a.Service().acm.ListTagsForCertificate(&acm.ListTagsForCertificateInput{CertificateArn: cert.CertificateArn})
+			list, err := a.Service().acm.ListTagsForCertificateWithContext(tracing.Context(logger), &acm.ListTagsForCertificateInput{CertificateArn: cert.CertificateArn})
 			if err != nil {
 				return nil, errors.Wrapf(err, "error listing tags for certificate %s", *cert.CertificateArn)
 			}
@@ -14,7 +14,7 @@ import (
 func (a *AWSTestSuite) TestGetCertificateSummaryByTag() {
 	gomock.InOrder(
 		a.Mocks.API.ACM.EXPECT().
-			ListCertificates(gomock.Any()).
+			ListCertificatesWithContext(gomock.Any(), gomock.Any()).
 			Return(&acm.ListCertificatesOutput{
 				CertificateSummaryList: []*acm.CertificateSummary{
 					{
@@ -29,12 +29,12 @@ func (a *AWSTestSuite) TestGetCertificateSummaryByTag() {
 			Times(1),
 
 		a.Mocks.API.ACM.EXPECT().
-			ListTagsForCertificate(gomock.Any()).
+			ListTagsForCertificateWithContext(gomock.Any(), gomock.Any()).
 			Return(&acm.ListTagsForCertificateOutput{}, nil).
 			Times(2),
 
 		a.Mocks.API.ACM.EXPECT().
-			ListCertificates(gomock.Any()).
+			ListCertificatesWithContext(gomock.Any(), gomock.Any()).
 			Return(&acm.ListCertificatesOutput{
 				CertificateSummaryList: []*acm.CertificateSummary{
 					{
@@ -45,7 +45,7 @@ func (a *AWSTestSuite) TestGetCertificateSummaryByTag() {
 			Times(1),
 
 		a.Mocks.API.ACM.EXPECT().
-			ListTagsForCertificate(gomock.Any()).
+			ListTagsForCertificateWithContext(gomock.Any(), gomock.Any()).
 			Return(&acm.ListTagsForCertificateOutput{
 				Tags: []*acm.Tag{{
 					Key:   aws.String("MattermostCloudInstallationCertificates"),
@@ -64,7 +64,7 @@ func (a *AWSTestSuite) TestGetCertificateSummaryByTag() {
 func (a *AWSTestSuite) TestGetCertificateSummaryByTagNotFound() {
 	gomock.InOrder(
 		a.Mocks.API.ACM.EXPECT().
-			ListCertificates(gomock.Any()).
+			ListCertificatesWithContext(gomock.Any(), gomock.Any()).
 			Return(&acm.ListCertificatesOutput{
 				CertificateSummaryList: []*acm.CertificateSummary{
 					{
@@ -78,7 +78,7 @@ func (a *AWSTestSuite) TestGetCertificateSummaryByTagNotFound() {
 			Times(1),
 
 		a.Mocks.API.ACM.EXPECT().
-			ListTagsForCertificate(gomock.Any()).
+			ListTagsForCertificateWithContext(gomock.Any(), gomock.Any()).
 			Return(&acm.ListTagsForCertificateOutput{
 				Tags: []*acm.Tag{{
 					Key:   aws.String("MattermostCloudInstallationCertificates"),
@@ -97,11 +97,11 @@ func (a *AWSTestSuite) TestGetCertificateSummaryByTagNotFound() {
 func (a *AWSTestSuite) TestGetCertificateSummaryByTagCertListError() {
 	gomock.InOrder(
 		a.Mocks.API.ACM.EXPECT().
-			ListCertificates(gomock.Any()).
+			ListCertificatesWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("list certificates error")).
 			Times(1),
 
-		a.Mocks.API.ACM.EXPECT().ListTagsForCertificate(gomock.Any()).Times(0),
+		a.Mocks.API.ACM.EXPECT().ListTagsForCertificateWithContext(gomock.Any(), gomock.Any()).Times(0),
 	)
 
 	summary, err := a.Mocks.AWS.GetCertificateSummaryByTag(DefaultInstallCertificatesTagKey, "not_found", a.Mocks.Log.Logger)
@@ -113,7 +113,7 @@ func (a *AWSTestSuite) TestGetCertificateSummaryByTagCertListError() {
 func (a *AWSTestSuite) TestGetCertificateSummaryByTagListTagsError() {
 	gomock.InOrder(
 		a.Mocks.API.ACM.EXPECT().
-			ListCertificates(gomock.Any()).
+			ListCertificatesWithContext(gomock.Any(), gomock.Any()).
 			Return(&acm.ListCertificatesOutput{
 				CertificateSummaryList: []*acm.CertificateSummary{
 					{
@@ -127,7 +127,7 @@ func (a *AWSTestSuite) TestGetCertificateSummaryByTagListTagsError() {
 			Times(1),
 
 		a.Mocks.API.ACM.EXPECT().
-			ListTagsForCertificate(gomock.Any()).
+			ListTagsForCertificateWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("list tags error")).
 			Times(1),
 	)
@@ -0,0 +1,619 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	rdsStatusAvailable = "available"
+	rdsStatusCreating  = "creating"
+	rdsStatusDeleting  = "deleting"
+
+	// s3MaxCopyObjectSize is the size of the largest object S3 copies in a
+	// single CopyObject call.
+	s3MaxCopyObjectSize = 5 << 30
+	// s3CopyPartSize is the size of the parts larger objects are copied in.
+	// S3 allows at most 10,000 parts, enough for objects of the 5TB maximum.
+	s3CopyPartSize = 1 << 30
+)
+
+// BackupOperator backs up and restores installations using RDS cluster
+// snapshots for their databases and S3 object copies for their filestores.
+type BackupOperator struct {
+	awsClient *Client
+}
+
+// NewBackupOperator returns a new BackupOperator.
+func NewBackupOperator(awsClient *Client) *BackupOperator {
+	return &BackupOperator{
+		awsClient: awsClient,
+	}
+}
+
+// StartDatabaseBackup starts taking a snapshot of the RDS cluster of the
+// installation and records its identifier in the backup.
+func (o *BackupOperator) StartDatabaseBackup(installation *model.Installation, backup *model.Backup, logger log.FieldLogger) error {
+	awsID := CloudID(installation.ID)
+	snapshotID := RDSBackupSnapshotID(installation.ID, backup.ID)
+
+	logger = logger.WithFields(log.Fields{
+		"db-cluster-name":  awsID,
+		"db-snapshot-name": snapshotID,
+	})
+
+	_, err := o.awsClient.Service().rds.CreateDBClusterSnapshotWithContext(tracing.Context(logger), &rds.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         aws.String(awsID),
+		DBClusterSnapshotIdentifier: aws.String(snapshotID),
+		Tags: []*rds.Tag{
+			{
+				Key:   aws.String(trimTagPrefix(DefaultInstallationBackupTagKey)),
+				Value: aws.String(backup.ID),
+			},
+		},
+	})
+	if err != nil && !IsErrorCode(err, rds.ErrCodeDBClusterSnapshotAlreadyExistsFault) {
+		return errors.Wrap(err, "failed to create a DB cluster snapshot")
+	}
+	backup.DatabaseSnapshotID = snapshotID
+
+	logger.Info("RDS database backup snapshot in progress")
+
+	return nil
+}
+
+// CheckDatabaseBackup returns whether the database snapshot of the backup is
+// complete and, once it is, its size in bytes.
+func (o *BackupOperator) CheckDatabaseBackup(backup *model.Backup, logger log.FieldLogger) (bool, int64, error) {
+	result, err := o.awsClient.Service().rds.DescribeDBClusterSnapshotsWithContext(tracing.Context(logger), &rds.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(backup.DatabaseSnapshotID),
+	})
+	if err != nil {
+		return false, 0, errors.Wrap(err, "failed to describe DB cluster snapshot")
+	}
+	if len(result.DBClusterSnapshots) != 1 {
+		return false, 0, errors.Errorf("expected 1 DB cluster snapshot, but got %d", len(result.DBClusterSnapshots))
+	}
+
+	snapshot := result.DBClusterSnapshots[0]
+	switch status := aws.StringValue(snapshot.Status); status {
+	case rdsStatusAvailable:
+		return true, aws.Int64Value(snapshot.AllocatedStorage) << 30, nil
+	case rdsStatusCreating:
+		logger.WithField("db-snapshot-name", backup.DatabaseSnapshotID).Debug("RDS database backup snapshot still in progress")
+		return false, 0, nil
+	default:
+		return false, 0, errors.Errorf("DB cluster snapshot is %s", status)
+	}
+}
+
+// BackupFilestore copies the objects of the installation filestore to the
+// backup bucket, records their location in the backup and returns their total
+// size in bytes.
+func (o *BackupOperator) BackupFilestore(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (int64, error) {
+	bucket, prefix, err := o.getFilestoreLocation(installation, store, logger)
+	if err != nil {
+		return 0, err
+	}
+
+	backupBucket, err := o.ensureBackupBucketCreated(logger)
+	if err != nil {
+		return 0, err
+	}
+	backupPrefix := fmt.Sprintf("%s/%s/", installation.ID, backup.ID)
+
+	size, err := o.copyObjects(bucket, prefix, backupBucket, backupPrefix, logger)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to copy filestore objects to backup bucket")
+	}
+	backup.FilestoreLocation = fmt.Sprintf("s3://%s/%s", backupBucket, backupPrefix)
+
+	logger.WithField("filestore-backup", backup.FilestoreLocation).Info("Filestore backed up")
+
+	return size, nil
+}
+
+// RestoreFilestore replaces the objects of the installation filestore with
+// those of the backup.
+//
+// The current objects are first copied to a pre-restore location in the
+// backup bucket, so that they are not lost if the restoration fails. A marker
+// object records that this copy is complete: a restoration retried after a
+// failure must not overwrite it with the partially restored objects. The
+// marker is removed once the restoration succeeded.
+func (o *BackupOperator) RestoreFilestore(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
+	bucket, prefix, err := o.getFilestoreLocation(installation, store, logger)
+	if err != nil {
+		return err
+	}
+
+	backupBucket, backupPrefix, err := parseS3URL(backup.FilestoreLocation)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse backup filestore location")
+	}
+
+	preRestoreBucket, err := o.ensureBackupBucketCreated(logger)
+	if err != nil {
+		return err
+	}
+	preRestorePrefix := fmt.Sprintf("%s/%s-pre-restore/", installation.ID, backup.ID)
+	preRestoreMarker := fmt.Sprintf("%s/%s-pre-restore.complete", installation.ID, backup.ID)
+
+	logger = logger.WithField("filestore-pre-restore", fmt.Sprintf("s3://%s/%s", preRestoreBucket, preRestorePrefix))
+
+	exists, err := o.objectExists(preRestoreBucket, preRestoreMarker, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to check pre-restore filestore copy")
+	}
+	if !exists {
+		err = o.awsClient.S3EnsureBucketDirectoryDeleted(preRestoreBucket, preRestorePrefix, logger)
+		if err != nil {
+			return errors.Wrap(err, "failed to clean up incomplete pre-restore filestore copy")
+		}
+
+		_, err = o.copyObjects(bucket, prefix, preRestoreBucket, preRestorePrefix, logger)
+		if err != nil {
+			return errors.Wrap(err, "failed to copy filestore objects ahead of restoration")
+		}
+
+		_, err = o.awsClient.Service().s3.PutObjectWithContext(tracing.Context(logger), &s3.PutObjectInput{
+			Bucket: aws.String(preRestoreBucket),
+			Key:    aws.String(preRestoreMarker),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to mark pre-restore filestore copy as complete")
+		}
+		logger.Info("Filestore copied ahead of restoration")
+	}
+
+	err = o.awsClient.S3EnsureBucketDirectoryDeleted(bucket, prefix, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to empty filestore")
+	}
+
+	_, err = o.copyObjects(backupBucket, backupPrefix, bucket, prefix, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to copy backup objects to filestore")
+	}
+
+	_, err = o.awsClient.Service().s3.DeleteObjectWithContext(tracing.Context(logger), &s3.DeleteObjectInput{
+		Bucket: aws.String(preRestoreBucket),
+		Key:    aws.String(preRestoreMarker),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to remove pre-restore filestore copy marker")
+	}
+
+	logger.WithField("filestore-backup", backup.FilestoreLocation).Info("Filestore restored")
+
+	return nil
+}
+
+// RestoreDatabase replaces the RDS cluster of the installation with one
+// restored from the database snapshot of the backup, returning true once the
+// restored database is available.
+//
+// The restoration takes a while, so RestoreDatabase is meant to be called
+// repeatedly, each call moving the restoration forward: the current cluster
+// is deleted, keeping a final snapshot of it, then the cluster is restored
+// under the same identifier and given a master instance. The backup may be
+// of another installation, as when cloning installations, so the restored
+// cluster is encrypted with the key of the installation and its master
+// password is reset to the one stored in the installation secret.
+//
+// The restored cluster and the final snapshot of the replaced one are named
+// after the restoration identifier of the installation, so that the same
+// backup may be restored several times.
+func (o *BackupOperator) RestoreDatabase(installation *model.Installation, backup *model.Backup, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (bool, error) {
+	awsID := CloudID(installation.ID)
+
+	restoreID := installation.RestoreID
+	if restoreID == "" {
+		// Restorations requested before restoration identifiers were
+		// recorded are identified by their backup.
+		restoreID = backup.ID
+	}
+
+	var databaseType string
+	switch installation.Database {
+	case model.InstallationDatabaseSingleTenantRDSMySQL:
+		databaseType = model.DatabaseEngineTypeMySQL
+	case model.InstallationDatabaseSingleTenantRDSPostgres:
+		databaseType = model.DatabaseEngineTypePostgres
+	default:
+		return false, errors.Errorf("restoring %s databases is not supported", installation.Database)
+	}
+
+	logger = logger.WithFields(log.Fields{
+		"db-cluster-name":  awsID,
+		"db-snapshot-name": backup.DatabaseSnapshotID,
+		"restore":          restoreID,
+	})
+
+	result, err := o.awsClient.Service().rds.DescribeDBClustersWithContext(tracing.Context(logger), &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(awsID),
+	})
+	if IsErrorCode(err, rds.ErrCodeDBClusterNotFoundFault) {
+		err = o.restoreDBCluster(installation, backup, restoreID, databaseType, store, logger)
+		if err != nil {
+			return false, err
+		}
+
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to describe DB cluster")
+	}
+	if len(result.DBClusters) != 1 {
+		return false, errors.Errorf("expected 1 DB cluster, but got %d", len(result.DBClusters))
+	}
+	cluster := result.DBClusters[0]
+
+	restored, err := o.isRestored(cluster, restoreID, logger)
+	if err != nil {
+		return false, err
+	}
+	if !restored {
+		if aws.StringValue(cluster.Status) == rdsStatusDeleting {
+			logger.Debug("Waiting for DB cluster to be deleted")
+			return false, nil
+		}
+
+		err = o.deleteDBCluster(installation, restoreID, cluster, logger)
+		if err != nil {
+			return false, err
+		}
+
+		return false, nil
+	}
+
+	if aws.StringValue(cluster.Status) != rdsStatusAvailable {
+		logger.Debug("Waiting for restored DB cluster to be available")
+		return false, nil
+	}
+
+	instanceID := RDSMasterInstanceID(installation.ID)
+	err = o.awsClient.rdsEnsureDBClusterInstanceCreated(awsID, instanceID, databaseType, logger)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create DB instance for restored cluster")
+	}
+
+	instances, err := o.awsClient.Service().rds.DescribeDBInstancesWithContext(tracing.Context(logger), &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(instanceID),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to describe DB instance")
+	}
+	if len(instances.DBInstances) != 1 {
+		return false, errors.Errorf("expected 1 DB instance, but got %d", len(instances.DBInstances))
+	}
+	if aws.StringValue(instances.DBInstances[0].DBInstanceStatus) != rdsStatusAvailable {
+		logger.Debug("Waiting for restored DB instance to be available")
+		return false, nil
+	}
+
+	rdsSecret, err := o.awsClient.secretsManagerGetRDSSecret(awsID, logger)
+	if err != nil {
+		return false, err
+	}
+
+	// Clusters restored from a snapshot get the default backup retention
+	// period rather than the one of clusters created by the provisioner.
+	_, err = o.awsClient.Service().rds.ModifyDBClusterWithContext(tracing.Context(logger), &rds.ModifyDBClusterInput{
+		DBClusterIdentifier:   aws.String(awsID),
+		MasterUserPassword:    aws.String(rdsSecret.MasterPassword),
+		BackupRetentionPeriod: aws.Int64(rdsBackupRetentionPeriod),
+		ApplyImmediately:      aws.Bool(true),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to update restored DB cluster")
+	}
+
+	logger.Info("RDS database restored")
+
+	return true, nil
+}
+
+// isRestored returns whether the given cluster was created by the given
+// restoration.
+func (o *BackupOperator) isRestored(cluster *rds.DBCluster, restoreID string, logger log.FieldLogger) (bool, error) {
+	tags, err := o.awsClient.Service().rds.ListTagsForResourceWithContext(tracing.Context(logger), &rds.ListTagsForResourceInput{
+		ResourceName: cluster.DBClusterArn,
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list DB cluster tags")
+	}
+
+	for _, tag := range tags.TagList {
+		if aws.StringValue(tag.Key) == trimTagPrefix(DefaultRestoredBackupTagKey) && aws.StringValue(tag.Value) == restoreID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// deleteDBCluster deletes the instances of the given cluster and, once they
+// are gone, the cluster itself, keeping a final snapshot of it.
+func (o *BackupOperator) deleteDBCluster(installation *model.Installation, restoreID string, cluster *rds.DBCluster, logger log.FieldLogger) error {
+	if len(cluster.DBClusterMembers) > 0 {
+		for _, member := range cluster.DBClusterMembers {
+			_, err := o.awsClient.Service().rds.DeleteDBInstanceWithContext(tracing.Context(logger), &rds.DeleteDBInstanceInput{
+				DBInstanceIdentifier: member.DBInstanceIdentifier,
+				SkipFinalSnapshot:    aws.Bool(true),
+			})
+			if err != nil && !IsErrorCode(err, rds.ErrCodeInvalidDBInstanceStateFault) {
+				return errors.Wrap(err, "unable to delete DB cluster instance")
+			}
+		}
+		logger.Debug("Waiting for DB cluster instances to be deleted")
+
+		return nil
+	}
+
+	_, err := o.awsClient.Service().rds.DeleteDBClusterWithContext(tracing.Context(logger), &rds.DeleteDBClusterInput{
+		DBClusterIdentifier:       cluster.DBClusterIdentifier,
+		FinalDBSnapshotIdentifier: aws.String(RDSPreRestoreSnapshotID(installation.ID, restoreID)),
+	})
+	if err != nil {
+		return errors.Wrap(err, "unable to delete DB cluster")
+	}
+	logger.Info("DB cluster deleted ahead of restoration")
+
+	return nil
+}
+
+// restoreDBCluster restores the cluster of the installation from the database
+// snapshot of the backup, in the same VPC as the installation, creating the
+// installation secret and encryption key first if needed. The cluster is
+// tagged with the given restoration identifier.
+func (o *BackupOperator) restoreDBCluster(installation *model.Installation, backup *model.Backup, restoreID, databaseType string, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
+	var engine, sgTagValue string
+	switch databaseType {
+	case model.DatabaseEngineTypeMySQL:
+		engine = "aurora-mysql"
+		sgTagValue = DefaultDBSecurityGroupTagMySQLValue
+	case model.DatabaseEngineTypePostgres:
+		engine = "aurora-postgresql"
+		sgTagValue = DefaultDBSecurityGroupTagPostgresValue
+	default:
+		return errors.Errorf("%s is an invalid database engine type", databaseType)
+	}
+
+	vpc, err := getVPCForInstallation(installation.ID, store, o.awsClient, logger)
+	if err != nil {
+		return errors.Wrap(err, "failed to find cluster installation VPC")
+	}
+
+	dbSecurityGroupIDs, err := o.awsClient.rdsGetDBSecurityGroupIDs(*vpc.VpcId, sgTagValue, logger)
+	if err != nil {
+		return err
+	}
+
+	dbSubnetGroupName, err := o.awsClient.rdsGetDBSubnetGroupName(*vpc.VpcId, logger)
+	if err != nil {
+		return err
+	}
+
+	awsID := CloudID(installation.ID)
+
+	_, err = o.awsClient.secretsManagerEnsureRDSSecretCreated(awsID, logger)
+	if err != nil {
+		return err
+	}
+
+	keyMetadata, err := NewRDSDatabase(databaseType, installation.ID, o.awsClient).ensureEncryptionKeyCreated(awsID, logger)
+	if err != nil {
+		return err
+	}
+
+	_, err = o.awsClient.Service().rds.RestoreDBClusterFromSnapshotWithContext(tracing.Context(logger), &rds.RestoreDBClusterFromSnapshotInput{
+		DBClusterIdentifier: aws.String(awsID),
+		SnapshotIdentifier:  aws.String(backup.DatabaseSnapshotID),
+		Engine:              aws.String(engine),
+		DBSubnetGroupName:   aws.String(dbSubnetGroupName),
+		VpcSecurityGroupIds: aws.StringSlice(dbSecurityGroupIDs),
+		KmsKeyId:            keyMetadata.KeyId,
+		Tags: []*rds.Tag{
+			{
+				Key:   aws.String(trimTagPrefix(DefaultRestoredBackupTagKey)),
+				Value: aws.String(restoreID),
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to restore DB cluster from snapshot")
+	}
+	logger.Info("DB cluster restoration from snapshot started")
+
+	return nil
+}
+
+// getFilestoreLocation returns the bucket and key prefix holding the objects
+// of the installation filestore.
+func (o *BackupOperator) getFilestoreLocation(installation *model.Installation, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (string, string, error) {
+	switch installation.Filestore {
+	case model.InstallationFilestoreAwsS3:
+		return CloudID(installation.ID), "", nil
+	case model.InstallationFilestoreMultiTenantAwsS3:
+		bucket, err := NewS3MultitenantFilestore(installation.ID, o.awsClient).getMultitenantBucketName(store, logger)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to find multitenant bucket")
+		}
+		return bucket, installation.ID + "/", nil
+	default:
+		return "", "", errors.Errorf("backups are not supported for %s filestores", installation.Filestore)
+	}
+}
+
+// ensureBackupBucketCreated returns the name of the backup bucket of the
+// environment, creating the bucket if needed.
+func (o *BackupOperator) ensureBackupBucketCreated(logger log.FieldLogger) (string, error) {
+	envName, err := o.awsClient.GetCloudEnvironmentName()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get cloud environment name")
+	}
+	bucket := MattermostBackupS3Name(envName)
+
+	_, err = o.awsClient.Service().s3.HeadBucketWithContext(tracing.Context(logger), &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err == nil {
+		return bucket, nil
+	}
+	if aerr, ok := err.(awserr.RequestFailure); !ok || aerr.StatusCode() != 404 {
+		return "", errors.Wrap(err, "failed to check backup bucket")
+	}
+
+	err = o.awsClient.s3EnsureBucketCreated(bucket, logger)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create backup bucket")
+	}
+	logger.WithField("s3-bucket-name", bucket).Info("Backup bucket created")
+
+	return bucket, nil
+}
+
+// copyObjects copies every object under the source prefix to the destination
+// prefix, returning their total size in bytes.
+func (o *BackupOperator) copyObjects(sourceBucket, sourcePrefix, destinationBucket, destinationPrefix string, logger log.FieldLogger) (int64, error) {
+	var objects []*s3.Object
+	err := o.awsClient.Service().s3.ListObjectsV2PagesWithContext(tracing.Context(logger), &s3.ListObjectsV2Input{
+		Bucket: aws.String(sourceBucket),
+		Prefix: aws.String(sourcePrefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		objects = append(objects, page.Contents...)
+		return true
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list objects of %s", sourceBucket)
+	}
+
+	var size int64
+	for _, object := range objects {
+		key := aws.StringValue(object.Key)
+		copySource := url.PathEscape(sourceBucket + "/" + key)
+		destinationKey := destinationPrefix + strings.TrimPrefix(key, sourcePrefix)
+
+		if aws.Int64Value(object.Size) > s3MaxCopyObjectSize {
+			err = o.copyLargeObject(copySource, aws.Int64Value(object.Size), destinationBucket, destinationKey, logger)
+		} else {
+			_, err = o.awsClient.Service().s3.CopyObjectWithContext(tracing.Context(logger), &s3.CopyObjectInput{
+				Bucket:     aws.String(destinationBucket),
+				Key:        aws.String(destinationKey),
+				CopySource: aws.String(copySource),
+			})
+		}
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to copy object %s", key)
+		}
+		size += aws.Int64Value(object.Size)
+	}
+
+	return size, nil
+}
+
+// copyLargeObject copies an object too large for a single CopyObject call
+// using a multipart upload, aborting the upload if any part fails.
+func (o *BackupOperator) copyLargeObject(copySource string, size int64, destinationBucket, destinationKey string, logger log.FieldLogger) error {
+	upload, err := o.awsClient.Service().s3.CreateMultipartUploadWithContext(tracing.Context(logger), &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(destinationBucket),
+		Key:    aws.String(destinationKey),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create multipart upload")
+	}
+
+	var parts []*s3.CompletedPart
+	for start, partNumber := int64(0), int64(1); start < size; start, partNumber = start+s3CopyPartSize, partNumber+1 {
+		end := start + s3CopyPartSize - 1
+		if end >= size {
+			end = size - 1
+		}
+
+		var result *s3.UploadPartCopyOutput
+		result, err = o.awsClient.Service().s3.UploadPartCopyWithContext(tracing.Context(logger), &s3.UploadPartCopyInput{
+			Bucket:          aws.String(destinationBucket),
+			Key:             aws.String(destinationKey),
+			UploadId:        upload.UploadId,
+			PartNumber:      aws.Int64(partNumber),
+			CopySource:      aws.String(copySource),
+			CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+		})
+		if err != nil {
+			err = errors.Wrapf(err, "failed to copy part %d", partNumber)
+			break
+		}
+		parts = append(parts, &s3.CompletedPart{
+			ETag:       result.CopyPartResult.ETag,
+			PartNumber: aws.Int64(partNumber),
+		})
+	}
+
+	if err == nil {
+		_, err = o.awsClient.Service().s3.CompleteMultipartUploadWithContext(tracing.Context(logger), &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(destinationBucket),
+			Key:             aws.String(destinationKey),
+			UploadId:        upload.UploadId,
+			MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+		})
+		if err == nil {
+			return nil
+		}
+		err = errors.Wrap(err, "failed to complete multipart upload")
+	}
+
+	_, abortErr := o.awsClient.Service().s3.AbortMultipartUploadWithContext(tracing.Context(logger), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(destinationBucket),
+		Key:      aws.String(destinationKey),
+		UploadId: upload.UploadId,
+	})
+	if abortErr != nil {
+		return errors.Wrapf(err, "also failed to abort multipart upload: %s", abortErr)
+	}
+
+	return err
+}
+
+// objectExists returns whether the given object exists.
+func (o *BackupOperator) objectExists(bucket, key string, logger log.FieldLogger) (bool, error) {
+	_, err := o.awsClient.Service().s3.HeadObjectWithContext(tracing.Context(logger), &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err == nil {
+		return true, nil
+	}
+	if aerr, ok := err.(awserr.RequestFailure); ok && aerr.StatusCode() == 404 {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// parseS3URL splits an s3://bucket/prefix URL into its bucket and prefix.
+func parseS3URL(location string) (string, string, error) {
+	u, err := url.Parse(location)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme != "s3" || u.Host == "" {
+		return "", "", errors.Errorf("%s is not an S3 URL", location)
+	}
+
+	return u.Host, strings.TrimPrefix(u.Path, "/"), nil
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/golang/mock/gomock"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+func (a *AWSTestSuite) TestBackupOperatorStartDatabaseBackup() {
+	backup := &model.Backup{ID: model.NewID(), InstallationID: a.InstallationA.ID}
+	snapshotID := RDSBackupSnapshotID(a.InstallationA.ID, backup.ID)
+
+	a.Mocks.API.RDS.EXPECT().
+		CreateDBClusterSnapshotWithContext(gomock.Any(), gomock.Any()).
+		Do(func(_ aws.Context, input *rds.CreateDBClusterSnapshotInput) {
+			a.Assert().Equal(CloudID(a.InstallationA.ID), *input.DBClusterIdentifier)
+			a.Assert().Equal(snapshotID, *input.DBClusterSnapshotIdentifier)
+		}).
+		Return(&rds.CreateDBClusterSnapshotOutput{}, nil).
+		Times(1)
+
+	operator := NewBackupOperator(a.Mocks.AWS)
+	err := operator.StartDatabaseBackup(a.InstallationA, backup, logrus.New())
+	a.Assert().NoError(err)
+	a.Assert().Equal(snapshotID, backup.DatabaseSnapshotID)
+}
+
+func (a *AWSTestSuite) TestBackupOperatorCheckDatabaseBackup() {
+	backup := &model.Backup{ID: model.NewID(), DatabaseSnapshotID: "snapshot"}
+	operator := NewBackupOperator(a.Mocks.AWS)
+
+	gomock.InOrder(
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusterSnapshotsWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClusterSnapshotsOutput{
+				DBClusterSnapshots: []*rds.DBClusterSnapshot{{Status: aws.String("creating")}},
+			}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusterSnapshotsWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClusterSnapshotsOutput{
+				DBClusterSnapshots: []*rds.DBClusterSnapshot{{Status: aws.String("available"), AllocatedStorage: aws.Int64(2)}},
+			}, nil),
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClusterSnapshotsWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClusterSnapshotsOutput{
+				DBClusterSnapshots: []*rds.DBClusterSnapshot{{Status: aws.String("failed")}},
+			}, nil),
+	)
+
+	done, size, err := operator.CheckDatabaseBackup(backup, logrus.New())
+	a.Assert().NoError(err)
+	a.Assert().False(done)
+	a.Assert().Zero(size)
+
+	done, size, err = operator.CheckDatabaseBackup(backup, logrus.New())
+	a.Assert().NoError(err)
+	a.Assert().True(done)
+	a.Assert().Equal(int64(2<<30), size)
+
+	_, _, err = operator.CheckDatabaseBackup(backup, logrus.New())
+	a.Assert().EqualError(err, "DB cluster snapshot is failed")
+}
+
+func (a *AWSTestSuite) TestBackupOperatorBackupFilestore() {
+	installation := &model.Installation{
+		ID:        a.InstallationA.ID,
+		Filestore: model.InstallationFilestoreAwsS3,
+	}
+	backup := &model.Backup{ID: model.NewID(), InstallationID: installation.ID}
+	backupPrefix := installation.ID + "/" + backup.ID + "/"
+
+	a.Mocks.API.IAM.EXPECT().
+		ListAccountAliases(gomock.Any()).
+		Return(&iam.ListAccountAliasesOutput{AccountAliases: aws.StringSlice([]string{"mattermost-cloud-test"})}, nil)
+	a.Mocks.API.S3.EXPECT().
+		HeadBucketWithContext(gomock.Any(), &s3.HeadBucketInput{Bucket: aws.String("mattermost-cloud-test-backups")}).
+		Return(&s3.HeadBucketOutput{}, nil)
+	a.Mocks.API.S3.EXPECT().
+		ListObjectsV2PagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ aws.Context, input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+			a.Assert().Equal(CloudID(installation.ID), *input.Bucket)
+			fn(&s3.ListObjectsV2Output{
+				Contents: []*s3.Object{
+					{Key: aws.String("data/a.png"), Size: aws.Int64(10)},
+					{Key: aws.String("data/b.png"), Size: aws.Int64(20)},
+				},
+			}, true)
+			return nil
+		})
+	a.Mocks.API.S3.EXPECT().
+		CopyObjectWithContext(gomock.Any(), &s3.CopyObjectInput{
+			Bucket:     aws.String("mattermost-cloud-test-backups"),
+			Key:        aws.String(backupPrefix + "data/a.png"),
+			CopySource: aws.String(CloudID(installation.ID) + "%2Fdata%2Fa.png"),
+		}).
+		Return(&s3.CopyObjectOutput{}, nil)
+	a.Mocks.API.S3.EXPECT().
+		CopyObjectWithContext(gomock.Any(), &s3.CopyObjectInput{
+			Bucket:     aws.String("mattermost-cloud-test-backups"),
+			Key:        aws.String(backupPrefix + "data/b.png"),
+			CopySource: aws.String(CloudID(installation.ID) + "%2Fdata%2Fb.png"),
+		}).
+		Return(&s3.CopyObjectOutput{}, nil)
+
+	operator := NewBackupOperator(a.Mocks.AWS)
+	size, err := operator.BackupFilestore(installation, backup, a.Mocks.Model.DatabaseInstallationStore, logrus.New())
+	a.Assert().NoError(err)
+	a.Assert().Equal(int64(30), size)
+	a.Assert().Equal("s3://mattermost-cloud-test-backups/"+backupPrefix, backup.FilestoreLocation)
+}
+
+func (a *AWSTestSuite) TestBackupOperatorCopyLargeObjects() {
+	size := int64(s3MaxCopyObjectSize + s3CopyPartSize/2)
+
+	listObjects := func() {
+		a.Mocks.API.S3.EXPECT().
+			ListObjectsV2PagesWithContext(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ aws.Context, input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+				fn(&s3.ListObjectsV2Output{
+					Contents: []*s3.Object{{Key: aws.String("data/large.zip"), Size: aws.Int64(size)}},
+				}, true)
+				return nil
+			})
+		a.Mocks.API.S3.EXPECT().
+			CreateMultipartUploadWithContext(gomock.Any(), &s3.CreateMultipartUploadInput{
+				Bucket: aws.String("destination"),
+				Key:    aws.String("backup/data/large.zip"),
+			}).
+			Return(&s3.CreateMultipartUploadOutput{UploadId: aws.String("upload")}, nil)
+	}
+
+	a.Run("copied in parts", func() {
+		a.SetupTest()
+		listObjects()
+
+		var ranges []string
+		a.Mocks.API.S3.EXPECT().
+			UploadPartCopyWithContext(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ aws.Context, input *s3.UploadPartCopyInput) (*s3.UploadPartCopyOutput, error) {
+				a.Assert().Equal("upload", *input.UploadId)
+				a.Assert().Equal("source%2Fdata%2Flarge.zip", *input.CopySource)
+				a.Assert().Equal(int64(len(ranges)+1), *input.PartNumber)
+				ranges = append(ranges, *input.CopySourceRange)
+				return &s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: aws.String("etag")}}, nil
+			}).
+			Times(6)
+		a.Mocks.API.S3.EXPECT().
+			CompleteMultipartUploadWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *s3.CompleteMultipartUploadInput) {
+				a.Assert().Len(input.MultipartUpload.Parts, 6)
+			}).
+			Return(&s3.CompleteMultipartUploadOutput{}, nil)
+
+		copied, err := NewBackupOperator(a.Mocks.AWS).copyObjects("source", "data/", "destination", "backup/data/", a.Mocks.Log.Logger)
+		a.Assert().NoError(err)
+		a.Assert().Equal(size, copied)
+		a.Assert().Equal("bytes=0-1073741823", ranges[0])
+		a.Assert().Equal("bytes=5368709120-5905580031", ranges[5])
+	})
+
+	a.Run("part failed", func() {
+		a.SetupTest()
+		listObjects()
+
+		a.Mocks.API.S3.EXPECT().
+			UploadPartCopyWithContext(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("internal error"))
+		a.Mocks.API.S3.EXPECT().
+			AbortMultipartUploadWithContext(gomock.Any(), &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String("destination"),
+				Key:      aws.String("backup/data/large.zip"),
+				UploadId: aws.String("upload"),
+			}).
+			Return(&s3.AbortMultipartUploadOutput{}, nil)
+
+		_, err := NewBackupOperator(a.Mocks.AWS).copyObjects("source", "data/", "destination", "backup/data/", a.Mocks.Log.Logger)
+		a.Assert().Error(err)
+	})
+}
+
+func (a *AWSTestSuite) TestBackupOperatorRestoreDatabase() {
+	installation := &model.Installation{
+		ID:        a.InstallationA.ID,
+		Database:  model.InstallationDatabaseSingleTenantRDSMySQL,
+		RestoreID: model.NewID(),
+	}
+	backup := &model.Backup{ID: model.NewID(), DatabaseSnapshotID: "snapshot"}
+	operator := NewBackupOperator(a.Mocks.AWS)
+
+	a.Run("delete instances of current cluster", func() {
+		a.SetupTest()
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClustersOutput{
+				DBClusters: []*rds.DBCluster{{
+					DBClusterArn:        aws.String("arn"),
+					DBClusterIdentifier: aws.String(CloudID(installation.ID)),
+					Status:              aws.String("available"),
+					DBClusterMembers:    []*rds.DBClusterMember{{DBInstanceIdentifier: aws.String(RDSMasterInstanceID(installation.ID))}},
+				}},
+			}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.ListTagsForResourceOutput{}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			DeleteDBInstanceWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DeleteDBInstanceOutput{}, nil)
+
+		done, err := NewBackupOperator(a.Mocks.AWS).RestoreDatabase(installation, backup, a.Mocks.Model.DatabaseInstallationStore, logrus.New())
+		a.Assert().NoError(err)
+		a.Assert().False(done)
+	})
+
+	a.Run("delete current cluster keeping a final snapshot", func() {
+		a.SetupTest()
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClustersOutput{
+				DBClusters: []*rds.DBCluster{{
+					DBClusterArn:        aws.String("arn"),
+					DBClusterIdentifier: aws.String(CloudID(installation.ID)),
+					Status:              aws.String("available"),
+				}},
+			}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.ListTagsForResourceOutput{}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			DeleteDBClusterWithContext(gomock.Any(), &rds.DeleteDBClusterInput{
+				DBClusterIdentifier:       aws.String(CloudID(installation.ID)),
+				FinalDBSnapshotIdentifier: aws.String(RDSPreRestoreSnapshotID(installation.ID, installation.RestoreID)),
+			}).
+			Return(&rds.DeleteDBClusterOutput{}, nil)
+
+		done, err := NewBackupOperator(a.Mocks.AWS).RestoreDatabase(installation, backup, a.Mocks.Model.DatabaseInstallationStore, logrus.New())
+		a.Assert().NoError(err)
+		a.Assert().False(done)
+	})
+
+	a.Run("cluster restored from the same backup before is replaced", func() {
+		a.SetupTest()
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClustersOutput{
+				DBClusters: []*rds.DBCluster{{
+					DBClusterArn:        aws.String("arn"),
+					DBClusterIdentifier: aws.String(CloudID(installation.ID)),
+					Status:              aws.String("available"),
+				}},
+			}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.ListTagsForResourceOutput{
+				TagList: []*rds.Tag{{Key: aws.String("RestoredBackup"), Value: aws.String(model.NewID())}},
+			}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			DeleteDBClusterWithContext(gomock.Any(), &rds.DeleteDBClusterInput{
+				DBClusterIdentifier:       aws.String(CloudID(installation.ID)),
+				FinalDBSnapshotIdentifier: aws.String(RDSPreRestoreSnapshotID(installation.ID, installation.RestoreID)),
+			}).
+			Return(&rds.DeleteDBClusterOutput{}, nil)
+
+		done, err := NewBackupOperator(a.Mocks.AWS).RestoreDatabase(installation, backup, a.Mocks.Model.DatabaseInstallationStore, logrus.New())
+		a.Assert().NoError(err)
+		a.Assert().False(done)
+	})
+
+	a.Run("restored cluster available", func() {
+		a.SetupTest()
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClustersOutput{
+				DBClusters: []*rds.DBCluster{{
+					DBClusterArn:        aws.String("arn"),
+					DBClusterIdentifier: aws.String(CloudID(installation.ID)),
+					Status:              aws.String("available"),
+				}},
+			}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.ListTagsForResourceOutput{
+				TagList: []*rds.Tag{{Key: aws.String("RestoredBackup"), Value: aws.String(installation.RestoreID)}},
+			}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBInstancesWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBInstancesOutput{
+				DBInstances: []*rds.DBInstance{{DBInstanceStatus: aws.String("available")}},
+			}, nil).
+			Times(2)
+		a.Mocks.API.SecretsManager.EXPECT().
+			GetSecretValueWithContext(gomock.Any(), gomock.Any()).
+			Return(&secretsmanager.GetSecretValueOutput{SecretString: &a.SecretString}, nil)
+		a.Mocks.API.RDS.EXPECT().
+			ModifyDBClusterWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *rds.ModifyDBClusterInput) {
+				a.Assert().Equal(CloudID(installation.ID), *input.DBClusterIdentifier)
+				a.Assert().NotEmpty(*input.MasterUserPassword)
+				a.Assert().Equal(int64(7), *input.BackupRetentionPeriod)
+				a.Assert().True(*input.ApplyImmediately)
+			}).
+			Return(&rds.ModifyDBClusterOutput{}, nil)
+
+		done, err := NewBackupOperator(a.Mocks.AWS).RestoreDatabase(installation, backup, a.Mocks.Model.DatabaseInstallationStore, logrus.New())
+		a.Assert().NoError(err)
+		a.Assert().True(done)
+	})
+
+	a.Run("unsupported database", func() {
+		_, err := operator.RestoreDatabase(&model.Installation{Database: model.InstallationDatabaseMysqlOperator}, backup, a.Mocks.Model.DatabaseInstallationStore, logrus.New())
+		a.Assert().Error(err)
+	})
+}
+
+func (a *AWSTestSuite) TestBackupOperatorParseS3URL() {
+	bucket, prefix, err := parseS3URL("s3://backups/installation/backup/")
+	a.Assert().NoError(err)
+	a.Assert().Equal("backups", bucket)
+	a.Assert().Equal("installation/backup/", prefix)
+
+	_, _, err = parseS3URL("https://backups/installation")
+	a.Assert().Error(err)
+}
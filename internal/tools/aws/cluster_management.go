@@ -61,7 +61,7 @@ func (a *Client) getClusterResourcesForVPC(vpcID string, logger log.FieldLogger)
 		Values: []*string{aws.String("private")},
 	})
 
-	privateSubnets, err := a.GetSubnetsWithFilters(privateSubnetFilter)
+	privateSubnets, err := a.GetSubnetsWithFilters(privateSubnetFilter, logger)
 	if err != nil {
 		return clusterResources, err
 	}
@@ -75,7 +75,7 @@ func (a *Client) getClusterResourcesForVPC(vpcID string, logger log.FieldLogger)
 		Values: []*string{aws.String("public")},
 	})
 
-	publicSubnets, err := a.GetSubnetsWithFilters(publicSubnetFilter)
+	publicSubnets, err := a.GetSubnetsWithFilters(publicSubnetFilter, logger)
 	if err != nil {
 		return clusterResources, err
 	}
@@ -89,7 +89,7 @@ func (a *Client) getClusterResourcesForVPC(vpcID string, logger log.FieldLogger)
 		Values: []*string{aws.String("master")},
 	})
 
-	masterSecurityGroups, err := a.GetSecurityGroupsWithFilters(masterSGFilter)
+	masterSecurityGroups, err := a.GetSecurityGroupsWithFilters(masterSGFilter, logger)
 	if err != nil {
 		return clusterResources, err
 	}
@@ -103,7 +103,7 @@ func (a *Client) getClusterResourcesForVPC(vpcID string, logger log.FieldLogger)
 		Values: []*string{aws.String("worker")},
 	})
 
-	workerSecurityGroups, err := a.GetSecurityGroupsWithFilters(workerSGFilter)
+	workerSecurityGroups, err := a.GetSecurityGroupsWithFilters(workerSGFilter, logger)
 	if err != nil {
 		return clusterResources, err
 	}
@@ -139,7 +139,7 @@ func (a *Client) GetAndClaimVpcResources(clusterID, owner string, logger log.Fie
 			},
 		},
 	}
-	clusterAlreadyClaimedVpcs, err := a.GetVpcsWithFilters(clusterAlreadyClaimedFilter)
+	clusterAlreadyClaimedVpcs, err := a.GetVpcsWithFilters(clusterAlreadyClaimedFilter, logger)
 	if err != nil {
 		return ClusterResources{}, err
 	}
@@ -160,7 +160,7 @@ func (a *Client) GetAndClaimVpcResources(clusterID, owner string, logger log.Fie
 			},
 		},
 	}
-	totalVpcs, err := a.GetVpcsWithFilters(totalVpcsFilter)
+	totalVpcs, err := a.GetVpcsWithFilters(totalVpcsFilter, logger)
 	if err != nil {
 		return ClusterResources{}, err
 	}
@@ -173,7 +173,7 @@ func (a *Client) GetAndClaimVpcResources(clusterID, owner string, logger log.Fie
 		},
 	}
 
-	vpcs, err := a.GetVpcsWithFilters(vpcFilters)
+	vpcs, err := a.GetVpcsWithFilters(vpcFilters, logger)
 	if err != nil {
 		return ClusterResources{}, err
 	}
@@ -227,7 +227,7 @@ func (a *Client) claimVpc(clusterResources ClusterResources, clusterID string, o
 			Values: []*string{aws.String(VpcClusterIDTagValueNone)},
 		},
 	}
-	vpcs, err := a.GetVpcsWithFilters(vpcFilter)
+	vpcs, err := a.GetVpcsWithFilters(vpcFilter, logger)
 	if err != nil {
 		return err
 	}
@@ -279,7 +279,7 @@ func (a *Client) releaseVpc(clusterID string, logger log.FieldLogger) error {
 		},
 	}
 
-	vpcs, err := a.GetVpcsWithFilters(vpcFilters)
+	vpcs, err := a.GetVpcsWithFilters(vpcFilters, logger)
 	if err != nil {
 		return err
 	}
@@ -308,7 +308,7 @@ func (a *Client) releaseVpc(clusterID string, logger log.FieldLogger) error {
 		},
 	}
 
-	publicSubnets, err := a.GetSubnetsWithFilters(publicSubnetFilter)
+	publicSubnets, err := a.GetSubnetsWithFilters(publicSubnetFilter, logger)
 	if err != nil {
 		return err
 	}
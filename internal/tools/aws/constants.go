@@ -151,6 +151,10 @@ const (
 	// existing installations.
 	rdsSuffix = "-rds"
 
+	// rdsBackupRetentionPeriod is the number of days automated backups of
+	// single tenant RDS clusters are kept, including restored ones.
+	rdsBackupRetentionPeriod = 7
+
 	// rdsMySQLDefaultSchema is the default schema given to a new RDS MySQL
 	// database. This is used to connect to multitenant RDS clusters to set up
 	// new installation databases as needed.
@@ -176,6 +180,14 @@ const (
 	// of a cluster installation.
 	DefaultClusterInstallationSnapshotTagKey = "tag:ClusterInstallationSnapshot"
 
+	// DefaultInstallationBackupTagKey is used for tagging database snapshots
+	// taken as part of an installation backup.
+	DefaultInstallationBackupTagKey = "tag:InstallationBackup"
+
+	// DefaultRestoredBackupTagKey is used for tagging database clusters
+	// restored from an installation backup with the restoration identifier.
+	DefaultRestoredBackupTagKey = "tag:RestoredBackup"
+
 	// DefaultAWSClientRetries supplies how many time the AWS client will
 	// retry a failed call.
 	DefaultAWSClientRetries = 3
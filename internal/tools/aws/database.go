@@ -18,6 +18,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/model"
 	mmv1alpha1 "github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
 )
@@ -80,19 +81,19 @@ func (d *RDSDatabase) Teardown(store model.InstallationDatabaseStoreInterface, k
 		return errors.Wrap(err, "unable to delete RDS DB cluster")
 	}
 
-	resourceNames, err := d.getKMSResourceNames(awsID)
+	resourceNames, err := d.getKMSResourceNames(awsID, logger)
 	if err != nil {
 		return errors.Wrapf(err, "unabled to get KMS resources associated with db cluster %s", awsID)
 	}
 
 	if len(resourceNames) > 0 {
-		enabledKeys, err := d.getEnabledEncryptionKeys(resourceNames)
+		enabledKeys, err := d.getEnabledEncryptionKeys(resourceNames, logger)
 		if err != nil {
 			return errors.Wrapf(err, "unabled to get encryption key associated with db cluster %s", awsID)
 		}
 
 		for _, keyMetadata := range enabledKeys {
-			err = d.client.kmsScheduleKeyDeletion(*keyMetadata.KeyId, KMSMaxTimeEncryptionKeyDeletion)
+			err = d.client.kmsScheduleKeyDeletion(*keyMetadata.KeyId, KMSMaxTimeEncryptionKeyDeletion, logger)
 			if err != nil {
 				return errors.Wrapf(err, "encryption key associated with db cluster %s could not be scheduled for deletion", awsID)
 			}
@@ -116,7 +117,7 @@ func (d *RDSDatabase) Snapshot(store model.InstallationDatabaseStoreInterface, l
 		"database-type":   d.databaseType,
 	})
 
-	_, err := d.client.Service().rds.CreateDBClusterSnapshot(&rds.CreateDBClusterSnapshotInput{
+	_, err := d.client.Service().rds.CreateDBClusterSnapshotWithContext(tracing.Context(logger), &rds.CreateDBClusterSnapshotInput{
 		DBClusterIdentifier:         aws.String(awsID),
 		DBClusterSnapshotIdentifier: aws.String(fmt.Sprintf("%s-snapshot-%v", awsID, time.Now().Nanosecond())),
 		Tags: []*rds.Tag{
@@ -135,6 +136,38 @@ func (d *RDSDatabase) Snapshot(store model.InstallationDatabaseStoreInterface, l
 	return nil
 }
 
+// IsAvailable returns whether the DB cluster and its master instance are both
+// available. Newly provisioned RDS databases take several minutes before they
+// accept connections.
+func (d *RDSDatabase) IsAvailable(logger log.FieldLogger) (bool, error) {
+	awsID := CloudID(d.installationID)
+
+	dbClusters, err := d.client.Service().rds.DescribeDBClustersWithContext(tracing.Context(logger), &rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(awsID),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to describe DB cluster")
+	}
+	if len(dbClusters.DBClusters) != 1 {
+		return false, errors.Errorf("expected 1 DB cluster, but got %d", len(dbClusters.DBClusters))
+	}
+	if aws.StringValue(dbClusters.DBClusters[0].Status) != DefaultRDSStatusAvailable {
+		return false, nil
+	}
+
+	dbInstances, err := d.client.Service().rds.DescribeDBInstancesWithContext(tracing.Context(logger), &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(RDSMasterInstanceID(d.installationID)),
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to describe DB instance")
+	}
+	if len(dbInstances.DBInstances) != 1 {
+		return false, errors.Errorf("expected 1 DB instance, but got %d", len(dbInstances.DBInstances))
+	}
+
+	return aws.StringValue(dbInstances.DBInstances[0].DBInstanceStatus) == DefaultRDSStatusAvailable, nil
+}
+
 // GenerateDatabaseSpecAndSecret creates the k8s database spec and secret for
 // accessing the RDS database.
 func (d *RDSDatabase) GenerateDatabaseSpecAndSecret(store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (*mmv1alpha1.Database, *corev1.Secret, error) {
@@ -150,7 +183,7 @@ func (d *RDSDatabase) GenerateDatabaseSpecAndSecret(store model.InstallationData
 		return nil, nil, err
 	}
 
-	dbClusters, err := d.client.Service().rds.DescribeDBClusters(&rds.DescribeDBClustersInput{
+	dbClusters, err := d.client.Service().rds.DescribeDBClustersWithContext(tracing.Context(logger), &rds.DescribeDBClustersInput{
 		DBClusterIdentifier: aws.String(awsID),
 	})
 	if err != nil {
@@ -243,7 +276,7 @@ func (d *RDSDatabase) rdsDatabaseProvision(installationID string, logger log.Fie
 			Values: []*string{aws.String(VpcAvailableTagValueFalse)},
 		},
 	}
-	vpcs, err := d.client.GetVpcsWithFilters(vpcFilters)
+	vpcs, err := d.client.GetVpcsWithFilters(vpcFilters, logger)
 	if err != nil {
 		return err
 	}
@@ -256,35 +289,11 @@ func (d *RDSDatabase) rdsDatabaseProvision(installationID string, logger log.Fie
 		return err
 	}
 
-	kmsResourceNames, err := d.getKMSResourceNames(awsID)
+	keyMetadata, err := d.ensureEncryptionKeyCreated(awsID, logger)
 	if err != nil {
 		return err
 	}
 
-	var keyMetadata *kms.KeyMetadata
-	if len(kmsResourceNames) > 0 {
-		enabledKeys, err := d.getEnabledEncryptionKeys(kmsResourceNames)
-		if err != nil {
-			return errors.Wrapf(err, "failed to get encryption keys for db cluster %s", awsID)
-		}
-
-		if len(enabledKeys) != 1 {
-			return errors.Errorf("db cluster %s should have exactly one enabled/active encryption key (found %d)", awsID, len(enabledKeys))
-		}
-
-		keyMetadata = enabledKeys[0]
-	} else {
-		keyMetadata, err = d.client.kmsCreateSymmetricKey(KMSKeyDescriptionRDS(awsID), []*kms.Tag{
-			{
-				TagKey:   aws.String(DefaultRDSEncryptionTagKey),
-				TagValue: aws.String(awsID),
-			},
-		})
-		if err != nil {
-			return errors.Wrapf(err, "failed to create an encryption key for db cluster %s", awsID)
-		}
-	}
-
 	logger.Infof("Encrypting RDS database with key %s", *keyMetadata.Arn)
 
 	err = d.client.rdsEnsureDBClusterCreated(awsID, *vpcs[0].VpcId, rdsSecret.MasterUsername, rdsSecret.MasterPassword, *keyMetadata.KeyId, d.databaseType, logger)
@@ -300,7 +309,41 @@ func (d *RDSDatabase) rdsDatabaseProvision(installationID string, logger log.Fie
 	return nil
 }
 
-func (d *RDSDatabase) getKMSResourceNames(awsID string) ([]*string, error) {
+// ensureEncryptionKeyCreated returns the key encrypting the given DB
+// cluster, creating it if it does not exist yet.
+func (d *RDSDatabase) ensureEncryptionKeyCreated(awsID string, logger log.FieldLogger) (*kms.KeyMetadata, error) {
+	kmsResourceNames, err := d.getKMSResourceNames(awsID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(kmsResourceNames) > 0 {
+		enabledKeys, err := d.getEnabledEncryptionKeys(kmsResourceNames, logger)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get encryption keys for db cluster %s", awsID)
+		}
+
+		if len(enabledKeys) != 1 {
+			return nil, errors.Errorf("db cluster %s should have exactly one enabled/active encryption key (found %d)", awsID, len(enabledKeys))
+		}
+
+		return enabledKeys[0], nil
+	}
+
+	keyMetadata, err := d.client.kmsCreateSymmetricKey(KMSKeyDescriptionRDS(awsID), []*kms.Tag{
+		{
+			TagKey:   aws.String(DefaultRDSEncryptionTagKey),
+			TagValue: aws.String(awsID),
+		},
+	}, logger)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create an encryption key for db cluster %s", awsID)
+	}
+
+	return keyMetadata, nil
+}
+
+func (d *RDSDatabase) getKMSResourceNames(awsID string, logger log.FieldLogger) ([]*string, error) {
 	kmsResources, err := d.client.resourceTaggingGetAllResources(resourcegroupstaggingapi.GetResourcesInput{
 		TagFilters: []*resourcegroupstaggingapi.TagFilter{
 			{
@@ -308,7 +351,7 @@ func (d *RDSDatabase) getKMSResourceNames(awsID string) ([]*string, error) {
 				Values: []*string{aws.String(awsID)},
 			},
 		},
-	})
+	}, logger)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to get KMS resources with tag %s:%s", DefaultRDSEncryptionTagKey, awsID)
 	}
@@ -321,11 +364,11 @@ func (d *RDSDatabase) getKMSResourceNames(awsID string) ([]*string, error) {
 	return resourceNameList, nil
 }
 
-func (d *RDSDatabase) getEnabledEncryptionKeys(resourceNameList []*string) ([]*kms.KeyMetadata, error) {
+func (d *RDSDatabase) getEnabledEncryptionKeys(resourceNameList []*string, logger log.FieldLogger) ([]*kms.KeyMetadata, error) {
 	var keys []*kms.KeyMetadata
 
 	for _, name := range resourceNameList {
-		keyMetadata, err := d.client.kmsGetSymmetricKey(*name)
+		keyMetadata, err := d.client.kmsGetSymmetricKey(*name, logger)
 		if err != nil {
 			return nil, err
 		}
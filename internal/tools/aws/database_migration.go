@@ -12,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 	log "github.com/sirupsen/logrus"
 
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/model"
 )
 
@@ -33,17 +34,17 @@ func NewRDSDatabaseMigration(masterInstallationID, slaveInstallationID string, a
 
 // Setup sets access from one RDS database to another and sets any configuration needed for replication.
 func (d *RDSDatabaseMigration) Setup(logger log.FieldLogger) (string, error) {
-	masterInstanceSG, err := d.describeDBInstanceSecurityGroup(RDSMasterInstanceID(d.masterInstallationID))
+	masterInstanceSG, err := d.describeDBInstanceSecurityGroup(RDSMasterInstanceID(d.masterInstallationID), logger)
 	if err != nil {
 		return "", d.toSetupError(err)
 	}
 
-	slaveInstanceSG, err := d.describeDBInstanceSecurityGroup(RDSMigrationInstanceID(d.slaveInstallationID))
+	slaveInstanceSG, err := d.describeDBInstanceSecurityGroup(RDSMigrationInstanceID(d.slaveInstallationID), logger)
 	if err != nil {
 		return "", d.toSetupError(err)
 	}
 
-	_, err = d.awsClient.Service().ec2.AuthorizeSecurityGroupIngress(&ec2.AuthorizeSecurityGroupIngressInput{
+	_, err = d.awsClient.Service().ec2.AuthorizeSecurityGroupIngressWithContext(tracing.Context(logger), &ec2.AuthorizeSecurityGroupIngressInput{
 		GroupId: masterInstanceSG.GroupId,
 		IpPermissions: []*ec2.IpPermission{
 			{
@@ -74,17 +75,17 @@ func (d *RDSDatabaseMigration) Setup(logger log.FieldLogger) (string, error) {
 
 // Teardown removes access from one RDS database to another and rollback any previous database configuration.
 func (d *RDSDatabaseMigration) Teardown(logger log.FieldLogger) (string, error) {
-	masterInstanceSG, err := d.describeDBInstanceSecurityGroup(RDSMasterInstanceID(d.masterInstallationID))
+	masterInstanceSG, err := d.describeDBInstanceSecurityGroup(RDSMasterInstanceID(d.masterInstallationID), logger)
 	if err != nil {
 		return "", d.toTeardownError(err)
 	}
 
-	slaveInstanceSG, err := d.describeDBInstanceSecurityGroup(RDSMigrationInstanceID(d.slaveInstallationID))
+	slaveInstanceSG, err := d.describeDBInstanceSecurityGroup(RDSMigrationInstanceID(d.slaveInstallationID), logger)
 	if err != nil {
 		return "", d.toTeardownError(err)
 	}
 
-	_, err = d.awsClient.Service().ec2.RevokeSecurityGroupIngress(&ec2.RevokeSecurityGroupIngressInput{
+	_, err = d.awsClient.Service().ec2.RevokeSecurityGroupIngressWithContext(tracing.Context(logger), &ec2.RevokeSecurityGroupIngressInput{
 		GroupId: masterInstanceSG.GroupId,
 		IpPermissions: []*ec2.IpPermission{
 			{
@@ -118,8 +119,8 @@ func (d *RDSDatabaseMigration) Replicate(logger log.FieldLogger) (string, error)
 	return "", errors.New("not implemented")
 }
 
-func (d *RDSDatabaseMigration) describeDBInstanceSecurityGroup(instanceID string) (*ec2.SecurityGroup, error) {
-	output, err := d.awsClient.Service().rds.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+func (d *RDSDatabaseMigration) describeDBInstanceSecurityGroup(instanceID string, logger log.FieldLogger) (*ec2.SecurityGroup, error) {
+	output, err := d.awsClient.Service().rds.DescribeDBInstancesWithContext(tracing.Context(logger), &rds.DescribeDBInstancesInput{
 		DBInstanceIdentifier: aws.String(instanceID),
 	})
 	if err != nil {
@@ -128,7 +129,7 @@ func (d *RDSDatabaseMigration) describeDBInstanceSecurityGroup(instanceID string
 
 	for _, instance := range output.DBInstances {
 		for _, vpcSG := range instance.VpcSecurityGroups {
-			sgOutput, err := d.awsClient.Service().ec2.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+			sgOutput, err := d.awsClient.Service().ec2.DescribeSecurityGroupsWithContext(tracing.Context(logger), &ec2.DescribeSecurityGroupsInput{
 				GroupIds: []*string{vpcSG.VpcSecurityGroupId},
 			})
 			if err != nil {
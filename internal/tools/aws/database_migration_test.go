@@ -183,7 +183,7 @@ func (a *AWSTestSuite) TestDatabaseRDSMigrationTeardownSGNotFoundError() {
 // Helpers
 
 func (a *AWSTestSuite) SetDescribeDBInstancesExpectation(vpcSecurityGroupID string) *gomock.Call {
-	return a.Mocks.API.RDS.EXPECT().DescribeDBInstances(gomock.Any()).
+	return a.Mocks.API.RDS.EXPECT().DescribeDBInstancesWithContext(gomock.Any(), gomock.Any()).
 		Return(&rds.DescribeDBInstancesOutput{
 			DBInstances: []*rds.DBInstance{{
 				VpcSecurityGroups: []*rds.VpcSecurityGroupMembership{{
@@ -194,7 +194,7 @@ func (a *AWSTestSuite) SetDescribeDBInstancesExpectation(vpcSecurityGroupID stri
 }
 
 func (a *AWSTestSuite) SetDescribeSecurityGroupsExpectation(groupID, groupName string, tag *ec2.Tag) *gomock.Call {
-	return a.Mocks.API.EC2.EXPECT().DescribeSecurityGroups(gomock.Any()).
+	return a.Mocks.API.EC2.EXPECT().DescribeSecurityGroupsWithContext(gomock.Any(), gomock.Any()).
 		Return(&ec2.DescribeSecurityGroupsOutput{
 			SecurityGroups: []*ec2.SecurityGroup{{
 				GroupId:   aws.String(groupID),
@@ -205,8 +205,8 @@ func (a *AWSTestSuite) SetDescribeSecurityGroupsExpectation(groupID, groupName s
 }
 
 func (a *AWSTestSuite) SetAuthorizeSecurityGroupIngress(description, groupIDMaster, groupIDSlave string) *gomock.Call {
-	return a.Mocks.API.EC2.EXPECT().AuthorizeSecurityGroupIngress(gomock.Any()).
-		Do(func(input *ec2.AuthorizeSecurityGroupIngressInput) {
+	return a.Mocks.API.EC2.EXPECT().AuthorizeSecurityGroupIngressWithContext(gomock.Any(), gomock.Any()).
+		Do(func(_ aws.Context, input *ec2.AuthorizeSecurityGroupIngressInput) {
 			a.Assert().Equal(description, *input.IpPermissions[0].UserIdGroupPairs[0].Description)
 			a.Assert().Equal(groupIDSlave, *input.IpPermissions[0].UserIdGroupPairs[0].GroupId)
 			a.Assert().Equal(groupIDMaster, *input.GroupId)
@@ -217,8 +217,8 @@ func (a *AWSTestSuite) SetAuthorizeSecurityGroupIngress(description, groupIDMast
 }
 
 func (a *AWSTestSuite) SetRevokeSecurityGroupIngress(description, groupIDMaster, groupIDSlave string) *gomock.Call {
-	return a.Mocks.API.EC2.EXPECT().RevokeSecurityGroupIngress(gomock.Any()).
-		Do(func(input *ec2.RevokeSecurityGroupIngressInput) {
+	return a.Mocks.API.EC2.EXPECT().RevokeSecurityGroupIngressWithContext(gomock.Any(), gomock.Any()).
+		Do(func(_ aws.Context, input *ec2.RevokeSecurityGroupIngressInput) {
 			a.Assert().Equal(groupIDSlave, *input.IpPermissions[0].UserIdGroupPairs[0].GroupId)
 			a.Assert().Equal(groupIDMaster, *input.GroupId)
 			a.Assert().Equal("tcp", *input.IpPermissions[0].IpProtocol)
@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/model"
 	mmv1alpha1 "github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
 
@@ -110,7 +111,7 @@ func (d *RDSMultitenantDatabase) Provision(store model.InstallationDatabaseStore
 	})
 	logger.Info("Provisioning Multitenant AWS RDS database")
 
-	vpc, err := getVPCForInstallation(d.installationID, store, d.client)
+	vpc, err := getVPCForInstallation(d.installationID, store, d.client, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to find cluster installation VPC")
 	}
@@ -129,7 +130,7 @@ func (d *RDSMultitenantDatabase) Provision(store model.InstallationDatabaseStore
 	defer unlockFn()
 	logger = logger.WithField("assigned-database", database.ID)
 
-	rdsCluster, err := d.describeRDSCluster(database.ID)
+	rdsCluster, err := d.describeRDSCluster(database.ID, logger)
 	if err != nil {
 		return errors.Wrapf(err, "failed to describe the multitenant RDS cluster ID %s", database.ID)
 	}
@@ -145,7 +146,7 @@ func (d *RDSMultitenantDatabase) Provision(store model.InstallationDatabaseStore
 		return errors.Wrap(err, "failed to run provisioning sql commands")
 	}
 
-	err = d.updateCounterTag(rdsCluster.DBClusterArn, database.Installations.Count())
+	err = d.updateCounterTag(rdsCluster.DBClusterArn, database.Installations.Count(), logger)
 	if err != nil {
 		return errors.Wrapf(err, "failed to update tag:counter in RDS cluster ID %s", *rdsCluster.DBClusterIdentifier)
 	}
@@ -188,7 +189,7 @@ func (d *RDSMultitenantDatabase) GenerateDatabaseSpecAndSecret(store model.Insta
 	}
 	defer unlock()
 
-	rdsCluster, err := d.describeRDSCluster(multitenantDatabase.ID)
+	rdsCluster, err := d.describeRDSCluster(multitenantDatabase.ID, logger)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to describe RDS cluster")
 	}
@@ -197,7 +198,7 @@ func (d *RDSMultitenantDatabase) GenerateDatabaseSpecAndSecret(store model.Insta
 
 	installationSecretName := RDSMultitenantSecretName(d.installationID)
 
-	result, err := d.client.Service().secretsManager.GetSecretValue(&secretsmanager.GetSecretValueInput{
+	result, err := d.client.Service().secretsManager.GetSecretValueWithContext(tracing.Context(logger), &secretsmanager.GetSecretValueInput{
 		SecretId: &installationSecretName,
 	})
 	if err != nil {
@@ -421,7 +422,7 @@ func (d *RDSMultitenantDatabase) getMultitenantDatabasesFromResourceTags(vpcID s
 			},
 		},
 		ResourceTypeFilters: []*string{aws.String(DefaultResourceTypeClusterRDS)},
-	})
+	}, logger)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get available multitenant RDS resources")
 	}
@@ -450,7 +451,7 @@ func (d *RDSMultitenantDatabase) getMultitenantDatabasesFromResourceTags(vpcID s
 				DatabaseType: d.databaseType,
 			}
 
-			ready, err := d.isRDSClusterEndpointsReady(*rdsClusterID)
+			ready, err := d.isRDSClusterEndpointsReady(*rdsClusterID, logger)
 			if err != nil {
 				logger.WithError(err).Errorf("Failed to check RDS cluster status. Skipping RDS cluster ID %s", *rdsClusterID)
 				continue
@@ -502,8 +503,8 @@ func (d *RDSMultitenantDatabase) getRDSClusterIDFromResourceTags(resourceTags []
 	return nil, nil
 }
 
-func (d *RDSMultitenantDatabase) updateCounterTag(resourceARN *string, counter int) error {
-	_, err := d.client.Service().rds.AddTagsToResource(&rds.AddTagsToResourceInput{
+func (d *RDSMultitenantDatabase) updateCounterTag(resourceARN *string, counter int, logger log.FieldLogger) error {
+	_, err := d.client.Service().rds.AddTagsToResourceWithContext(tracing.Context(logger), &rds.AddTagsToResourceInput{
 		ResourceName: resourceARN,
 		Tags: []*rds.Tag{
 			{
@@ -519,7 +520,7 @@ func (d *RDSMultitenantDatabase) updateCounterTag(resourceARN *string, counter i
 	return nil
 }
 
-func (d *RDSMultitenantDatabase) createInstallationSecret(secretName, username, description string, tags []*secretsmanager.Tag) (*RDSSecret, error) {
+func (d *RDSMultitenantDatabase) createInstallationSecret(secretName, username, description string, tags []*secretsmanager.Tag, logger log.FieldLogger) (*RDSSecret, error) {
 	rdsSecretPayload := RDSSecret{
 		MasterUsername: username,
 		MasterPassword: newRandomPassword(40),
@@ -534,7 +535,7 @@ func (d *RDSMultitenantDatabase) createInstallationSecret(secretName, username,
 		return nil, errors.Wrap(err, "failed to marshal secrets manager payload")
 	}
 
-	_, err = d.client.Service().secretsManager.CreateSecret(&secretsmanager.CreateSecretInput{
+	_, err = d.client.Service().secretsManager.CreateSecretWithContext(tracing.Context(logger), &secretsmanager.CreateSecretInput{
 		Name:         aws.String(secretName),
 		Description:  aws.String(description),
 		Tags:         tags,
@@ -547,8 +548,8 @@ func (d *RDSMultitenantDatabase) createInstallationSecret(secretName, username,
 	return &rdsSecretPayload, nil
 }
 
-func (d *RDSMultitenantDatabase) describeRDSCluster(dbClusterID string) (*rds.DBCluster, error) {
-	dbClusterOutput, err := d.client.Service().rds.DescribeDBClusters(&rds.DescribeDBClustersInput{
+func (d *RDSMultitenantDatabase) describeRDSCluster(dbClusterID string, logger log.FieldLogger) (*rds.DBCluster, error) {
+	dbClusterOutput, err := d.client.Service().rds.DescribeDBClustersWithContext(tracing.Context(logger), &rds.DescribeDBClustersInput{
 		Filters: []*rds.Filter{
 			{
 				Name:   aws.String("db-cluster-id"),
@@ -614,7 +615,7 @@ func (d *RDSMultitenantDatabase) validateMultitenantDatabaseInstallations(multit
 // removeInstallationFromMultitenantDatabase performs the work necessary to
 // remove a single installation database from a multitenant RDS cluster.
 func (d *RDSMultitenantDatabase) removeInstallationFromMultitenantDatabase(database *model.MultitenantDatabase, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
-	rdsCluster, err := d.describeRDSCluster(database.ID)
+	rdsCluster, err := d.describeRDSCluster(database.ID, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to describe multitenant database")
 	}
@@ -629,7 +630,7 @@ func (d *RDSMultitenantDatabase) removeInstallationFromMultitenantDatabase(datab
 
 	numInstallations := database.Installations.Count()
 
-	err = d.updateCounterTag(rdsCluster.DBClusterArn, numInstallations-1)
+	err = d.updateCounterTag(rdsCluster.DBClusterArn, numInstallations-1, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to update counter tag")
 	}
@@ -642,7 +643,7 @@ func (d *RDSMultitenantDatabase) removeInstallationFromMultitenantDatabase(datab
 	err = store.UpdateMultitenantDatabase(database)
 	if err != nil {
 		logger.WithError(err).Warnf("Failed to remove multitenant database from datastore. Rolling tag:counter value back to %d", numInstallations)
-		updateTagErr := d.updateCounterTag(rdsCluster.DBClusterArn, numInstallations)
+		updateTagErr := d.updateCounterTag(rdsCluster.DBClusterArn, numInstallations, logger)
 		if updateTagErr != nil {
 			logger.WithError(err).Errorf("Failed to roll back tag:counter. Value is still %d", numInstallations-1)
 		}
@@ -655,7 +656,7 @@ func (d *RDSMultitenantDatabase) removeInstallationFromMultitenantDatabase(datab
 func (d *RDSMultitenantDatabase) dropDatabaseAndDeleteSecret(rdsClusterID, rdsClusterendpoint string, store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) error {
 	databaseName := MattermostRDSDatabaseName(d.installationID)
 
-	masterSecretValue, err := d.client.Service().secretsManager.GetSecretValue(&secretsmanager.GetSecretValueInput{
+	masterSecretValue, err := d.client.Service().secretsManager.GetSecretValueWithContext(tracing.Context(logger), &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(rdsClusterID),
 	})
 	if err != nil {
@@ -678,7 +679,7 @@ func (d *RDSMultitenantDatabase) dropDatabaseAndDeleteSecret(rdsClusterID, rdsCl
 
 	multitenantDatabaseSecretName := RDSMultitenantSecretName(d.installationID)
 
-	_, err = d.client.Service().secretsManager.DeleteSecret(&secretsmanager.DeleteSecretInput{
+	_, err = d.client.Service().secretsManager.DeleteSecretWithContext(tracing.Context(logger), &secretsmanager.DeleteSecretInput{
 		SecretId: aws.String(multitenantDatabaseSecretName),
 	})
 	if err != nil && !IsErrorCode(err, secretsmanager.ErrCodeResourceNotFoundException) {
@@ -688,10 +689,10 @@ func (d *RDSMultitenantDatabase) dropDatabaseAndDeleteSecret(rdsClusterID, rdsCl
 	return nil
 }
 
-func (d *RDSMultitenantDatabase) ensureMultitenantDatabaseSecretIsCreated(rdsClusterID, VpcID *string) (*RDSSecret, error) {
+func (d *RDSMultitenantDatabase) ensureMultitenantDatabaseSecretIsCreated(rdsClusterID, VpcID *string, logger log.FieldLogger) (*RDSSecret, error) {
 	installationSecretName := RDSMultitenantSecretName(d.installationID)
 
-	installationSecretValue, err := d.client.Service().secretsManager.GetSecretValue(&secretsmanager.GetSecretValueInput{
+	installationSecretValue, err := d.client.Service().secretsManager.GetSecretValueWithContext(tracing.Context(logger), &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(installationSecretName),
 	})
 	if err != nil && !IsErrorCode(err, secretsmanager.ErrCodeResourceNotFoundException) {
@@ -725,7 +726,7 @@ func (d *RDSMultitenantDatabase) ensureMultitenantDatabaseSecretIsCreated(rdsClu
 		// valid just in case. Name can't be longer than 32 characters for MySQL
 		// databases though.
 		username := fmt.Sprintf("user_%s", d.installationID)
-		installationSecret, err = d.createInstallationSecret(installationSecretName, username, description, tags)
+		installationSecret, err = d.createInstallationSecret(installationSecretName, username, description, tags, logger)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to create a multitenant RDS database secret %s", installationSecretName)
 		}
@@ -734,8 +735,8 @@ func (d *RDSMultitenantDatabase) ensureMultitenantDatabaseSecretIsCreated(rdsClu
 	return installationSecret, nil
 }
 
-func (d *RDSMultitenantDatabase) isRDSClusterEndpointsReady(rdsClusterID string) (bool, error) {
-	output, err := d.client.service.rds.DescribeDBClusterEndpoints(&rds.DescribeDBClusterEndpointsInput{
+func (d *RDSMultitenantDatabase) isRDSClusterEndpointsReady(rdsClusterID string, logger log.FieldLogger) (bool, error) {
+	output, err := d.client.service.rds.DescribeDBClusterEndpointsWithContext(tracing.Context(logger), &rds.DescribeDBClusterEndpointsInput{
 		DBClusterIdentifier: aws.String(rdsClusterID),
 	})
 	if err != nil {
@@ -754,7 +755,7 @@ func (d *RDSMultitenantDatabase) isRDSClusterEndpointsReady(rdsClusterID string)
 func (d *RDSMultitenantDatabase) runProvisionSQLCommands(installationDatabaseName, vpcID string, rdsCluster *rds.DBCluster, logger log.FieldLogger) error {
 	rdsID := *rdsCluster.DBClusterIdentifier
 
-	masterSecretValue, err := d.client.Service().secretsManager.GetSecretValue(&secretsmanager.GetSecretValueInput{
+	masterSecretValue, err := d.client.Service().secretsManager.GetSecretValueWithContext(tracing.Context(logger), &secretsmanager.GetSecretValueInput{
 		SecretId: rdsCluster.DBClusterIdentifier,
 	})
 	if err != nil {
@@ -775,7 +776,7 @@ func (d *RDSMultitenantDatabase) runProvisionSQLCommands(installationDatabaseNam
 		return errors.Wrapf(err, "failed to create schema in multitenant RDS cluster %s", rdsID)
 	}
 
-	installationSecret, err := d.ensureMultitenantDatabaseSecretIsCreated(rdsCluster.DBClusterIdentifier, &vpcID)
+	installationSecret, err := d.ensureMultitenantDatabaseSecretIsCreated(rdsCluster.DBClusterIdentifier, &vpcID, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to get a secret for installation")
 	}
@@ -51,7 +51,7 @@ func (a *AWSTestSuite) TestProvisioningMultitenantDatabase() {
 			Times(1),
 
 		// Find the VPC which the installation belongs to.
-		a.Mocks.API.EC2.EXPECT().DescribeVpcs(gomock.Any()).
+		a.Mocks.API.EC2.EXPECT().DescribeVpcsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{VpcId: &a.VPCa}}}, nil).
 			Times(1),
 
@@ -76,8 +76,8 @@ func (a *AWSTestSuite) TestProvisioningMultitenantDatabase() {
 
 		// Get resources from AWS and try to find a RDS cluster that the database can be created.
 		a.Mocks.API.ResourceGroupsTagging.EXPECT().
-			GetResources(gomock.Any()).
-			Do(func(input *gt.GetResourcesInput) {
+			GetResourcesWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *gt.GetResourcesInput) {
 				a.Assert().Equal(input.ResourceTypeFilters, []*string{aws.String(DefaultResourceTypeClusterRDS)})
 				tagFilter := []*gt.TagFilter{
 					{
@@ -156,7 +156,7 @@ func (a *AWSTestSuite) TestProvisioningMultitenantDatabase() {
 			}, nil),
 
 		a.Mocks.API.RDS.EXPECT().
-			DescribeDBClusterEndpoints(gomock.Any()).
+			DescribeDBClusterEndpointsWithContext(gomock.Any(), gomock.Any()).
 			Return(&rds.DescribeDBClusterEndpointsOutput{
 				DBClusterEndpoints: []*rds.DBClusterEndpoint{
 					{
@@ -196,8 +196,8 @@ func (a *AWSTestSuite) TestProvisioningMultitenantDatabase() {
 			Times(1),
 
 		a.Mocks.API.RDS.EXPECT().
-			DescribeDBClusters(gomock.Any()).
-			Do(func(input *rds.DescribeDBClustersInput) {
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *rds.DescribeDBClustersInput) {
 				a.Assert().Equal(input.Filters, []*rds.Filter{
 					{
 						Name:   aws.String("db-cluster-id"),
@@ -217,8 +217,8 @@ func (a *AWSTestSuite) TestProvisioningMultitenantDatabase() {
 			Times(1),
 
 		a.Mocks.API.SecretsManager.EXPECT().
-			GetSecretValue(gomock.Any()).
-			Do(func(input *secretsmanager.GetSecretValueInput) {
+			GetSecretValueWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *secretsmanager.GetSecretValueInput) {
 
 			}).
 			Return(&secretsmanager.GetSecretValueOutput{
@@ -54,21 +54,21 @@ func (a *AWSTestSuite) TestProvisioningRDSAcceptance() {
 			Times(1),
 
 		// Find the VPC which the installation belongs to.
-		a.Mocks.API.EC2.EXPECT().DescribeVpcs(gomock.Any()).
+		a.Mocks.API.EC2.EXPECT().DescribeVpcsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{VpcId: &a.VPCa}}}, nil).
 			Times(1),
 
 		// Create a database secret.
 		a.Mocks.API.SecretsManager.EXPECT().
-			GetSecretValue(gomock.Any()).
+			GetSecretValueWithContext(gomock.Any(), gomock.Any()).
 			Return(&secretsmanager.GetSecretValueOutput{SecretString: &a.SecretString}, nil).
 			Times(1),
 
 		// Create encryption key since none has been created yet.
 		a.Mocks.API.ResourceGroupsTagging.EXPECT().
-			GetResources(gomock.Any()).
+			GetResourcesWithContext(gomock.Any(), gomock.Any()).
 			Return(&gt.GetResourcesOutput{}, nil).
-			Do(func(input *gt.GetResourcesInput) {
+			Do(func(_ aws.Context, input *gt.GetResourcesInput) {
 				a.Assert().Equal(DefaultRDSEncryptionTagKey, *input.TagFilters[0].Key)
 				a.Assert().Equal(CloudID(a.InstallationA.ID), *input.TagFilters[0].Values[0])
 				a.Assert().Nil(input.PaginationToken)
@@ -76,8 +76,8 @@ func (a *AWSTestSuite) TestProvisioningRDSAcceptance() {
 			Times(1),
 
 		a.Mocks.API.KMS.EXPECT().
-			CreateKey(gomock.Any()).
-			Do(func(input *kms.CreateKeyInput) {
+			CreateKeyWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *kms.CreateKeyInput) {
 				a.Assert().Equal(*input.Tags[0].TagKey, DefaultRDSEncryptionTagKey)
 				a.Assert().Equal(*input.Tags[0].TagValue, CloudID(a.InstallationA.ID))
 			}).
@@ -91,7 +91,7 @@ func (a *AWSTestSuite) TestProvisioningRDSAcceptance() {
 			Times(1),
 
 		// Retrive the Availability Zones.
-		a.Mocks.API.EC2.EXPECT().DescribeAvailabilityZones(gomock.Any()).
+		a.Mocks.API.EC2.EXPECT().DescribeAvailabilityZonesWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: []*ec2.AvailabilityZone{{ZoneName: aws.String("us-honk-1a")}, {ZoneName: aws.String("us-honk-1b")}}}, nil).
 			Times(1),
 	)
@@ -130,21 +130,21 @@ func (a *AWSTestSuite) TestProvisioningRDSWithExistentEncryptionKey() {
 			Times(1),
 
 		// Find the VPC which the installation belongs to.
-		a.Mocks.API.EC2.EXPECT().DescribeVpcs(gomock.Any()).
+		a.Mocks.API.EC2.EXPECT().DescribeVpcsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeVpcsOutput{Vpcs: []*ec2.Vpc{{VpcId: &a.VPCa}}}, nil).
 			Times(1),
 
 		// Create a database secret.
 		a.Mocks.API.SecretsManager.EXPECT().
-			GetSecretValue(gomock.Any()).
+			GetSecretValueWithContext(gomock.Any(), gomock.Any()).
 			Return(&secretsmanager.GetSecretValueOutput{SecretString: &a.SecretString}, nil).
 			Times(1),
 
 		// Get encryption key associated with this installation. This step assumes that
 		// the key already exists.
 		a.Mocks.API.ResourceGroupsTagging.EXPECT().
-			GetResources(gomock.Any()).
-			Do(func(input *gt.GetResourcesInput) {
+			GetResourcesWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *gt.GetResourcesInput) {
 				a.Assert().Equal(DefaultRDSEncryptionTagKey, *input.TagFilters[0].Key)
 				a.Assert().Equal(CloudID(a.InstallationA.ID), *input.TagFilters[0].Values[0])
 				a.Assert().Nil(input.PaginationToken)
@@ -159,7 +159,7 @@ func (a *AWSTestSuite) TestProvisioningRDSWithExistentEncryptionKey() {
 			Times(1),
 
 		a.Mocks.API.KMS.EXPECT().
-			DescribeKey(gomock.Any()).
+			DescribeKeyWithContext(gomock.Any(), gomock.Any()).
 			Return(&kms.DescribeKeyOutput{
 				KeyMetadata: &kms.KeyMetadata{
 					Arn:      aws.String(a.ResourceARN),
@@ -167,13 +167,13 @@ func (a *AWSTestSuite) TestProvisioningRDSWithExistentEncryptionKey() {
 					KeyState: aws.String(kms.KeyStateEnabled),
 				},
 			}, nil).
-			Do(func(input *kms.DescribeKeyInput) {
+			Do(func(_ aws.Context, input *kms.DescribeKeyInput) {
 				a.Assert().Equal(*input.KeyId, a.ResourceARN)
 			}).
 			Times(1),
 
 		// Retrive the Availability Zones.
-		a.Mocks.API.EC2.EXPECT().DescribeAvailabilityZones(gomock.Any()).
+		a.Mocks.API.EC2.EXPECT().DescribeAvailabilityZonesWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: []*ec2.AvailabilityZone{{ZoneName: aws.String("us-honk-1a")}, {ZoneName: aws.String("us-honk-1b")}}}, nil).
 			Times(1),
 	)
@@ -201,8 +201,8 @@ func (a *AWSTestSuite) TestSnapshot() {
 			Return(testlib.NewLoggerEntry()).
 			Times(1),
 
-		a.Mocks.API.RDS.EXPECT().CreateDBClusterSnapshot(gomock.Any()).
-			Return(&rds.CreateDBClusterSnapshotOutput{}, nil).Do(func(input *rds.CreateDBClusterSnapshotInput) {
+		a.Mocks.API.RDS.EXPECT().CreateDBClusterSnapshotWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.CreateDBClusterSnapshotOutput{}, nil).Do(func(_ aws.Context, input *rds.CreateDBClusterSnapshotInput) {
 			a.Assert().Equal(*input.DBClusterIdentifier, CloudID(a.ClusterA.ID))
 			a.Assert().True(strings.Contains(*input.DBClusterSnapshotIdentifier, fmt.Sprintf("%s-snapshot-", a.ClusterA.ID)))
 			a.Assert().Greater(len(input.Tags), 0)
@@ -237,7 +237,7 @@ func (a *AWSTestSuite) TestSnapshotError() {
 			Times(1),
 
 		a.Mocks.API.RDS.EXPECT().
-			CreateDBClusterSnapshot(gomock.Any()).
+			CreateDBClusterSnapshotWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("database is not stable")).
 			Times(1),
 
@@ -253,25 +253,106 @@ func (a *AWSTestSuite) TestSnapshotError() {
 	a.Assert().Equal("failed to create a DB cluster snapshot: database is not stable", err.Error())
 }
 
+func (a *AWSTestSuite) TestIsAvailable() {
+	database := RDSDatabase{
+		databaseType:   model.DatabaseEngineTypeMySQL,
+		installationID: a.InstallationA.ID,
+		client:         a.Mocks.AWS,
+	}
+
+	a.Run("cluster not available", func() {
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+			Return(&rds.DescribeDBClustersOutput{
+				DBClusters: []*rds.DBCluster{{Status: aws.String("creating")}},
+			}, nil).
+			Do(func(_ aws.Context, input *rds.DescribeDBClustersInput) {
+				a.Assert().Equal(CloudID(a.InstallationA.ID), *input.DBClusterIdentifier)
+			}).
+			Times(1)
+
+		available, err := database.IsAvailable(a.Mocks.Log.Logger)
+		a.Require().NoError(err)
+		a.Assert().False(available)
+	})
+
+	a.Run("instance not available", func() {
+		gomock.InOrder(
+			a.Mocks.API.RDS.EXPECT().
+				DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+				Return(&rds.DescribeDBClustersOutput{
+					DBClusters: []*rds.DBCluster{{Status: aws.String(DefaultRDSStatusAvailable)}},
+				}, nil).
+				Times(1),
+
+			a.Mocks.API.RDS.EXPECT().
+				DescribeDBInstancesWithContext(gomock.Any(), gomock.Any()).
+				Return(&rds.DescribeDBInstancesOutput{
+					DBInstances: []*rds.DBInstance{{DBInstanceStatus: aws.String("creating")}},
+				}, nil).
+				Do(func(_ aws.Context, input *rds.DescribeDBInstancesInput) {
+					a.Assert().Equal(RDSMasterInstanceID(a.InstallationA.ID), *input.DBInstanceIdentifier)
+				}).
+				Times(1),
+		)
+
+		available, err := database.IsAvailable(a.Mocks.Log.Logger)
+		a.Require().NoError(err)
+		a.Assert().False(available)
+	})
+
+	a.Run("cluster and instance available", func() {
+		gomock.InOrder(
+			a.Mocks.API.RDS.EXPECT().
+				DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+				Return(&rds.DescribeDBClustersOutput{
+					DBClusters: []*rds.DBCluster{{Status: aws.String(DefaultRDSStatusAvailable)}},
+				}, nil).
+				Times(1),
+
+			a.Mocks.API.RDS.EXPECT().
+				DescribeDBInstancesWithContext(gomock.Any(), gomock.Any()).
+				Return(&rds.DescribeDBInstancesOutput{
+					DBInstances: []*rds.DBInstance{{DBInstanceStatus: aws.String(DefaultRDSStatusAvailable)}},
+				}, nil).
+				Times(1),
+		)
+
+		available, err := database.IsAvailable(a.Mocks.Log.Logger)
+		a.Require().NoError(err)
+		a.Assert().True(available)
+	})
+
+	a.Run("describe cluster fails", func() {
+		a.Mocks.API.RDS.EXPECT().
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
+			Return(nil, errors.New("throttled")).
+			Times(1)
+
+		_, err := database.IsAvailable(a.Mocks.Log.Logger)
+		a.Require().EqualError(err, "failed to describe DB cluster: throttled")
+	})
+}
+
 // Helpers
 
 // This whole block deals with RDS DB Cluster creation.
 func (a *AWSTestSuite) SetExpectCreateDBCluster() {
 	gomock.InOrder(
 		a.Mocks.API.RDS.EXPECT().
-			DescribeDBClusters(gomock.Any()).
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("db cluster does not exist")).
 			Times(1),
 
 		a.Mocks.API.EC2.EXPECT().
-			DescribeSecurityGroups(gomock.Any()).
+			DescribeSecurityGroupsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeSecurityGroupsOutput{
 				SecurityGroups: []*ec2.SecurityGroup{{GroupId: &a.GroupID}},
 			}, nil).
 			Times(1),
 
 		a.Mocks.API.RDS.EXPECT().
-			DescribeDBSubnetGroups(gomock.Any()).
+			DescribeDBSubnetGroupsWithContext(gomock.Any(), gomock.Any()).
 			Return(&rds.DescribeDBSubnetGroupsOutput{
 				DBSubnetGroups: []*rds.DBSubnetGroup{
 					{
@@ -282,8 +363,8 @@ func (a *AWSTestSuite) SetExpectCreateDBCluster() {
 			Times(1),
 
 		a.Mocks.API.RDS.EXPECT().
-			CreateDBCluster(gomock.Any()).
-			Do(func(input *rds.CreateDBClusterInput) {
+			CreateDBClusterWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *rds.CreateDBClusterInput) {
 				for _, zone := range input.AvailabilityZones {
 					a.Assert().Contains(a.RDSAvailabilityZones, *zone)
 				}
@@ -300,15 +381,15 @@ func (a *AWSTestSuite) SetExpectCreateDBCluster() {
 func (a *AWSTestSuite) SetExpectCreateDBInstance() {
 	gomock.InOrder(
 		a.Mocks.API.RDS.EXPECT().
-			DescribeDBInstances(gomock.Any()).
+			DescribeDBInstancesWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("db cluster instance does not exist")).
-			Do(func(input *rds.DescribeDBInstancesInput) {
+			Do(func(_ aws.Context, input *rds.DescribeDBInstancesInput) {
 				a.Assert().Equal(*input.DBInstanceIdentifier, RDSMasterInstanceID(a.InstallationA.ID))
 			}),
 
 		a.Mocks.API.RDS.EXPECT().
-			CreateDBInstance(gomock.Any()).Return(nil, nil).
-			Do(func(input *rds.CreateDBInstanceInput) {
+			CreateDBInstanceWithContext(gomock.Any(), gomock.Any()).Return(nil, nil).
+			Do(func(_ aws.Context, input *rds.CreateDBInstanceInput) {
 				a.Assert().Equal(*input.DBClusterIdentifier, CloudID(a.InstallationA.ID))
 				a.Assert().Equal(*input.DBInstanceIdentifier, RDSMasterInstanceID(a.InstallationA.ID))
 			}).
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -15,7 +16,7 @@ import (
 // DynamoDBEnsureTableDeleted is used to check if DynamoDB table exists and delete it.
 func (a *Client) DynamoDBEnsureTableDeleted(tableName string, logger log.FieldLogger) error {
 	// First check if table still exists.
-	_, err := a.Service().dynamodb.DescribeTable(&dynamodb.DescribeTableInput{
+	_, err := a.Service().dynamodb.DescribeTableWithContext(tracing.Context(logger), &dynamodb.DescribeTableInput{
 		TableName: aws.String(tableName),
 	})
 	if aerr, ok := err.(awserr.Error); ok {
@@ -25,7 +26,7 @@ func (a *Client) DynamoDBEnsureTableDeleted(tableName string, logger log.FieldLo
 		}
 	}
 
-	_, err = a.Service().dynamodb.DeleteTable(&dynamodb.DeleteTableInput{
+	_, err = a.Service().dynamodb.DeleteTableWithContext(tracing.Context(logger), &dynamodb.DeleteTableInput{
 		TableName: aws.String(tableName),
 	})
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -20,7 +21,7 @@ func (a *Client) TagResource(resourceID, key, value string, logger log.FieldLogg
 		return errors.New("Missing resource ID")
 	}
 
-	resp, err := a.Service().ec2.CreateTags(&ec2.CreateTagsInput{
+	resp, err := a.Service().ec2.CreateTagsWithContext(tracing.Context(logger), &ec2.CreateTagsInput{
 		Resources: []*string{
 			aws.String(resourceID),
 		},
@@ -49,7 +50,7 @@ func (a *Client) UntagResource(resourceID, key, value string, logger log.FieldLo
 		return errors.New("unable to remove AWS tag from resource: missing resource ID")
 	}
 
-	resp, err := a.Service().ec2.DeleteTags(&ec2.DeleteTagsInput{
+	resp, err := a.Service().ec2.DeleteTagsWithContext(tracing.Context(logger), &ec2.DeleteTagsInput{
 		Resources: []*string{
 			aws.String(resourceID),
 		},
@@ -79,7 +80,7 @@ func (a *Client) IsValidAMI(AMIImage string, logger log.FieldLogger) (bool, erro
 		return true, nil
 	}
 
-	out, err := a.Service().ec2.DescribeImages(&ec2.DescribeImagesInput{
+	out, err := a.Service().ec2.DescribeImagesWithContext(tracing.Context(logger), &ec2.DescribeImagesInput{
 		Filters: []*ec2.Filter{
 			{
 				Name:   aws.String("image-id"),
@@ -98,8 +99,8 @@ func (a *Client) IsValidAMI(AMIImage string, logger log.FieldLogger) (bool, erro
 }
 
 // GetVpcsWithFilters returns VPCs matching a given filter.
-func (a *Client) GetVpcsWithFilters(filters []*ec2.Filter) ([]*ec2.Vpc, error) {
-	vpcOutput, err := a.Service().ec2.DescribeVpcs(&ec2.DescribeVpcsInput{
+func (a *Client) GetVpcsWithFilters(filters []*ec2.Filter, logger log.FieldLogger) ([]*ec2.Vpc, error) {
+	vpcOutput, err := a.Service().ec2.DescribeVpcsWithContext(tracing.Context(logger), &ec2.DescribeVpcsInput{
 		Filters: filters,
 	})
 	if err != nil {
@@ -110,8 +111,8 @@ func (a *Client) GetVpcsWithFilters(filters []*ec2.Filter) ([]*ec2.Vpc, error) {
 }
 
 // GetSubnetsWithFilters returns subnets matching a given filter.
-func (a *Client) GetSubnetsWithFilters(filters []*ec2.Filter) ([]*ec2.Subnet, error) {
-	subnetOutput, err := a.Service().ec2.DescribeSubnets(&ec2.DescribeSubnetsInput{
+func (a *Client) GetSubnetsWithFilters(filters []*ec2.Filter, logger log.FieldLogger) ([]*ec2.Subnet, error) {
+	subnetOutput, err := a.Service().ec2.DescribeSubnetsWithContext(tracing.Context(logger), &ec2.DescribeSubnetsInput{
 		Filters: filters,
 	})
 	if err != nil {
@@ -122,8 +123,8 @@ func (a *Client) GetSubnetsWithFilters(filters []*ec2.Filter) ([]*ec2.Subnet, er
 }
 
 // GetSecurityGroupsWithFilters returns SGs matching a given filter.
-func (a *Client) GetSecurityGroupsWithFilters(filters []*ec2.Filter) ([]*ec2.SecurityGroup, error) {
-	sgOutput, err := a.Service().ec2.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+func (a *Client) GetSecurityGroupsWithFilters(filters []*ec2.Filter, logger log.FieldLogger) ([]*ec2.SecurityGroup, error) {
+	sgOutput, err := a.Service().ec2.DescribeSecurityGroupsWithContext(tracing.Context(logger), &ec2.DescribeSecurityGroupsInput{
 		Filters: filters,
 	})
 	if err != nil {
@@ -23,7 +23,7 @@ func (a *AWSTestSuite) TestTagResource() {
 		WithFields(logrus.Fields{"tag-key": "tag-key", "tag-value": "tag-value"}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			CreateTags(gomock.Any()).
+			CreateTagsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.CreateTagsOutput{}, nil))
 
 	err := a.Mocks.AWS.TagResource(a.ResourceID, "tag-key", "tag-value", a.Mocks.Log.Logger)
@@ -35,7 +35,7 @@ func (a *AWSTestSuite) TestTagResourceError() {
 		WithFields(logrus.Fields{"tag-key": "tag-key", "tag-value": "tag-value"}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			CreateTags(gomock.Any()).
+			CreateTagsWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("invalid tag")))
 
 	err := a.Mocks.AWS.TagResource(a.ResourceID, "tag-key", "tag-value", a.Mocks.Log.Logger)
@@ -48,7 +48,7 @@ func (a *AWSTestSuite) TestUntagResource() {
 		WithFields(logrus.Fields{"tag-key": "tag-key", "tag-value": "tag-value"}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DeleteTags(gomock.Any()).
+			DeleteTagsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DeleteTagsOutput{}, nil))
 
 	err := a.Mocks.AWS.UntagResource(a.ResourceID, "tag-key", "tag-value", a.Mocks.Log.Logger)
@@ -60,7 +60,7 @@ func (a *AWSTestSuite) TestUntagResourceEmptyResourceID() {
 		WithFields(logrus.Fields{"tag-key": "tag-key", "tag-value": "tag-value"}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DeleteTags(gomock.Any()).
+			DeleteTagsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DeleteTagsOutput{}, nil))
 
 	err := a.Mocks.AWS.UntagResource("", "tag-key", "tag-value", a.Mocks.Log.Logger)
@@ -74,7 +74,7 @@ func (a *AWSTestSuite) TestUntagResourceError() {
 		WithFields(logrus.Fields{"tag-key": "tag-key", "tag-value": "tag-value"}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DeleteTags(gomock.Any()).
+			DeleteTagsWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("tag not found")))
 
 	err := a.Mocks.AWS.UntagResource(a.ResourceID, "tag-key", "tag-value", a.Mocks.Log.Logger)
@@ -88,7 +88,7 @@ func (a *AWSTestSuite) TestIsValidAMIEmptyResourceID() {
 		WithFields(logrus.Fields{}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DescribeImages(gomock.Any()).
+			DescribeImagesWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("tag not found")))
 
 	ok, err := a.Mocks.AWS.IsValidAMI("", a.Mocks.Log.Logger)
@@ -101,7 +101,7 @@ func (a *AWSTestSuite) TestIsValidAMI() {
 		WithFields(logrus.Fields{}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DescribeImages(gomock.Any()).
+			DescribeImagesWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeImagesOutput{
 				Images: make([]*ec2.Image, 2),
 			}, nil))
@@ -116,7 +116,7 @@ func (a *AWSTestSuite) TestIsValidAMINoImages() {
 		WithFields(logrus.Fields{}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DescribeImages(gomock.Any()).
+			DescribeImagesWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeImagesOutput{
 				Images: make([]*ec2.Image, 0),
 			}, nil))
@@ -132,7 +132,7 @@ func (a *AWSTestSuite) TestIsValidAMIError() {
 		WithFields(logrus.Fields{}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DescribeImages(gomock.Any()).
+			DescribeImagesWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("resource id not found")))
 
 	ok, err := a.Mocks.AWS.IsValidAMI(a.ResourceID, log.New())
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/model"
 	mmv1alpha1 "github.com/mattermost/mattermost-operator/apis/mattermost/v1alpha1"
 	"github.com/pkg/errors"
@@ -54,7 +55,7 @@ func (f *S3MultitenantFilestore) Teardown(keepData bool, store model.Installatio
 	})
 	logger.Info("Tearing down AWS S3 filestore")
 
-	bucketName, err := f.getMultitenantBucketName(store)
+	bucketName, err := f.getMultitenantBucketName(store, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to find multitenant bucket")
 	}
@@ -96,7 +97,7 @@ func (f *S3MultitenantFilestore) GenerateFilestoreSpecAndSecret(store model.Inst
 	})
 	logger.Debug("Generating S3 multitenant filestore information")
 
-	bucketName, err := f.getMultitenantBucketName(store)
+	bucketName, err := f.getMultitenantBucketName(store, logger)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to find multitenant bucket")
 	}
@@ -146,7 +147,7 @@ func (f *S3MultitenantFilestore) s3FilestoreProvision(store model.InstallationDa
 	})
 	logger.Info("Provisioning AWS multitenant S3 filestore")
 
-	bucketName, err := f.getMultitenantBucketName(store)
+	bucketName, err := f.getMultitenantBucketName(store, logger)
 	if err != nil {
 		return errors.Wrap(err, "failed to find multitenant bucket")
 	}
@@ -193,12 +194,12 @@ func (f *S3MultitenantFilestore) s3FilestoreProvision(store model.InstallationDa
 	return nil
 }
 
-func (f *S3MultitenantFilestore) getMultitenantBucketName(store model.InstallationDatabaseStoreInterface) (string, error) {
+func (f *S3MultitenantFilestore) getMultitenantBucketName(store model.InstallationDatabaseStoreInterface, logger log.FieldLogger) (string, error) {
 	envName, err := f.awsClient.GetCloudEnvironmentName()
 	if err != nil {
 		return "", errors.Wrap(err, "failed to get cloud environment name")
 	}
-	vpc, err := getVPCForInstallation(f.installationID, store, f.awsClient)
+	vpc, err := getVPCForInstallation(f.installationID, store, f.awsClient, logger)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to find cluster installation VPC")
 	}
@@ -206,7 +207,7 @@ func (f *S3MultitenantFilestore) getMultitenantBucketName(store model.Installati
 	bucketName := MattermostMultitenantS3Name(envName, *vpc.VpcId)
 
 	// Ensure the bucket exists and that the tags are correct.
-	tags, err := f.awsClient.Service().s3.GetBucketTagging(&s3.GetBucketTaggingInput{
+	tags, err := f.awsClient.Service().s3.GetBucketTaggingWithContext(tracing.Context(logger), &s3.GetBucketTaggingInput{
 		Bucket: aws.String(bucketName),
 	})
 	if aerr, ok := err.(awserr.Error); ok {
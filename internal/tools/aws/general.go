@@ -6,13 +6,15 @@ package aws
 
 import (
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 )
 
 // getAvailabilityZones retrive the Availabitly zones for the AWS region set in the Client.
-func (a *Client) getAvailabilityZones() ([]*string, error) {
-	resp, err := a.Service().ec2.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{})
+func (a *Client) getAvailabilityZones(logger log.FieldLogger) ([]*string, error) {
+	resp, err := a.Service().ec2.DescribeAvailabilityZonesWithContext(tracing.Context(logger), &ec2.DescribeAvailabilityZonesInput{})
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to get the AWS availabity zones for region %s", *a.config.Region)
 	}
@@ -15,6 +15,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
 // CloudID returns the standard ID used for AWS resource names. This ID is used
@@ -81,6 +82,24 @@ func RDSMigrationInstanceID(installationID string) string {
 	return fmt.Sprintf("%s-migration", CloudID(installationID))
 }
 
+// RDSBackupSnapshotID formats the name used for the RDS database snapshot of
+// an installation backup.
+func RDSBackupSnapshotID(installationID, backupID string) string {
+	return fmt.Sprintf("%s-backup-%s", CloudID(installationID), backupID)
+}
+
+// RDSPreRestoreSnapshotID formats the name used for the final RDS database
+// snapshot taken before the given restoration of an installation backup.
+func RDSPreRestoreSnapshotID(installationID, restoreID string) string {
+	return fmt.Sprintf("%s-pre-restore-%s", CloudID(installationID), restoreID)
+}
+
+// MattermostBackupS3Name formats the name of the S3 bucket holding
+// installation filestore backups.
+func MattermostBackupS3Name(environmentName string) string {
+	return fmt.Sprintf("mattermost-cloud-%s-backups", environmentName)
+}
+
 // IsErrorCode asserts that an AWS error has a certain code.
 func IsErrorCode(err error, code string) bool {
 	if err != nil {
@@ -152,7 +171,7 @@ func RDSMultitenantClusterSecretDescription(installationID, rdsClusterID string)
 // getVPCForInstallation returns a single VPC that the cluster installation of
 // the provided installation resides in. Installations with multiple cluster
 // installations are currently not supported.
-func getVPCForInstallation(installationID string, store model.InstallationDatabaseStoreInterface, client *Client) (*ec2.Vpc, error) {
+func getVPCForInstallation(installationID string, store model.InstallationDatabaseStoreInterface, client *Client, logger log.FieldLogger) (*ec2.Vpc, error) {
 	clusterInstallations, err := store.GetClusterInstallations(&model.ClusterInstallationFilter{
 		PerPage:        model.AllPerPage,
 		InstallationID: installationID,
@@ -179,7 +198,7 @@ func getVPCForInstallation(installationID string, store model.InstallationDataba
 			Name:   aws.String(VpcAvailableTagKey),
 			Values: []*string{aws.String(VpcAvailableTagValueFalse)},
 		},
-	})
+	}, logger)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to lookup VPC for installation %s", installationID)
 	}
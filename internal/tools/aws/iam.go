@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -31,7 +32,7 @@ type policyStatementEntry struct {
 type policyStatementCondition map[string]map[string][]string
 
 func (a *Client) iamEnsureUserCreated(awsID string, logger log.FieldLogger) (*iam.User, error) {
-	getResult, err := a.Service().iam.GetUser(&iam.GetUserInput{
+	getResult, err := a.Service().iam.GetUserWithContext(tracing.Context(logger), &iam.GetUserInput{
 		UserName: aws.String(awsID),
 	})
 	if err == nil {
@@ -46,7 +47,7 @@ func (a *Client) iamEnsureUserCreated(awsID string, logger log.FieldLogger) (*ia
 		return nil, err
 	}
 
-	createResult, err := a.Service().iam.CreateUser(&iam.CreateUserInput{
+	createResult, err := a.Service().iam.CreateUserWithContext(tracing.Context(logger), &iam.CreateUserInput{
 		UserName: aws.String(awsID),
 	})
 	if err != nil {
@@ -59,7 +60,7 @@ func (a *Client) iamEnsureUserCreated(awsID string, logger log.FieldLogger) (*ia
 }
 
 func (a *Client) iamEnsureUserDeleted(awsID string, logger log.FieldLogger) error {
-	_, err := a.Service().iam.GetUser(&iam.GetUserInput{
+	_, err := a.Service().iam.GetUserWithContext(tracing.Context(logger), &iam.GetUserInput{
 		UserName: aws.String(awsID),
 	})
 	if err != nil {
@@ -74,14 +75,14 @@ func (a *Client) iamEnsureUserDeleted(awsID string, logger log.FieldLogger) erro
 		return err
 	}
 
-	policyResult, err := a.Service().iam.ListAttachedUserPolicies(&iam.ListAttachedUserPoliciesInput{
+	policyResult, err := a.Service().iam.ListAttachedUserPoliciesWithContext(tracing.Context(logger), &iam.ListAttachedUserPoliciesInput{
 		UserName: aws.String(awsID),
 	})
 	if err != nil {
 		return err
 	}
 	for _, policy := range policyResult.AttachedPolicies {
-		_, err = a.Service().iam.DetachUserPolicy(&iam.DetachUserPolicyInput{
+		_, err = a.Service().iam.DetachUserPolicyWithContext(tracing.Context(logger), &iam.DetachUserPolicyInput{
 			PolicyArn: policy.PolicyArn,
 			UserName:  aws.String(awsID),
 		})
@@ -94,7 +95,7 @@ func (a *Client) iamEnsureUserDeleted(awsID string, logger log.FieldLogger) erro
 			"iam-policy-name": *policy.PolicyName,
 		}).Debug("AWS IAM policy detached from user")
 
-		_, err = a.Service().iam.DeletePolicy(&iam.DeletePolicyInput{
+		_, err = a.Service().iam.DeletePolicyWithContext(tracing.Context(logger), &iam.DeletePolicyInput{
 			PolicyArn: policy.PolicyArn,
 		})
 		if err != nil {
@@ -107,14 +108,14 @@ func (a *Client) iamEnsureUserDeleted(awsID string, logger log.FieldLogger) erro
 		}).Debug("AWS IAM policy deleted")
 	}
 
-	accessKeyResult, err := a.Service().iam.ListAccessKeys(&iam.ListAccessKeysInput{
+	accessKeyResult, err := a.Service().iam.ListAccessKeysWithContext(tracing.Context(logger), &iam.ListAccessKeysInput{
 		UserName: aws.String(awsID),
 	})
 	if err != nil {
 		return err
 	}
 	for _, ak := range accessKeyResult.AccessKeyMetadata {
-		_, err = a.Service().iam.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+		_, err = a.Service().iam.DeleteAccessKeyWithContext(tracing.Context(logger), &iam.DeleteAccessKeyInput{
 			AccessKeyId: ak.AccessKeyId,
 			UserName:    aws.String(awsID),
 		})
@@ -128,7 +129,7 @@ func (a *Client) iamEnsureUserDeleted(awsID string, logger log.FieldLogger) erro
 		}).Debug("AWS IAM user access key deleted")
 	}
 
-	_, err = a.Service().iam.DeleteUser(&iam.DeleteUserInput{
+	_, err = a.Service().iam.DeleteUserWithContext(tracing.Context(logger), &iam.DeleteUserInput{
 		UserName: aws.String(awsID),
 	})
 	if err != nil {
@@ -141,7 +142,7 @@ func (a *Client) iamEnsureUserDeleted(awsID string, logger log.FieldLogger) erro
 }
 
 func (a *Client) iamEnsureS3PolicyCreated(awsID, policyARN, bucketName, permittedDirectory string, logger log.FieldLogger) (*iam.Policy, error) {
-	getResult, err := a.Service().iam.GetPolicy(&iam.GetPolicyInput{
+	getResult, err := a.Service().iam.GetPolicyWithContext(tracing.Context(logger), &iam.GetPolicyInput{
 		PolicyArn: aws.String(policyARN),
 	})
 	if err == nil {
@@ -199,7 +200,7 @@ func (a *Client) iamEnsureS3PolicyCreated(awsID, policyARN, bucketName, permitte
 		return nil, errors.Wrap(err, "unable to marshal IAM policy")
 	}
 
-	createResult, err := a.Service().iam.CreatePolicy(&iam.CreatePolicyInput{
+	createResult, err := a.Service().iam.CreatePolicyWithContext(tracing.Context(logger), &iam.CreatePolicyInput{
 		PolicyDocument: aws.String(string(b)),
 		PolicyName:     aws.String(awsID),
 	})
@@ -213,7 +214,7 @@ func (a *Client) iamEnsureS3PolicyCreated(awsID, policyARN, bucketName, permitte
 }
 
 func (a *Client) iamEnsurePolicyAttached(awsID, policyARN string, logger log.FieldLogger) error {
-	_, err := a.Service().iam.AttachUserPolicy(&iam.AttachUserPolicyInput{
+	_, err := a.Service().iam.AttachUserPolicyWithContext(tracing.Context(logger), &iam.AttachUserPolicyInput{
 		PolicyArn: aws.String(policyARN),
 		UserName:  aws.String(awsID),
 	})
@@ -225,14 +226,14 @@ func (a *Client) iamEnsurePolicyAttached(awsID, policyARN string, logger log.Fie
 }
 
 func (a *Client) iamEnsureAccessKeyCreated(awsID string, logger log.FieldLogger) (*iam.AccessKey, error) {
-	listResult, err := a.Service().iam.ListAccessKeys(&iam.ListAccessKeysInput{
+	listResult, err := a.Service().iam.ListAccessKeysWithContext(tracing.Context(logger), &iam.ListAccessKeysInput{
 		UserName: aws.String(awsID),
 	})
 	if err != nil {
 		return nil, err
 	}
 	for _, ak := range listResult.AccessKeyMetadata {
-		_, err = a.Service().iam.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+		_, err = a.Service().iam.DeleteAccessKeyWithContext(tracing.Context(logger), &iam.DeleteAccessKeyInput{
 			AccessKeyId: ak.AccessKeyId,
 			UserName:    aws.String(awsID),
 		})
@@ -246,7 +247,7 @@ func (a *Client) iamEnsureAccessKeyCreated(awsID string, logger log.FieldLogger)
 		}).Info("AWS IAM user access key deleted")
 	}
 
-	createResult, err := a.Service().iam.CreateAccessKey(&iam.CreateAccessKeyInput{
+	createResult, err := a.Service().iam.CreateAccessKeyWithContext(tracing.Context(logger), &iam.CreateAccessKeyInput{
 		UserName: aws.String(awsID),
 	})
 	if err != nil {
@@ -274,7 +275,7 @@ func (a *Client) AttachPolicyToRole(roleName, policyName string, logger log.Fiel
 	policyARN := fmt.Sprintf("arn:aws:iam::%s:policy/%s", accountID, policyName)
 
 	logger.Infof("Attaching policy (%s) to IAM role (%s)", policyARN, roleName)
-	_, err = a.Service().iam.AttachRolePolicy(&iam.AttachRolePolicyInput{
+	_, err = a.Service().iam.AttachRolePolicyWithContext(tracing.Context(logger), &iam.AttachRolePolicyInput{
 		PolicyArn: aws.String(policyARN),
 		RoleName:  aws.String(roleName),
 	})
@@ -294,7 +295,7 @@ func (a *Client) DetachPolicyFromRole(roleName, policyName string, logger log.Fi
 	policyARN := fmt.Sprintf("arn:aws:iam::%s:policy/%s", accountID, policyName)
 
 	logger.Infof("Dettaching policy (%s) from IAM role (%s)", policyARN, roleName)
-	_, err = a.Service().iam.DetachRolePolicy(&iam.DetachRolePolicyInput{
+	_, err = a.Service().iam.DetachRolePolicyWithContext(tracing.Context(logger), &iam.DetachRolePolicyInput{
 		PolicyArn: aws.String(policyARN),
 		RoleName:  aws.String(roleName),
 	})
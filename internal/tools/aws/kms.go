@@ -7,11 +7,13 @@ package aws
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
+	log "github.com/sirupsen/logrus"
 )
 
 // kmsCreateSymmetricKey creates a symmetric encryption key with alias.
-func (a *Client) kmsCreateSymmetricKey(keyDescription string, tags []*kms.Tag) (*kms.KeyMetadata, error) {
-	createKeyOut, err := a.Service().kms.CreateKey(&kms.CreateKeyInput{
+func (a *Client) kmsCreateSymmetricKey(keyDescription string, tags []*kms.Tag, logger log.FieldLogger) (*kms.KeyMetadata, error) {
+	createKeyOut, err := a.Service().kms.CreateKeyWithContext(tracing.Context(logger), &kms.CreateKeyInput{
 		Description: aws.String(keyDescription),
 		Tags:        tags,
 	})
@@ -24,8 +26,8 @@ func (a *Client) kmsCreateSymmetricKey(keyDescription string, tags []*kms.Tag) (
 
 // kmsCreateAlias creates an alias for a symmetric encryption key. Alias allows retrieving the key ID in one call and
 // without special permissions that would be necessary if looking up it by tags for example.
-func (a *Client) kmsCreateAlias(keyID, aliasName string) error {
-	_, err := a.Service().kms.CreateAlias(&kms.CreateAliasInput{
+func (a *Client) kmsCreateAlias(keyID, aliasName string, logger log.FieldLogger) error {
+	_, err := a.Service().kms.CreateAliasWithContext(tracing.Context(logger), &kms.CreateAliasInput{
 		AliasName:   aws.String(aliasName),
 		TargetKeyId: aws.String(keyID),
 	})
@@ -37,8 +39,8 @@ func (a *Client) kmsCreateAlias(keyID, aliasName string) error {
 }
 
 // kmsDisableSymmetricKey disable a symmetric encryption key with alias.
-func (a *Client) kmsDisableSymmetricKey(keyID string) error {
-	_, err := a.Service().kms.DisableKey(&kms.DisableKeyInput{
+func (a *Client) kmsDisableSymmetricKey(keyID string, logger log.FieldLogger) error {
+	_, err := a.Service().kms.DisableKeyWithContext(tracing.Context(logger), &kms.DisableKeyInput{
 		KeyId: aws.String(keyID),
 	})
 	if err != nil {
@@ -49,8 +51,8 @@ func (a *Client) kmsDisableSymmetricKey(keyID string) error {
 }
 
 // kmsGetSymmetricKey get a symmetric encryption key with alias.
-func (a *Client) kmsGetSymmetricKey(aliasName string) (*kms.KeyMetadata, error) {
-	describeKeyOut, err := a.Service().kms.DescribeKey(&kms.DescribeKeyInput{
+func (a *Client) kmsGetSymmetricKey(aliasName string, logger log.FieldLogger) (*kms.KeyMetadata, error) {
+	describeKeyOut, err := a.Service().kms.DescribeKeyWithContext(tracing.Context(logger), &kms.DescribeKeyInput{
 		KeyId: aws.String(aliasName),
 	})
 	if err != nil {
@@ -63,8 +65,8 @@ func (a *Client) kmsGetSymmetricKey(aliasName string) (*kms.KeyMetadata, error)
 // kmsScheduleKeyDeletion sets a supplied key for deletion in n days. The service will return an error
 // if scheduled time is are less than 7 or more than 30 days.
 // https://docs.aws.amazon.com/kms/latest/APIReference/API_ScheduleKeyDeletion.html#API_ScheduleKeyDeletion_RequestSyntax
-func (a *Client) kmsScheduleKeyDeletion(keyID string, days int64) error {
-	_, err := a.Service().kms.ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
+func (a *Client) kmsScheduleKeyDeletion(keyID string, days int64, logger log.FieldLogger) error {
+	_, err := a.Service().kms.ScheduleKeyDeletionWithContext(tracing.Context(logger), &kms.ScheduleKeyDeletionInput{
 		KeyId:               aws.String(keyID),
 		PendingWindowInDays: aws.Int64(days),
 	})
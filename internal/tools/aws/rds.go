@@ -13,13 +13,14 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
 func (a *Client) rdsGetDBSecurityGroupIDs(vpcID, tagValue string, logger log.FieldLogger) ([]string, error) {
-	result, err := a.Service().ec2.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{
+	result, err := a.Service().ec2.DescribeSecurityGroupsWithContext(tracing.Context(logger), &ec2.DescribeSecurityGroupsInput{
 		Filters: []*ec2.Filter{
 			{
 				Name:   aws.String("vpc-id"),
@@ -58,7 +59,7 @@ func (a *Client) rdsGetDBSubnetGroupName(vpcID string, logger log.FieldLogger) (
 	//
 	// We should periodically check if filters become supported and move to that
 	// when they do.
-	result, err := a.Service().rds.DescribeDBSubnetGroups(nil)
+	result, err := a.Service().rds.DescribeDBSubnetGroupsWithContext(tracing.Context(logger), nil)
 	if err != nil {
 		return "", err
 	}
@@ -94,7 +95,7 @@ func (a *Client) rdsEnsureDBClusterCreated(awsID, vpcID, username, password, kms
 		return errors.Errorf("%s is an invalid database engine type", databaseType)
 	}
 
-	_, err := a.Service().rds.DescribeDBClusters(&rds.DescribeDBClustersInput{
+	_, err := a.Service().rds.DescribeDBClustersWithContext(tracing.Context(logger), &rds.DescribeDBClustersInput{
 		DBClusterIdentifier: aws.String(awsID),
 	})
 	if err == nil {
@@ -113,7 +114,7 @@ func (a *Client) rdsEnsureDBClusterCreated(awsID, vpcID, username, password, kms
 		return err
 	}
 
-	azs, err := a.getAvailabilityZones()
+	azs, err := a.getAvailabilityZones(logger)
 	if err != nil {
 		return err
 	}
@@ -128,7 +129,7 @@ func (a *Client) rdsEnsureDBClusterCreated(awsID, vpcID, username, password, kms
 
 	input := &rds.CreateDBClusterInput{
 		AvailabilityZones:     rdsAZs,
-		BackupRetentionPeriod: aws.Int64(7),
+		BackupRetentionPeriod: aws.Int64(rdsBackupRetentionPeriod),
 		DBClusterIdentifier:   aws.String(awsID),
 		DatabaseName:          aws.String("mattermost"),
 		EngineMode:            aws.String("provisioned"),
@@ -143,7 +144,7 @@ func (a *Client) rdsEnsureDBClusterCreated(awsID, vpcID, username, password, kms
 		KmsKeyId:              aws.String(kmsKeyID),
 	}
 
-	_, err = a.Service().rds.CreateDBCluster(input)
+	_, err = a.Service().rds.CreateDBClusterWithContext(tracing.Context(logger), input)
 	if err != nil {
 		return err
 	}
@@ -154,7 +155,7 @@ func (a *Client) rdsEnsureDBClusterCreated(awsID, vpcID, username, password, kms
 }
 
 func (a *Client) rdsEnsureDBClusterInstanceCreated(awsID, instanceName, databaseType string, logger log.FieldLogger) error {
-	_, err := a.Service().rds.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+	_, err := a.Service().rds.DescribeDBInstancesWithContext(tracing.Context(logger), &rds.DescribeDBInstancesInput{
 		DBInstanceIdentifier: aws.String(instanceName),
 	})
 	if err == nil {
@@ -177,7 +178,7 @@ func (a *Client) rdsEnsureDBClusterInstanceCreated(awsID, instanceName, database
 		return errors.Errorf("%s is an invalid database engine type", databaseType)
 	}
 
-	_, err = a.Service().rds.CreateDBInstance(&rds.CreateDBInstanceInput{
+	_, err = a.Service().rds.CreateDBInstanceWithContext(tracing.Context(logger), &rds.CreateDBInstanceInput{
 		DBClusterIdentifier:  aws.String(awsID),
 		DBInstanceIdentifier: aws.String(instanceName),
 		DBInstanceClass:      aws.String(instanceClass),
@@ -194,7 +195,7 @@ func (a *Client) rdsEnsureDBClusterInstanceCreated(awsID, instanceName, database
 }
 
 func (a *Client) rdsEnsureDBClusterDeleted(awsID string, logger log.FieldLogger) error {
-	result, err := a.Service().rds.DescribeDBClusters(&rds.DescribeDBClustersInput{
+	result, err := a.Service().rds.DescribeDBClustersWithContext(tracing.Context(logger), &rds.DescribeDBClustersInput{
 		DBClusterIdentifier: aws.String(awsID),
 	})
 	if err != nil {
@@ -212,7 +213,7 @@ func (a *Client) rdsEnsureDBClusterDeleted(awsID string, logger log.FieldLogger)
 	}
 
 	for _, instance := range result.DBClusters[0].DBClusterMembers {
-		_, err = a.Service().rds.DeleteDBInstance(&rds.DeleteDBInstanceInput{
+		_, err = a.Service().rds.DeleteDBInstanceWithContext(tracing.Context(logger), &rds.DeleteDBInstanceInput{
 			DBInstanceIdentifier: instance.DBInstanceIdentifier,
 			SkipFinalSnapshot:    aws.Bool(true),
 		})
@@ -222,7 +223,7 @@ func (a *Client) rdsEnsureDBClusterDeleted(awsID string, logger log.FieldLogger)
 		logger.WithField("db-instance-name", *instance.DBInstanceIdentifier).Debug("DB instance deleted")
 	}
 
-	_, err = a.Service().rds.DeleteDBCluster(&rds.DeleteDBClusterInput{
+	_, err = a.Service().rds.DeleteDBClusterWithContext(tracing.Context(logger), &rds.DeleteDBClusterInput{
 		DBClusterIdentifier: aws.String(awsID),
 		SkipFinalSnapshot:   aws.Bool(true),
 	})
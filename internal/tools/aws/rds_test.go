@@ -15,7 +15,7 @@ import (
 )
 
 func (a *AWSTestSuite) TestRDSEnsureDBClusterCreated() {
-	a.Mocks.API.RDS.EXPECT().DescribeDBClusters(gomock.Any()).Return(nil, errors.New("db cluster does not exist")).Times(1)
+	a.Mocks.API.RDS.EXPECT().DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).Return(nil, errors.New("db cluster does not exist")).Times(1)
 
 	a.Mocks.Log.Logger.EXPECT().
 		WithField(gomock.Any(), gomock.Any()).
@@ -23,7 +23,7 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreated() {
 		Times(3)
 
 	a.Mocks.API.EC2.EXPECT().
-		DescribeSecurityGroups(gomock.Any()).
+		DescribeSecurityGroupsWithContext(gomock.Any(), gomock.Any()).
 		Return(&ec2.DescribeSecurityGroupsOutput{
 			SecurityGroups: []*ec2.SecurityGroup{
 				{
@@ -33,7 +33,7 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreated() {
 		}, nil)
 
 	a.Mocks.API.RDS.EXPECT().
-		DescribeDBSubnetGroups(gomock.Any()).
+		DescribeDBSubnetGroupsWithContext(gomock.Any(), gomock.Any()).
 		Return(&rds.DescribeDBSubnetGroupsOutput{
 			DBSubnetGroups: []*rds.DBSubnetGroup{
 				{
@@ -43,9 +43,9 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreated() {
 		}, nil)
 
 	a.Mocks.API.RDS.EXPECT().
-		CreateDBCluster(gomock.Any()).
+		CreateDBClusterWithContext(gomock.Any(), gomock.Any()).
 		Return(nil, nil).
-		Do(func(input *rds.CreateDBClusterInput) {
+		Do(func(_ aws.Context, input *rds.CreateDBClusterInput) {
 			for _, zone := range input.AvailabilityZones {
 				a.Assert().Contains(a.RDSAvailabilityZones, *zone)
 			}
@@ -57,27 +57,27 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreated() {
 		Times(1)
 
 	a.Mocks.API.KMS.EXPECT().
-		CreateKey(gomock.Any()).
+		CreateKeyWithContext(gomock.Any(), gomock.Any()).
 		Return(&kms.CreateKeyOutput{
 			KeyMetadata: &kms.KeyMetadata{
 				KeyId: aws.String(a.RDSEncryptionKeyID),
 			},
 		}, nil).
-		Do(func(input *kms.CreateKeyInput) {
+		Do(func(_ aws.Context, input *kms.CreateKeyInput) {
 			a.Assert().Equal(*input.Description, "Key used for encrypting RDS database")
 		}).
 		Times(1)
 
 	a.Mocks.API.KMS.EXPECT().
-		CreateAlias(gomock.Any()).
+		CreateAliasWithContext(gomock.Any(), gomock.Any()).
 		Return(nil, nil).
-		Do(func(input *kms.CreateAliasInput) {
+		Do(func(_ aws.Context, input *kms.CreateAliasInput) {
 			a.Assert().Equal(*input.AliasName, KMSAliasNameRDS(CloudID(a.InstallationA.ID)))
 		}).
 		Times(1)
 
 	// Retrive the Availability Zones.
-	a.Mocks.API.EC2.EXPECT().DescribeAvailabilityZones(gomock.Any()).
+	a.Mocks.API.EC2.EXPECT().DescribeAvailabilityZonesWithContext(gomock.Any(), gomock.Any()).
 		Return(&ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: []*ec2.AvailabilityZone{{ZoneName: aws.String("us-honk-1a")}, {ZoneName: aws.String("us-honk-1b")}}}, nil).
 		Times(1)
 
@@ -91,7 +91,7 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedAlreadyCreated() {
 		Return(testlib.NewLoggerEntry()).
 		Times(1).
 		After(a.Mocks.API.RDS.EXPECT().
-			DescribeDBClusters(gomock.Any()).
+			DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, nil).
 			Times(1))
 
@@ -101,7 +101,7 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedAlreadyCreated() {
 
 func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedWithSGError() {
 	a.Mocks.API.RDS.EXPECT().
-		DescribeDBClusters(gomock.Any()).
+		DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).
 		Return(nil, errors.New("db cluster does not exist")).
 		Times(1)
 
@@ -110,7 +110,7 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedWithSGError() {
 		Return(testlib.NewLoggerEntry()).
 		Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DescribeSecurityGroups(gomock.Any()).
+			DescribeSecurityGroupsWithContext(gomock.Any(), gomock.Any()).
 			Return(nil, errors.New("invalid group id")))
 
 	err := a.Mocks.AWS.rdsEnsureDBClusterCreated(CloudID(a.InstallationA.ID), a.VPCa, a.DBUser, a.DBPassword, a.RDSEncryptionKeyID, a.RDSEngineType, a.Mocks.Log.Logger)
@@ -119,13 +119,13 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedWithSGError() {
 }
 
 func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedSubnetError() {
-	a.Mocks.API.RDS.EXPECT().DescribeDBClusters(gomock.Any()).Return(nil, errors.New("db cluster does not exist")).Times(1)
+	a.Mocks.API.RDS.EXPECT().DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).Return(nil, errors.New("db cluster does not exist")).Times(1)
 
 	a.Mocks.Log.Logger.EXPECT().
 		WithField("security-group-ids", []string{a.GroupID}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DescribeSecurityGroups(gomock.Any()).
+			DescribeSecurityGroupsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeSecurityGroupsOutput{
 				SecurityGroups: []*ec2.SecurityGroup{{GroupId: &a.GroupID}},
 			}, nil))
@@ -135,7 +135,7 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedSubnetError() {
 		Return(testlib.NewLoggerEntry()).
 		Times(1).
 		After(a.Mocks.API.RDS.EXPECT().
-			DescribeDBSubnetGroups(gomock.Any()).
+			DescribeDBSubnetGroupsWithContext(gomock.Any(), gomock.Any()).
 			Return(&rds.DescribeDBSubnetGroupsOutput{
 				DBSubnetGroups: []*rds.DBSubnetGroup{},
 			}, errors.New("invalid cluster id")))
@@ -147,13 +147,13 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedSubnetError() {
 }
 
 func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedError() {
-	a.Mocks.API.RDS.EXPECT().DescribeDBClusters(gomock.Any()).Return(nil, errors.New("db cluster does not exist")).Times(1)
+	a.Mocks.API.RDS.EXPECT().DescribeDBClustersWithContext(gomock.Any(), gomock.Any()).Return(nil, errors.New("db cluster does not exist")).Times(1)
 
 	a.Mocks.Log.Logger.EXPECT().
 		WithField("security-group-ids", []string{a.GroupID}).
 		Return(testlib.NewLoggerEntry()).Times(1).
 		After(a.Mocks.API.EC2.EXPECT().
-			DescribeSecurityGroups(gomock.Any()).
+			DescribeSecurityGroupsWithContext(gomock.Any(), gomock.Any()).
 			Return(&ec2.DescribeSecurityGroupsOutput{
 				SecurityGroups: []*ec2.SecurityGroup{{GroupId: &a.GroupID}},
 			}, nil))
@@ -163,7 +163,7 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedError() {
 		Return(testlib.NewLoggerEntry()).
 		Times(1).
 		After(a.Mocks.API.RDS.EXPECT().
-			DescribeDBSubnetGroups(gomock.Any()).
+			DescribeDBSubnetGroupsWithContext(gomock.Any(), gomock.Any()).
 			Return(&rds.DescribeDBSubnetGroupsOutput{
 				DBSubnetGroups: []*rds.DBSubnetGroup{
 					{
@@ -173,12 +173,12 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterCreatedError() {
 			}, nil))
 
 	a.Mocks.API.RDS.EXPECT().
-		CreateDBCluster(gomock.Any()).
+		CreateDBClusterWithContext(gomock.Any(), gomock.Any()).
 		Return(nil, errors.New("invalid cluster name")).
 		Times(1)
 
 	// Retrive the Availability Zones.
-	a.Mocks.API.EC2.EXPECT().DescribeAvailabilityZones(gomock.Any()).
+	a.Mocks.API.EC2.EXPECT().DescribeAvailabilityZonesWithContext(gomock.Any(), gomock.Any()).
 		Return(&ec2.DescribeAvailabilityZonesOutput{AvailabilityZones: []*ec2.AvailabilityZone{{ZoneName: aws.String("us-honk-1a")}, {ZoneName: aws.String("us-honk-1b")}}}, nil).
 		Times(1)
 
@@ -194,9 +194,9 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterInstanceCreated() {
 		Times(1)
 
 	a.Mocks.API.RDS.EXPECT().
-		DescribeDBInstances(gomock.Any()).
+		DescribeDBInstancesWithContext(gomock.Any(), gomock.Any()).
 		Return(nil, errors.New("db cluster instance does not exist")).
-		Do(func(input *rds.DescribeDBInstancesInput) {
+		Do(func(_ aws.Context, input *rds.DescribeDBInstancesInput) {
 			a.Assert().Equal(*input.DBInstanceIdentifier, RDSMasterInstanceID(a.InstallationA.ID))
 		})
 
@@ -204,8 +204,8 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterInstanceCreated() {
 		Return(testlib.NewLoggerEntry()).
 		Times(1).
 		After(a.Mocks.API.RDS.EXPECT().
-			CreateDBInstance(gomock.Any()).Return(nil, nil).
-			Do(func(input *rds.CreateDBInstanceInput) {
+			CreateDBInstanceWithContext(gomock.Any(), gomock.Any()).Return(nil, nil).
+			Do(func(_ aws.Context, input *rds.CreateDBInstanceInput) {
 				a.Assert().Equal(*input.DBClusterIdentifier, CloudID(a.InstallationA.ID))
 				a.Assert().Equal(*input.DBInstanceIdentifier, RDSMasterInstanceID(a.InstallationA.ID))
 			}).
@@ -222,9 +222,9 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterInstanceAlreadyExistError() {
 		Times(1)
 
 	a.Mocks.API.RDS.EXPECT().
-		DescribeDBInstances(gomock.Any()).
+		DescribeDBInstancesWithContext(gomock.Any(), gomock.Any()).
 		Return(nil, nil).
-		Do(func(input *rds.DescribeDBInstancesInput) {
+		Do(func(_ aws.Context, input *rds.DescribeDBInstancesInput) {
 			a.Assert().Equal(*input.DBInstanceIdentifier, RDSMasterInstanceID(a.InstallationA.ID))
 		})
 
@@ -243,9 +243,9 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterInstanceCreateError() {
 		Times(1)
 
 	a.Mocks.API.RDS.EXPECT().
-		DescribeDBInstances(gomock.Any()).
+		DescribeDBInstancesWithContext(gomock.Any(), gomock.Any()).
 		Return(nil, errors.New("db cluster instance does not exist")).
-		Do(func(input *rds.DescribeDBInstancesInput) {
+		Do(func(_ aws.Context, input *rds.DescribeDBInstancesInput) {
 			a.Assert().Equal(*input.DBInstanceIdentifier, RDSMasterInstanceID(a.InstallationA.ID))
 		})
 
@@ -253,8 +253,8 @@ func (a *AWSTestSuite) TestRDSEnsureDBClusterInstanceCreateError() {
 		Return(testlib.NewLoggerEntry()).
 		Times(1).
 		After(a.Mocks.API.RDS.EXPECT().
-			CreateDBInstance(gomock.Any()).Return(nil, errors.New("instance creation failure")).
-			Do(func(input *rds.CreateDBInstanceInput) {
+			CreateDBInstanceWithContext(gomock.Any(), gomock.Any()).Return(nil, errors.New("instance creation failure")).
+			Do(func(_ aws.Context, input *rds.CreateDBInstanceInput) {
 				a.Assert().Equal(*input.DBClusterIdentifier, CloudID(a.InstallationA.ID))
 				a.Assert().Equal(*input.DBInstanceIdentifier, RDSMasterInstanceID(a.InstallationA.ID))
 			}).
@@ -6,15 +6,17 @@ package aws
 
 import (
 	gt "github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
+	log "github.com/sirupsen/logrus"
 )
 
-func (a *Client) resourceTaggingGetAllResources(input gt.GetResourcesInput) ([]*gt.ResourceTagMapping, error) {
+func (a *Client) resourceTaggingGetAllResources(input gt.GetResourcesInput, logger log.FieldLogger) ([]*gt.ResourceTagMapping, error) {
 	var resources []*gt.ResourceTagMapping
 	var next *string
 
 	for {
 		input.PaginationToken = next
-		output, err := a.Service().resourceGroupsTagging.GetResources(&input)
+		output, err := a.Service().resourceGroupsTagging.GetResourcesWithContext(tracing.Context(logger), &input)
 		if err != nil {
 			return nil, err
 		}
@@ -15,8 +15,8 @@ import (
 func (a *AWSTestSuite) TestResourceTaggingGetAllResources() {
 	gomock.InOrder(
 		a.Mocks.API.ResourceGroupsTagging.EXPECT().
-			GetResources(gomock.Any()).
-			Do(func(input *gt.GetResourcesInput) {
+			GetResourcesWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *gt.GetResourcesInput) {
 				a.Assert().Equal(DefaultRDSEncryptionTagKey, *input.TagFilters[0].Key)
 				a.Assert().Equal(CloudID(a.InstallationA.ID), *input.TagFilters[0].Values[0])
 				a.Assert().Nil(input.PaginationToken)
@@ -32,8 +32,8 @@ func (a *AWSTestSuite) TestResourceTaggingGetAllResources() {
 			Times(1),
 
 		a.Mocks.API.ResourceGroupsTagging.EXPECT().
-			GetResources(gomock.Any()).
-			Do(func(input *gt.GetResourcesInput) {
+			GetResourcesWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *gt.GetResourcesInput) {
 				a.Assert().Equal(DefaultRDSEncryptionTagKey, *input.TagFilters[0].Key)
 				a.Assert().Equal(CloudID(a.InstallationA.ID), *input.TagFilters[0].Values[0])
 				a.Assert().Equal("next_token", *input.PaginationToken)
@@ -56,7 +56,7 @@ func (a *AWSTestSuite) TestResourceTaggingGetAllResources() {
 				Values: []*string{aws.String(CloudID(a.InstallationA.ID))},
 			},
 		},
-	})
+	}, a.Mocks.Log.Logger)
 
 	a.Assert().NoError(err)
 	a.Assert().Equal(2, len(result))
@@ -65,8 +65,8 @@ func (a *AWSTestSuite) TestResourceTaggingGetAllResources() {
 func (a *AWSTestSuite) TestResourceTaggingGetAllResourcesEmpty() {
 	gomock.InOrder(
 		a.Mocks.API.ResourceGroupsTagging.EXPECT().
-			GetResources(gomock.Any()).
-			Do(func(input *gt.GetResourcesInput) {
+			GetResourcesWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *gt.GetResourcesInput) {
 				a.Assert().Equal(DefaultRDSEncryptionTagKey, *input.TagFilters[0].Key)
 				a.Assert().Equal(CloudID(a.InstallationA.ID), *input.TagFilters[0].Values[0])
 				a.Assert().Nil(input.PaginationToken)
@@ -82,7 +82,7 @@ func (a *AWSTestSuite) TestResourceTaggingGetAllResourcesEmpty() {
 				Values: []*string{aws.String(CloudID(a.InstallationA.ID))},
 			},
 		},
-	})
+	}, a.Mocks.Log.Logger)
 
 	a.Assert().NoError(err)
 	a.Assert().Equal(0, len(result))
@@ -91,8 +91,8 @@ func (a *AWSTestSuite) TestResourceTaggingGetAllResourcesEmpty() {
 func (a *AWSTestSuite) TestResourceTaggingGetAllResourcesError() {
 	gomock.InOrder(
 		a.Mocks.API.ResourceGroupsTagging.EXPECT().
-			GetResources(gomock.Any()).
-			Do(func(input *gt.GetResourcesInput) {
+			GetResourcesWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *gt.GetResourcesInput) {
 				a.Assert().Equal(DefaultRDSEncryptionTagKey, *input.TagFilters[0].Key)
 				a.Assert().Equal(CloudID(a.InstallationA.ID), *input.TagFilters[0].Values[0])
 				a.Assert().Nil(input.PaginationToken)
@@ -108,7 +108,7 @@ func (a *AWSTestSuite) TestResourceTaggingGetAllResourcesError() {
 				Values: []*string{aws.String(CloudID(a.InstallationA.ID))},
 			},
 		},
-	})
+	}, a.Mocks.Log.Logger)
 
 	a.Assert().Nil(result)
 	a.Assert().Error(err)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -73,7 +74,7 @@ func (a *Client) GetPrivateZoneIDForDefaultTag(logger log.FieldLogger) (string,
 
 // GetTagByKeyAndZoneID returns a Tag of a given tag:key and of a given route53 id
 func (a *Client) GetTagByKeyAndZoneID(key string, id string, logger log.FieldLogger) (*Tag, error) {
-	tagList, err := a.Service().route53.ListTagsForResource(&route53.ListTagsForResourceInput{
+	tagList, err := a.Service().route53.ListTagsForResourceWithContext(tracing.Context(logger), &route53.ListTagsForResourceInput{
 		ResourceId:   aws.String(id),
 		ResourceType: aws.String(hostedZoneResourceType),
 	})
@@ -100,7 +101,7 @@ func (a *Client) GetTagByKeyAndZoneID(key string, id string, logger log.FieldLog
 }
 
 func (a *Client) getZoneDNS(hostedZoneID string, logger log.FieldLogger) (string, error) {
-	out, err := a.Service().route53.GetHostedZone(&route53.GetHostedZoneInput{
+	out, err := a.Service().route53.GetHostedZoneWithContext(tracing.Context(logger), &route53.GetHostedZoneInput{
 		Id: aws.String(hostedZoneID),
 	})
 	if err != nil {
@@ -138,7 +139,7 @@ func (a *Client) createCNAME(hostedZoneID, dnsName string, dnsEndpoints []string
 		})
 	}
 
-	resp, err := a.Service().route53.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+	resp, err := a.Service().route53.ChangeResourceRecordSetsWithContext(tracing.Context(logger), &route53.ChangeResourceRecordSetsInput{
 		ChangeBatch: &route53.ChangeBatch{
 			Changes: []*route53.Change{
 				{
@@ -214,7 +215,7 @@ func (a *Client) DeletePrivateCNAME(dnsName string, logger log.FieldLogger) erro
 func (a *Client) isProvisionedCNAME(hostedZoneID, dnsName string, logger log.FieldLogger) bool {
 	nextRecordName := dnsName
 	for {
-		recordList, err := a.Service().route53.ListResourceRecordSets(
+		recordList, err := a.Service().route53.ListResourceRecordSetsWithContext(tracing.Context(logger),
 			&route53.ListResourceRecordSetsInput{
 				HostedZoneId:    &hostedZoneID,
 				StartRecordName: &nextRecordName,
@@ -246,7 +247,7 @@ func (a *Client) deleteCNAME(hostedZoneID, dnsName string, logger log.FieldLogge
 	nextRecordName := dnsName
 	var recordSets []*route53.ResourceRecordSet
 	for {
-		recordList, err := a.Service().route53.ListResourceRecordSets(
+		recordList, err := a.Service().route53.ListResourceRecordSetsWithContext(tracing.Context(logger),
 			&route53.ListResourceRecordSetsInput{
 				HostedZoneId:    &hostedZoneID,
 				StartRecordName: &nextRecordName,
@@ -280,7 +281,7 @@ func (a *Client) deleteCNAME(hostedZoneID, dnsName string, logger log.FieldLogge
 		return nil
 	}
 
-	resp, err := a.Service().route53.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+	resp, err := a.Service().route53.ChangeResourceRecordSetsWithContext(tracing.Context(logger), &route53.ChangeResourceRecordSetsInput{
 		ChangeBatch:  &route53.ChangeBatch{Changes: changes},
 		HostedZoneId: &hostedZoneID,
 	})
@@ -301,7 +302,7 @@ func (a *Client) deleteCNAME(hostedZoneID, dnsName string, logger log.FieldLogge
 func (a *Client) getHostedZoneIDWithTag(tag Tag, logger log.FieldLogger) (string, error) {
 	var next *string
 	for {
-		zoneList, err := a.Service().route53.ListHostedZones(&route53.ListHostedZonesInput{Marker: next})
+		zoneList, err := a.Service().route53.ListHostedZonesWithContext(tracing.Context(logger), &route53.ListHostedZonesInput{Marker: next})
 		if err != nil {
 			return "", errors.Wrapf(err, "listing hosted all zones")
 		}
@@ -312,7 +313,7 @@ func (a *Client) getHostedZoneIDWithTag(tag Tag, logger log.FieldLogger) (string
 				return "", errors.Wrapf(err, "when parsing hosted zone: %s", zone.String())
 			}
 
-			tagList, err := a.Service().route53.ListTagsForResource(&route53.ListTagsForResourceInput{
+			tagList, err := a.Service().route53.ListTagsForResourceWithContext(tracing.Context(logger), &route53.ListTagsForResourceInput{
 				ResourceId:   aws.String(id),
 				ResourceType: aws.String(hostedZoneResourceType),
 			})
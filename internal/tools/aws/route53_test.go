@@ -16,7 +16,7 @@ import (
 func (a *AWSTestSuite) TestRoute53CreatePublicCNAME() {
 	gomock.InOrder(
 		a.Mocks.API.Route53.EXPECT().
-			ListHostedZones(&route53.ListHostedZonesInput{}).
+			ListHostedZonesWithContext(gomock.Any(), &route53.ListHostedZonesInput{}).
 			Return(&route53.ListHostedZonesOutput{
 				HostedZones: []*route53.HostedZone{
 					{
@@ -29,8 +29,8 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAME() {
 			Times(1),
 
 		a.Mocks.API.Route53.EXPECT().
-			ListTagsForResource(gomock.Any()).
-			Do(func(input *route53.ListTagsForResourceInput) {
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *route53.ListTagsForResourceInput) {
 				a.Assert().Equal(a.HostedZoneID, *input.ResourceId)
 				a.Assert().Equal("hostedzone", *input.ResourceType)
 			}).
@@ -47,7 +47,7 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAME() {
 			Times(1),
 
 		a.Mocks.API.Route53.EXPECT().
-			ListHostedZones(gomock.Any()).
+			ListHostedZonesWithContext(gomock.Any(), gomock.Any()).
 			Return(&route53.ListHostedZonesOutput{
 				HostedZones: []*route53.HostedZone{
 					{
@@ -59,8 +59,8 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAME() {
 			Times(1),
 
 		a.Mocks.API.Route53.EXPECT().
-			ListTagsForResource(gomock.Any()).
-			Do(func(input *route53.ListTagsForResourceInput) {
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *route53.ListTagsForResourceInput) {
 				a.Assert().Equal(a.HostedZoneID, *input.ResourceId)
 				a.Assert().Equal("hostedzone", *input.ResourceType)
 			}).
@@ -77,8 +77,8 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAME() {
 			Times(1),
 
 		a.Mocks.API.Route53.EXPECT().
-			ChangeResourceRecordSets(gomock.Any()).
-			Do(func(input *route53.ChangeResourceRecordSetsInput) {
+			ChangeResourceRecordSetsWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *route53.ChangeResourceRecordSetsInput) {
 				a.Assert().Equal("mattermost.com", *input.ChangeBatch.Changes[0].ResourceRecordSet.Name)
 				a.Assert().Equal("example.mattermost.com", *input.ChangeBatch.Changes[0].ResourceRecordSet.ResourceRecords[0].Value)
 				a.Assert().Equal(a.HostedZoneID, *input.HostedZoneId)
@@ -100,12 +100,12 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAME() {
 
 func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEListZonesError() {
 	a.Mocks.API.Route53.EXPECT().
-		ListHostedZones(&route53.ListHostedZonesInput{}).
+		ListHostedZonesWithContext(gomock.Any(), &route53.ListHostedZonesInput{}).
 		Return(nil, errors.New("invalid input")).
 		Times(1)
 
-	a.Mocks.API.Route53.EXPECT().ListTagsForResource(gomock.Any()).Times(0)
-	a.Mocks.API.Route53.EXPECT().ChangeResourceRecordSets(gomock.Any()).Times(0)
+	a.Mocks.API.Route53.EXPECT().ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).Times(0)
+	a.Mocks.API.Route53.EXPECT().ChangeResourceRecordSetsWithContext(gomock.Any(), gomock.Any()).Times(0)
 	a.Mocks.Log.Logger.EXPECT().WithFields(gomock.Any()).Times(0)
 
 	err := a.Mocks.AWS.CreatePublicCNAME("mattermost.com", []string{"example.mattermost.com"}, a.Mocks.Log.Logger)
@@ -116,7 +116,7 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEListZonesError() {
 func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEListTagsError() {
 	gomock.InOrder(
 		a.Mocks.API.Route53.EXPECT().
-			ListHostedZones(&route53.ListHostedZonesInput{}).
+			ListHostedZonesWithContext(gomock.Any(), &route53.ListHostedZonesInput{}).
 			Return(&route53.ListHostedZonesOutput{
 				HostedZones: []*route53.HostedZone{
 					{
@@ -129,8 +129,8 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEListTagsError() {
 			Times(1),
 
 		a.Mocks.API.Route53.EXPECT().
-			ListTagsForResource(gomock.Any()).
-			Do(func(input *route53.ListTagsForResourceInput) {
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *route53.ListTagsForResourceInput) {
 				a.Assert().Equal(a.HostedZoneID, *input.ResourceId)
 				a.Assert().Equal("hostedzone", *input.ResourceType)
 			}).
@@ -138,7 +138,7 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEListTagsError() {
 			Times(1),
 	)
 
-	a.Mocks.API.Route53.EXPECT().ChangeResourceRecordSets(gomock.Any()).Times(0)
+	a.Mocks.API.Route53.EXPECT().ChangeResourceRecordSetsWithContext(gomock.Any(), gomock.Any()).Times(0)
 	a.Mocks.Log.Logger.EXPECT().WithFields(gomock.Any()).Times(0)
 
 	err := a.Mocks.AWS.CreatePublicCNAME("mattermost.com", []string{"example.mattermost.com"}, a.Mocks.Log.Logger)
@@ -149,7 +149,7 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEListTagsError() {
 func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEChangeRecordSetsError() {
 	gomock.InOrder(
 		a.Mocks.API.Route53.EXPECT().
-			ListHostedZones(gomock.Any()).
+			ListHostedZonesWithContext(gomock.Any(), gomock.Any()).
 			Return(&route53.ListHostedZonesOutput{
 				HostedZones: []*route53.HostedZone{
 					{
@@ -161,8 +161,8 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEChangeRecordSetsError() {
 			Times(1),
 
 		a.Mocks.API.Route53.EXPECT().
-			ListTagsForResource(gomock.Any()).
-			Do(func(input *route53.ListTagsForResourceInput) {
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *route53.ListTagsForResourceInput) {
 				a.Assert().Equal(a.HostedZoneID, *input.ResourceId)
 				a.Assert().Equal("hostedzone", *input.ResourceType)
 			}).
@@ -179,8 +179,8 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEChangeRecordSetsError() {
 			Times(1),
 
 		a.Mocks.API.Route53.EXPECT().
-			ChangeResourceRecordSets(gomock.Any()).
-			Do(func(input *route53.ChangeResourceRecordSetsInput) {
+			ChangeResourceRecordSetsWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *route53.ChangeResourceRecordSetsInput) {
 				a.Assert().Equal("mattermost.com", *input.ChangeBatch.Changes[0].ResourceRecordSet.Name)
 				a.Assert().Equal("example.mattermost.com", *input.ChangeBatch.Changes[0].ResourceRecordSet.ResourceRecords[0].Value)
 				a.Assert().Equal(a.HostedZoneID, *input.HostedZoneId)
@@ -199,7 +199,7 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAMEChangeRecordSetsError() {
 func (a *AWSTestSuite) TestRoute53CreatePublicCNAMENoHostedZone() {
 	gomock.InOrder(
 		a.Mocks.API.Route53.EXPECT().
-			ListHostedZones(gomock.Any()).
+			ListHostedZonesWithContext(gomock.Any(), gomock.Any()).
 			Return(&route53.ListHostedZonesOutput{
 				HostedZones: []*route53.HostedZone{
 					{
@@ -211,8 +211,8 @@ func (a *AWSTestSuite) TestRoute53CreatePublicCNAMENoHostedZone() {
 			Times(1),
 
 		a.Mocks.API.Route53.EXPECT().
-			ListTagsForResource(gomock.Any()).
-			Do(func(input *route53.ListTagsForResourceInput) {
+			ListTagsForResourceWithContext(gomock.Any(), gomock.Any()).
+			Do(func(_ aws.Context, input *route53.ListTagsForResourceInput) {
 				a.Assert().Equal(a.HostedZoneID, *input.ResourceId)
 				a.Assert().Equal("hostedzone", *input.ResourceType)
 			}).
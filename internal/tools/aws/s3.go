@@ -9,12 +9,13 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
 func (a *Client) s3EnsureBucketCreated(bucketName string, logger log.FieldLogger) error {
-	_, err := a.Service().s3.CreateBucket(&s3.CreateBucketInput{
+	_, err := a.Service().s3.CreateBucketWithContext(tracing.Context(logger), &s3.CreateBucketInput{
 		Bucket: aws.String(bucketName),
 		ACL:    aws.String("private"),
 	})
@@ -22,7 +23,7 @@ func (a *Client) s3EnsureBucketCreated(bucketName string, logger log.FieldLogger
 		return errors.Wrap(err, "unable to create bucket")
 	}
 
-	_, err = a.Service().s3.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{
+	_, err = a.Service().s3.PutPublicAccessBlockWithContext(tracing.Context(logger), &s3.PutPublicAccessBlockInput{
 		Bucket: aws.String(bucketName),
 		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
 			BlockPublicAcls:       aws.Bool(true),
@@ -35,7 +36,7 @@ func (a *Client) s3EnsureBucketCreated(bucketName string, logger log.FieldLogger
 		return errors.Wrap(err, "unable to block public bucket access")
 	}
 
-	_, err = a.Service().s3.PutBucketEncryption(&s3.PutBucketEncryptionInput{
+	_, err = a.Service().s3.PutBucketEncryptionWithContext(tracing.Context(logger), &s3.PutBucketEncryptionInput{
 		Bucket: aws.String(bucketName),
 		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
 			Rules: []*s3.ServerSideEncryptionRule{
@@ -58,7 +59,7 @@ func (a *Client) s3EnsureBucketCreated(bucketName string, logger log.FieldLogger
 func (a *Client) S3EnsureBucketDeleted(bucketName string, logger log.FieldLogger) error {
 	// First check if bucket still exists. There isn't a "GetBucket" so we will
 	// try to get the bucket policy instead.
-	_, err := a.Service().s3.GetBucketPolicy(&s3.GetBucketPolicyInput{
+	_, err := a.Service().s3.GetBucketPolicyWithContext(tracing.Context(logger), &s3.GetBucketPolicyInput{
 		Bucket: aws.String(bucketName),
 	})
 	if aerr, ok := err.(awserr.Error); ok {
@@ -73,12 +74,12 @@ func (a *Client) S3EnsureBucketDeleted(bucketName string, logger log.FieldLogger
 		Bucket: aws.String(bucketName),
 	})
 
-	err = s3manager.NewBatchDeleteWithClient(a.Service().s3).Delete(aws.BackgroundContext(), iter)
+	err = s3manager.NewBatchDeleteWithClient(a.Service().s3).Delete(tracing.Context(logger), iter)
 	if err != nil {
 		return errors.Wrap(err, "unable to delete bucket contents")
 	}
 
-	_, err = a.Service().s3.DeleteBucket(&s3.DeleteBucketInput{
+	_, err = a.Service().s3.DeleteBucketWithContext(tracing.Context(logger), &s3.DeleteBucketInput{
 		Bucket: aws.String(bucketName),
 	})
 	if err != nil {
@@ -96,7 +97,7 @@ func (a *Client) S3EnsureBucketDirectoryDeleted(bucketName, directory string, lo
 		Prefix: aws.String(directory),
 	})
 
-	err := s3manager.NewBatchDeleteWithClient(a.Service().s3).Delete(aws.BackgroundContext(), iter)
+	err := s3manager.NewBatchDeleteWithClient(a.Service().s3).Delete(tracing.Context(logger), iter)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete bucket directory")
 	}
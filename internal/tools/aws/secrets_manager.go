@@ -12,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
@@ -71,7 +72,7 @@ func (a *Client) secretsManagerEnsureIAMAccessKeySecretCreated(awsID string, ak
 	}
 
 	secretName := IAMSecretName(awsID)
-	_, err = a.Service().secretsManager.CreateSecret(&secretsmanager.CreateSecretInput{
+	_, err = a.Service().secretsManager.CreateSecretWithContext(tracing.Context(logger), &secretsmanager.CreateSecretInput{
 		Name:         aws.String(secretName),
 		Description:  aws.String(fmt.Sprintf("IAM access key for user %s", awsID)),
 		SecretString: aws.String(string(b)),
@@ -88,7 +89,7 @@ func (a *Client) secretsManagerEnsureRDSSecretCreated(awsID string, logger log.F
 	rdsSecretPayload := &RDSSecret{}
 
 	// Check if we already have an RDS secret for this installation.
-	result, err := a.Service().secretsManager.GetSecretValue(&secretsmanager.GetSecretValueInput{
+	result, err := a.Service().secretsManager.GetSecretValueWithContext(tracing.Context(logger), &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(secretName),
 	})
 	if err == nil {
@@ -121,7 +122,7 @@ func (a *Client) secretsManagerEnsureRDSSecretCreated(awsID string, logger log.F
 		return nil, errors.Wrap(err, "unable to marshal secrets manager payload")
 	}
 
-	_, err = a.Service().secretsManager.CreateSecret(&secretsmanager.CreateSecretInput{
+	_, err = a.Service().secretsManager.CreateSecretWithContext(tracing.Context(logger), &secretsmanager.CreateSecretInput{
 		Name:         aws.String(secretName),
 		Description:  aws.String(fmt.Sprintf("RDS configuration for %s", awsID)),
 		SecretString: aws.String(string(b)),
@@ -138,7 +139,7 @@ func (a *Client) secretsManagerEnsureRDSSecretCreated(awsID string, logger log.F
 // secretsManagerGetIAMAccessKey returns the AccessKey for an IAM account.
 func (a *Client) secretsManagerGetIAMAccessKey(awsID string, logger log.FieldLogger) (*IAMAccessKey, error) {
 	secretName := IAMSecretName(awsID)
-	result, err := a.Service().secretsManager.GetSecretValue(&secretsmanager.GetSecretValueInput{
+	result, err := a.Service().secretsManager.GetSecretValueWithContext(tracing.Context(logger), &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(secretName),
 	})
 	if err != nil {
@@ -161,7 +162,7 @@ func (a *Client) secretsManagerGetIAMAccessKey(awsID string, logger log.FieldLog
 
 func (a *Client) secretsManagerGetRDSSecret(awsID string, logger log.FieldLogger) (*RDSSecret, error) {
 	secretName := RDSSecretName(awsID)
-	result, err := a.Service().secretsManager.GetSecretValue(&secretsmanager.GetSecretValueInput{
+	result, err := a.Service().secretsManager.GetSecretValueWithContext(tracing.Context(logger), &secretsmanager.GetSecretValueInput{
 		SecretId: aws.String(secretName),
 	})
 	if err != nil {
@@ -191,7 +192,7 @@ func (a *Client) secretsManagerEnsureRDSSecretDeleted(awsID string, logger log.F
 }
 
 func (a *Client) secretsManagerEnsureSecretDeleted(secretName string, logger log.FieldLogger) error {
-	_, err := a.Service().secretsManager.DeleteSecret(&secretsmanager.DeleteSecretInput{
+	_, err := a.Service().secretsManager.DeleteSecretWithContext(tracing.Context(logger), &secretsmanager.DeleteSecretInput{
 		SecretId: aws.String(secretName),
 	})
 	if err != nil {
@@ -45,5 +45,7 @@ func NewAWSSessionWithLogger(config *aws.Config, logger log.FieldLogger) (*sessi
 		}
 	})
 
+	addTracingHandlers(&awsSession.Handlers)
+
 	return awsSession, nil
 }
@@ -0,0 +1,54 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// requestSpanKey is the context key of the span started for an AWS request.
+type requestSpanKey struct{}
+
+// addTracingHandlers records a span for every AWS API request made with the
+// given handlers, including its retries. Spans are children of the span
+// carried by the request context, if any.
+func addTracingHandlers(handlers *request.Handlers) {
+	handlers.Build.PushFrontNamed(request.NamedHandler{
+		Name: "tracing.StartSpan",
+		Fn: func(r *request.Request) {
+			ctx, span := tracing.StartSpanFromContext(r.Context(), "aws "+r.ClientInfo.ServiceID+"."+r.Operation.Name,
+				attribute.String("aws.service", r.ClientInfo.ServiceID),
+				attribute.String("aws.operation", r.Operation.Name),
+				attribute.String("aws.region", aws.StringValue(r.Config.Region)),
+			)
+			r.SetContext(context.WithValue(ctx, requestSpanKey{}, span))
+		},
+	})
+
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "tracing.EndSpan",
+		Fn: func(r *request.Request) {
+			span, ok := r.Context().Value(requestSpanKey{}).(trace.Span)
+			if !ok {
+				return
+			}
+			span.SetAttributes(
+				attribute.String("aws.request_id", r.RequestID),
+				attribute.Int("aws.retries", r.RetryCount),
+			)
+			if r.HTTPResponse != nil {
+				span.SetAttributes(attribute.Int("http.status_code", r.HTTPResponse.StatusCode))
+			}
+			tracing.RecordError(span, r.Error)
+			span.End()
+		},
+	})
+}
@@ -13,11 +13,14 @@ import (
 	"bytes"
 	"io"
 	"os/exec"
+	"path/filepath"
 	"sync"
 
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // OutputLogger allows custom logging of the run command output.
@@ -49,6 +52,13 @@ func Run(cmd *exec.Cmd, logger log.FieldLogger, outputLogger OutputLogger) ([]by
 		"run": runID,
 	})
 
+	// Arguments are left out of the span as they may hold secrets, such as
+	// the values given to helm with --set.
+	span, logger := tracing.StartSpan(logger, "exec "+filepath.Base(cmd.Path),
+		attribute.String("exec.run", runID),
+	)
+	defer span.End()
+
 	logger.WithFields(log.Fields{
 		"cmd":  cmd.Path,
 		"args": cmd.Args,
@@ -92,6 +102,7 @@ func Run(cmd *exec.Cmd, logger log.FieldLogger, outputLogger OutputLogger) ([]by
 	wg.Wait()
 
 	if err != nil {
+		tracing.RecordError(span, err)
 		logger.WithError(err).Error("failed invocation")
 
 		return stdout.Bytes(), stderr.Bytes(), errors.Wrap(err, "failed invocation")
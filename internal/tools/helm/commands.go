@@ -5,8 +5,11 @@
 package helm
 
 import (
-	"github.com/pkg/errors"
 	"os/exec"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
+	"github.com/pkg/errors"
 )
 
 // RunGenericCommand runs any given helm command.
@@ -21,6 +24,8 @@ func (c *Cmd) RunGenericCommand(arg ...string) error {
 
 // RunCommandRaw runs any given helm command returning raw output.
 func (c *Cmd) RunCommandRaw(arg ...string) ([]byte, error) {
+	defer metrics.ObserveToolCommand("helm", arg, time.Now())
+
 	cmd := exec.Command(c.helmPath, arg...)
 	return cmd.Output()
 }
@@ -7,7 +7,9 @@ package helm
 import (
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/internal/tools/exechelper"
 	log "github.com/sirupsen/logrus"
 )
@@ -22,6 +24,8 @@ func outputLogger(line string, logger log.FieldLogger) {
 }
 
 func (c *Cmd) run(arg ...string) ([]byte, []byte, error) {
+	defer metrics.ObserveToolCommand("helm", arg, time.Now())
+
 	cmd := exec.Command(c.helmPath, arg...)
 
 	return exechelper.Run(cmd, c.logger, outputLogger)
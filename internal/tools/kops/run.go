@@ -11,8 +11,11 @@ import (
 	"os/exec"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/internal/tools/exechelper"
+	"github.com/mattermost/mattermost-cloud/internal/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -65,6 +68,8 @@ func outputLogger(line string, logger log.FieldLogger) {
 }
 
 func (c *Cmd) run(arg ...string) ([]byte, []byte, error) {
+	defer metrics.ObserveToolCommand("kops", arg, time.Now())
+
 	cmd := exec.Command(c.kopsPath, arg...)
 	cmd.Env = append(
 		os.Environ(),
@@ -76,6 +81,8 @@ func (c *Cmd) run(arg ...string) ([]byte, []byte, error) {
 }
 
 func (c *Cmd) runSilent(arg ...string) ([]byte, []byte, error) {
+	defer metrics.ObserveToolCommand("kops", arg, time.Now())
+
 	cmd := exec.Command(c.kopsPath, arg...)
 	cmd.Env = append(
 		os.Environ(),
@@ -83,7 +90,10 @@ func (c *Cmd) runSilent(arg ...string) ([]byte, []byte, error) {
 		fmt.Sprintf("KUBECONFIG=%s", c.GetKubeConfigPath()),
 	)
 
-	return exechelper.Run(cmd, silentLogger(), func(string, log.FieldLogger) {})
+	// Keep the span of the caller so that silent runs are still traced.
+	logger := silentLogger().WithFields(log.Fields{}).WithContext(tracing.Context(c.logger))
+
+	return exechelper.Run(cmd, logger, func(string, log.FieldLogger) {})
 }
 
 func silentLogger() log.FieldLogger {
@@ -9,7 +9,9 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/internal/tools/exechelper"
 	log "github.com/sirupsen/logrus"
 )
@@ -38,6 +40,8 @@ func outputLogger(line string, logger log.FieldLogger) {
 }
 
 func (c *Cmd) run(arg ...string) ([]byte, []byte, error) {
+	defer metrics.ObserveToolCommand("terraform", arg, time.Now())
+
 	cmd := exec.Command(c.terraformPath, append(arg, "-no-color")...)
 	cmd.Dir = c.dir
 	cmd.Env = append(os.Environ(), "TF_IN_AUTOMATION=1")
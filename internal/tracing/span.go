@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// resourceContexts holds the context of the span currently transitioning each
+// resource. Supervisors lock resources before transitioning them, so at most
+// one such span exists per resource.
+var resourceContexts = struct {
+	sync.RWMutex
+	contexts map[string]context.Context
+}{
+	contexts: make(map[string]context.Context),
+}
+
+// Context returns the context carried by the given logger, or a background
+// context if it does not carry one.
+func Context(logger log.FieldLogger) context.Context {
+	entry, ok := logger.(*log.Entry)
+	if !ok || entry.Context == nil {
+		return context.Background()
+	}
+
+	return entry.Context
+}
+
+// StartSpan starts a span as a child of the span carried by the given logger,
+// returning a logger carrying the new span for use by callees. The caller
+// must end the span.
+func StartSpan(logger log.FieldLogger, name string, attributes ...attribute.KeyValue) (trace.Span, *log.Entry) {
+	return startSpan(Context(logger), logger, name, attributes)
+}
+
+// StartSpanFromContext starts a span as a child of the span carried by the
+// given context. The caller must end the span.
+func StartSpanFromContext(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// StartResourceSpan starts a span like StartSpan and registers it as the
+// parent of spans started with StartResourceChildSpan for the given resource
+// until it ends.
+func StartResourceSpan(logger log.FieldLogger, resourceID, name string, attributes ...attribute.KeyValue) (trace.Span, *log.Entry) {
+	span, spanLogger := StartSpan(logger, name, attributes...)
+
+	resourceContexts.Lock()
+	resourceContexts.contexts[resourceID] = spanLogger.Context
+	resourceContexts.Unlock()
+
+	return &resourceSpan{Span: span, resourceID: resourceID}, spanLogger
+}
+
+// StartResourceChildSpan starts a span as a child of the span carried by the
+// given logger or, failing that, of the span transitioning the first of the
+// given resources to have one. This lets components which are handed the
+// resources being transitioned, but not the supervisor's logger, attach their
+// spans to the transition.
+func StartResourceChildSpan(logger log.FieldLogger, name string, resourceIDs ...string) (trace.Span, *log.Entry) {
+	ctx := Context(logger)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		resourceContexts.RLock()
+		for _, resourceID := range resourceIDs {
+			if resourceCtx, ok := resourceContexts.contexts[resourceID]; ok {
+				ctx = resourceCtx
+				break
+			}
+		}
+		resourceContexts.RUnlock()
+	}
+
+	return startSpan(ctx, logger, name, nil)
+}
+
+// RecordError marks the given span as failed with the given error, if any.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func startSpan(ctx context.Context, logger log.FieldLogger, name string, attributes []attribute.KeyValue) (trace.Span, *log.Entry) {
+	ctx, span := StartSpanFromContext(ctx, name, attributes...)
+
+	return span, logger.WithFields(nil).WithContext(ctx)
+}
+
+// resourceSpan unregisters itself as the span transitioning its resource when
+// it ends.
+type resourceSpan struct {
+	trace.Span
+	resourceID string
+}
+
+func (s *resourceSpan) End(options ...trace.SpanOption) {
+	resourceContexts.Lock()
+	delete(resourceContexts.contexts, s.resourceID)
+	resourceContexts.Unlock()
+
+	s.Span.End(options...)
+}
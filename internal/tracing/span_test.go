@@ -0,0 +1,115 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package tracing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/internal/testlib"
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupTestTracing(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return exporter
+}
+
+func TestSpans(t *testing.T) {
+	exporter := setupTestTracing(t)
+	logger := testlib.MakeLogger(t)
+
+	t.Run("child of logger span", func(t *testing.T) {
+		exporter.Reset()
+
+		parent, parentLogger := StartSpan(logger, "parent")
+		child, _ := StartSpan(parentLogger.WithField("key", "value"), "child")
+		RecordError(child, errors.New("failure"))
+		child.End()
+		parent.End()
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 2)
+		require.Equal(t, "child", spans[0].Name)
+		require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+		require.Equal(t, codes.Error, spans[0].StatusCode)
+		require.False(t, spans[1].Parent.IsValid())
+	})
+
+	t.Run("child of resource span", func(t *testing.T) {
+		exporter.Reset()
+		resourceID := model.NewID()
+
+		transition, _ := StartResourceSpan(logger, resourceID, "transition")
+		child, _ := StartResourceChildSpan(logger, "provisioner", model.NewID(), resourceID)
+		child.End()
+		transition.End()
+
+		orphan, _ := StartResourceChildSpan(logger, "orphan", resourceID)
+		orphan.End()
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 3)
+		require.Equal(t, "provisioner", spans[0].Name)
+		require.Equal(t, transition.SpanContext().SpanID(), spans[0].Parent.SpanID())
+		require.Equal(t, "orphan", spans[2].Name)
+		require.False(t, spans[2].Parent.IsValid())
+	})
+
+	t.Run("logger span takes precedence", func(t *testing.T) {
+		exporter.Reset()
+		resourceID := model.NewID()
+
+		transition, _ := StartResourceSpan(logger, resourceID, "transition")
+		parent, parentLogger := StartSpan(logger, "parent")
+		child, _ := StartResourceChildSpan(parentLogger, "child", resourceID)
+		child.End()
+		parent.End()
+		transition.End()
+
+		spans := exporter.GetSpans()
+		require.Len(t, spans, 3)
+		require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	})
+}
+
+func TestSetupDisabled(t *testing.T) {
+	shutdown, err := Setup(Config{}, model.NewID(), testlib.MakeLogger(t))
+	require.NoError(t, err)
+	shutdown()
+}
+
+func TestSetupFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tracing")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	file := filepath.Join(dir, "spans.json")
+	shutdown, err := Setup(Config{File: file}, model.NewID(), testlib.MakeLogger(t))
+	require.NoError(t, err)
+
+	span, _ := StartSpan(testlib.MakeLogger(t), "exported")
+	span.End()
+	shutdown()
+
+	contents, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "exported")
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+// Package tracing records OpenTelemetry spans of the work done by the
+// provisioning server.
+//
+// The provisioner does not thread a context.Context through its call chains,
+// but it does thread loggers. Spans are therefore carried from caller to
+// callee in the context of the logrus entry passed along, and supervisors
+// additionally register the span of the transition they are running against
+// the resource being transitioned so that the provisioner can find it.
+package tracing
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+)
+
+const (
+	// tracerName identifies the instrumentation library recording spans.
+	tracerName = "github.com/mattermost/mattermost-cloud"
+	// serviceName identifies the provisioning server in exported spans.
+	serviceName = "mattermost-cloud-provisioner"
+)
+
+// Config describes where spans are exported.
+type Config struct {
+	// OTLPEndpoint is the host:port of an OTLP gRPC collector.
+	OTLPEndpoint string
+	// OTLPInsecure disables TLS when connecting to the OTLP collector.
+	OTLPInsecure bool
+	// File is the path of a file to which spans are appended as JSON.
+	File string
+}
+
+// Enabled returns whether the configuration exports spans anywhere.
+func (c Config) Enabled() bool {
+	return c.OTLPEndpoint != "" || c.File != ""
+}
+
+// Setup installs a global tracer provider exporting spans as configured. The
+// returned function flushes pending spans and must be called on shutdown. If
+// the configuration does not enable any exporter, spans are not recorded.
+func Setup(config Config, instanceID string, logger log.FieldLogger) (func(), error) {
+	if !config.Enabled() {
+		return func() {}, nil
+	}
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceInstanceIDKey.String(instanceID),
+		)),
+	}
+	var closers []func(context.Context) error
+
+	if config.OTLPEndpoint != "" {
+		driverOptions := []otlpgrpc.Option{otlpgrpc.WithEndpoint(config.OTLPEndpoint)}
+		if config.OTLPInsecure {
+			driverOptions = append(driverOptions, otlpgrpc.WithInsecure())
+		}
+
+		// The exporter connects in the background, so an unreachable collector
+		// does not prevent the server from starting.
+		exporter, err := otlp.NewExporter(context.Background(), otlpgrpc.NewDriver(driverOptions...))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create OTLP span exporter")
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+		logger.WithField("endpoint", config.OTLPEndpoint).Info("Exporting trace spans over OTLP")
+	}
+
+	if config.File != "" {
+		file, err := os.OpenFile(config.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to open trace span file")
+		}
+		exporter, err := stdout.NewExporter(stdout.WithWriter(file), stdout.WithoutMetricExport())
+		if err != nil {
+			file.Close()
+			return nil, errors.Wrap(err, "failed to create file span exporter")
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+		closers = append(closers, func(context.Context) error { return file.Close() })
+		logger.WithField("file", config.File).Info("Exporting trace spans to file")
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+
+	shutdown := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		err := provider.Shutdown(ctx)
+		if err != nil {
+			logger.WithError(err).Error("Failed to flush trace spans")
+		}
+		for _, closer := range closers {
+			err = closer(ctx)
+			if err != nil {
+				logger.WithError(err).Error("Failed to close trace span exporter")
+			}
+		}
+	}
+
+	return shutdown, nil
+}
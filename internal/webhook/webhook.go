@@ -6,20 +6,42 @@ package webhook
 
 import (
 	"bytes"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/mattermost/mattermost-cloud/internal/events"
+	"github.com/mattermost/mattermost-cloud/internal/metrics"
 	"github.com/mattermost/mattermost-cloud/model"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	// MaxDeliveryAttempts is the number of times a delivery is attempted
+	// before it is marked as failed.
+	MaxDeliveryAttempts = 10
+
+	initialRetryDelay     = 10 * time.Second
+	maxRetryDelay         = time.Hour
+	maxResponseBodyLength = 1024
+)
+
 type webhookStore interface {
 	GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error)
+	CreateWebhookDelivery(delivery *model.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *model.WebhookDelivery) error
 }
 
-// SendToAllWebhooks sends a given payload to all webhooks.
+// SendToAllWebhooks publishes the given payload to the event stream, then
+// records a delivery of it for every matching webhook and makes a first
+// attempt at sending each of them. Deliveries that fail are retried by the
+// webhook delivery supervisor.
 func SendToAllWebhooks(store webhookStore, payload *model.WebhookPayload, logger *log.Entry) error {
+	events.DefaultBroker.Publish(payload)
+
 	hooks, err := store.GetWebhooks(&model.WebhookFilter{
 		PerPage:        model.AllPerPage,
 		IncludeDeleted: false,
@@ -28,41 +50,130 @@ func SendToAllWebhooks(store webhookStore, payload *model.WebhookPayload, logger
 		return errors.Wrap(err, "Failed to find webhooks")
 	}
 
-	sendWebhooks(hooks, payload, logger)
+	return sendWebhooks(store, hooks, payload, logger)
+}
+
+// sendWebhooks persists a delivery for each webhook and then attempts them via
+// goroutines. Failed attempts are recorded on the delivery to be retried later.
+func sendWebhooks(store webhookStore, hooks []*model.Webhook, payload *model.WebhookPayload, logger *log.Entry) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payloadStr, err := payload.ToJSON()
+	if err != nil {
+		return errors.Wrap(err, "unable to create payload string to send to webhook")
+	}
+
+	var matchingHooks []*model.Webhook
+	for _, hook := range hooks {
+		if hook.Matches(payload) {
+			matchingHooks = append(matchingHooks, hook)
+		}
+	}
+
+	logger.Debugf("Sending %d webhook(s)", len(matchingHooks))
+
+	for _, hook := range matchingHooks {
+		delivery := &model.WebhookDelivery{
+			WebhookID:     hook.ID,
+			Payload:       payloadStr,
+			State:         model.WebhookDeliveryStatePending,
+			NextAttemptAt: nowMillis() + initialRetryDelay.Milliseconds(),
+		}
+		err = store.CreateWebhookDelivery(delivery)
+		if err != nil {
+			logger.WithField("webhookURL", hook.URL).WithError(err).Error("Unable to record webhook delivery")
+			continue
+		}
+
+		go func(hook *model.Webhook, delivery *model.WebhookDelivery) {
+			AttemptDelivery(hook, delivery, logger)
+			err := store.UpdateWebhookDelivery(delivery)
+			if err != nil {
+				logger.WithField("webhookURL", hook.URL).WithError(err).Error("Unable to record webhook delivery attempt")
+			}
+		}(hook, delivery)
+	}
 
 	return nil
 }
 
-// sendWebhooks sends webhooks via fire-and-forget goroutines. The send-webhook
-// failures are logged, but not handled.
-func sendWebhooks(hooks []*model.Webhook, payload *model.WebhookPayload, logger *log.Entry) {
-	if len(hooks) == 0 {
+// AttemptDelivery sends the payload of the given delivery to the webhook and
+// records the outcome on the delivery. The caller is responsible for
+// persisting the updated delivery.
+func AttemptDelivery(hook *model.Webhook, delivery *model.WebhookDelivery, logger log.FieldLogger) {
+	delivery.Attempts++
+	delivery.LastAttemptAt = nowMillis()
+
+	responseCode, responseBody, err := sendWebhook(hook, delivery.Payload)
+	delivery.ResponseCode = responseCode
+	delivery.ResponseBody = responseBody
+	if err == nil {
+		metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookDeliverySuccess).Inc()
+		delivery.State = model.WebhookDeliveryStateDelivered
+		delivery.LastError = ""
 		return
 	}
 
-	logger.Debugf("Sending %d webhook(s)", len(hooks))
+	metrics.WebhookDeliveries.WithLabelValues(metrics.WebhookDeliveryFailure).Inc()
+	logger.WithField("webhookURL", hook.URL).WithError(err).Warnf("Webhook delivery attempt %d failed", delivery.Attempts)
+	delivery.LastError = err.Error()
 
-	for _, hook := range hooks {
-		go sendWebhook(hook, payload, logger)
+	if delivery.Attempts >= MaxDeliveryAttempts {
+		delivery.State = model.WebhookDeliveryStateFailed
+		return
 	}
+
+	delivery.State = model.WebhookDeliveryStatePending
+	delivery.NextAttemptAt = delivery.LastAttemptAt + retryDelay(delivery.Attempts).Milliseconds()
 }
 
-func sendWebhook(hook *model.Webhook, payload *model.WebhookPayload, logger *log.Entry) error {
-	payloadStr, err := payload.ToJSON()
-	if err != nil {
-		logger.WithField("webhookURL", hook.URL).WithError(err).Error("Unable to create payload string to send to webhook")
-		return errors.Wrap(err, "unable to create payload string to send to webhook")
+// retryDelay returns the exponential backoff delay to wait after the given
+// number of failed attempts.
+func retryDelay(attempts int) time.Duration {
+	delay := initialRetryDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
 	}
 
-	req, err := http.NewRequest("POST", hook.URL, bytes.NewBuffer([]byte(payloadStr)))
+	return delay
+}
+
+func sendWebhook(hook *model.Webhook, payload string) (int, string, error) {
+	req, err := http.NewRequest("POST", hook.URL, bytes.NewBuffer([]byte(payload)))
+	if err != nil {
+		return 0, "", errors.Wrap(err, "unable to create webhook request")
+	}
 	req.Header.Set("Content-Type", "application/json")
+	if hook.IsSigned() {
+		timestamp := nowMillis()
+		req.Header.Set(model.WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(model.WebhookSignatureHeader, model.SignWebhookPayload(hook.Secret, timestamp, []byte(payload)))
+	}
 
 	client := &http.Client{Timeout: 5 * time.Second}
-	_, err = client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
-		logger.WithField("webhookURL", hook.URL).WithError(err).Error("Unable to send webhook")
-		return errors.Wrap(err, "unable to send webhook")
+		return 0, "", errors.Wrap(err, "unable to send webhook")
 	}
+	defer resp.Body.Close()
 
-	return nil
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBodyLength))
+	if err != nil {
+		return resp.StatusCode, "", errors.Wrap(err, "unable to read webhook response")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, string(body), errors.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, string(body), nil
+}
+
+func nowMillis() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
 }
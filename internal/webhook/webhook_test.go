@@ -5,9 +5,13 @@
 package webhook
 
 import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/mattermost/mattermost-cloud/internal/events"
 	"github.com/mattermost/mattermost-cloud/internal/testlib"
 	"github.com/mattermost/mattermost-cloud/model"
 	log "github.com/sirupsen/logrus"
@@ -15,13 +19,23 @@ import (
 )
 
 type mockWebhookStore struct {
-	Webhooks []*model.Webhook
+	Webhooks   []*model.Webhook
+	Deliveries []*model.WebhookDelivery
 }
 
 func (s *mockWebhookStore) GetWebhooks(filter *model.WebhookFilter) ([]*model.Webhook, error) {
 	return s.Webhooks, nil
 }
 
+func (s *mockWebhookStore) CreateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	s.Deliveries = append(s.Deliveries, delivery)
+	return nil
+}
+
+func (s *mockWebhookStore) UpdateWebhookDelivery(delivery *model.WebhookDelivery) error {
+	return nil
+}
+
 func TestGetAndSendWebhooks(t *testing.T) {
 	mockStore := &mockWebhookStore{}
 	logger := testlib.MakeLogger(t).WithFields(log.Fields{
@@ -60,18 +74,38 @@ func TestGetAndSendWebhooks(t *testing.T) {
 	})
 }
 
-// TODO: add happy-path test.
-func TestSendWebhooks(t *testing.T) {
+func TestSendWebhooksFilters(t *testing.T) {
 	logger := testlib.MakeLogger(t).WithFields(log.Fields{
 		"webhooks-tests": true,
 	})
-	hook := &model.Webhook{
+	mockStore := &mockWebhookStore{
+		Webhooks: []*model.Webhook{
+			{ID: model.NewID(), URL: "https://all.com"},
+			{ID: model.NewID(), URL: "https://clusters.com", EventTypes: []string{model.TypeCluster}},
+			{ID: model.NewID(), URL: "https://owner.com", EventOwnerID: "owner1"},
+			{ID: model.NewID(), URL: "https://failures.com", EventStates: []string{"*-failed"}},
+		},
+	}
+
+	payload := &model.WebhookPayload{
+		Type:     model.TypeInstallation,
 		ID:       model.NewID(),
-		OwnerID:  model.NewID(),
-		URL:      "https://not-a-real-host",
-		CreateAt: 10,
-		DeleteAt: 0,
+		OwnerID:  "owner1",
+		NewState: model.InstallationStateStable,
 	}
+
+	subscription := events.DefaultBroker.Subscribe(model.EventStreamFilter{})
+	defer subscription.Close()
+
+	err := SendToAllWebhooks(mockStore, payload, logger)
+	require.NoError(t, err)
+	require.Equal(t, payload, <-subscription.Events())
+	require.Len(t, mockStore.Deliveries, 2)
+	require.Equal(t, mockStore.Webhooks[0].ID, mockStore.Deliveries[0].WebhookID)
+	require.Equal(t, mockStore.Webhooks[2].ID, mockStore.Deliveries[1].WebhookID)
+}
+
+func TestSendWebhooks(t *testing.T) {
 	payload := &model.WebhookPayload{
 		Type:      "type",
 		ID:        model.NewID(),
@@ -80,7 +114,134 @@ func TestSendWebhooks(t *testing.T) {
 		Timestamp: time.Now().UnixNano(),
 		ExtraData: map[string]string{"ClusterID": model.NewID()},
 	}
+	payloadStr, err := payload.ToJSON()
+	require.NoError(t, err)
+
+	t.Run("unreachable host", func(t *testing.T) {
+		hook := &model.Webhook{
+			ID:       model.NewID(),
+			OwnerID:  model.NewID(),
+			URL:      "https://not-a-real-host",
+			CreateAt: 10,
+			DeleteAt: 0,
+		}
+
+		_, _, err = sendWebhook(hook, payloadStr)
+		require.Contains(t, err.Error(), "unable to send webhook")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var received string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			received = string(body)
+			w.Write([]byte("ok"))
+		}))
+		defer ts.Close()
+
+		hook := &model.Webhook{ID: model.NewID(), URL: ts.URL}
+
+		responseCode, responseBody, err := sendWebhook(hook, payloadStr)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, responseCode)
+		require.Equal(t, "ok", responseBody)
+		require.Equal(t, payloadStr, received)
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		var verifyErr error
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			verifyErr = model.VerifyWebhookSignature("secret", body,
+				r.Header.Get(model.WebhookTimestampHeader),
+				r.Header.Get(model.WebhookSignatureHeader),
+			)
+		}))
+		defer ts.Close()
+
+		hook := &model.Webhook{ID: model.NewID(), URL: ts.URL, Secret: "secret"}
+
+		_, _, err := sendWebhook(hook, payloadStr)
+		require.NoError(t, err)
+		require.NoError(t, verifyErr)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		var header http.Header
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header = r.Header
+		}))
+		defer ts.Close()
+
+		hook := &model.Webhook{ID: model.NewID(), URL: ts.URL}
+
+		_, _, err := sendWebhook(hook, payloadStr)
+		require.NoError(t, err)
+		require.Empty(t, header.Get(model.WebhookSignatureHeader))
+		require.Empty(t, header.Get(model.WebhookTimestampHeader))
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		hook := &model.Webhook{ID: model.NewID(), URL: ts.URL}
+
+		responseCode, _, err := sendWebhook(hook, payloadStr)
+		require.Error(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, responseCode)
+	})
+}
+
+func TestAttemptDelivery(t *testing.T) {
+	logger := testlib.MakeLogger(t)
+
+	var status int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	hook := &model.Webhook{ID: model.NewID(), URL: ts.URL}
+
+	t.Run("delivered", func(t *testing.T) {
+		status = http.StatusOK
+		delivery := &model.WebhookDelivery{State: model.WebhookDeliveryStatePending}
+
+		AttemptDelivery(hook, delivery, logger)
+		require.Equal(t, model.WebhookDeliveryStateDelivered, delivery.State)
+		require.Equal(t, 1, delivery.Attempts)
+		require.Equal(t, http.StatusOK, delivery.ResponseCode)
+		require.Empty(t, delivery.LastError)
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		delivery := &model.WebhookDelivery{State: model.WebhookDeliveryStatePending, Attempts: 2}
+
+		AttemptDelivery(hook, delivery, logger)
+		require.Equal(t, model.WebhookDeliveryStatePending, delivery.State)
+		require.Equal(t, 3, delivery.Attempts)
+		require.Equal(t, http.StatusInternalServerError, delivery.ResponseCode)
+		require.NotEmpty(t, delivery.LastError)
+		require.Equal(t, delivery.LastAttemptAt+retryDelay(3).Milliseconds(), delivery.NextAttemptAt)
+	})
+
+	t.Run("failed after max attempts", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		delivery := &model.WebhookDelivery{State: model.WebhookDeliveryStatePending, Attempts: MaxDeliveryAttempts - 1}
+
+		AttemptDelivery(hook, delivery, logger)
+		require.Equal(t, model.WebhookDeliveryStateFailed, delivery.State)
+		require.Equal(t, MaxDeliveryAttempts, delivery.Attempts)
+	})
+}
 
-	err := sendWebhook(hook, payload, logger)
-	require.Contains(t, err.Error(), "unable to send webhook")
+func TestRetryDelay(t *testing.T) {
+	require.Equal(t, initialRetryDelay, retryDelay(1))
+	require.Equal(t, 2*initialRetryDelay, retryDelay(2))
+	require.Equal(t, 4*initialRetryDelay, retryDelay(3))
+	require.Equal(t, maxRetryDelay, retryDelay(100))
 }
@@ -16,6 +16,15 @@ const (
 	annotationAllowedFormat = "annotations must start with a letter and can contain only lowercase letters, numbers or '_', '-' characters"
 )
 
+// AnnotationOnDemandCluster is the annotation added to clusters which were
+// created by the installation supervisor because no existing cluster could
+// accept a new installation.
+const AnnotationOnDemandCluster = "on-demand"
+
+// AnnotationNoIdleHibernation is the annotation opting an installation out of
+// being hibernated automatically when idle.
+const AnnotationNoIdleHibernation = "no-idle-hibernation"
+
 var annotationRegex = regexp.MustCompile("^[a-z]+[a-z0-9_-]*$")
 
 // Annotation represents an annotation.
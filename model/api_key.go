@@ -0,0 +1,108 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// APIKeyScopeReadOnly allows read access to every resource.
+	APIKeyScopeReadOnly = "read-only"
+	// APIKeyScopeInstallationAdmin allows managing installations, groups and
+	// webhooks in addition to read access.
+	APIKeyScopeInstallationAdmin = "installation-admin"
+	// APIKeyScopeClusterAdmin allows every operation, including cluster
+	// management and running commands inside cluster installations.
+	APIKeyScopeClusterAdmin = "cluster-admin"
+)
+
+// AllAPIKeyScopes is a list of all API key scopes.
+var AllAPIKeyScopes = []string{
+	APIKeyScopeReadOnly,
+	APIKeyScopeInstallationAdmin,
+	APIKeyScopeClusterAdmin,
+}
+
+// APIKeyHeader is the HTTP header used to send API keys.
+const APIKeyHeader = "Authorization"
+
+// APIKeyHeaderPrefix is the prefix of the APIKeyHeader value.
+const APIKeyHeaderPrefix = "Bearer "
+
+// APIKey is a credential used to authenticate requests to the provisioning
+// server. Only a hash of the key itself is ever stored.
+type APIKey struct {
+	ID      string
+	Name    string
+	KeyHash string `json:"-"`
+	Scopes  []string
+	// OwnerID binds the API key to an owner, limiting it to installations,
+	// cluster installations and webhooks of that owner. Keys without an owner
+	// may access every resource.
+	OwnerID  string
+	CreateAt int64
+	DeleteAt int64
+}
+
+// APIKeyFilter describes the parameters used to constrain a set of API keys.
+type APIKeyFilter struct {
+	Page           int
+	PerPage        int
+	IncludeDeleted bool
+}
+
+// IsRevoked returns whether the API key has been revoked.
+func (k *APIKey) IsRevoked() bool {
+	return k.DeleteAt != 0
+}
+
+// IsOwnerBound returns whether the API key is limited to the resources of a
+// single owner.
+func (k *APIKey) IsOwnerBound() bool {
+	return k.OwnerID != ""
+}
+
+// HasScope returns whether the API key grants the given scope. Broader scopes
+// imply narrower ones: cluster-admin grants everything and installation-admin
+// grants read-only.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, keyScope := range k.Scopes {
+		if keyScope == scope || keyScope == APIKeyScopeClusterAdmin {
+			return true
+		}
+		if keyScope == APIKeyScopeInstallationAdmin && scope == APIKeyScopeReadOnly {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsValidAPIKeyScope returns whether the given scope is known.
+func IsValidAPIKeyScope(scope string) bool {
+	return containsString(AllAPIKeyScopes, scope)
+}
+
+// NewAPIKeySecret generates a new random API key.
+func NewAPIKeySecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate random API key")
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// HashAPIKey returns the hash of the given API key as stored in the database.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
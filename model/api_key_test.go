@@ -0,0 +1,50 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyHasScope(t *testing.T) {
+	var testCases = []struct {
+		scopes   []string
+		scope    string
+		expected bool
+	}{
+		{nil, APIKeyScopeReadOnly, false},
+		{[]string{APIKeyScopeReadOnly}, APIKeyScopeReadOnly, true},
+		{[]string{APIKeyScopeReadOnly}, APIKeyScopeInstallationAdmin, false},
+		{[]string{APIKeyScopeReadOnly}, APIKeyScopeClusterAdmin, false},
+		{[]string{APIKeyScopeInstallationAdmin}, APIKeyScopeReadOnly, true},
+		{[]string{APIKeyScopeInstallationAdmin}, APIKeyScopeInstallationAdmin, true},
+		{[]string{APIKeyScopeInstallationAdmin}, APIKeyScopeClusterAdmin, false},
+		{[]string{APIKeyScopeClusterAdmin}, APIKeyScopeReadOnly, true},
+		{[]string{APIKeyScopeClusterAdmin}, APIKeyScopeInstallationAdmin, true},
+		{[]string{APIKeyScopeClusterAdmin}, APIKeyScopeClusterAdmin, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.scope, func(t *testing.T) {
+			apiKey := &APIKey{Scopes: tc.scopes}
+			require.Equal(t, tc.expected, apiKey.HasScope(tc.scope))
+		})
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	key1, err := NewAPIKeySecret()
+	require.NoError(t, err)
+	key2, err := NewAPIKeySecret()
+	require.NoError(t, err)
+
+	require.Len(t, key1, 64)
+	require.NotEqual(t, key1, key2)
+	require.Equal(t, HashAPIKey(key1), HashAPIKey(key1))
+	require.NotEqual(t, HashAPIKey(key1), HashAPIKey(key2))
+	require.NotEqual(t, key1, HashAPIKey(key1))
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// AuditRecord is a record of a mutating request made to the provisioning
+// server API.
+type AuditRecord struct {
+	ID        string
+	RequestID string
+	// APIKeyID identifies the caller. It is empty when API keys are not
+	// required by the provisioning server.
+	APIKeyID     string
+	RemoteAddr   string
+	Method       string
+	Path         string
+	ResourceType string
+	ResourceID   string
+	// RequestBody is the request body with secrets redacted.
+	RequestBody string
+	StatusCode  int
+	CreateAt    int64
+}
+
+// AuditFilter describes the parameters used to constrain a set of audit
+// records.
+type AuditFilter struct {
+	ResourceType string
+	ResourceID   string
+	// StartTime and EndTime, in milliseconds since the epoch, restrict the
+	// records to those created at or after and before the given times.
+	StartTime int64
+	EndTime   int64
+	Page      int
+	PerPage   int
+}
+
+// GetAuditRecordsRequest describes the parameters to request a list of audit
+// records.
+type GetAuditRecordsRequest struct {
+	ResourceType string
+	ResourceID   string
+	StartTime    int64
+	EndTime      int64
+	Page         int
+	PerPage      int
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetAuditRecordsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if len(request.ResourceType) != 0 {
+		q.Add("resource_type", request.ResourceType)
+	}
+	if len(request.ResourceID) != 0 {
+		q.Add("resource_id", request.ResourceID)
+	}
+	if request.StartTime != 0 {
+		q.Add("start_time", strconv.FormatInt(request.StartTime, 10))
+	}
+	if request.EndTime != 0 {
+		q.Add("end_time", strconv.FormatInt(request.EndTime, 10))
+	}
+	q.Add("page", strconv.Itoa(request.Page))
+	q.Add("per_page", strconv.Itoa(request.PerPage))
+	u.RawQuery = q.Encode()
+}
+
+// AuditRecordsFromReader decodes a json-encoded list of audit records from
+// the given io.Reader.
+func AuditRecordsFromReader(reader io.Reader) ([]*AuditRecord, error) {
+	records := []*AuditRecord{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&records)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BackupStateRequested is a backup waiting to be taken.
+	BackupStateRequested = "backup-requested"
+	// BackupStateInProgress is a backup whose filestore was copied and whose
+	// database snapshot is being taken.
+	BackupStateInProgress = "backup-in-progress"
+	// BackupStateSucceeded is a backup that can be restored.
+	BackupStateSucceeded = "backup-succeeded"
+	// BackupStateFailed is a backup that could not be taken.
+	BackupStateFailed = "backup-failed"
+)
+
+// AllBackupStatesPendingWork is a list of all backup states that the backup
+// supervisor will attempt to complete on the next "tick".
+var AllBackupStatesPendingWork = []string{
+	BackupStateRequested,
+	BackupStateInProgress,
+}
+
+// Backup is a copy of the database and filestore of an installation at a
+// point in time, taken in the background by a supervisor.
+type Backup struct {
+	ID             string
+	InstallationID string
+	State          string
+	// DatabaseSnapshotID is the identifier of the RDS cluster snapshot of the
+	// installation database.
+	DatabaseSnapshotID string `json:",omitempty"`
+	// FilestoreLocation is the S3 URL under which the objects of the
+	// installation filestore were copied.
+	FilestoreLocation string `json:",omitempty"`
+	// Size is the size of the backup in bytes, known once it succeeded.
+	Size           int64
+	Error          string `json:",omitempty"`
+	CreateAt       int64
+	CompleteAt     int64
+	LockAcquiredBy *string
+	LockAcquiredAt int64
+}
+
+// IsRestorable returns whether the backup can be restored.
+func (b *Backup) IsRestorable() bool {
+	return b.State == BackupStateSucceeded
+}
+
+// BackupFilter describes the parameters used to constrain a set of backups.
+type BackupFilter struct {
+	InstallationID string
+	State          string
+	Page           int
+	PerPage        int
+}
+
+// ValidateBackupSupport returns an error if the database or filestore of the
+// given installation cannot be backed up and restored.
+//
+// Backups rely on RDS cluster snapshots, so only installations with a
+// single-tenant RDS database are supported. Multitenant databases share their
+// cluster with other installations, and operator databases have no snapshots;
+// the latter can be migrated to an RDS database first.
+func ValidateBackupSupport(installation *Installation) error {
+	switch installation.Database {
+	case InstallationDatabaseSingleTenantRDSMySQL, InstallationDatabaseSingleTenantRDSPostgres:
+	case InstallationDatabaseMysqlOperator:
+		return errors.Errorf("backups are not supported for %s databases; migrate the installation to a %s database first", installation.Database, InstallationDatabaseSingleTenantRDSMySQL)
+	default:
+		return errors.Errorf("backups are not supported for %s databases", installation.Database)
+	}
+
+	switch installation.Filestore {
+	case InstallationFilestoreAwsS3, InstallationFilestoreMultiTenantAwsS3:
+	default:
+		return errors.Errorf("backups are not supported for %s filestores", installation.Filestore)
+	}
+
+	return nil
+}
+
+// GetBackupsRequest describes the parameters to request a list of backups.
+type GetBackupsRequest struct {
+	State   string
+	Page    int
+	PerPage int
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetBackupsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if request.State != "" {
+		q.Add("state", request.State)
+	}
+	q.Add("page", strconv.Itoa(request.Page))
+	q.Add("per_page", strconv.Itoa(request.PerPage))
+	u.RawQuery = q.Encode()
+}
+
+// BackupFromReader decodes a json-encoded backup from the given io.Reader.
+func BackupFromReader(reader io.Reader) (*Backup, error) {
+	backup := Backup{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&backup)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &backup, nil
+}
+
+// BackupsFromReader decodes a json-encoded list of backups from the given
+// io.Reader.
+func BackupsFromReader(reader io.Reader) ([]*Backup, error) {
+	backups := []*Backup{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&backups)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return backups, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateBackupSupport(t *testing.T) {
+	require.NoError(t, model.ValidateBackupSupport(&model.Installation{
+		Database:  model.InstallationDatabaseSingleTenantRDSMySQL,
+		Filestore: model.InstallationFilestoreAwsS3,
+	}))
+	require.NoError(t, model.ValidateBackupSupport(&model.Installation{
+		Database:  model.InstallationDatabaseSingleTenantRDSPostgres,
+		Filestore: model.InstallationFilestoreMultiTenantAwsS3,
+	}))
+	require.Error(t, model.ValidateBackupSupport(&model.Installation{
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreAwsS3,
+	}))
+	require.Error(t, model.ValidateBackupSupport(&model.Installation{
+		Database:  model.InstallationDatabaseMultiTenantRDSMySQL,
+		Filestore: model.InstallationFilestoreAwsS3,
+	}))
+	require.Error(t, model.ValidateBackupSupport(&model.Installation{
+		Database:  model.InstallationDatabaseSingleTenantRDSMySQL,
+		Filestore: model.InstallationFilestoreMinioOperator,
+	}))
+}
+
+func TestInstallationValidTransitionStateRestoration(t *testing.T) {
+	for state, valid := range map[string]bool{
+		model.InstallationStateHibernating:       true,
+		model.InstallationStateRestorationFailed: true,
+		model.InstallationStateStable:            false,
+		model.InstallationStateUpdateInProgress:  false,
+	} {
+		installation := &model.Installation{State: state}
+		require.Equal(t, valid, installation.ValidTransitionState(model.InstallationStateRestorationRequested), state)
+	}
+}
@@ -0,0 +1,253 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// BulkActionHibernate requests the hibernation of each installation.
+	BulkActionHibernate = "hibernate"
+	// BulkActionWakeUp requests each hibernating installation to wake up.
+	BulkActionWakeUp = "wake-up"
+	// BulkActionUpdate applies the operation's patch to each installation.
+	BulkActionUpdate = "update"
+	// BulkActionJoinGroup moves each installation into the operation's group.
+	BulkActionJoinGroup = "join-group"
+	// BulkActionDelete requests the deletion of each installation.
+	BulkActionDelete = "delete"
+)
+
+const (
+	// BulkOperationStatePending is an operation waiting to be run.
+	BulkOperationStatePending = "pending"
+	// BulkOperationStateCompleted is an operation that was run against every
+	// selected installation. Individual installations may still have failed.
+	BulkOperationStateCompleted = "completed"
+	// BulkOperationStateFailed is an operation that could not be run at all.
+	BulkOperationStateFailed = "failed"
+)
+
+const (
+	// BulkResultSucceeded is an installation to which the action was applied.
+	BulkResultSucceeded = "succeeded"
+	// BulkResultPlanned is an installation to which a dry-run would have
+	// applied the action.
+	BulkResultPlanned = "planned"
+	// BulkResultSkipped is an installation to which the action does not apply,
+	// for example because of its state or because it would not change.
+	BulkResultSkipped = "skipped"
+	// BulkResultFailed is an installation to which the action could not be
+	// applied.
+	BulkResultFailed = "failed"
+)
+
+const (
+	// DefaultBulkOperationConcurrency is the number of installations acted on
+	// at once when a request does not specify it.
+	DefaultBulkOperationConcurrency = 5
+	// MaxBulkOperationConcurrency caps the number of installations acted on at
+	// once.
+	MaxBulkOperationConcurrency = 25
+)
+
+// BulkOperation is an action applied to every installation matching a
+// filter, run in the background by a supervisor.
+type BulkOperation struct {
+	ID             string
+	Action         string
+	Filter         *InstallationFilter
+	Patch          *PatchInstallationRequest `json:",omitempty"`
+	GroupID        string                    `json:",omitempty"`
+	DryRun         bool
+	Concurrency    int
+	State          string
+	Error          string `json:",omitempty"`
+	CreateAt       int64
+	CompleteAt     int64
+	LockAcquiredBy *string
+	LockAcquiredAt int64
+	// Results holds the outcome for each installation acted on so far. It is
+	// only populated when fetching a single bulk operation.
+	Results []*BulkOperationResult `json:",omitempty"`
+}
+
+// IsPending returns whether the bulk operation has yet to be run.
+func (o *BulkOperation) IsPending() bool {
+	return o.State == BulkOperationStatePending
+}
+
+// BulkOperationResult is the outcome of a bulk operation for a single
+// installation.
+type BulkOperationResult struct {
+	ID              string
+	BulkOperationID string
+	InstallationID  string
+	OldState        string
+	NewState        string
+	Status          string
+	Message         string `json:",omitempty"`
+	CreateAt        int64
+}
+
+// BulkOperationFilter describes the parameters used to constrain a set of
+// bulk operations.
+type BulkOperationFilter struct {
+	State   string
+	Page    int
+	PerPage int
+}
+
+// CreateBulkOperationRequest specifies the parameters of a new bulk operation.
+type CreateBulkOperationRequest struct {
+	// Filter selects the installations to act on. Paging is ignored: every
+	// matching installation which is not deleted is acted on.
+	Filter InstallationFilter
+	// All must be set to act on every installation when Filter selects none
+	// in particular, guarding against acting on all of them by mistake.
+	All    bool
+	Action string
+	// Patch is required by the update action.
+	Patch *PatchInstallationRequest
+	// GroupID is required by the join-group action.
+	GroupID string
+	// DryRun reports the installations that would be acted on without
+	// modifying them.
+	DryRun bool
+	// Concurrency is the number of installations acted on at once.
+	Concurrency int
+}
+
+// SetDefaults sets the default values for a bulk operation request.
+func (request *CreateBulkOperationRequest) SetDefaults() {
+	if request.Concurrency == 0 {
+		request.Concurrency = DefaultBulkOperationConcurrency
+	}
+}
+
+// Validate validates the values of a bulk operation request.
+func (request *CreateBulkOperationRequest) Validate() error {
+	switch request.Action {
+	case BulkActionHibernate, BulkActionWakeUp, BulkActionDelete:
+	case BulkActionUpdate:
+		if request.Patch == nil {
+			return errors.New("must specify a patch for the update action")
+		}
+		err := request.Patch.Validate()
+		if err != nil {
+			return errors.Wrap(err, "invalid patch")
+		}
+	case BulkActionJoinGroup:
+		if request.GroupID == "" {
+			return errors.New("must specify a group for the join-group action")
+		}
+	default:
+		return errors.Errorf("unsupported action %s", request.Action)
+	}
+
+	if request.Concurrency < 1 || request.Concurrency > MaxBulkOperationConcurrency {
+		return errors.Errorf("concurrency must be between 1 and %d", MaxBulkOperationConcurrency)
+	}
+
+	if !request.All && !request.hasSelector() {
+		return errors.New("must filter the installations to act on, or explicitly select all of them")
+	}
+
+	err := ValidateSort(request.Filter.SortBy, request.Filter.SortOrder, InstallationSortKeys)
+	if err != nil {
+		return errors.Wrap(err, "invalid filter")
+	}
+
+	return nil
+}
+
+// hasSelector returns whether the filter of the request narrows down the
+// installations to act on.
+func (request *CreateBulkOperationRequest) hasSelector() bool {
+	filter := request.Filter
+
+	return filter.OwnerID != "" ||
+		filter.GroupID != "" ||
+		filter.DNS != "" ||
+		len(filter.States) != 0 ||
+		len(filter.Annotations) != 0 ||
+		filter.Version != "" ||
+		filter.Image != "" ||
+		filter.Database != "" ||
+		filter.Filestore != "" ||
+		filter.Size != "" ||
+		filter.Affinity != "" ||
+		filter.CreatedAfter != 0 ||
+		filter.CreatedBefore != 0
+}
+
+// NewCreateBulkOperationRequestFromReader will create a CreateBulkOperationRequest from an io.Reader with JSON data.
+func NewCreateBulkOperationRequestFromReader(reader io.Reader) (*CreateBulkOperationRequest, error) {
+	var createBulkOperationRequest CreateBulkOperationRequest
+	err := json.NewDecoder(reader).Decode(&createBulkOperationRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode create bulk operation request")
+	}
+
+	createBulkOperationRequest.SetDefaults()
+	err = createBulkOperationRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "create bulk operation request failed validation")
+	}
+
+	return &createBulkOperationRequest, nil
+}
+
+// GetBulkOperationsRequest describes the parameters to request a list of bulk
+// operations.
+type GetBulkOperationsRequest struct {
+	State   string
+	Page    int
+	PerPage int
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetBulkOperationsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if request.State != "" {
+		q.Add("state", request.State)
+	}
+	q.Add("page", strconv.Itoa(request.Page))
+	q.Add("per_page", strconv.Itoa(request.PerPage))
+	u.RawQuery = q.Encode()
+}
+
+// BulkOperationFromReader decodes a json-encoded bulk operation from the
+// given io.Reader.
+func BulkOperationFromReader(reader io.Reader) (*BulkOperation, error) {
+	bulkOperation := BulkOperation{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&bulkOperation)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &bulkOperation, nil
+}
+
+// BulkOperationsFromReader decodes a json-encoded list of bulk operations
+// from the given io.Reader.
+func BulkOperationsFromReader(reader io.Reader) ([]*BulkOperation, error) {
+	bulkOperations := []*BulkOperation{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&bulkOperations)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return bulkOperations, nil
+}
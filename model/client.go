@@ -11,10 +11,22 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
+const (
+	// createAttempts is the number of times a create request is sent before
+	// giving up.
+	createAttempts = 3
+	// createRetryDelay is the delay between attempts of a create request.
+	createRetryDelay = time.Second
+	// walkPerPage is the page size used to walk a list when none is
+	// requested.
+	walkPerPage = 100
+)
+
 // Client is the programmatic interface to the provisioning server API.
 type Client struct {
 	address    string
@@ -41,6 +53,14 @@ func NewClientWithHeaders(address string, headers map[string]string) *Client {
 	}
 }
 
+// NewClientWithAPIKey creates a client to the provisioning server at the given
+// address that authenticates with the given API key.
+func NewClientWithAPIKey(address, apiKey string) *Client {
+	return NewClientWithHeaders(address, map[string]string{
+		APIKeyHeader: APIKeyHeaderPrefix + apiKey,
+	})
+}
+
 // closeBody ensures the Body of an http.Response is properly closed.
 func closeBody(r *http.Response) {
 	if r.Body != nil {
@@ -66,6 +86,10 @@ func (c *Client) doGet(u string) (*http.Response, error) {
 }
 
 func (c *Client) doPost(u string, request interface{}) (*http.Response, error) {
+	return c.doPostWithHeaders(u, request, nil)
+}
+
+func (c *Client) doPostWithHeaders(u string, request interface{}, headers map[string]string) (*http.Response, error) {
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal request")
@@ -78,11 +102,34 @@ func (c *Client) doPost(u string, request interface{}) (*http.Response, error) {
 	for k, v := range c.headers {
 		req.Header.Add(k, v)
 	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	return c.httpClient.Do(req)
 }
 
+// doCreate posts a request creating a resource under a new idempotency key.
+// The request is retried under the same key if no response was received or
+// the server was still serving an earlier attempt, so that retrying never
+// creates duplicate resources.
+func (c *Client) doCreate(u string, request interface{}) (*http.Response, error) {
+	headers := map[string]string{IdempotencyKeyHeader: NewID()}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.doPostWithHeaders(u, request, headers)
+		if attempt == createAttempts || (err == nil && resp.StatusCode != http.StatusConflict) {
+			return resp, err
+		}
+		if err == nil {
+			closeBody(resp)
+		}
+
+		time.Sleep(createRetryDelay)
+	}
+}
+
 func (c *Client) doPut(u string, request interface{}) (*http.Response, error) {
 	requestBytes, err := json.Marshal(request)
 	if err != nil {
@@ -114,7 +161,7 @@ func (c *Client) doDelete(u string) (*http.Response, error) {
 
 // CreateCluster requests the creation of a cluster from the configured provisioning server.
 func (c *Client) CreateCluster(request *CreateClusterRequest) (*ClusterDTO, error) {
-	resp, err := c.doPost(c.buildURL("/api/clusters"), request)
+	resp, err := c.doCreate(c.buildURL("/api/clusters"), request)
 	if err != nil {
 		return nil, err
 	}
@@ -186,25 +233,70 @@ func (c *Client) GetCluster(clusterID string) (*ClusterDTO, error) {
 
 // GetClusters fetches the list of clusters from the configured provisioning server.
 func (c *Client) GetClusters(request *GetClustersRequest) ([]*ClusterDTO, error) {
+	clusters, _, err := c.GetClustersPage(request)
+
+	return clusters, err
+}
+
+// GetClustersPage fetches a page of clusters from the configured provisioning
+// server, along with the total number of clusters and the cursor of the next
+// page.
+func (c *Client) GetClustersPage(request *GetClustersRequest) ([]*ClusterDTO, *PageInfo, error) {
 	u, err := url.Parse(c.buildURL("/api/clusters"))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	request.ApplyToURL(u)
 
 	resp, err := c.doGet(u.String())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return ClusterDTOsFromReader(resp.Body)
+		pageInfo, err := PageInfoFromHeader(resp.Header)
+		if err != nil {
+			return nil, nil, err
+		}
+		clusters, err := ClusterDTOsFromReader(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return clusters, pageInfo, nil
 
 	default:
-		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// WalkClusters calls fn with every one of the clusters matching the
+// request, following the page cursors from the requested page onwards. It stops
+// at the first error returned by fn.
+func (c *Client) WalkClusters(request *GetClustersRequest, fn func(*ClusterDTO) error) error {
+	pageRequest := *request
+	if pageRequest.PerPage == 0 {
+		pageRequest.PerPage = walkPerPage
+	}
+
+	for {
+		clusters, pageInfo, err := c.GetClustersPage(&pageRequest)
+		if err != nil {
+			return err
+		}
+		for _, cluster := range clusters {
+			err = fn(cluster)
+			if err != nil {
+				return err
+			}
+		}
+		if pageInfo.NextCursor == "" {
+			return nil
+		}
+		pageRequest.Cursor = pageInfo.NextCursor
 	}
 }
 
@@ -259,6 +351,23 @@ func (c *Client) UpgradeCluster(clusterID string, request *PatchUpgradeClusterRe
 	}
 }
 
+// DrainCluster moves all installations off of a cluster.
+func (c *Client) DrainCluster(clusterID string, request *DrainClusterRequest) (*ClusterDTO, error) {
+	resp, err := c.doPost(c.buildURL("/api/cluster/%s/drain", clusterID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return ClusterDTOFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // ResizeCluster resizes a cluster with a new size value.
 func (c *Client) ResizeCluster(clusterID string, request *PatchClusterSizeRequest) (*ClusterDTO, error) {
 	resp, err := c.doPut(c.buildURL("/api/cluster/%s/size", clusterID), request)
@@ -295,7 +404,7 @@ func (c *Client) DeleteCluster(clusterID string) error {
 
 // CreateInstallation requests the creation of a installation from the configured provisioning server.
 func (c *Client) CreateInstallation(request *CreateInstallationRequest) (*InstallationDTO, error) {
-	resp, err := c.doPost(c.buildURL("/api/installations"), request)
+	resp, err := c.doCreate(c.buildURL("/api/installations"), request)
 	if err != nil {
 		return nil, err
 	}
@@ -384,25 +493,70 @@ func (c *Client) GetInstallationByDNS(DNS string, request *GetInstallationReques
 
 // GetInstallations fetches the list of installations from the configured provisioning server.
 func (c *Client) GetInstallations(request *GetInstallationsRequest) ([]*InstallationDTO, error) {
+	installations, _, err := c.GetInstallationsPage(request)
+
+	return installations, err
+}
+
+// GetInstallationsPage fetches a page of installations from the configured provisioning
+// server, along with the total number of installations and the cursor of the next
+// page.
+func (c *Client) GetInstallationsPage(request *GetInstallationsRequest) ([]*InstallationDTO, *PageInfo, error) {
 	u, err := url.Parse(c.buildURL("/api/installations"))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	request.ApplyToURL(u)
 
 	resp, err := c.doGet(u.String())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return InstallationDTOsFromReader(resp.Body)
+		pageInfo, err := PageInfoFromHeader(resp.Header)
+		if err != nil {
+			return nil, nil, err
+		}
+		installations, err := InstallationDTOsFromReader(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return installations, pageInfo, nil
 
 	default:
-		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// WalkInstallations calls fn with every one of the installations matching the
+// request, following the page cursors from the requested page onwards. It stops
+// at the first error returned by fn.
+func (c *Client) WalkInstallations(request *GetInstallationsRequest, fn func(*InstallationDTO) error) error {
+	pageRequest := *request
+	if pageRequest.PerPage == 0 {
+		pageRequest.PerPage = walkPerPage
+	}
+
+	for {
+		installations, pageInfo, err := c.GetInstallationsPage(&pageRequest)
+		if err != nil {
+			return err
+		}
+		for _, installation := range installations {
+			err = fn(installation)
+			if err != nil {
+				return err
+			}
+		}
+		if pageInfo.NextCursor == "" {
+			return nil
+		}
+		pageRequest.Cursor = pageInfo.NextCursor
 	}
 }
 
@@ -464,6 +618,59 @@ func (c *Client) HibernateInstallation(installationID string) (*InstallationDTO,
 	}
 }
 
+// MigrateInstallation moves an installation to another cluster.
+func (c *Client) MigrateInstallation(installationID string, request *MigrateInstallationRequest) (*InstallationDTO, error) {
+	resp, err := c.doPost(c.buildURL("/api/installation/%s/migrate", installationID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return InstallationDTOFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CloneInstallation creates a new installation from a copy of the database
+// and filestore of the given installation.
+func (c *Client) CloneInstallation(installationID string, request *CloneInstallationRequest) (*InstallationDTO, error) {
+	resp, err := c.doCreate(c.buildURL("/api/installation/%s/clone", installationID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return InstallationDTOFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// MigrateInstallationDatabase moves the data of the given hibernating
+// installation to another database and filestore.
+func (c *Client) MigrateInstallationDatabase(installationID string, request *MigrateInstallationDatabaseRequest) (*InstallationDTO, error) {
+	resp, err := c.doPost(c.buildURL("/api/installation/%s/database_migration", installationID), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return InstallationDTOFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // WakeupInstallation wakes an installation from hibernation.
 func (c *Client) WakeupInstallation(installationID string) (*InstallationDTO, error) {
 	resp, err := c.doPost(c.buildURL("/api/installation/%s/wakeup", installationID), nil)
@@ -481,6 +688,42 @@ func (c *Client) WakeupInstallation(installationID string) (*InstallationDTO, er
 	}
 }
 
+// SetInstallationHibernationSchedule replaces the hibernation schedule of the
+// given installation.
+func (c *Client) SetInstallationHibernationSchedule(installationID string, schedule *HibernationSchedule) (*InstallationDTO, error) {
+	resp, err := c.doPut(c.buildURL("/api/installation/%s/hibernation_schedule", installationID), schedule)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return InstallationDTOFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// DeleteInstallationHibernationSchedule removes the hibernation schedule of the
+// given installation.
+func (c *Client) DeleteInstallationHibernationSchedule(installationID string) error {
+	resp, err := c.doDelete(c.buildURL("/api/installation/%s/hibernation_schedule", installationID))
+	if err != nil {
+		return err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+
+	default:
+		return errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // DeleteInstallation deletes the given installation and all resources contained therein.
 func (c *Client) DeleteInstallation(installationID string) error {
 	resp, err := c.doDelete(c.buildURL("/api/installation/%s", installationID))
@@ -520,25 +763,70 @@ func (c *Client) GetClusterInstallation(clusterInstallationID string) (*ClusterI
 
 // GetClusterInstallations fetches the list of cluster installations from the configured provisioning server.
 func (c *Client) GetClusterInstallations(request *GetClusterInstallationsRequest) ([]*ClusterInstallation, error) {
+	clusterInstallations, _, err := c.GetClusterInstallationsPage(request)
+
+	return clusterInstallations, err
+}
+
+// GetClusterInstallationsPage fetches a page of cluster installations from the configured provisioning
+// server, along with the total number of cluster installations and the cursor of the next
+// page.
+func (c *Client) GetClusterInstallationsPage(request *GetClusterInstallationsRequest) ([]*ClusterInstallation, *PageInfo, error) {
 	u, err := url.Parse(c.buildURL("/api/cluster_installations"))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	request.ApplyToURL(u)
 
 	resp, err := c.doGet(u.String())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return ClusterInstallationsFromReader(resp.Body)
+		pageInfo, err := PageInfoFromHeader(resp.Header)
+		if err != nil {
+			return nil, nil, err
+		}
+		clusterInstallations, err := ClusterInstallationsFromReader(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return clusterInstallations, pageInfo, nil
 
 	default:
-		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// WalkClusterInstallations calls fn with every one of the cluster installations matching the
+// request, following the page cursors from the requested page onwards. It stops
+// at the first error returned by fn.
+func (c *Client) WalkClusterInstallations(request *GetClusterInstallationsRequest, fn func(*ClusterInstallation) error) error {
+	pageRequest := *request
+	if pageRequest.PerPage == 0 {
+		pageRequest.PerPage = walkPerPage
+	}
+
+	for {
+		clusterInstallations, pageInfo, err := c.GetClusterInstallationsPage(&pageRequest)
+		if err != nil {
+			return err
+		}
+		for _, clusterInstallation := range clusterInstallations {
+			err = fn(clusterInstallation)
+			if err != nil {
+				return err
+			}
+		}
+		if pageInfo.NextCursor == "" {
+			return nil
+		}
+		pageRequest.Cursor = pageInfo.NextCursor
 	}
 }
 
@@ -621,7 +909,7 @@ func (c *Client) ExecClusterInstallationCLI(clusterInstallationID, command strin
 
 // CreateGroup requests the creation of a group from the configured provisioning server.
 func (c *Client) CreateGroup(request *CreateGroupRequest) (*Group, error) {
-	resp, err := c.doPost(c.buildURL("/api/groups"), request)
+	resp, err := c.doCreate(c.buildURL("/api/groups"), request)
 	if err != nil {
 		return nil, err
 	}
@@ -692,25 +980,70 @@ func (c *Client) GetGroup(groupID string) (*Group, error) {
 
 // GetGroups fetches the list of groups from the configured provisioning server.
 func (c *Client) GetGroups(request *GetGroupsRequest) ([]*Group, error) {
+	groups, _, err := c.GetGroupsPage(request)
+
+	return groups, err
+}
+
+// GetGroupsPage fetches a page of groups from the configured provisioning
+// server, along with the total number of groups and the cursor of the next
+// page.
+func (c *Client) GetGroupsPage(request *GetGroupsRequest) ([]*Group, *PageInfo, error) {
 	u, err := url.Parse(c.buildURL("/api/groups"))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	request.ApplyToURL(u)
 
 	resp, err := c.doGet(u.String())
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer closeBody(resp)
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return GroupsFromReader(resp.Body)
+		pageInfo, err := PageInfoFromHeader(resp.Header)
+		if err != nil {
+			return nil, nil, err
+		}
+		groups, err := GroupsFromReader(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return groups, pageInfo, nil
 
 	default:
-		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+		return nil, nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// WalkGroups calls fn with every one of the groups matching the
+// request, following the page cursors from the requested page onwards. It stops
+// at the first error returned by fn.
+func (c *Client) WalkGroups(request *GetGroupsRequest, fn func(*Group) error) error {
+	pageRequest := *request
+	if pageRequest.PerPage == 0 {
+		pageRequest.PerPage = walkPerPage
+	}
+
+	for {
+		groups, pageInfo, err := c.GetGroupsPage(&pageRequest)
+		if err != nil {
+			return err
+		}
+		for _, group := range groups {
+			err = fn(group)
+			if err != nil {
+				return err
+			}
+		}
+		if pageInfo.NextCursor == "" {
+			return nil
+		}
+		pageRequest.Cursor = pageInfo.NextCursor
 	}
 }
 
@@ -801,7 +1134,7 @@ func (c *Client) GetMultitenantDatabases(request *GetDatabasesRequest) ([]*Multi
 
 // CreateWebhook requests the creation of a webhook from the configured provisioning server.
 func (c *Client) CreateWebhook(request *CreateWebhookRequest) (*Webhook, error) {
-	resp, err := c.doPost(c.buildURL("/api/webhooks"), request)
+	resp, err := c.doCreate(c.buildURL("/api/webhooks"), request)
 	if err != nil {
 		return nil, err
 	}
@@ -838,7 +1171,77 @@ func (c *Client) GetWebhook(webhookID string) (*Webhook, error) {
 
 // GetWebhooks fetches the list of webhooks from the configured provisioning server.
 func (c *Client) GetWebhooks(request *GetWebhooksRequest) ([]*Webhook, error) {
+	webhooks, _, err := c.GetWebhooksPage(request)
+
+	return webhooks, err
+}
+
+// GetWebhooksPage fetches a page of webhooks from the configured provisioning
+// server, along with the total number of webhooks and the cursor of the next
+// page.
+func (c *Client) GetWebhooksPage(request *GetWebhooksRequest) ([]*Webhook, *PageInfo, error) {
 	u, err := url.Parse(c.buildURL("/api/webhooks"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		pageInfo, err := PageInfoFromHeader(resp.Header)
+		if err != nil {
+			return nil, nil, err
+		}
+		webhooks, err := WebhooksFromReader(resp.Body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return webhooks, pageInfo, nil
+
+	default:
+		return nil, nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// WalkWebhooks calls fn with every one of the webhooks matching the
+// request, following the page cursors from the requested page onwards. It stops
+// at the first error returned by fn.
+func (c *Client) WalkWebhooks(request *GetWebhooksRequest, fn func(*Webhook) error) error {
+	pageRequest := *request
+	if pageRequest.PerPage == 0 {
+		pageRequest.PerPage = walkPerPage
+	}
+
+	for {
+		webhooks, pageInfo, err := c.GetWebhooksPage(&pageRequest)
+		if err != nil {
+			return err
+		}
+		for _, webhook := range webhooks {
+			err = fn(webhook)
+			if err != nil {
+				return err
+			}
+		}
+		if pageInfo.NextCursor == "" {
+			return nil
+		}
+		pageRequest.Cursor = pageInfo.NextCursor
+	}
+}
+
+// GetWebhookDeliveries fetches the list of deliveries to a webhook from the
+// configured provisioning server.
+func (c *Client) GetWebhookDeliveries(webhookID string, request *GetWebhookDeliveriesRequest) ([]*WebhookDelivery, error) {
+	u, err := url.Parse(c.buildURL("/api/webhook/%s/deliveries", webhookID))
 	if err != nil {
 		return nil, err
 	}
@@ -853,7 +1256,25 @@ func (c *Client) GetWebhooks(request *GetWebhooksRequest) ([]*Webhook, error) {
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		return WebhooksFromReader(resp.Body)
+		return WebhookDeliveriesFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// ReplayWebhookDeliveries schedules all failed deliveries to a webhook to be
+// attempted again.
+func (c *Client) ReplayWebhookDeliveries(webhookID string) ([]*WebhookDelivery, error) {
+	resp, err := c.doPost(c.buildURL("/api/webhook/%s/deliveries/replay", webhookID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return WebhookDeliveriesFromReader(resp.Body)
 
 	default:
 		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
@@ -877,6 +1298,249 @@ func (c *Client) DeleteWebhook(webhookID string) error {
 	}
 }
 
+// StreamEvents opens a stream of state transition events from the configured
+// provisioning server. The returned stream must be closed by the caller.
+func (c *Client) StreamEvents(request *StreamEventsRequest) (*EventStream, error) {
+	u, err := url.Parse(c.buildURL("/api/events/stream"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return NewEventStream(resp.Body), nil
+
+	default:
+		closeBody(resp)
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetClusterEvents fetches the state change history of a cluster from the
+// configured provisioning server.
+func (c *Client) GetClusterEvents(clusterID string, request *GetEventsRequest) ([]*Event, error) {
+	return c.getEvents(c.buildURL("/api/cluster/%s/events", clusterID), request)
+}
+
+// GetInstallationEvents fetches the state change history of an installation
+// from the configured provisioning server.
+func (c *Client) GetInstallationEvents(installationID string, request *GetEventsRequest) ([]*Event, error) {
+	return c.getEvents(c.buildURL("/api/installation/%s/events", installationID), request)
+}
+
+// GetClusterInstallationEvents fetches the state change history of a cluster
+// installation from the configured provisioning server.
+func (c *Client) GetClusterInstallationEvents(clusterInstallationID string, request *GetEventsRequest) ([]*Event, error) {
+	return c.getEvents(c.buildURL("/api/cluster_installation/%s/events", clusterInstallationID), request)
+}
+
+// GetGroupEvents fetches the state change history of the installations in a
+// group from the configured provisioning server.
+func (c *Client) GetGroupEvents(groupID string, request *GetEventsRequest) ([]*Event, error) {
+	return c.getEvents(c.buildURL("/api/group/%s/events", groupID), request)
+}
+
+func (c *Client) getEvents(eventsURL string, request *GetEventsRequest) ([]*Event, error) {
+	u, err := url.Parse(eventsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return EventsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetAuditRecords fetches the list of audit records of mutating API requests
+// from the configured provisioning server, newest first.
+func (c *Client) GetAuditRecords(request *GetAuditRecordsRequest) ([]*AuditRecord, error) {
+	u, err := url.Parse(c.buildURL("/api/audit"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return AuditRecordsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateBulkOperation requests the given action to be applied to every
+// installation matching the request filter.
+func (c *Client) CreateBulkOperation(request *CreateBulkOperationRequest) (*BulkOperation, error) {
+	resp, err := c.doCreate(c.buildURL("/api/bulk_operations"), request)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return BulkOperationFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetBulkOperation fetches the bulk operation, along with its results so far,
+// from the configured provisioning server.
+func (c *Client) GetBulkOperation(bulkOperationID string) (*BulkOperation, error) {
+	resp, err := c.doGet(c.buildURL("/api/bulk_operation/%s", bulkOperationID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return BulkOperationFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetBulkOperations fetches the list of bulk operations from the configured
+// provisioning server, newest first.
+func (c *Client) GetBulkOperations(request *GetBulkOperationsRequest) ([]*BulkOperation, error) {
+	u, err := url.Parse(c.buildURL("/api/bulk_operations"))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return BulkOperationsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// CreateInstallationBackup requests a backup of the database and filestore
+// of the given installation.
+func (c *Client) CreateInstallationBackup(installationID string) (*Backup, error) {
+	resp, err := c.doCreate(c.buildURL("/api/installation/%s/backups", installationID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return BackupFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetInstallationBackup fetches the given backup of the given installation
+// from the configured provisioning server.
+func (c *Client) GetInstallationBackup(installationID, backupID string) (*Backup, error) {
+	resp, err := c.doGet(c.buildURL("/api/installation/%s/backup/%s", installationID, backupID))
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return BackupFromReader(resp.Body)
+
+	case http.StatusNotFound:
+		return nil, nil
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// GetInstallationBackups fetches the list of backups of the given
+// installation from the configured provisioning server, newest first.
+func (c *Client) GetInstallationBackups(installationID string, request *GetBackupsRequest) ([]*Backup, error) {
+	u, err := url.Parse(c.buildURL("/api/installation/%s/backups", installationID))
+	if err != nil {
+		return nil, err
+	}
+
+	request.ApplyToURL(u)
+
+	resp, err := c.doGet(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return BackupsFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
+// RestoreInstallationBackup replaces the database and filestore of the given
+// hibernating installation with those of the given backup.
+func (c *Client) RestoreInstallationBackup(installationID, backupID string) (*InstallationDTO, error) {
+	resp, err := c.doPost(c.buildURL("/api/installation/%s/backup/%s/restore", installationID, backupID), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	switch resp.StatusCode {
+	case http.StatusAccepted:
+		return InstallationDTOFromReader(resp.Body)
+
+	default:
+		return nil, errors.Errorf("failed with status code %d", resp.StatusCode)
+	}
+}
+
 // LockAPIForCluster locks API changes for a given cluster.
 func (c *Client) LockAPIForCluster(clusterID string) error {
 	return c.makeSecurityCall("cluster", clusterID, "api", "lock")
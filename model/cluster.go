@@ -20,6 +20,7 @@ type Cluster struct {
 	ProvisionerMetadataKops *KopsMetadata
 	UtilityMetadata         *UtilityMetadata
 	AllowInstallations      bool
+	DrainMaxConcurrent      int `json:"DrainMaxConcurrent,omitempty"`
 	CreateAt                int64
 	DeleteAt                int64
 	APISecurityLock         bool
@@ -71,9 +72,26 @@ func ClustersFromReader(reader io.Reader) ([]*Cluster, error) {
 
 // ClusterFilter describes the parameters used to constrain a set of clusters.
 type ClusterFilter struct {
-	Page           int
-	PerPage        int
+	Page    int
+	PerPage int
+	// Cursor, when set, selects the page following the item it was taken
+	// from, ignoring Page.
+	Cursor         *PageCursor
 	IncludeDeleted bool
+	// States restricts the clusters to those in any of the given states.
+	States []string
+	// Annotations restricts the clusters to those having all of the given
+	// annotations.
+	Annotations []string
+	// CreatedAfter and CreatedBefore, in milliseconds since the epoch,
+	// restrict the clusters to those created strictly after and before the
+	// given times.
+	CreatedAfter  int64
+	CreatedBefore int64
+	// SortBy is one of ClusterSortKeys, defaulting to SortByCreateAt.
+	SortBy string
+	// SortOrder is SortOrderAscending, the default, or SortOrderDescending.
+	SortOrder string
 }
 
 var clusterVersionMatcher = regexp.MustCompile(`^(([0-9]{1,3}.[0-9]{1,3}.[0-9]{1,3})|(latest))$`)
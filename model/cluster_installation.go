@@ -28,8 +28,10 @@ type ClusterInstallationFilter struct {
 	IDs            []string
 	InstallationID string
 	ClusterID      string
+	OwnerID        string
 	Page           int
 	PerPage        int
+	Cursor         *PageCursor
 	IncludeDeleted bool
 }
 
@@ -5,6 +5,7 @@
 package model
 
 import (
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -29,3 +30,38 @@ type CIMigrationDatabase interface {
 	Teardown(logger log.FieldLogger) (string, error)
 	Replicate(logger log.FieldLogger) (string, error)
 }
+
+// ValidateDatabaseMigration returns an error if the data of the given
+// installation cannot be migrated to the given database and filestore types.
+// An empty or unchanged type leaves that backend alone, but at least one of
+// them must change.
+func ValidateDatabaseMigration(installation *Installation, database, filestore string) error {
+	migrateDatabase := database != "" && database != installation.Database
+	migrateFilestore := filestore != "" && filestore != installation.Filestore
+
+	if !migrateDatabase && !migrateFilestore {
+		return errors.New("installation already uses the requested database and filestore")
+	}
+
+	if migrateDatabase {
+		if installation.Database != InstallationDatabaseMysqlOperator {
+			return errors.Errorf("migrating data out of %s databases is not supported", installation.Database)
+		}
+		switch database {
+		case InstallationDatabaseSingleTenantRDSMySQL, InstallationDatabaseMultiTenantRDSMySQL:
+		default:
+			return errors.Errorf("migrating data from %s to %s databases is not supported", installation.Database, database)
+		}
+	}
+
+	if migrateFilestore {
+		if installation.Filestore != InstallationFilestoreMinioOperator {
+			return errors.Errorf("migrating data out of %s filestores is not supported", installation.Filestore)
+		}
+		if filestore != InstallationFilestoreAwsS3 {
+			return errors.Errorf("migrating data from %s to %s filestores is not supported", installation.Filestore, filestore)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDatabaseMigration(t *testing.T) {
+	operatorInstallation := &model.Installation{
+		Database:  model.InstallationDatabaseMysqlOperator,
+		Filestore: model.InstallationFilestoreMinioOperator,
+	}
+
+	require.NoError(t, model.ValidateDatabaseMigration(operatorInstallation, model.InstallationDatabaseSingleTenantRDSMySQL, ""))
+	require.NoError(t, model.ValidateDatabaseMigration(operatorInstallation, model.InstallationDatabaseMultiTenantRDSMySQL, model.InstallationFilestoreAwsS3))
+	require.NoError(t, model.ValidateDatabaseMigration(operatorInstallation, "", model.InstallationFilestoreAwsS3))
+	require.NoError(t, model.ValidateDatabaseMigration(operatorInstallation, model.InstallationDatabaseMysqlOperator, model.InstallationFilestoreAwsS3))
+
+	require.Error(t, model.ValidateDatabaseMigration(operatorInstallation, "", ""))
+	require.Error(t, model.ValidateDatabaseMigration(operatorInstallation, model.InstallationDatabaseMysqlOperator, model.InstallationFilestoreMinioOperator))
+	require.Error(t, model.ValidateDatabaseMigration(operatorInstallation, model.InstallationDatabaseSingleTenantRDSPostgres, ""))
+	require.Error(t, model.ValidateDatabaseMigration(operatorInstallation, "", model.InstallationFilestoreMultiTenantAwsS3))
+
+	awsInstallation := &model.Installation{
+		Database:  model.InstallationDatabaseSingleTenantRDSMySQL,
+		Filestore: model.InstallationFilestoreAwsS3,
+	}
+	require.Error(t, model.ValidateDatabaseMigration(awsInstallation, model.InstallationDatabaseMultiTenantRDSMySQL, ""))
+	require.Error(t, model.ValidateDatabaseMigration(awsInstallation, "", model.InstallationFilestoreMinioOperator))
+}
+
+func TestInstallationValidTransitionStateDBMigration(t *testing.T) {
+	for state, valid := range map[string]bool{
+		model.InstallationStateHibernating:                   true,
+		model.InstallationStateDBMigrationFailed:             true,
+		model.InstallationStateStable:                        false,
+		model.InstallationStateDBMigrationInProgress:         false,
+		model.InstallationStateDBMigrationRollbackInProgress: false,
+	} {
+		installation := &model.Installation{State: state}
+		require.Equal(t, valid, installation.ValidTransitionState(model.InstallationStateDBMigrationRequested), state)
+	}
+}
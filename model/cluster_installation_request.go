@@ -19,6 +19,7 @@ type GetClusterInstallationsRequest struct {
 	InstallationID string
 	Page           int
 	PerPage        int
+	Cursor         string
 	IncludeDeleted bool
 }
 
@@ -29,6 +30,9 @@ func (request *GetClusterInstallationsRequest) ApplyToURL(u *url.URL) {
 	q.Add("installation", request.InstallationID)
 	q.Add("page", strconv.Itoa(request.Page))
 	q.Add("per_page", strconv.Itoa(request.PerPage))
+	if request.Cursor != "" {
+		q.Add("cursor", request.Cursor)
+	}
 	if request.IncludeDeleted {
 		q.Add("include_deleted", "true")
 	}
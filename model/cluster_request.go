@@ -123,7 +123,14 @@ func NewCreateClusterRequestFromReader(reader io.Reader) (*CreateClusterRequest,
 type GetClustersRequest struct {
 	Page           int
 	PerPage        int
+	Cursor         string
 	IncludeDeleted bool
+	States         []string
+	Annotations    []string
+	CreatedAfter   int64
+	CreatedBefore  int64
+	SortBy         string
+	SortOrder      string
 }
 
 // ApplyToURL modifies the given url to include query string parameters for the request.
@@ -131,9 +138,13 @@ func (request *GetClustersRequest) ApplyToURL(u *url.URL) {
 	q := u.Query()
 	q.Add("page", strconv.Itoa(request.Page))
 	q.Add("per_page", strconv.Itoa(request.PerPage))
+	if request.Cursor != "" {
+		q.Add("cursor", request.Cursor)
+	}
 	if request.IncludeDeleted {
 		q.Add("include_deleted", "true")
 	}
+	applyListFilterToQuery(q, request.States, request.Annotations, request.CreatedAfter, request.CreatedBefore, request.SortBy, request.SortOrder)
 	u.RawQuery = q.Encode()
 }
 
@@ -268,6 +279,49 @@ func NewResizeClusterRequestFromReader(reader io.Reader) (*PatchClusterSizeReque
 	return &patchClusterSizeRequest, nil
 }
 
+// DefaultClusterDrainMaxConcurrent is the default number of installations
+// that are migrated off of a draining cluster at the same time.
+const DefaultClusterDrainMaxConcurrent = 3
+
+// DrainClusterRequest specifies the parameters for moving all installations
+// off of a cluster.
+type DrainClusterRequest struct {
+	MaxConcurrent int `json:"max-concurrent,omitempty"`
+}
+
+// SetDefaults sets the default values for a cluster drain request.
+func (request *DrainClusterRequest) SetDefaults() {
+	if request.MaxConcurrent == 0 {
+		request.MaxConcurrent = DefaultClusterDrainMaxConcurrent
+	}
+}
+
+// Validate validates the values of a cluster drain request.
+func (request *DrainClusterRequest) Validate() error {
+	if request.MaxConcurrent < 1 {
+		return errors.New("max concurrent has to be 1 or greater")
+	}
+
+	return nil
+}
+
+// NewDrainClusterRequestFromReader will create a DrainClusterRequest from an io.Reader with JSON data.
+func NewDrainClusterRequestFromReader(reader io.Reader) (*DrainClusterRequest, error) {
+	var drainClusterRequest DrainClusterRequest
+	err := json.NewDecoder(reader).Decode(&drainClusterRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode drain cluster request")
+	}
+
+	drainClusterRequest.SetDefaults()
+	err = drainClusterRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "drain cluster request failed validation")
+	}
+
+	return &drainClusterRequest, nil
+}
+
 // ProvisionClusterRequest contains metadata related to changing the installed cluster state.
 type ProvisionClusterRequest struct {
 	DesiredUtilityVersions map[string]string `json:"utility-versions,omitempty"`
@@ -163,3 +163,25 @@ func TestResizeClusterRequestValid(t *testing.T) {
 		})
 	}
 }
+
+func TestDrainClusterRequestValid(t *testing.T) {
+	var testCases = []struct {
+		testName     string
+		request      *model.DrainClusterRequest
+		requireError bool
+	}{
+		{"valid", &model.DrainClusterRequest{MaxConcurrent: 2}, false},
+		{"zero max concurrent", &model.DrainClusterRequest{}, true},
+		{"negative max concurrent", &model.DrainClusterRequest{MaxConcurrent: -1}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			if tc.requireError {
+				assert.Error(t, tc.request.Validate())
+			} else {
+				assert.NoError(t, tc.request.Validate())
+			}
+		})
+	}
+}
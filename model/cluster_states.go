@@ -26,6 +26,11 @@ const (
 	ClusterStateResizeRequested = "resize-requested"
 	// ClusterStateResizeFailed is a cluster that failed to resize.
 	ClusterStateResizeFailed = "resize-failed"
+	// ClusterStateDrainRequested is a cluster in the process of having its
+	// installations moved to other clusters.
+	ClusterStateDrainRequested = "drain-requested"
+	// ClusterStateDrainFailed is a cluster that failed to drain.
+	ClusterStateDrainFailed = "drain-failed"
 	// ClusterStateDeletionRequested is a cluster in the process of being deleted.
 	ClusterStateDeletionRequested = "deletion-requested"
 	// ClusterStateDeletionFailed is a cluster that failed deletion.
@@ -48,6 +53,8 @@ var AllClusterStates = []string{
 	ClusterStateUpgradeFailed,
 	ClusterStateResizeRequested,
 	ClusterStateResizeFailed,
+	ClusterStateDrainRequested,
+	ClusterStateDrainFailed,
 	ClusterStateDeletionRequested,
 	ClusterStateDeletionFailed,
 	ClusterStateDeleted,
@@ -64,6 +71,7 @@ var AllClusterStatesPendingWork = []string{
 	ClusterStateRefreshMetadata,
 	ClusterStateUpgradeRequested,
 	ClusterStateResizeRequested,
+	ClusterStateDrainRequested,
 	ClusterStateDeletionRequested,
 }
 
@@ -77,9 +85,22 @@ var AllClusterRequestStates = []string{
 	ClusterStateProvisioningRequested,
 	ClusterStateUpgradeRequested,
 	ClusterStateResizeRequested,
+	ClusterStateDrainRequested,
 	ClusterStateDeletionRequested,
 }
 
+// ClusterStateIsSettled returns whether the given state is one that the
+// cluster supervisor will not move out of on its own.
+func ClusterStateIsSettled(state string) bool {
+	for _, pendingState := range AllClusterStatesPendingWork {
+		if state == pendingState {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ValidTransitionState returns whether a cluster can be transitioned into the
 // new state or not based on its current state.
 func (c *Cluster) ValidTransitionState(newState string) bool {
@@ -92,6 +113,8 @@ func (c *Cluster) ValidTransitionState(newState string) bool {
 		return validTransitionToClusterStateUpgradeRequested(c.State)
 	case ClusterStateResizeRequested:
 		return validTransitionToClusterStateResizeRequested(c.State)
+	case ClusterStateDrainRequested:
+		return validTransitionToClusterStateDrainRequested(c.State)
 	case ClusterStateDeletionRequested:
 		return validTransitionToClusterStateDeletionRequested(c.State)
 	}
@@ -142,6 +165,17 @@ func validTransitionToClusterStateResizeRequested(currentState string) bool {
 	return false
 }
 
+func validTransitionToClusterStateDrainRequested(currentState string) bool {
+	switch currentState {
+	case ClusterStateStable,
+		ClusterStateDrainRequested,
+		ClusterStateDrainFailed:
+		return true
+	}
+
+	return false
+}
+
 func validTransitionToClusterStateDeletionRequested(currentState string) bool {
 	switch currentState {
 	case ClusterStateStable,
@@ -150,6 +184,7 @@ func validTransitionToClusterStateDeletionRequested(currentState string) bool {
 		ClusterStateProvisioningFailed,
 		ClusterStateUpgradeRequested,
 		ClusterStateUpgradeFailed,
+		ClusterStateDrainFailed,
 		ClusterStateDeletionRequested,
 		ClusterStateDeletionFailed:
 		return true
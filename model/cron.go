@@ -0,0 +1,163 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cronSearchLimit bounds how far back in time a cron expression is matched.
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+// cronField describes the allowed values of a field of a cron expression.
+type cronField struct {
+	name string
+	min  int
+	max  int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// CronExpression is a parsed cron expression made of the five standard
+// fields: minute, hour, day of month, month and day of week. Each field is
+// either *, a value, a range such as 1-5, optionally followed by a step such
+// as */15, or a comma separated list of those. Times are matched in UTC.
+type CronExpression struct {
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// As in cron, when both the day of month and the day of week are
+	// restricted, a day matching either is selected.
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// ParseCronExpression parses the given five field cron expression.
+func ParseCronExpression(expression string) (*CronExpression, error) {
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, errors.Errorf("cron expression %q must have %d fields", expression, len(cronFields))
+	}
+
+	var values [5]uint64
+	for i, field := range fields {
+		var err error
+		values[i], err = parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cron expression %q", expression)
+		}
+	}
+
+	// Sunday is both 0 and 7.
+	if values[4]&(1<<7) != 0 {
+		values[4] |= 1
+	}
+
+	return &CronExpression{
+		minute:        values[0],
+		hour:          values[1],
+		dayOfMonth:    values[2],
+		month:         values[3],
+		dayOfWeek:     values[4],
+		anyDayOfMonth: fields[2] == "*",
+		anyDayOfWeek:  fields[4] == "*",
+	}, nil
+}
+
+// parseCronField parses a single field of a cron expression into the set of
+// values it matches.
+func parseCronField(field string, bounds cronField) (uint64, error) {
+	var values uint64
+	for _, item := range strings.Split(field, ",") {
+		valueRange, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			var err error
+			valueRange = item[:i]
+			step, err = strconv.Atoi(item[i+1:])
+			if err != nil || step < 1 {
+				return 0, errors.Errorf("invalid step in %s field %q", bounds.name, item)
+			}
+		}
+
+		start, end := bounds.min, bounds.max
+		if valueRange != "*" {
+			limits := strings.SplitN(valueRange, "-", 2)
+			var err error
+			start, err = strconv.Atoi(limits[0])
+			if err != nil {
+				return 0, errors.Errorf("invalid value in %s field %q", bounds.name, item)
+			}
+			switch {
+			case len(limits) == 2:
+				end, err = strconv.Atoi(limits[1])
+				if err != nil {
+					return 0, errors.Errorf("invalid value in %s field %q", bounds.name, item)
+				}
+			case step == 1:
+				end = start
+			}
+			// A single value with a step, such as 5/15, runs up to the
+			// maximum of the field.
+		}
+		if start < bounds.min || end > bounds.max || start > end {
+			return 0, errors.Errorf("%s field %q is out of range %d-%d", bounds.name, item, bounds.min, bounds.max)
+		}
+
+		for value := start; value <= end; value += step {
+			values |= 1 << uint(value)
+		}
+	}
+
+	return values, nil
+}
+
+// Previous returns the latest time at or before the given one, truncated to
+// the minute, matched by the expression. The zero time is returned if no time
+// within the past five years matches, e.g. for February 30th.
+func (c *CronExpression) Previous(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute)
+	limit := t.Add(-cronSearchLimit)
+
+	for t.After(limit) {
+		year, month, day := t.Date()
+		switch {
+		case c.month&(1<<uint(month)) == 0:
+			t = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+		case !c.matchesDay(t):
+			t = time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Add(-time.Minute)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = t.Truncate(time.Hour).Add(-time.Minute)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(-time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (c *CronExpression) matchesDay(t time.Time) bool {
+	dayOfMonth := c.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := c.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if c.anyDayOfMonth || c.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronExpression(t *testing.T) {
+	for _, expression := range []string{
+		"* * * * *",
+		"0 20 * * 1-5",
+		"*/15 8-18 * * *",
+		"5/10 0 1,15 * *",
+		"0 0 * 1-3,12 0,7",
+	} {
+		_, err := model.ParseCronExpression(expression)
+		require.NoError(t, err, expression)
+	}
+
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"1-a * * * *",
+	} {
+		_, err := model.ParseCronExpression(expression)
+		require.Error(t, err, expression)
+	}
+}
+
+func TestCronExpressionPrevious(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2021, time.March, 17, 14, 32, 45, 0, time.UTC)
+
+	for _, testCase := range []struct {
+		expression string
+		expected   time.Time
+	}{
+		{"* * * * *", time.Date(2021, time.March, 17, 14, 32, 0, 0, time.UTC)},
+		{"0 20 * * 1-5", time.Date(2021, time.March, 16, 20, 0, 0, 0, time.UTC)},
+		{"0 8 * * 1-5", time.Date(2021, time.March, 17, 8, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2021, time.March, 17, 14, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2021, time.March, 14, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2021, time.March, 14, 0, 0, 0, 0, time.UTC)},
+		{"30 6 1 * *", time.Date(2021, time.March, 1, 6, 30, 0, 0, time.UTC)},
+		{"0 12 25 12 *", time.Date(2020, time.December, 25, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Either the day of month or the day of week match when both are
+		// restricted.
+		{"0 0 1 * 1", time.Date(2021, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	} {
+		expression, err := model.ParseCronExpression(testCase.expression)
+		require.NoError(t, err)
+		require.Equal(t, testCase.expected, expression.Previous(now), testCase.expression)
+	}
+}
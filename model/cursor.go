@@ -0,0 +1,116 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TotalCountHeader is the response header holding the total number of
+	// items matching a list request, across every page.
+	TotalCountHeader = "X-Total-Count"
+
+	// NextCursorHeader is the response header holding the cursor from which
+	// the page following the returned one starts. It is omitted on the last
+	// page.
+	NextCursorHeader = "X-Next-Cursor"
+)
+
+// PageCursor marks the position of the last item of a page in a sorted list.
+// Unlike page numbers, the next page starts right after that item even if
+// items are created or deleted in the meantime.
+type PageCursor struct {
+	SortValue string `json:"v,omitempty"`
+	CreateAt  int64  `json:"c"`
+	ID        string `json:"i"`
+}
+
+// Encode returns the opaque form of the cursor handed to API clients.
+func (c *PageCursor) Encode() string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodePageCursor decodes a cursor previously returned by Encode.
+func DecodePageCursor(cursor string) (*PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode cursor")
+	}
+
+	var pageCursor PageCursor
+	err = json.Unmarshal(data, &pageCursor)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode cursor")
+	}
+	if pageCursor.ID == "" {
+		return nil, errors.New("cursor is missing an ID")
+	}
+
+	return &pageCursor, nil
+}
+
+// NewClusterCursor returns the cursor positioned at the given cluster in a
+// list sorted by the given key.
+func NewClusterCursor(cluster *Cluster, sortBy string) *PageCursor {
+	cursor := &PageCursor{CreateAt: cluster.CreateAt, ID: cluster.ID}
+	if sortBy == SortByState {
+		cursor.SortValue = cluster.State
+	}
+
+	return cursor
+}
+
+// NewInstallationCursor returns the cursor positioned at the given
+// installation in a list sorted by the given key.
+func NewInstallationCursor(installation *Installation, sortBy string) *PageCursor {
+	cursor := &PageCursor{CreateAt: installation.CreateAt, ID: installation.ID}
+	switch sortBy {
+	case SortByState:
+		cursor.SortValue = installation.State
+	case SortByOwner:
+		cursor.SortValue = installation.OwnerID
+	case SortByDNS:
+		cursor.SortValue = installation.DNS
+	case SortByVersion:
+		cursor.SortValue = installation.Version
+	}
+
+	return cursor
+}
+
+// PageInfo describes the page of a list returned by the API.
+type PageInfo struct {
+	// TotalCount is the number of items matching the request across every
+	// page.
+	TotalCount int64
+	// NextCursor is the cursor from which the next page starts, empty on the
+	// last page.
+	NextCursor string
+}
+
+// PageInfoFromHeader reads the page description from the given response
+// headers.
+func PageInfoFromHeader(header http.Header) (*PageInfo, error) {
+	pageInfo := &PageInfo{NextCursor: header.Get(NextCursorHeader)}
+
+	totalCount := header.Get(TotalCountHeader)
+	if totalCount != "" {
+		var err error
+		pageInfo.TotalCount, err = strconv.ParseInt(totalCount, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s header", TotalCountHeader)
+		}
+	}
+
+	return pageInfo, nil
+}
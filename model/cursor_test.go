@@ -0,0 +1,57 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageCursor(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		cursor := &model.PageCursor{SortValue: "dns.example.com", CreateAt: 1234, ID: model.NewID()}
+
+		decoded, err := model.DecodePageCursor(cursor.Encode())
+		require.NoError(t, err)
+		require.Equal(t, cursor, decoded)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+			_, err := model.DecodePageCursor(cursor)
+			require.Error(t, err, cursor)
+		}
+	})
+
+	t.Run("installation cursor", func(t *testing.T) {
+		installation := &model.Installation{ID: "id", OwnerID: "owner", DNS: "dns", Version: "version", State: "state", CreateAt: 10}
+
+		require.Equal(t, &model.PageCursor{CreateAt: 10, ID: "id"}, model.NewInstallationCursor(installation, ""))
+		require.Equal(t, "owner", model.NewInstallationCursor(installation, model.SortByOwner).SortValue)
+		require.Equal(t, "dns", model.NewInstallationCursor(installation, model.SortByDNS).SortValue)
+		require.Equal(t, "version", model.NewInstallationCursor(installation, model.SortByVersion).SortValue)
+		require.Equal(t, "state", model.NewInstallationCursor(installation, model.SortByState).SortValue)
+	})
+}
+
+func TestPageInfoFromHeader(t *testing.T) {
+	header := http.Header{}
+	pageInfo, err := model.PageInfoFromHeader(header)
+	require.NoError(t, err)
+	require.Equal(t, &model.PageInfo{}, pageInfo)
+
+	header.Set(model.TotalCountHeader, "42")
+	header.Set(model.NextCursorHeader, "cursor")
+	pageInfo, err = model.PageInfoFromHeader(header)
+	require.NoError(t, err)
+	require.Equal(t, &model.PageInfo{TotalCount: 42, NextCursor: "cursor"}, pageInfo)
+
+	header.Set(model.TotalCountHeader, "many")
+	_, err = model.PageInfoFromHeader(header)
+	require.Error(t, err)
+}
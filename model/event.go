@@ -0,0 +1,148 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Event is a record of a supervisor changing the state of a resource.
+type Event struct {
+	ID           string
+	ResourceType string
+	ResourceID   string
+	OldState     string
+	NewState     string
+	InstanceID   string
+	Error        string `json:"Error,omitempty"`
+	CreateAt     int64
+}
+
+// EventFilter describes the parameters used to constrain a set of events.
+type EventFilter struct {
+	ResourceType string
+	ResourceID   string
+	// GroupID selects the events of the installations in the given group.
+	GroupID string
+	Page    int
+	PerPage int
+}
+
+// GetEventsRequest describes the parameters to request a list of events.
+type GetEventsRequest struct {
+	Page    int
+	PerPage int
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetEventsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	q.Add("page", strconv.Itoa(request.Page))
+	q.Add("per_page", strconv.Itoa(request.PerPage))
+	u.RawQuery = q.Encode()
+}
+
+// EventsFromReader decodes a json-encoded list of events from the given io.Reader.
+func EventsFromReader(reader io.Reader) ([]*Event, error) {
+	events := []*Event{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&events)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// EventStreamFilter describes the parameters used to constrain a stream of events.
+type EventStreamFilter struct {
+	Type    string
+	ID      string
+	OwnerID string
+}
+
+// Matches returns whether the given event passes the filter.
+func (f *EventStreamFilter) Matches(payload *WebhookPayload) bool {
+	if len(f.Type) != 0 && f.Type != payload.Type {
+		return false
+	}
+	if len(f.ID) != 0 && f.ID != payload.ID {
+		return false
+	}
+	if len(f.OwnerID) != 0 && f.OwnerID != payload.OwnerID {
+		return false
+	}
+
+	return true
+}
+
+// StreamEventsRequest describes the parameters to request a stream of events.
+type StreamEventsRequest struct {
+	Type string
+	ID   string
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *StreamEventsRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if len(request.Type) != 0 {
+		q.Add("type", request.Type)
+	}
+	if len(request.ID) != 0 {
+		q.Add("id", request.ID)
+	}
+	u.RawQuery = q.Encode()
+}
+
+// EventStream reads server-sent events from the provisioning server.
+type EventStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+}
+
+// NewEventStream creates an EventStream reading from the given response body.
+func NewEventStream(body io.ReadCloser) *EventStream {
+	return &EventStream{
+		body:    body,
+		scanner: bufio.NewScanner(body),
+	}
+}
+
+// Next blocks until the next event is received. io.EOF is returned once the
+// server closes the stream.
+func (s *EventStream) Next() (*WebhookPayload, error) {
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			// Blank event separators and keepalive comments.
+			continue
+		}
+
+		payload, err := WebhookPayloadFromReader(strings.NewReader(strings.TrimPrefix(line, "data:")))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode event")
+		}
+
+		return payload, nil
+	}
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// Close stops reading the stream.
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
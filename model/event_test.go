@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamFilterMatches(t *testing.T) {
+	payload := &WebhookPayload{Type: TypeInstallation, ID: "id1", OwnerID: "owner1"}
+
+	require.True(t, (&EventStreamFilter{}).Matches(payload))
+	require.True(t, (&EventStreamFilter{Type: TypeInstallation}).Matches(payload))
+	require.True(t, (&EventStreamFilter{Type: TypeInstallation, ID: "id1"}).Matches(payload))
+	require.False(t, (&EventStreamFilter{Type: TypeCluster}).Matches(payload))
+	require.False(t, (&EventStreamFilter{ID: "id2"}).Matches(payload))
+	require.True(t, (&EventStreamFilter{OwnerID: "owner1"}).Matches(payload))
+	require.False(t, (&EventStreamFilter{OwnerID: "owner2"}).Matches(payload))
+}
+
+func TestEventStream(t *testing.T) {
+	t.Run("events", func(t *testing.T) {
+		stream := NewEventStream(ioutil.NopCloser(strings.NewReader(
+			": keepalive\n\n" +
+				`data: {"id":"id1","type":"installation","new_state":"stable","old_state":"creation-requested"}` + "\n\n" +
+				": keepalive\n\n" +
+				`data: {"id":"id2","type":"cluster","new_state":"deletion-requested","old_state":"stable"}` + "\n\n",
+		)))
+		defer stream.Close()
+
+		payload, err := stream.Next()
+		require.NoError(t, err)
+		require.Equal(t, &WebhookPayload{ID: "id1", Type: TypeInstallation, NewState: "stable", OldState: "creation-requested"}, payload)
+
+		payload, err = stream.Next()
+		require.NoError(t, err)
+		require.Equal(t, &WebhookPayload{ID: "id2", Type: TypeCluster, NewState: "deletion-requested", OldState: "stable"}, payload)
+
+		payload, err = stream.Next()
+		require.Equal(t, io.EOF, err)
+		require.Nil(t, payload)
+	})
+
+	t.Run("invalid event", func(t *testing.T) {
+		stream := NewEventStream(ioutil.NopCloser(strings.NewReader("data: {invalid\n\n")))
+		defer stream.Close()
+
+		_, err := stream.Next()
+		require.Error(t, err)
+	})
+}
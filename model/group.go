@@ -11,25 +11,29 @@ import (
 
 // Group represents a group of Mattermost installations.
 type Group struct {
-	ID              string
-	Sequence        int64
-	Name            string
-	Description     string
-	Version         string
-	Image           string
-	MaxRolling      int64
-	MattermostEnv   EnvVarMap
-	CreateAt        int64
-	DeleteAt        int64
-	APISecurityLock bool
-	LockAcquiredBy  *string
-	LockAcquiredAt  int64
+	ID            string
+	Sequence      int64
+	Name          string
+	Description   string
+	Version       string
+	Image         string
+	MaxRolling    int64
+	MattermostEnv EnvVarMap
+	// HibernationSchedule hibernates and wakes up the installations of the
+	// group on a schedule, taking precedence over their own schedules.
+	HibernationSchedule *HibernationSchedule `json:"HibernationSchedule,omitempty"`
+	CreateAt            int64
+	DeleteAt            int64
+	APISecurityLock     bool
+	LockAcquiredBy      *string
+	LockAcquiredAt      int64
 }
 
 // GroupFilter describes the parameters used to constrain a set of groups.
 type GroupFilter struct {
 	Page           int
 	PerPage        int
+	Cursor         *PageCursor
 	IncludeDeleted bool
 }
 
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/url"
+	"reflect"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -22,6 +23,9 @@ type CreateGroupRequest struct {
 	MaxRolling      int64
 	APISecurityLock bool
 	MattermostEnv   EnvVarMap
+	// HibernationSchedule optionally hibernates and wakes up the
+	// installations of the group on a schedule.
+	HibernationSchedule *HibernationSchedule `json:"HibernationSchedule,omitempty"`
 }
 
 // SetDefaults sets the default values for a group create request.
@@ -43,6 +47,12 @@ func (request *CreateGroupRequest) Validate() error {
 	if err != nil {
 		return errors.Wrapf(err, "bad environment variable map in create group request")
 	}
+	if request.HibernationSchedule != nil {
+		err = request.HibernationSchedule.Validate()
+		if err != nil {
+			return errors.Wrap(err, "invalid hibernation schedule")
+		}
+	}
 
 	return nil
 }
@@ -73,6 +83,9 @@ type PatchGroupRequest struct {
 	Version       *string
 	Image         *string
 	MattermostEnv EnvVarMap
+	// HibernationSchedule replaces the hibernation schedule of the group. An
+	// empty schedule removes it.
+	HibernationSchedule *HibernationSchedule `json:"HibernationSchedule,omitempty"`
 }
 
 // Apply applies the patch to the given group.
@@ -104,6 +117,16 @@ func (p *PatchGroupRequest) Apply(group *Group) bool {
 			applied = true
 		}
 	}
+	if p.HibernationSchedule != nil {
+		schedule := p.HibernationSchedule
+		if schedule.IsEmpty() {
+			schedule = nil
+		}
+		if !reflect.DeepEqual(schedule, group.HibernationSchedule) {
+			applied = true
+			group.HibernationSchedule = schedule
+		}
+	}
 
 	return applied
 }
@@ -116,6 +139,12 @@ func (p *PatchGroupRequest) Validate() error {
 	if p.MaxRolling != nil && *p.MaxRolling < 1 {
 		return errors.New("max rolling must be 1 or greater")
 	}
+	if !p.HibernationSchedule.IsEmpty() {
+		err := p.HibernationSchedule.Validate()
+		if err != nil {
+			return errors.Wrap(err, "invalid hibernation schedule")
+		}
+	}
 	// EnvVarMap validation is skipped as all configurations of this now imply
 	// a specific patch action should be taken.
 
@@ -142,6 +171,7 @@ func NewPatchGroupRequestFromReader(reader io.Reader) (*PatchGroupRequest, error
 type GetGroupsRequest struct {
 	Page           int
 	PerPage        int
+	Cursor         string
 	IncludeDeleted bool
 }
 
@@ -150,6 +180,9 @@ func (request *GetGroupsRequest) ApplyToURL(u *url.URL) {
 	q := u.Query()
 	q.Add("page", strconv.Itoa(request.Page))
 	q.Add("per_page", strconv.Itoa(request.PerPage))
+	if request.Cursor != "" {
+		q.Add("cursor", request.Cursor)
+	}
 	if request.IncludeDeleted {
 		q.Add("include_deleted", "true")
 	}
@@ -0,0 +1,103 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// HibernationScheduleActionHibernate is a scheduled hibernation.
+	HibernationScheduleActionHibernate = "hibernate"
+	// HibernationScheduleActionWakeUp is a scheduled wake-up.
+	HibernationScheduleActionWakeUp = "wake-up"
+)
+
+// HibernationSchedule describes when an installation is hibernated and woken
+// up. Both are cron expressions evaluated in UTC, e.g. "0 20 * * 1-5" to
+// hibernate on weekday evenings and "0 7 * * 1-5" to wake up on weekday
+// mornings. Either may be left empty to only schedule the other transition.
+//
+// The schedule describes the state the installation should be in: the last
+// action it scheduled is applied as soon as it is set, e.g. an installation
+// given the schedule above on a Saturday is hibernated right away.
+type HibernationSchedule struct {
+	HibernateAt string `json:"HibernateAt,omitempty"`
+	WakeUpAt    string `json:"WakeUpAt,omitempty"`
+}
+
+// IsEmpty returns whether the schedule schedules nothing.
+func (s *HibernationSchedule) IsEmpty() bool {
+	return s == nil || (s.HibernateAt == "" && s.WakeUpAt == "")
+}
+
+// Validate validates the cron expressions of the schedule.
+func (s *HibernationSchedule) Validate() error {
+	if s.IsEmpty() {
+		return errors.New("hibernation schedule must have a hibernation or a wake-up time")
+	}
+	if s.HibernateAt != "" {
+		_, err := ParseCronExpression(s.HibernateAt)
+		if err != nil {
+			return errors.Wrap(err, "invalid hibernation time")
+		}
+	}
+	if s.WakeUpAt != "" {
+		_, err := ParseCronExpression(s.WakeUpAt)
+		if err != nil {
+			return errors.Wrap(err, "invalid wake-up time")
+		}
+	}
+
+	return nil
+}
+
+// LastAction returns the most recent scheduled action at or before the given
+// time, and when it was scheduled. An empty action is returned if nothing was
+// scheduled. A hibernation and a wake-up scheduled at the same time resolve to
+// the wake-up.
+func (s *HibernationSchedule) LastAction(now time.Time) (string, time.Time, error) {
+	var hibernateAt, wakeUpAt time.Time
+	if s.HibernateAt != "" {
+		expression, err := ParseCronExpression(s.HibernateAt)
+		if err != nil {
+			return "", time.Time{}, errors.Wrap(err, "invalid hibernation time")
+		}
+		hibernateAt = expression.Previous(now)
+	}
+	if s.WakeUpAt != "" {
+		expression, err := ParseCronExpression(s.WakeUpAt)
+		if err != nil {
+			return "", time.Time{}, errors.Wrap(err, "invalid wake-up time")
+		}
+		wakeUpAt = expression.Previous(now)
+	}
+
+	switch {
+	case hibernateAt.IsZero() && wakeUpAt.IsZero():
+		return "", time.Time{}, nil
+	case hibernateAt.After(wakeUpAt):
+		return HibernationScheduleActionHibernate, hibernateAt, nil
+	default:
+		return HibernationScheduleActionWakeUp, wakeUpAt, nil
+	}
+}
+
+// HibernationScheduleFromReader decodes a json-encoded hibernation schedule
+// from the given io.Reader.
+func HibernationScheduleFromReader(reader io.Reader) (*HibernationSchedule, error) {
+	schedule := HibernationSchedule{}
+	decoder := json.NewDecoder(reader)
+	err := decoder.Decode(&schedule)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHibernationScheduleValidate(t *testing.T) {
+	require.Error(t, (&model.HibernationSchedule{}).Validate())
+	require.Error(t, (&model.HibernationSchedule{HibernateAt: "tonight"}).Validate())
+	require.Error(t, (&model.HibernationSchedule{HibernateAt: "0 20 * * *", WakeUpAt: "morning"}).Validate())
+	require.NoError(t, (&model.HibernationSchedule{HibernateAt: "0 20 * * *"}).Validate())
+	require.NoError(t, (&model.HibernationSchedule{WakeUpAt: "0 8 * * *"}).Validate())
+	require.NoError(t, (&model.HibernationSchedule{HibernateAt: "0 20 * * 1-5", WakeUpAt: "0 8 * * 1-5"}).Validate())
+}
+
+func TestHibernationScheduleLastAction(t *testing.T) {
+	schedule := &model.HibernationSchedule{HibernateAt: "0 20 * * 1-5", WakeUpAt: "0 8 * * 1-5"}
+
+	// Wednesday afternoon.
+	action, at, err := schedule.LastAction(time.Date(2021, time.March, 17, 14, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, model.HibernationScheduleActionWakeUp, action)
+	require.Equal(t, time.Date(2021, time.March, 17, 8, 0, 0, 0, time.UTC), at)
+
+	// Saturday.
+	action, at, err = schedule.LastAction(time.Date(2021, time.March, 20, 14, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, model.HibernationScheduleActionHibernate, action)
+	require.Equal(t, time.Date(2021, time.March, 19, 20, 0, 0, 0, time.UTC), at)
+
+	action, _, err = (&model.HibernationSchedule{HibernateAt: "0 0 30 2 *"}).LastAction(time.Now())
+	require.NoError(t, err)
+	require.Empty(t, action)
+
+	_, _, err = (&model.HibernationSchedule{WakeUpAt: "invalid"}).LastAction(time.Now())
+	require.Error(t, err)
+}
+
+func TestPatchGroupRequestHibernationSchedule(t *testing.T) {
+	group := &model.Group{}
+	schedule := &model.HibernationSchedule{HibernateAt: "0 20 * * *"}
+
+	require.True(t, (&model.PatchGroupRequest{HibernationSchedule: schedule}).Apply(group))
+	require.Equal(t, schedule, group.HibernationSchedule)
+	require.False(t, (&model.PatchGroupRequest{HibernationSchedule: &model.HibernationSchedule{HibernateAt: "0 20 * * *"}}).Apply(group))
+	require.True(t, (&model.PatchGroupRequest{HibernationSchedule: &model.HibernationSchedule{}}).Apply(group))
+	require.Nil(t, group.HibernationSchedule)
+
+	require.Error(t, (&model.PatchGroupRequest{HibernationSchedule: &model.HibernationSchedule{WakeUpAt: "invalid"}}).Validate())
+	require.NoError(t, (&model.PatchGroupRequest{HibernationSchedule: &model.HibernationSchedule{}}).Validate())
+}
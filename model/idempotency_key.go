@@ -0,0 +1,58 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying a client-chosen key
+	// that makes retries of a create request return the original resource
+	// instead of creating a duplicate.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from an earlier
+	// request with the same idempotency key.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// MaxIdempotencyKeyLength is the maximum accepted length of an
+	// idempotency key.
+	MaxIdempotencyKeyLength = 255
+)
+
+// IdempotencyKey records a create request made with an idempotency key and,
+// once it has been served, its response.
+type IdempotencyKey struct {
+	ID string
+	// RequestKey is the key sent by the client.
+	RequestKey string
+	// APIKeyID scopes the key to the caller. It is empty when API keys are not
+	// required by the provisioning server.
+	APIKeyID string
+	// RequestHash identifies the request made with the key, so that reusing a
+	// key for a different request can be rejected.
+	RequestHash  string
+	StatusCode   int
+	ResponseBody string
+	CreateAt     int64
+}
+
+// IsCompleted returns whether the response to the request has been recorded.
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.StatusCode != 0
+}
+
+// HashIdempotentRequest returns the hash identifying a request made with an
+// idempotency key.
+func HashIdempotentRequest(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashIdempotentRequest(t *testing.T) {
+	hash := HashIdempotentRequest("POST", "/api/groups", []byte(`{"Name":"group"}`))
+	require.Equal(t, hash, HashIdempotentRequest("POST", "/api/groups", []byte(`{"Name":"group"}`)))
+	require.NotEqual(t, hash, HashIdempotentRequest("POST", "/api/webhooks", []byte(`{"Name":"group"}`)))
+	require.NotEqual(t, hash, HashIdempotentRequest("POST", "/api/groups", []byte(`{"Name":"other"}`)))
+	require.NotEqual(t, HashIdempotentRequest("POST", "/api/a", []byte("b")), HashIdempotentRequest("POST", "/api/", []byte("ab")))
+}
@@ -12,26 +12,56 @@ import (
 
 // Installation represents a Mattermost installation.
 type Installation struct {
-	ID              string
-	OwnerID         string
-	GroupID         *string
-	GroupSequence   *int64 `json:"GroupSequence,omitempty"`
-	Version         string
-	Image           string
-	DNS             string
-	Database        string
-	Filestore       string
-	License         string
-	MattermostEnv   EnvVarMap
-	Size            string
-	Affinity        string
-	State           string
-	CreateAt        int64
-	DeleteAt        int64
-	APISecurityLock bool
-	LockAcquiredBy  *string
-	LockAcquiredAt  int64
-	GroupOverrides  map[string]string `json:"GroupOverrides,omitempty"`
+	ID                          string
+	OwnerID                     string
+	GroupID                     *string
+	GroupSequence               *int64 `json:"GroupSequence,omitempty"`
+	Version                     string
+	Image                       string
+	DNS                         string
+	Database                    string
+	Filestore                   string
+	License                     string
+	MattermostEnv               EnvVarMap
+	Size                        string
+	Affinity                    string
+	PlacementStrategy           string
+	RequiredClusterAnnotations  []string `json:"RequiredClusterAnnotations,omitempty"`
+	PreferredClusterAnnotations []string `json:"PreferredClusterAnnotations,omitempty"`
+	MigrationTargetClusterID    string   `json:"MigrationTargetClusterID,omitempty"`
+	State                       string
+	CreateAt                    int64
+	DeleteAt                    int64
+	APISecurityLock             bool
+	LockAcquiredBy              *string
+	LockAcquiredAt              int64
+	GroupOverrides              map[string]string `json:"GroupOverrides,omitempty"`
+	// HibernationSchedule optionally hibernates and wakes up the installation
+	// on a schedule.
+	HibernationSchedule *HibernationSchedule `json:"HibernationSchedule,omitempty"`
+	// ScheduledActionAt is when the last hibernation or wake-up scheduled for
+	// the installation was scheduled, once applied. Transitions requested
+	// in between, such as waking up an installation during its scheduled
+	// hibernation, are left alone until the next scheduled one.
+	ScheduledActionAt int64 `json:"ScheduledActionAt,omitempty"`
+	// RestoreBackupID is the backup the installation is being, or was last,
+	// restored to. Installations cloned from another installation are created
+	// from the backup of the source installation.
+	RestoreBackupID string `json:"RestoreBackupID,omitempty"`
+	// RestoreID identifies the restoration of RestoreBackupID, telling apart
+	// repeated restorations of the same backup.
+	RestoreID string `json:"RestoreID,omitempty"`
+	// CloneScrubCommand is run on a cloned installation once it is created,
+	// typically to scrub user data copied from the source installation. It is
+	// cleared once it succeeded; email and push notifications are disabled
+	// until then.
+	CloneScrubCommand []string `json:"CloneScrubCommand,omitempty"`
+	// DatabaseMigrationTarget is the database type the installation data is
+	// being migrated to, if any.
+	DatabaseMigrationTarget string `json:"DatabaseMigrationTarget,omitempty"`
+	// FilestoreMigrationTarget is the filestore type the installation data is
+	// being migrated to, if any.
+	FilestoreMigrationTarget string `json:"FilestoreMigrationTarget,omitempty"`
 
 	// configconfigMergedWithGroup is set when the installation configuration
 	// has been overridden with group configuration. This value can then be
@@ -51,12 +81,35 @@ type InstallationsCount struct {
 
 // InstallationFilter describes the parameters used to constrain a set of installations.
 type InstallationFilter struct {
-	OwnerID        string
-	GroupID        string
-	Page           int
-	PerPage        int
+	OwnerID string
+	GroupID string
+	Page    int
+	PerPage int
+	// Cursor, when set, selects the page following the item it was taken
+	// from, ignoring Page.
+	Cursor         *PageCursor
 	IncludeDeleted bool
 	DNS            string
+	// States restricts the installations to those in any of the given states.
+	States []string
+	// Annotations restricts the installations to those having all of the
+	// given annotations.
+	Annotations []string
+	Version     string
+	Image       string
+	Database    string
+	Filestore   string
+	Size        string
+	Affinity    string
+	// CreatedAfter and CreatedBefore, in milliseconds since the epoch,
+	// restrict the installations to those created strictly after and before
+	// the given times.
+	CreatedAfter  int64
+	CreatedBefore int64
+	// SortBy is one of InstallationSortKeys, defaulting to SortByCreateAt.
+	SortBy string
+	// SortOrder is SortOrderAscending, the default, or SortOrderDescending.
+	SortOrder string
 }
 
 // Clone returns a deep copy the installation.
@@ -136,6 +189,14 @@ func (i *Installation) MergeWithGroup(group *Group, includeOverrides bool) {
 		}
 		i.MattermostEnv[key] = value
 	}
+	if group.HibernationSchedule != nil {
+		if includeOverrides && i.HibernationSchedule != nil && *i.HibernationSchedule != *group.HibernationSchedule {
+			i.GroupOverrides["Installation HibernationSchedule"] = fmt.Sprintf("%+v", *i.HibernationSchedule)
+			i.GroupOverrides["Group HibernationSchedule"] = fmt.Sprintf("%+v", *group.HibernationSchedule)
+		}
+		schedule := *group.HibernationSchedule
+		i.HibernationSchedule = &schedule
+	}
 }
 
 // InstallationFromReader decodes a json-encoded installation from the given io.Reader.
@@ -0,0 +1,28 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+// InstallationActivity describes how much an installation was used over a
+// period of time.
+type InstallationActivity struct {
+	// ActiveUsers is the highest number of users connected at once.
+	ActiveUsers int64
+	// Requests is the number of API requests served.
+	Requests int64
+}
+
+// Add adds the activity of another cluster installation of the same
+// installation.
+func (a *InstallationActivity) Add(activity *InstallationActivity) {
+	a.ActiveUsers += activity.ActiveUsers
+	a.Requests += activity.Requests
+}
+
+// IsIdle returns whether nobody used the installation. Up to maxRequests API
+// requests are tolerated as they are also made by health probes and
+// monitoring rather than users.
+func (a *InstallationActivity) IsIdle(maxRequests int64) bool {
+	return a.ActiveUsers == 0 && a.Requests <= maxRequests
+}
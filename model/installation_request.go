@@ -22,19 +22,30 @@ import (
 
 // CreateInstallationRequest specifies the parameters for a new installation.
 type CreateInstallationRequest struct {
-	OwnerID          string
-	GroupID          string
-	Version          string
-	Image            string
-	DNS              string
-	License          string
-	Size             string
-	Affinity         string
-	Database         string
-	Filestore        string
-	APISecurityLock  bool
-	MattermostEnv    EnvVarMap
-	Annotations []string
+	OwnerID           string
+	GroupID           string
+	Version           string
+	Image             string
+	DNS               string
+	License           string
+	Size              string
+	Affinity          string
+	PlacementStrategy string
+	Database          string
+	Filestore         string
+	APISecurityLock   bool
+	MattermostEnv     EnvVarMap
+	Annotations       []string
+
+	// RequiredClusterAnnotations are annotations a cluster must have for the
+	// installation to be scheduled on it.
+	RequiredClusterAnnotations []string
+	// PreferredClusterAnnotations are annotations of clusters which should be
+	// favored when scheduling the installation.
+	PreferredClusterAnnotations []string
+	// HibernationSchedule optionally hibernates and wakes up the installation
+	// on a schedule.
+	HibernationSchedule *HibernationSchedule `json:"HibernationSchedule,omitempty"`
 }
 
 // https://man7.org/linux/man-pages/man7/hostname.7.html
@@ -81,6 +92,9 @@ func (request *CreateInstallationRequest) Validate() error {
 	if !IsSupportedAffinity(request.Affinity) {
 		return errors.Errorf("unsupported affinity %s", request.Affinity)
 	}
+	if request.PlacementStrategy != "" && !IsSupportedPlacementStrategy(request.PlacementStrategy) {
+		return errors.Errorf("unsupported placement strategy %s", request.PlacementStrategy)
+	}
 	if !IsSupportedDatabase(request.Database) {
 		return errors.Errorf("unsupported database %s", request.Database)
 	}
@@ -91,6 +105,20 @@ func (request *CreateInstallationRequest) Validate() error {
 	if err != nil {
 		return errors.Wrap(err, "invalid env var settings")
 	}
+	_, err = AnnotationsFromStringSlice(request.RequiredClusterAnnotations)
+	if err != nil {
+		return errors.Wrap(err, "invalid required cluster annotations")
+	}
+	_, err = AnnotationsFromStringSlice(request.PreferredClusterAnnotations)
+	if err != nil {
+		return errors.Wrap(err, "invalid preferred cluster annotations")
+	}
+	if request.HibernationSchedule != nil {
+		err = request.HibernationSchedule.Validate()
+		if err != nil {
+			return errors.Wrap(err, "invalid hibernation schedule")
+		}
+	}
 
 	return checkSpaces(request)
 }
@@ -205,8 +233,21 @@ type GetInstallationsRequest struct {
 	IncludeGroupConfigOverrides bool
 	Page                        int
 	PerPage                     int
+	Cursor                      string
 	IncludeDeleted              bool
 	DNS                         string
+	States                      []string
+	Annotations                 []string
+	Version                     string
+	Image                       string
+	Database                    string
+	Filestore                   string
+	Size                        string
+	Affinity                    string
+	CreatedAfter                int64
+	CreatedBefore               int64
+	SortBy                      string
+	SortOrder                   string
 }
 
 // ApplyToURL modifies the given url to include query string parameters for the request.
@@ -222,12 +263,28 @@ func (request *GetInstallationsRequest) ApplyToURL(u *url.URL) {
 	}
 	q.Add("page", strconv.Itoa(request.Page))
 	q.Add("per_page", strconv.Itoa(request.PerPage))
+	if request.Cursor != "" {
+		q.Add("cursor", request.Cursor)
+	}
 	if request.IncludeDeleted {
 		q.Add("include_deleted", "true")
 	}
 	if request.DNS != "" {
 		q.Add("dns_name", request.DNS)
 	}
+	for name, value := range map[string]string{
+		"version":   request.Version,
+		"image":     request.Image,
+		"database":  request.Database,
+		"filestore": request.Filestore,
+		"size":      request.Size,
+		"affinity":  request.Affinity,
+	} {
+		if value != "" {
+			q.Add(name, value)
+		}
+	}
+	applyListFilterToQuery(q, request.States, request.Annotations, request.CreatedAfter, request.CreatedBefore, request.SortBy, request.SortOrder)
 	u.RawQuery = q.Encode()
 }
 
@@ -304,3 +361,121 @@ func NewPatchInstallationRequestFromReader(reader io.Reader) (*PatchInstallation
 
 	return &patchInstallationRequest, nil
 }
+
+// MigrateInstallationRequest specifies the parameters for moving an
+// installation to another cluster.
+type MigrateInstallationRequest struct {
+	TargetClusterID string
+}
+
+// Validate validates the values of an installation migrate request.
+func (request *MigrateInstallationRequest) Validate() error {
+	if len(request.TargetClusterID) == 0 {
+		return errors.New("must specify target cluster")
+	}
+
+	return nil
+}
+
+// NewMigrateInstallationRequestFromReader will create a MigrateInstallationRequest from an io.Reader with JSON data.
+func NewMigrateInstallationRequestFromReader(reader io.Reader) (*MigrateInstallationRequest, error) {
+	var migrateInstallationRequest MigrateInstallationRequest
+	err := json.NewDecoder(reader).Decode(&migrateInstallationRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode migrate installation request")
+	}
+
+	err = migrateInstallationRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid migrate installation request")
+	}
+
+	return &migrateInstallationRequest, nil
+}
+
+// CloneInstallationRequest specifies the parameters for creating a new
+// installation from a copy of the database and filestore of another one.
+type CloneInstallationRequest struct {
+	OwnerID string
+	DNS     string
+	// BackupID is the backup of the source installation to clone. A new
+	// backup is taken when none is given.
+	BackupID string `json:"BackupID,omitempty"`
+	// ScrubCommand is run on the clone once it is created, typically to
+	// scrub user data copied from the source installation. Like other
+	// commands run in cluster installations, it requires the cluster-admin
+	// scope and an API key not bound to an owner.
+	ScrubCommand []string `json:"ScrubCommand,omitempty"`
+}
+
+// Validate validates the values of an installation clone request.
+func (request *CloneInstallationRequest) Validate() error {
+	if request.OwnerID == "" {
+		return errors.New("must specify owner")
+	}
+	if err := isValidDNS(request.DNS); err != nil {
+		return err
+	}
+	for _, arg := range request.ScrubCommand {
+		if arg == "" {
+			return errors.New("scrub command arguments must not be empty")
+		}
+	}
+
+	return nil
+}
+
+// NewCloneInstallationRequestFromReader will create a CloneInstallationRequest from an io.Reader with JSON data.
+func NewCloneInstallationRequestFromReader(reader io.Reader) (*CloneInstallationRequest, error) {
+	var cloneInstallationRequest CloneInstallationRequest
+	err := json.NewDecoder(reader).Decode(&cloneInstallationRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode clone installation request")
+	}
+
+	err = cloneInstallationRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid clone installation request")
+	}
+
+	return &cloneInstallationRequest, nil
+}
+
+// MigrateInstallationDatabaseRequest specifies the database and filestore
+// types to move the data of an installation to. Leaving either of them empty
+// keeps the current one.
+type MigrateInstallationDatabaseRequest struct {
+	Database  string `json:"Database,omitempty"`
+	Filestore string `json:"Filestore,omitempty"`
+}
+
+// Validate validates the values of an installation database migration request.
+func (request *MigrateInstallationDatabaseRequest) Validate() error {
+	if request.Database == "" && request.Filestore == "" {
+		return errors.New("must specify a database or filestore to migrate to")
+	}
+	if request.Database != "" && !IsSupportedDatabase(request.Database) {
+		return errors.Errorf("unsupported database %s", request.Database)
+	}
+	if request.Filestore != "" && !IsSupportedFilestore(request.Filestore) {
+		return errors.Errorf("unsupported filestore %s", request.Filestore)
+	}
+
+	return nil
+}
+
+// NewMigrateInstallationDatabaseRequestFromReader will create a MigrateInstallationDatabaseRequest from an io.Reader with JSON data.
+func NewMigrateInstallationDatabaseRequestFromReader(reader io.Reader) (*MigrateInstallationDatabaseRequest, error) {
+	var migrateInstallationDatabaseRequest MigrateInstallationDatabaseRequest
+	err := json.NewDecoder(reader).Decode(&migrateInstallationDatabaseRequest)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to decode migrate installation database request")
+	}
+
+	err = migrateInstallationDatabaseRequest.Validate()
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid migrate installation database request")
+	}
+
+	return &migrateInstallationDatabaseRequest, nil
+}
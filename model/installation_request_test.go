@@ -107,6 +107,52 @@ func TestCreateInstallationRequestValid(t *testing.T) {
 				Affinity: "solo",
 			},
 		},
+		{
+			"invalid placement strategy",
+			true,
+			&model.CreateInstallationRequest{
+				OwnerID:           "owner1",
+				DNS:               "domain4321.com",
+				PlacementStrategy: "random",
+			},
+		},
+		{
+			"valid placement strategy",
+			false,
+			&model.CreateInstallationRequest{
+				OwnerID:           "owner1",
+				DNS:               "domain4321.com",
+				PlacementStrategy: model.PlacementStrategySpread,
+			},
+		},
+		{
+			"invalid required cluster annotation",
+			true,
+			&model.CreateInstallationRequest{
+				OwnerID:                    "owner1",
+				DNS:                        "domain4321.com",
+				RequiredClusterAnnotations: []string{"Invalid Annotation"},
+			},
+		},
+		{
+			"invalid preferred cluster annotation",
+			true,
+			&model.CreateInstallationRequest{
+				OwnerID:                     "owner1",
+				DNS:                         "domain4321.com",
+				PreferredClusterAnnotations: []string{"a"},
+			},
+		},
+		{
+			"valid cluster annotations",
+			false,
+			&model.CreateInstallationRequest{
+				OwnerID:                     "owner1",
+				DNS:                         "domain4321.com",
+				RequiredClusterAnnotations:  []string{"regulated"},
+				PreferredClusterAnnotations: []string{"canary"},
+			},
+		},
 		{
 			"invalid database",
 			true,
@@ -475,6 +521,124 @@ func TestNewPatchInstallationRequestFromReader(t *testing.T) {
 	})
 }
 
+func TestNewMigrateInstallationRequestFromReader(t *testing.T) {
+	t.Run("empty request", func(t *testing.T) {
+		request, err := model.NewMigrateInstallationRequestFromReader(bytes.NewReader([]byte(
+			``,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		request, err := model.NewMigrateInstallationRequestFromReader(bytes.NewReader([]byte(
+			`{test`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		request, err := model.NewMigrateInstallationRequestFromReader(bytes.NewReader([]byte(
+			`{"TargetClusterID":"cluster1"}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &model.MigrateInstallationRequest{TargetClusterID: "cluster1"}, request)
+	})
+}
+
+func TestNewCloneInstallationRequestFromReader(t *testing.T) {
+	t.Run("empty request", func(t *testing.T) {
+		request, err := model.NewCloneInstallationRequestFromReader(bytes.NewReader([]byte(
+			``,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		request, err := model.NewCloneInstallationRequestFromReader(bytes.NewReader([]byte(
+			`{test`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("missing owner", func(t *testing.T) {
+		request, err := model.NewCloneInstallationRequestFromReader(bytes.NewReader([]byte(
+			`{"DNS":"clone.example.com"}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("empty scrub command argument", func(t *testing.T) {
+		request, err := model.NewCloneInstallationRequestFromReader(bytes.NewReader([]byte(
+			`{"OwnerID":"owner1","DNS":"clone.example.com","ScrubCommand":["sh",""]}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		request, err := model.NewCloneInstallationRequestFromReader(bytes.NewReader([]byte(
+			`{"OwnerID":"owner1","DNS":"clone.example.com","BackupID":"backup1","ScrubCommand":["sh","-c","./scrub.sh"]}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &model.CloneInstallationRequest{
+			OwnerID:      "owner1",
+			DNS:          "clone.example.com",
+			BackupID:     "backup1",
+			ScrubCommand: []string{"sh", "-c", "./scrub.sh"},
+		}, request)
+	})
+}
+
+func TestNewMigrateInstallationDatabaseRequestFromReader(t *testing.T) {
+	t.Run("empty request", func(t *testing.T) {
+		request, err := model.NewMigrateInstallationDatabaseRequestFromReader(bytes.NewReader([]byte(
+			``,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		request, err := model.NewMigrateInstallationDatabaseRequestFromReader(bytes.NewReader([]byte(
+			`{test`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("unsupported database", func(t *testing.T) {
+		request, err := model.NewMigrateInstallationDatabaseRequestFromReader(bytes.NewReader([]byte(
+			`{"Database":"sqlite"}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("unsupported filestore", func(t *testing.T) {
+		request, err := model.NewMigrateInstallationDatabaseRequestFromReader(bytes.NewReader([]byte(
+			`{"Filestore":"floppy"}`,
+		)))
+		require.Error(t, err)
+		require.Nil(t, request)
+	})
+
+	t.Run("request", func(t *testing.T) {
+		request, err := model.NewMigrateInstallationDatabaseRequestFromReader(bytes.NewReader([]byte(
+			`{"Database":"aws-rds","Filestore":"aws-s3"}`,
+		)))
+		require.NoError(t, err)
+		require.Equal(t, &model.MigrateInstallationDatabaseRequest{
+			Database:  model.InstallationDatabaseSingleTenantRDSMySQL,
+			Filestore: model.InstallationFilestoreAwsS3,
+		}, request)
+	})
+}
+
 func sToP(s string) *string {
 	return &s
 }
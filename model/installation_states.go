@@ -40,6 +40,43 @@ const (
 	InstallationStateUpdateInProgress = "update-in-progress"
 	// InstallationStateUpdateFailed is an installation that failed to update.
 	InstallationStateUpdateFailed = "update-failed"
+	// InstallationStateMigrationRequested is an installation that is about to
+	// be moved to another cluster.
+	InstallationStateMigrationRequested = "migration-requested"
+	// InstallationStateMigrationInProgress is an installation waiting for its
+	// cluster installation on the target cluster to become stable.
+	InstallationStateMigrationInProgress = "migration-in-progress"
+	// InstallationStateMigrationDNS is an installation having DNS switched to
+	// the target cluster.
+	InstallationStateMigrationDNS = "migration-configuring-dns"
+	// InstallationStateMigrationCleanup is an installation having cluster
+	// installations on the source cluster removed.
+	InstallationStateMigrationCleanup = "migration-cleanup"
+	// InstallationStateMigrationFailed is an installation that failed to move
+	// to another cluster.
+	InstallationStateMigrationFailed = "migration-failed"
+	// InstallationStateRestorationRequested is a hibernating installation
+	// about to have its database and filestore restored from a backup.
+	InstallationStateRestorationRequested = "restoration-requested"
+	// InstallationStateRestorationInProgress is an installation whose
+	// filestore was restored and whose database is being restored.
+	InstallationStateRestorationInProgress = "restoration-in-progress"
+	// InstallationStateRestorationFailed is an installation that failed to be
+	// restored from a backup.
+	InstallationStateRestorationFailed = "restoration-failed"
+	// InstallationStateDBMigrationRequested is a hibernating installation
+	// about to have its database and filestore data moved to new backends.
+	InstallationStateDBMigrationRequested = "db-migration-requested"
+	// InstallationStateDBMigrationInProgress is an installation whose data is
+	// being replicated to its new database and filestore backends.
+	InstallationStateDBMigrationInProgress = "db-migration-in-progress"
+	// InstallationStateDBMigrationRollbackInProgress is an installation whose
+	// new database and filestore backends are being removed after a failed
+	// migration.
+	InstallationStateDBMigrationRollbackInProgress = "db-migration-rollback-in-progress"
+	// InstallationStateDBMigrationFailed is an installation that failed to
+	// move its data to new backends and was left on its original ones.
+	InstallationStateDBMigrationFailed = "db-migration-failed"
 	// InstallationStateDeletionRequested is an installation to be deleted.
 	InstallationStateDeletionRequested = "deletion-requested"
 	// InstallationStateDeletionInProgress is an installation being deleted.
@@ -75,6 +112,18 @@ var AllInstallationStates = []string{
 	InstallationStateUpdateRequested,
 	InstallationStateUpdateInProgress,
 	InstallationStateUpdateFailed,
+	InstallationStateMigrationRequested,
+	InstallationStateMigrationInProgress,
+	InstallationStateMigrationDNS,
+	InstallationStateMigrationCleanup,
+	InstallationStateMigrationFailed,
+	InstallationStateRestorationRequested,
+	InstallationStateRestorationInProgress,
+	InstallationStateRestorationFailed,
+	InstallationStateDBMigrationRequested,
+	InstallationStateDBMigrationInProgress,
+	InstallationStateDBMigrationRollbackInProgress,
+	InstallationStateDBMigrationFailed,
 	InstallationStateDeletionRequested,
 	InstallationStateDeletionInProgress,
 	InstallationStateDeletionFinalCleanup,
@@ -98,6 +147,15 @@ var AllInstallationStatesPendingWork = []string{
 	InstallationStateHibernationInProgress,
 	InstallationStateUpdateRequested,
 	InstallationStateUpdateInProgress,
+	InstallationStateMigrationRequested,
+	InstallationStateMigrationInProgress,
+	InstallationStateMigrationDNS,
+	InstallationStateMigrationCleanup,
+	InstallationStateRestorationRequested,
+	InstallationStateRestorationInProgress,
+	InstallationStateDBMigrationRequested,
+	InstallationStateDBMigrationInProgress,
+	InstallationStateDBMigrationRollbackInProgress,
 	InstallationStateDeletionRequested,
 	InstallationStateDeletionInProgress,
 	InstallationStateDeletionFinalCleanup,
@@ -112,6 +170,9 @@ var AllInstallationRequestStates = []string{
 	InstallationStateCreationRequested,
 	InstallationStateHibernationRequested,
 	InstallationStateUpdateRequested,
+	InstallationStateMigrationRequested,
+	InstallationStateRestorationRequested,
+	InstallationStateDBMigrationRequested,
 	InstallationStateDeletionRequested,
 }
 
@@ -125,6 +186,12 @@ func (i *Installation) ValidTransitionState(newState string) bool {
 		return validTransitionToInstallationStateHibernationRequested(i.State)
 	case InstallationStateUpdateRequested:
 		return validTransitionToInstallationStateUpgradeRequested(i.State)
+	case InstallationStateMigrationRequested:
+		return validTransitionToInstallationStateMigrationRequested(i.State)
+	case InstallationStateRestorationRequested:
+		return validTransitionToInstallationStateRestorationRequested(i.State)
+	case InstallationStateDBMigrationRequested:
+		return validTransitionToInstallationStateDBMigrationRequested(i.State)
 	case InstallationStateDeletionRequested:
 		return validTransitionToInstallationStateDeletionRequested(i.State)
 	}
@@ -132,6 +199,18 @@ func (i *Installation) ValidTransitionState(newState string) bool {
 	return false
 }
 
+// InstallationStateIsSettled returns whether the given state is one that the
+// installation supervisor will not move out of on its own.
+func InstallationStateIsSettled(state string) bool {
+	for _, pendingState := range AllInstallationStatesPendingWork {
+		if state == pendingState {
+			return false
+		}
+	}
+
+	return true
+}
+
 func validTransitionToInstallationStateCreationRequested(currentState string) bool {
 	switch currentState {
 	case InstallationStateCreationRequested,
@@ -156,7 +235,39 @@ func validTransitionToInstallationStateUpgradeRequested(currentState string) boo
 	case InstallationStateStable,
 		InstallationStateHibernating,
 		InstallationStateUpdateRequested,
-		InstallationStateUpdateFailed:
+		InstallationStateUpdateFailed,
+		InstallationStateDBMigrationFailed:
+		return true
+	}
+
+	return false
+}
+
+func validTransitionToInstallationStateMigrationRequested(currentState string) bool {
+	switch currentState {
+	case InstallationStateStable,
+		InstallationStateMigrationRequested,
+		InstallationStateMigrationFailed:
+		return true
+	}
+
+	return false
+}
+
+func validTransitionToInstallationStateRestorationRequested(currentState string) bool {
+	switch currentState {
+	case InstallationStateHibernating,
+		InstallationStateRestorationFailed:
+		return true
+	}
+
+	return false
+}
+
+func validTransitionToInstallationStateDBMigrationRequested(currentState string) bool {
+	switch currentState {
+	case InstallationStateHibernating,
+		InstallationStateDBMigrationFailed:
 		return true
 	}
 
@@ -176,6 +287,9 @@ func validTransitionToInstallationStateDeletionRequested(currentState string) bo
 		InstallationStateUpdateRequested,
 		InstallationStateUpdateInProgress,
 		InstallationStateUpdateFailed,
+		InstallationStateMigrationFailed,
+		InstallationStateRestorationFailed,
+		InstallationStateDBMigrationFailed,
 		InstallationStateDeletionRequested,
 		InstallationStateDeletionInProgress,
 		InstallationStateDeletionFinalCleanup,
@@ -0,0 +1,30 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+const (
+	// PlacementStrategyBinPacking prefers the most heavily loaded clusters so
+	// that existing clusters are filled before new ones are used.
+	PlacementStrategyBinPacking = "bin-packing"
+	// PlacementStrategySpread prefers the least loaded clusters so that
+	// installations are distributed evenly across clusters.
+	PlacementStrategySpread = "spread"
+	// PlacementStrategyAnnotationAffinity prefers clusters sharing the most
+	// annotations with the installation.
+	PlacementStrategyAnnotationAffinity = "annotation-affinity"
+)
+
+// IsSupportedPlacementStrategy returns true if the given placement strategy
+// string is supported.
+func IsSupportedPlacementStrategy(strategy string) bool {
+	switch strategy {
+	case PlacementStrategyBinPacking,
+		PlacementStrategySpread,
+		PlacementStrategyAnnotationAffinity:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSupportedPlacementStrategy(t *testing.T) {
+	var testCases = []struct {
+		strategy        string
+		expectSupported bool
+	}{
+		{"", false},
+		{"unknown", false},
+		{model.PlacementStrategyBinPacking, true},
+		{model.PlacementStrategySpread, true},
+		{model.PlacementStrategyAnnotationAffinity, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.strategy, func(t *testing.T) {
+			assert.Equal(t, tc.expectSupported, model.IsSupportedPlacementStrategy(tc.strategy))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SortOrderAscending sorts a list from the smallest to the largest value.
+	SortOrderAscending = "asc"
+	// SortOrderDescending sorts a list from the largest to the smallest value.
+	SortOrderDescending = "desc"
+)
+
+const (
+	// SortByCreateAt sorts a list by creation time.
+	SortByCreateAt = "create_at"
+	// SortByState sorts a list by state.
+	SortByState = "state"
+	// SortByOwner sorts a list of installations by owner.
+	SortByOwner = "owner"
+	// SortByDNS sorts a list of installations by DNS name.
+	SortByDNS = "dns"
+	// SortByVersion sorts a list of installations by Mattermost version.
+	SortByVersion = "version"
+)
+
+// ClusterSortKeys are the keys by which a list of clusters may be sorted.
+var ClusterSortKeys = []string{SortByCreateAt, SortByState}
+
+// InstallationSortKeys are the keys by which a list of installations may be
+// sorted.
+var InstallationSortKeys = []string{SortByCreateAt, SortByState, SortByOwner, SortByDNS, SortByVersion}
+
+// ValidateSort returns an error if the given sort key is not one of the given
+// supported keys or the given sort order is unknown. Empty values select the
+// default sort.
+func ValidateSort(sortBy, sortOrder string, supportedKeys []string) error {
+	if sortBy != "" {
+		supported := false
+		for _, key := range supportedKeys {
+			if sortBy == key {
+				supported = true
+				break
+			}
+		}
+		if !supported {
+			return errors.Errorf("unsupported sort key %s", sortBy)
+		}
+	}
+
+	switch sortOrder {
+	case "", SortOrderAscending, SortOrderDescending:
+		return nil
+	default:
+		return errors.Errorf("unsupported sort order %s", sortOrder)
+	}
+}
+
+// applyListFilterToQuery adds the filter and sort parameters shared by the
+// list requests to the given query.
+func applyListFilterToQuery(q url.Values, states, annotations []string, createdAfter, createdBefore int64, sortBy, sortOrder string) {
+	for _, state := range states {
+		q.Add("state", state)
+	}
+	for _, annotation := range annotations {
+		q.Add("annotation", annotation)
+	}
+	if createdAfter > 0 {
+		q.Add("created_after", strconv.FormatInt(createdAfter, 10))
+	}
+	if createdBefore > 0 {
+		q.Add("created_before", strconv.FormatInt(createdBefore, 10))
+	}
+	if sortBy != "" {
+		q.Add("sort", sortBy)
+	}
+	if sortOrder != "" {
+		q.Add("order", sortOrder)
+	}
+}
@@ -0,0 +1,20 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model_test
+
+import (
+	"testing"
+
+	"github.com/mattermost/mattermost-cloud/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateSort(t *testing.T) {
+	require.NoError(t, model.ValidateSort("", "", model.ClusterSortKeys))
+	require.NoError(t, model.ValidateSort(model.SortByState, model.SortOrderDescending, model.ClusterSortKeys))
+	require.NoError(t, model.ValidateSort(model.SortByDNS, model.SortOrderAscending, model.InstallationSortKeys))
+	require.Error(t, model.ValidateSort(model.SortByDNS, "", model.ClusterSortKeys))
+	require.Error(t, model.ValidateSort("", "random", model.InstallationSortKeys))
+}
@@ -7,6 +7,7 @@ package model
 import (
 	"encoding/json"
 	"io"
+	"path"
 )
 
 const (
@@ -17,15 +18,29 @@ const (
 	// TypeClusterInstallation is the string value that represents a cluster
 	// installation.
 	TypeClusterInstallation = "cluster_installaton"
+	// TypeGroup is the string value that represents a group.
+	TypeGroup = "group"
+	// TypeWebhook is the string value that represents a webhook.
+	TypeWebhook = "webhook"
+	// TypeBulkOperation is the string value that represents a bulk operation.
+	TypeBulkOperation = "bulk_operation"
+	// TypeBackup is the string value that represents an installation backup.
+	TypeBackup = "backup"
 )
 
 // Webhook is
 type Webhook struct {
-	ID       string
-	OwnerID  string
-	URL      string
-	CreateAt int64
-	DeleteAt int64
+	ID      string
+	OwnerID string
+	URL     string
+	Secret  string `json:"-"`
+	// EventTypes, EventStates and EventOwnerID restrict the events sent to
+	// the webhook. Empty values match every event.
+	EventTypes   []string
+	EventStates  []string
+	EventOwnerID string
+	CreateAt     int64
+	DeleteAt     int64
 }
 
 // WebhookFilter describes the parameters used to constrain a set of webhooks.
@@ -33,6 +48,7 @@ type WebhookFilter struct {
 	OwnerID        string
 	Page           int
 	PerPage        int
+	Cursor         *PageCursor
 	IncludeDeleted bool
 }
 
@@ -43,9 +59,37 @@ type WebhookPayload struct {
 	Type      string            `json:"type"`
 	NewState  string            `json:"new_state"`
 	OldState  string            `json:"old_state"`
+	OwnerID   string            `json:"owner_id,omitempty"`
 	ExtraData map[string]string `json:"extra_data,omitempty"`
 }
 
+// IsSigned returns whether payloads sent to the webhook are signed.
+func (w *Webhook) IsSigned() bool {
+	return w.Secret != ""
+}
+
+// Matches returns whether the given payload passes the event filters of the
+// webhook. NewState is matched against the EventStates glob patterns such as
+// "creation-*" or "*-failed".
+func (w *Webhook) Matches(payload *WebhookPayload) bool {
+	if len(w.EventOwnerID) != 0 && w.EventOwnerID != payload.OwnerID {
+		return false
+	}
+	if len(w.EventTypes) != 0 && !containsString(w.EventTypes, payload.Type) {
+		return false
+	}
+	if len(w.EventStates) == 0 {
+		return true
+	}
+	for _, pattern := range w.EventStates {
+		if matched, _ := path.Match(pattern, payload.NewState); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
 // IsDeleted returns whether the webhook was marked as deleted or not.
 func (w *Webhook) IsDeleted() bool {
 	return w.DeleteAt != 0
@@ -97,3 +141,13 @@ func WebhookPayloadFromReader(reader io.Reader) (*WebhookPayload, error) {
 
 	return &payload, nil
 }
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
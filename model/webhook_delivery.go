@@ -0,0 +1,82 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// WebhookDeliveryStatePending is a delivery that has not yet succeeded
+	// and will be attempted again.
+	WebhookDeliveryStatePending = "pending"
+	// WebhookDeliveryStateDelivered is a delivery that was accepted by the
+	// webhook receiver.
+	WebhookDeliveryStateDelivered = "delivered"
+	// WebhookDeliveryStateFailed is a delivery that ran out of attempts.
+	WebhookDeliveryStateFailed = "failed"
+)
+
+// WebhookDelivery is a record of a single payload being sent to a webhook.
+type WebhookDelivery struct {
+	ID             string
+	WebhookID      string
+	Payload        string
+	State          string
+	Attempts       int
+	NextAttemptAt  int64
+	LastAttemptAt  int64
+	ResponseCode   int
+	ResponseBody   string
+	LastError      string
+	CreateAt       int64
+	LockAcquiredBy *string
+	LockAcquiredAt int64
+}
+
+// WebhookDeliveryFilter describes the parameters used to constrain a set of
+// webhook deliveries.
+type WebhookDeliveryFilter struct {
+	WebhookID string
+	State     string
+	Page      int
+	PerPage   int
+}
+
+// GetWebhookDeliveriesRequest describes the parameters to request a list of
+// webhook deliveries.
+type GetWebhookDeliveriesRequest struct {
+	State   string
+	Page    int
+	PerPage int
+}
+
+// ApplyToURL modifies the given url to include query string parameters for the request.
+func (request *GetWebhookDeliveriesRequest) ApplyToURL(u *url.URL) {
+	q := u.Query()
+	if request.State != "" {
+		q.Add("state", request.State)
+	}
+	q.Add("page", strconv.Itoa(request.Page))
+	q.Add("per_page", strconv.Itoa(request.PerPage))
+	u.RawQuery = q.Encode()
+}
+
+// WebhookDeliveriesFromReader decodes a json-encoded list of webhook
+// deliveries from the given io.Reader.
+func WebhookDeliveriesFromReader(reader io.Reader) ([]*WebhookDelivery, error) {
+	deliveries := []*WebhookDelivery{}
+	decoder := json.NewDecoder(reader)
+
+	err := decoder.Decode(&deliveries)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"path"
 	"strconv"
 
 	"github.com/pkg/errors"
@@ -18,6 +19,14 @@ import (
 type CreateWebhookRequest struct {
 	OwnerID string
 	URL     string
+	// Secret is optional. When set, every payload sent to the webhook is
+	// signed with it; see VerifyWebhookSignature.
+	Secret string
+	// EventTypes, EventStates and EventOwnerID optionally restrict the
+	// events sent to the webhook.
+	EventTypes   []string
+	EventStates  []string
+	EventOwnerID string
 }
 
 // NewCreateWebhookRequestFromReader will create a CreateWebhookRequest from an io.Reader with JSON data.
@@ -46,6 +55,19 @@ func NewCreateWebhookRequestFromReader(reader io.Reader) (*CreateWebhookRequest,
 	if uri.Host == "" {
 		return nil, errors.New("must specify host")
 	}
+	for _, eventType := range createWebhookRequest.EventTypes {
+		switch eventType {
+		case TypeCluster, TypeInstallation, TypeClusterInstallation:
+		default:
+			return nil, errors.Errorf("'%s' is not a valid event type", eventType)
+		}
+	}
+	for _, pattern := range createWebhookRequest.EventStates {
+		_, err = path.Match(pattern, "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "'%s' is not a valid event state pattern", pattern)
+		}
+	}
 
 	return &createWebhookRequest, nil
 }
@@ -55,6 +77,7 @@ type GetWebhooksRequest struct {
 	OwnerID        string
 	Page           int
 	PerPage        int
+	Cursor         string
 	IncludeDeleted bool
 }
 
@@ -64,6 +87,9 @@ func (request *GetWebhooksRequest) ApplyToURL(u *url.URL) {
 	q.Add("owner", request.OwnerID)
 	q.Add("page", strconv.Itoa(request.Page))
 	q.Add("per_page", strconv.Itoa(request.PerPage))
+	if request.Cursor != "" {
+		q.Add("cursor", request.Cursor)
+	}
 	if request.IncludeDeleted {
 		q.Add("include_deleted", "true")
 	}
@@ -0,0 +1,65 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// WebhookSignatureHeader is the request header carrying the signature of
+	// a webhook payload sent to a webhook with a secret.
+	WebhookSignatureHeader = "X-Cloud-Signature"
+	// WebhookTimestampHeader is the request header carrying the time, in
+	// milliseconds, at which a signed webhook payload was sent.
+	WebhookTimestampHeader = "X-Cloud-Timestamp"
+
+	webhookSignaturePrefix = "sha256="
+)
+
+// SignWebhookPayload returns the signature of the given webhook payload body
+// sent at the given timestamp. The signature is the hex-encoded HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret, prefixed with "sha256=".
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks that the given signature and timestamp header
+// values match the webhook payload body for the given secret. Receivers should
+// additionally reject timestamps that are too old to guard against replays.
+func VerifyWebhookSignature(secret string, body []byte, timestamp, signature string) error {
+	if timestamp == "" {
+		return errors.New("missing webhook timestamp")
+	}
+	if signature == "" {
+		return errors.New("missing webhook signature")
+	}
+	if !strings.HasPrefix(signature, webhookSignaturePrefix) {
+		return errors.New("unsupported webhook signature scheme")
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.Wrap(err, "invalid webhook timestamp")
+	}
+
+	expected := SignWebhookPayload(secret, ts, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errors.New("webhook signature does not match")
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2015-present Mattermost, Inc. All Rights Reserved.
+// See LICENSE.txt for license information.
+//
+
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSignature(t *testing.T) {
+	secret := "supersecret"
+	body := []byte(`{"id":"id"}`)
+
+	signature := SignWebhookPayload(secret, 1234, body)
+	require.Equal(t, "sha256=", signature[:7])
+	require.Len(t, signature, 7+64)
+
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, VerifyWebhookSignature(secret, body, "1234", signature))
+	})
+
+	t.Run("missing timestamp", func(t *testing.T) {
+		require.EqualError(t, VerifyWebhookSignature(secret, body, "", signature), "missing webhook timestamp")
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		require.EqualError(t, VerifyWebhookSignature(secret, body, "1234", ""), "missing webhook signature")
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		require.EqualError(t, VerifyWebhookSignature(secret, body, "1234", "md5=abc"), "unsupported webhook signature scheme")
+	})
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		require.Error(t, VerifyWebhookSignature(secret, body, "abc", signature))
+	})
+
+	t.Run("different timestamp", func(t *testing.T) {
+		require.EqualError(t, VerifyWebhookSignature(secret, body, "1235", signature), "webhook signature does not match")
+	})
+
+	t.Run("different body", func(t *testing.T) {
+		require.EqualError(t, VerifyWebhookSignature(secret, []byte(`{"id":"other"}`), "1234", signature), "webhook signature does not match")
+	})
+
+	t.Run("different secret", func(t *testing.T) {
+		require.EqualError(t, VerifyWebhookSignature("othersecret", body, "1234", signature), "webhook signature does not match")
+	})
+}
@@ -27,6 +27,43 @@ func TestWebhookIsDeleted(t *testing.T) {
 	})
 }
 
+func TestWebhookIsSigned(t *testing.T) {
+	require.False(t, (&Webhook{}).IsSigned())
+	require.True(t, (&Webhook{Secret: "secret"}).IsSigned())
+}
+
+func TestWebhookMatches(t *testing.T) {
+	payload := &WebhookPayload{
+		Type:     TypeInstallation,
+		ID:       "id",
+		OwnerID:  "owner1",
+		NewState: InstallationStateCreationRequested,
+	}
+
+	testCases := []struct {
+		Description string
+		Webhook     *Webhook
+		Expected    bool
+	}{
+		{"no filters", &Webhook{}, true},
+		{"matching type", &Webhook{EventTypes: []string{TypeCluster, TypeInstallation}}, true},
+		{"other type", &Webhook{EventTypes: []string{TypeCluster}}, false},
+		{"matching state", &Webhook{EventStates: []string{InstallationStateCreationRequested}}, true},
+		{"matching state pattern", &Webhook{EventStates: []string{"stable", "creation-*"}}, true},
+		{"other state pattern", &Webhook{EventStates: []string{"*-failed"}}, false},
+		{"matching owner", &Webhook{EventOwnerID: "owner1"}, true},
+		{"other owner", &Webhook{EventOwnerID: "owner2"}, false},
+		{"all matching", &Webhook{EventTypes: []string{TypeInstallation}, EventStates: []string{"creation-*"}, EventOwnerID: "owner1"}, true},
+		{"one not matching", &Webhook{EventTypes: []string{TypeInstallation}, EventStates: []string{"creation-*"}, EventOwnerID: "owner2"}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Description, func(t *testing.T) {
+			require.Equal(t, tc.Expected, tc.Webhook.Matches(payload))
+		})
+	}
+}
+
 func TestWebhookPayloadToJSON(t *testing.T) {
 	payload := &WebhookPayload{
 		Timestamp: 123456789,